@@ -0,0 +1,117 @@
+// Archive subcommand for bulk archiving project items
+// Archives the items whose field values match --filter conditions, e.g. done items from old sprints
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// ArchiveConfig holds the options for the archive subcommand
+type ArchiveConfig struct {
+	Project string
+	Filters []string
+	Yes     bool
+
+	// noTerminal is set when stdin isn't a terminal
+	noTerminal bool
+}
+
+// newArchiveCmd creates the archive subcommand
+func newArchiveCmd() *cobra.Command {
+	var config ArchiveConfig
+
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive the project items matching a filter",
+		Long: `Archive every item of a project whose field values match all --filter
+conditions. Conditions are FIELD=PATTERN or FIELD!=PATTERN, matched
+ignoring case, where the pattern may use shell globs (* and ?).
+
+You are asked to confirm before anything is changed, unless --yes is given.
+
+Examples:
+  gh project-import archive --project "owner/project-name" --filter "Status=Done"
+  gh project-import archive --project "owner/project-name" --filter "Status=Done" --filter "Sprint=2023-*" --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			config.noTerminal = !term.IsTerminal(os.Stdin)
+			return runArchive(cmd.Context(), client, config, os.Stdin)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringArrayVarP(&config.Filters, "filter", "f", nil, "Archive items whose field matches, as FIELD=PATTERN or FIELD!=PATTERN (repeatable, all must match) (required)")
+	cmd.Flags().BoolVarP(&config.Yes, "yes", "y", false, "Don't ask for confirmation")
+
+	cmd.MarkFlagRequired("project")
+	cmd.MarkFlagRequired("filter")
+
+	return cmd
+}
+
+// runArchive archives the items matching the filter, asking on in for
+// confirmation first unless config.Yes is set
+func runArchive(ctx context.Context, client ghclient.Client, config ArchiveConfig, in io.Reader) error {
+	filter, err := mapping.ParseItemFilter(config.Filters)
+	if err != nil {
+		return err
+	}
+	if filter.Empty() {
+		return fmt.Errorf("at least one --filter is required")
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	items, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	var matched []ghclient.ProjectItem
+	for _, item := range items {
+		if !item.Archived && filter.Match(item.Fields) {
+			matched = append(matched, item)
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Printf("No items in project '%s' match the filter\n", project.Title)
+		return nil
+	}
+
+	if err := confirmChanges(in, os.Stdout, config.Yes, config.noTerminal, fmt.Sprintf("Archive %d of %d items in project '%s'?", len(matched), len(items), project.Title)); err != nil {
+		return err
+	}
+
+	archived, failed := 0, 0
+	for _, item := range matched {
+		if err := client.ArchiveProjectItem(ctx, project.ID, item.ID); err != nil {
+			slog.Error("Failed to archive item", "item", item.ID, "title", ghclient.GetString(item.Content, "title"), "error", err)
+			failed++
+			continue
+		}
+		archived++
+	}
+
+	fmt.Println(report.Success("Archived %d items", archived))
+	if failed > 0 {
+		return fmt.Errorf("failed to archive %d items", failed)
+	}
+	return nil
+}
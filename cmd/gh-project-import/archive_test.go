@@ -0,0 +1,38 @@
+// Tests for archiving project items
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunArchive(t *testing.T) {
+	client := newCleanClient()
+	client.items[0].Fields = map[string]interface{}{"Status": "Done", "Sprint": "Sprint 1"}
+	client.items[1].Fields = map[string]interface{}{"Status": "Done", "Sprint": "Sprint 9"}
+	client.items[2].Fields = map[string]interface{}{"Status": "Done", "Sprint": "Sprint 1"}
+
+	captureStdout(t, func() {
+		if err := runArchive(context.Background(), client, ArchiveConfig{Project: "owner/project", Filters: []string{"status=done", "Sprint=Sprint ?"}}, strings.NewReader("n\n")); !errors.Is(err, errAborted) {
+			t.Errorf("Expected the run to be aborted, got %v", err)
+		}
+		if len(client.archived) != 0 {
+			t.Errorf("Expected nothing to be archived without confirmation, got %v", client.archived)
+		}
+
+		if err := runArchive(context.Background(), client, ArchiveConfig{Project: "owner/project", Filters: []string{"status=done", "Sprint=Sprint ?"}, Yes: true}, strings.NewReader("")); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	// The third item is already archived
+	if strings.Join(client.archived, ",") != "PVTI_draft,PVTI_issue" {
+		t.Errorf("Expected the matching unarchived items to be archived, got %v", client.archived)
+	}
+
+	if err := runArchive(context.Background(), client, ArchiveConfig{Project: "owner/project", Filters: []string{"Status"}}, strings.NewReader("")); err == nil {
+		t.Error("Expected an invalid filter to be rejected")
+	}
+}
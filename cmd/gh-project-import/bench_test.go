@@ -0,0 +1,237 @@
+// Benchmarks for large imports
+// Measures the parse, plan and apply phases of an import of synthetic items
+// against the fake GitHub API server. BENCH_ITEMS sets the number of items
+// (default 1000); `make bench ITEMS=10000` runs them all.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// benchStatuses are the options of the benchmark project's Status field
+var benchStatuses = []string{"Todo", "In Progress", "Blocked", "Done"}
+
+// benchItems returns the number of items to benchmark with
+func benchItems(b *testing.B) int {
+	value := os.Getenv("BENCH_ITEMS")
+	if value == "" {
+		return 1000
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		b.Fatalf("BENCH_ITEMS must be a positive number, got %q", value)
+	}
+	return n
+}
+
+// benchIssueURL returns the URL of the issue or pull request synthetic item
+// i refers to, if any: every tenth item is an issue and every twentieth, of
+// the rest, a pull request
+func benchIssueURL(i int) string {
+	switch {
+	case i%10 == 0:
+		return fmt.Sprintf("https://github.com/acme/app/issues/%d", i+1)
+	case i%20 == 5:
+		return fmt.Sprintf("https://github.com/acme/app/pull/%d", i+1)
+	}
+	return ""
+}
+
+// writeSyntheticCSV writes a CSV source of n synthetic items to dir: mostly
+// draft issues, some issues and pull requests, each with a title, a body and
+// a value for every field of the benchmark project
+func writeSyntheticCSV(b *testing.B, dir string, n int) string {
+	b.Helper()
+	path := filepath.Join(dir, fmt.Sprintf("items-%d.csv", n))
+	file, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"Title", "URL", "Body", "Status", "Estimate", "Due Date", "Notes"})
+	for i := 0; i < n; i++ {
+		writer.Write([]string{
+			fmt.Sprintf("Item %d: %s", i+1, strings.Repeat("synthetic ", i%5+1)),
+			benchIssueURL(i),
+			fmt.Sprintf("Generated item %d.\n\n- [ ] First step\n- [ ] Second step", i+1),
+			benchStatuses[i%len(benchStatuses)],
+			strconv.Itoa(i%8 + 1),
+			fmt.Sprintf("2026-%02d-%02d", i%12+1, i%28+1),
+			fmt.Sprintf("Batch %d", i/100+1),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		b.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// newBenchProject returns a fake with an empty acme/Roadmap project whose
+// fields match the synthetic items, and the issues and pull requests the
+// first n items refer to
+func newBenchProject(n int) (*ghclient.FakeClient, *ghclient.FakeProject) {
+	fake := ghclient.NewFakeClient()
+	var options []ghclient.ProjectFieldOption
+	for i, name := range benchStatuses {
+		options = append(options, ghclient.ProjectFieldOption{ID: fmt.Sprintf("O_%d", i), Name: name})
+	}
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: options},
+		ghclient.ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"},
+		ghclient.ProjectField{ID: "F_due", Name: "Due Date", Type: "DATE"},
+		ghclient.ProjectField{ID: "F_notes", Name: "Notes", Type: "TEXT"},
+	)
+	for i := 0; i < n; i++ {
+		if url := benchIssueURL(i); url != "" {
+			fake.AddIssue(url, fmt.Sprintf("Item %d", i+1), "")
+		}
+	}
+	return fake, project
+}
+
+// benchConfig is the configuration the benchmarks import with
+func benchConfig(source string) Config {
+	return Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+}
+
+// discardOutput sends stdout and stderr, where the RESULT line goes, to the
+// null device until the benchmark ends
+func discardOutput(b *testing.B) {
+	b.Helper()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devNull, devNull
+	b.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		devNull.Close()
+	})
+}
+
+// parseBenchItems reads and prepares the synthetic items the way runImport
+// does before they reach a project
+func parseBenchItems(b *testing.B, config Config) []parser.ImportItem {
+	b.Helper()
+	items, err := parser.ParseSources(config.Sources, config.sourceOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	prep, err := parseItemPreparation(config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	items, _, err = prep.prepare(context.Background(), items, 0, config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := parser.ValidateImportItems(items); err != nil {
+		b.Fatal(err)
+	}
+	return items
+}
+
+// BenchmarkParse measures reading a CSV source into items
+func BenchmarkParse(b *testing.B) {
+	n := benchItems(b)
+	config := benchConfig(writeSyntheticCSV(b, b.TempDir(), n))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		items, err := parser.ParseSources(config.Sources, config.sourceOptions())
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(items) != n {
+			b.Fatalf("Expected %d items, got %d", n, len(items))
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*n), "µs/item")
+}
+
+// BenchmarkPlan measures a dry run: preparing and validating the items,
+// resolving the project and checking every field value against it
+func BenchmarkPlan(b *testing.B) {
+	n := benchItems(b)
+	config := benchConfig(writeSyntheticCSV(b, b.TempDir(), n))
+	config.DryRun = true
+	config.StrictFields = true
+	fake, _ := newBenchProject(n)
+	client, err := ghclient.NewFakeServer(fake).Client()
+	if err != nil {
+		b.Fatal(err)
+	}
+	discardOutput(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		items, err := parser.ParseSources(config.Sources, config.sourceOptions())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		prep, err := parseItemPreparation(config)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if items, _, err = prep.prepare(context.Background(), items, 0, config); err != nil {
+			b.Fatal(err)
+		}
+		if err := parser.ValidateImportItems(items); err != nil {
+			b.Fatal(err)
+		}
+		if err := importToDestinations(context.Background(), client, items, config, strings.NewReader("")); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*n), "µs/item")
+}
+
+// BenchmarkApply measures importing the items into an empty project through
+// the real client, with the fake server answering its requests
+func BenchmarkApply(b *testing.B) {
+	n := benchItems(b)
+	config := benchConfig(writeSyntheticCSV(b, b.TempDir(), n))
+	discardOutput(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		items := parseBenchItems(b, config)
+		fake, project := newBenchProject(n)
+		client, err := ghclient.NewFakeServer(fake).Client()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		if err := importToDestinations(context.Background(), client, items, config, strings.NewReader("")); err != nil {
+			b.Fatal(err)
+		}
+
+		b.StopTimer()
+		if len(project.Items) != n {
+			b.Fatalf("Expected %d items in the project, got %d", n, len(project.Items))
+		}
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*n), "µs/item")
+}
@@ -0,0 +1,203 @@
+// Changelog subcommand comparing two project exports
+// Summarizes new and removed items, status transitions and reassignments between snapshots
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// ChangelogConfig holds the options for the changelog subcommand
+type ChangelogConfig struct {
+	OldFile     string
+	NewFile     string
+	StatusField string
+}
+
+// StatusChange records an item whose status changed between exports
+type StatusChange struct {
+	Title string
+	From  string
+	To    string
+}
+
+// Reassignment records an item whose assignees changed between exports
+type Reassignment struct {
+	Title string
+	From  []string
+	To    []string
+}
+
+// Changelog holds the differences between two exports
+type Changelog struct {
+	Added         []parser.ImportItem
+	Removed       []parser.ImportItem
+	StatusChanges []StatusChange
+	Reassignments []Reassignment
+}
+
+// newChangelogCmd creates the changelog subcommand
+func newChangelogCmd() *cobra.Command {
+	var config ChangelogConfig
+
+	cmd := &cobra.Command{
+		Use:   "changelog <old-export> <new-export>",
+		Short: "Summarize what changed between two project exports",
+		Long: `Compare two exports of the same project (for example from scheduled
+"gh project item-list --format json" backups) and list new and removed items,
+status transitions and reassignments.
+
+Items are matched by project item ID, falling back to their URL and then title.
+
+Examples:
+  gh project-import changelog backup-monday.json backup-friday.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.OldFile = args[0]
+			config.NewFile = args[1]
+			return runChangelog(config)
+		},
+	}
+
+	cmd.Flags().StringVar(&config.StatusField, "status-field", "Status", "Field that holds the item status (matched case-insensitively)")
+
+	return cmd
+}
+
+// runChangelog compares two export files and prints the changes
+func runChangelog(config ChangelogConfig) error {
+	oldItems, err := parser.ParseSourceFile(config.OldFile, parser.SourceOptions{})
+	if err != nil {
+		return err
+	}
+
+	newItems, err := parser.ParseSourceFile(config.NewFile, parser.SourceOptions{})
+	if err != nil {
+		return err
+	}
+
+	changelog := computeChangelog(oldItems, newItems, config.StatusField)
+	printChangelog(changelog, config)
+	return nil
+}
+
+// changelogKey identifies an item across exports
+func changelogKey(item parser.ImportItem) string {
+	switch {
+	case item.ID != "":
+		return "id:" + item.ID
+	case item.URL != "":
+		return "url:" + item.URL
+	case item.Content.URL != "":
+		return "url:" + item.Content.URL
+	default:
+		return "title:" + item.Title
+	}
+}
+
+// lookupField returns a field value by name, ignoring case
+func lookupField(item parser.ImportItem, name string) string {
+	for key, value := range item.Fields {
+		if strings.EqualFold(key, name) {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return ""
+}
+
+// computeChangelog finds the differences between two sets of items
+func computeChangelog(oldItems, newItems []parser.ImportItem, statusField string) Changelog {
+	var changelog Changelog
+
+	oldByKey := make(map[string]parser.ImportItem)
+	for _, item := range oldItems {
+		oldByKey[changelogKey(item)] = item
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range newItems {
+		key := changelogKey(item)
+		seen[key] = true
+
+		old, existed := oldByKey[key]
+		if !existed {
+			changelog.Added = append(changelog.Added, item)
+			continue
+		}
+
+		if from, to := lookupField(old, statusField), lookupField(item, statusField); from != to {
+			changelog.StatusChanges = append(changelog.StatusChanges, StatusChange{Title: item.Title, From: from, To: to})
+		}
+
+		from, to := sortedCopy(old.Assignees), sortedCopy(item.Assignees)
+		if strings.Join(from, ",") != strings.Join(to, ",") {
+			changelog.Reassignments = append(changelog.Reassignments, Reassignment{Title: item.Title, From: from, To: to})
+		}
+	}
+
+	for _, item := range oldItems {
+		if !seen[changelogKey(item)] {
+			changelog.Removed = append(changelog.Removed, item)
+		}
+	}
+
+	return changelog
+}
+
+// sortedCopy returns a sorted copy of a string slice
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// describeValue renders an empty value so it's visible in the changelog
+func describeValue(value string) string {
+	if value == "" {
+		return noValue
+	}
+	return value
+}
+
+// printChangelog prints the changes in a readable report
+func printChangelog(changelog Changelog, config ChangelogConfig) {
+	fmt.Printf("Changes from %s to %s\n", config.OldFile, config.NewFile)
+
+	total := len(changelog.Added) + len(changelog.Removed) + len(changelog.StatusChanges) + len(changelog.Reassignments)
+	if total == 0 {
+		fmt.Println("No changes")
+		return
+	}
+
+	if len(changelog.Added) > 0 {
+		fmt.Printf("\nNew items (%d):\n", len(changelog.Added))
+		for _, item := range changelog.Added {
+			fmt.Printf("  + %s\n", item.Title)
+		}
+	}
+
+	if len(changelog.Removed) > 0 {
+		fmt.Printf("\nRemoved items (%d):\n", len(changelog.Removed))
+		for _, item := range changelog.Removed {
+			fmt.Printf("  - %s\n", item.Title)
+		}
+	}
+
+	if len(changelog.StatusChanges) > 0 {
+		fmt.Printf("\n%s changes (%d):\n", config.StatusField, len(changelog.StatusChanges))
+		for _, change := range changelog.StatusChanges {
+			fmt.Printf("  ~ %s: %s → %s\n", change.Title, describeValue(change.From), describeValue(change.To))
+		}
+	}
+
+	if len(changelog.Reassignments) > 0 {
+		fmt.Printf("\nReassignments (%d):\n", len(changelog.Reassignments))
+		for _, change := range changelog.Reassignments {
+			fmt.Printf("  ~ %s: %s → %s\n", change.Title, describeValue(strings.Join(change.From, ", ")), describeValue(strings.Join(change.To, ", ")))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Tests for the changelog subcommand
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestChangelog(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldFile := filepath.Join(tmpDir, "old.json")
+	oldContent := `{"items": [
+		{"id": "PVTI_1", "title": "Keep", "status": "Todo", "assignees": ["alice"]},
+		{"id": "PVTI_2", "title": "Drop", "status": "Done"},
+		{"id": "PVTI_3", "title": "Move", "status": "Todo", "assignees": ["alice", "bob"]}
+	], "totalCount": 3}`
+	if err := os.WriteFile(oldFile, []byte(oldContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	newFile := filepath.Join(tmpDir, "new.json")
+	newContent := `{"items": [
+		{"id": "PVTI_1", "title": "Keep", "status": "Todo", "assignees": ["alice"]},
+		{"id": "PVTI_3", "title": "Move", "status": "In Progress", "assignees": ["carol"]},
+		{"id": "PVTI_4", "title": "Fresh"}
+	], "totalCount": 3}`
+	if err := os.WriteFile(newFile, []byte(newContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	oldItems, err := parser.ParseSourceFile(oldFile, parser.SourceOptions{})
+	if err != nil {
+		t.Fatalf("Failed to parse old export: %v", err)
+	}
+	newItems, err := parser.ParseSourceFile(newFile, parser.SourceOptions{})
+	if err != nil {
+		t.Fatalf("Failed to parse new export: %v", err)
+	}
+
+	changelog := computeChangelog(oldItems, newItems, "Status")
+
+	if len(changelog.Added) != 1 || changelog.Added[0].Title != "Fresh" {
+		t.Errorf("Expected 'Fresh' to be added, got %v", changelog.Added)
+	}
+	if len(changelog.Removed) != 1 || changelog.Removed[0].Title != "Drop" {
+		t.Errorf("Expected 'Drop' to be removed, got %v", changelog.Removed)
+	}
+	if len(changelog.StatusChanges) != 1 || changelog.StatusChanges[0] != (StatusChange{Title: "Move", From: "Todo", To: "In Progress"}) {
+		t.Errorf("Unexpected status changes: %v", changelog.StatusChanges)
+	}
+	if len(changelog.Reassignments) != 1 || changelog.Reassignments[0].To[0] != "carol" {
+		t.Errorf("Unexpected reassignments: %v", changelog.Reassignments)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runChangelog(ChangelogConfig{OldFile: oldFile, NewFile: newFile, StatusField: "Status"}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+	if !contains(out, "~ Move: Todo → In Progress") || !contains(out, "~ Move: alice, bob → carol") {
+		t.Errorf("Unexpected changelog output:\n%s", out)
+	}
+}
@@ -0,0 +1,293 @@
+// Import progress checkpoints and the status subcommand
+// Records per-item progress on disk so long runs can be inspected and resumed
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCheckpointFile is where import progress is recorded unless --checkpoint is given
+const defaultCheckpointFile = ".gh-project-import.checkpoint.json"
+
+// Checkpoint records the progress of an import run
+type Checkpoint struct {
+	Sources     []string            `json:"sources"`
+	FromClassic string              `json:"from_classic,omitempty"`
+	FromQuery   string              `json:"from_query,omitempty"`
+	Project     string              `json:"project"`
+	Skip        int                 `json:"skip,omitempty"`
+	Limit       int                 `json:"limit,omitempty"`
+	Total       int                 `json:"total"`
+	Completed   []int               `json:"completed"`
+	Failed      []CheckpointFailure `json:"failed,omitempty"`
+	Finished    bool                `json:"finished"`
+	PID         int                 `json:"pid"`
+	Started     time.Time           `json:"started"`
+	Updated     time.Time           `json:"updated"`
+
+	// completed and failed index Completed and Failed by item, so looking an
+	// item up doesn't scan every item of a large run. They are built on first
+	// use.
+	completed map[int]bool
+	failed    map[int]bool
+}
+
+// CheckpointFailure describes an item that failed to import
+type CheckpointFailure struct {
+	Item  int    `json:"item"`
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+// newCheckpoint creates an empty checkpoint for an import run
+func newCheckpoint(config Config, total int) *Checkpoint {
+	now := time.Now()
+	return &Checkpoint{
+		Sources:     config.Sources,
+		FromClassic: config.FromClassic,
+		FromQuery:   config.FromQuery,
+		Project:     config.Project,
+		Skip:        config.Skip,
+		Limit:       config.Limit,
+		Total:       total,
+		Completed:   []int{},
+		PID:         os.Getpid(),
+		Started:     now,
+		Updated:     now,
+	}
+}
+
+// openCheckpoint starts a new checkpoint for the run, or loads the existing one
+// when resuming. A resumed checkpoint must belong to the same source and project.
+func openCheckpoint(config Config, total int) (*Checkpoint, error) {
+	if !config.Resume {
+		return newCheckpoint(config, total), nil
+	}
+
+	checkpoint, err := LoadCheckpoint(config.Checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resume: %w", err)
+	}
+
+	if !slices.Equal(checkpoint.Sources, config.Sources) || checkpoint.FromClassic != config.FromClassic || checkpoint.FromQuery != config.FromQuery || checkpoint.Project != config.Project {
+		return nil, fmt.Errorf("cannot resume: checkpoint %s is for %s → %s, not %s → %s",
+			config.Checkpoint, checkpoint.sourceName(), checkpoint.Project, config.sourceName(), config.Project)
+	}
+	if checkpoint.Skip != config.Skip || checkpoint.Limit != config.Limit {
+		return nil, fmt.Errorf("cannot resume: checkpoint was recorded with --skip %d --limit %d", checkpoint.Skip, checkpoint.Limit)
+	}
+	if checkpoint.Total != total {
+		return nil, fmt.Errorf("cannot resume: source file now has %d items but the checkpoint recorded %d", total, checkpoint.Total)
+	}
+
+	checkpoint.Finished = false
+	checkpoint.PID = os.Getpid()
+	return checkpoint, nil
+}
+
+// sourceName describes where the checkpointed run read its items from
+func (c *Checkpoint) sourceName() string {
+	return Config{Sources: c.Sources, FromClassic: c.FromClassic, FromQuery: c.FromQuery}.sourceName()
+}
+
+// LoadCheckpoint reads a checkpoint file from disk
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %s: %w", path, err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file %s: %w", path, err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint to disk, replacing the file atomically so a
+// concurrent status reader never sees a partial write
+func (c *Checkpoint) Save(path string) error {
+	c.Updated = time.Now()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := writeFileAtomically(path, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomically replaces the file at path with data through a temporary
+// file in the same directory, so readers never see a partial write
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// index builds the completed and failed sets from Completed and Failed
+func (c *Checkpoint) index() {
+	if c.completed != nil {
+		return
+	}
+	c.completed = make(map[int]bool, len(c.Completed))
+	for _, item := range c.Completed {
+		c.completed[item] = true
+	}
+	c.failed = make(map[int]bool, len(c.Failed))
+	for _, failure := range c.Failed {
+		c.failed[failure.Item] = true
+	}
+}
+
+// IsCompleted reports whether the 1-based item index was imported successfully
+func (c *Checkpoint) IsCompleted(item int) bool {
+	c.index()
+	return c.completed[item]
+}
+
+// MarkCompleted records a successfully imported item
+func (c *Checkpoint) MarkCompleted(item int) {
+	c.index()
+	if !c.completed[item] {
+		c.completed[item] = true
+		c.Completed = append(c.Completed, item)
+	}
+	if !c.failed[item] {
+		return
+	}
+	// A retried item that now succeeds is no longer a failure
+	delete(c.failed, item)
+	failed := c.Failed[:0]
+	for _, failure := range c.Failed {
+		if failure.Item != item {
+			failed = append(failed, failure)
+		}
+	}
+	c.Failed = failed
+}
+
+// Unmark forgets that an item was imported, e.g. after it was rolled back
+func (c *Checkpoint) Unmark(item int) {
+	c.index()
+	if !c.completed[item] {
+		return
+	}
+	delete(c.completed, item)
+	completed := c.Completed[:0]
+	for _, completedItem := range c.Completed {
+		if completedItem != item {
+			completed = append(completed, completedItem)
+		}
+	}
+	c.Completed = completed
+}
+
+// MarkFailed records an item that failed to import
+func (c *Checkpoint) MarkFailed(item int, title string, err error) {
+	c.index()
+	if c.failed[item] {
+		for i, failure := range c.Failed {
+			if failure.Item == item {
+				c.Failed[i].Error = err.Error()
+				return
+			}
+		}
+	}
+	c.failed[item] = true
+	c.Failed = append(c.Failed, CheckpointFailure{Item: item, Title: title, Error: err.Error()})
+}
+
+// newStatusCmd creates the status subcommand
+func newStatusCmd() *cobra.Command {
+	var checkpointPath string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the progress of a running or interrupted import",
+		Long: `Read the checkpoint written by an import run and print its progress,
+the failures recorded so far, and how to resume it if it was interrupted.
+
+Examples:
+  gh project-import status
+  gh project-import status --checkpoint /tmp/migration.checkpoint.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(checkpointPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&checkpointPath, "checkpoint", defaultCheckpointFile, "Checkpoint file written by the import run")
+
+	return cmd
+}
+
+// runStatus prints the progress recorded in a checkpoint file
+func runStatus(checkpointPath string) error {
+	if _, err := os.Stat(checkpointPath); os.IsNotExist(err) {
+		return fmt.Errorf("no checkpoint found at %s", checkpointPath)
+	}
+
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	state := "in progress"
+	if checkpoint.Finished {
+		state = "finished"
+	}
+
+	processed := len(checkpoint.Completed) + len(checkpoint.Failed)
+	fmt.Printf("Import of %s into project %s (%s)\n", checkpoint.sourceName(), checkpoint.Project, state)
+	fmt.Printf("  Started:   %s\n", checkpoint.Started.Format(time.RFC3339))
+	fmt.Printf("  Updated:   %s (%s ago)\n", checkpoint.Updated.Format(time.RFC3339), time.Since(checkpoint.Updated).Round(time.Second))
+	fmt.Printf("  Progress:  %d/%d items processed\n", processed, checkpoint.Total)
+	fmt.Printf("  Imported:  %d\n", len(checkpoint.Completed))
+	fmt.Printf("  Failed:    %d\n", len(checkpoint.Failed))
+
+	for _, failure := range checkpoint.Failed {
+		fmt.Printf("   - item %d (\"%s\"): %s\n", failure.Item, failure.Title, failure.Error)
+	}
+
+	if !checkpoint.Finished || len(checkpoint.Failed) > 0 {
+		fmt.Printf("\nIf the run is no longer active (PID %d), resume it with:\n", checkpoint.PID)
+		var sourceFlags []string
+		for _, source := range checkpoint.Sources {
+			sourceFlags = append(sourceFlags, fmt.Sprintf("--source %q", source))
+		}
+		sourceFlag := strings.Join(sourceFlags, " ")
+		if checkpoint.FromClassic != "" {
+			sourceFlag = fmt.Sprintf("--from-classic %q", checkpoint.FromClassic)
+		}
+		if checkpoint.FromQuery != "" {
+			sourceFlag = fmt.Sprintf("--from-query %q", checkpoint.FromQuery)
+		}
+		fmt.Printf("  gh project-import %s --project %q --resume --checkpoint %q\n", sourceFlag, checkpoint.Project, checkpointPath)
+	}
+
+	return nil
+}
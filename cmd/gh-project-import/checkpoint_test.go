@@ -0,0 +1,141 @@
+// Tests for import checkpoints and the status subcommand
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	checkpoint := newCheckpoint(Config{Sources: []string{"items.json"}, Project: "owner/project"}, 3)
+	checkpoint.MarkCompleted(1)
+	checkpoint.MarkFailed(2, "Second", fmt.Errorf("boom"))
+	checkpoint.MarkFailed(3, "Third", fmt.Errorf("boom"))
+	checkpoint.MarkCompleted(3) // retried successfully
+
+	if err := checkpoint.Save(path); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+
+	if !loaded.IsCompleted(1) || !loaded.IsCompleted(3) || loaded.IsCompleted(2) {
+		t.Errorf("Unexpected completed items: %v", loaded.Completed)
+	}
+	if len(loaded.Failed) != 1 || loaded.Failed[0].Item != 2 {
+		t.Errorf("Expected only item 2 to be failed, got %v", loaded.Failed)
+	}
+
+	if err := runStatus(path); err != nil {
+		t.Errorf("Expected status to succeed, got: %v", err)
+	}
+	if err := runStatus(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing checkpoint")
+	}
+}
+
+func TestImportItemsResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	config := Config{Sources: []string{"items.json"}, Project: "owner/project", Quiet: true, Checkpoint: path}
+
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "First"}, {Title: "Second"}, {Title: "Third"}}
+
+	// Simulate an interrupted run that imported the first item
+	previous := newCheckpoint(config, len(items))
+	previous.MarkCompleted(1)
+	if err := previous.Save(path); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	config.Resume = true
+	client := &countingDraftClient{}
+	if err := importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, config); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	if client.calls != 2 {
+		t.Errorf("Expected 2 items to be created on resume, got %d", client.calls)
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if !checkpoint.Finished || len(checkpoint.Completed) != 3 {
+		t.Errorf("Expected finished checkpoint with 3 completed items, got %+v", checkpoint)
+	}
+
+	// Resuming against a different project is refused
+	config.Project = "owner/other"
+	if err := importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, config); err == nil {
+		t.Error("Expected error when resuming a checkpoint for a different project")
+	}
+}
+
+// countingDraftClient is a ghclient.Client stub that counts created draft issues
+type countingDraftClient struct {
+	ghclient.Client
+	calls int
+}
+
+func (c *countingDraftClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	c.calls++
+	return fmt.Sprintf("PVTI_%d", c.calls), nil
+}
+
+func TestImportItemsSavesCheckpointPeriodically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	config := Config{Sources: []string{"items.json"}, Project: "owner/project", Quiet: true, Checkpoint: path}
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := make([]parser.ImportItem, checkpointSaveItems*2+50)
+	for i := range items {
+		items[i].Title = fmt.Sprintf("Item %d", i+1)
+	}
+
+	// Record how many items the saved checkpoint had as each item is imported
+	client := &checkpointReadingClient{path: path}
+	if err := importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, config); err != nil {
+		t.Fatal(err)
+	}
+	for i, saved := range client.saved {
+		if want := i / checkpointSaveItems * checkpointSaveItems; saved != want {
+			t.Fatalf("Item %d: expected the checkpoint to hold %d items, got %d", i+1, want, saved)
+		}
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checkpoint.Finished || len(checkpoint.Completed) != len(items) {
+		t.Errorf("Expected the finished run to save every item, got %d", len(checkpoint.Completed))
+	}
+}
+
+// checkpointReadingClient is a ghclient.Client stub that reads the saved
+// checkpoint before creating each draft issue
+type checkpointReadingClient struct {
+	ghclient.Client
+	path  string
+	saved []int
+}
+
+func (c *checkpointReadingClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	checkpoint, err := LoadCheckpoint(c.path)
+	if err != nil {
+		return "", err
+	}
+	c.saved = append(c.saved, len(checkpoint.Completed))
+	return fmt.Sprintf("PVTI_%d", len(c.saved)), nil
+}
@@ -0,0 +1,86 @@
+// Migration from GitHub classic (v1) project boards
+// Converts the columns and cards of a classic board into Projects v2 import items
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ParseClassicProjectIdentifier splits an owner/repo/project-number identifier
+func ParseClassicProjectIdentifier(identifier string) (string, string, int, error) {
+	parts := strings.Split(identifier, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, fmt.Errorf("invalid classic project identifier: %s (expected owner/repo/project-number)", identifier)
+	}
+
+	number, err := strconv.Atoi(parts[2])
+	if err != nil || number < 1 {
+		return "", "", 0, fmt.Errorf("invalid classic project number in %s", identifier)
+	}
+
+	return parts[0], parts[1], number, nil
+}
+
+// FetchClassicProjectItems reads a classic project board and converts its cards into import items
+func FetchClassicProjectItems(ctx context.Context, client ghclient.Client, identifier string) ([]parser.ImportItem, error) {
+	owner, repo, number, err := ParseClassicProjectIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := client.GetClassicProjectColumns(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertClassicProjectColumns(columns), nil
+}
+
+// classicContentURLPattern matches the REST API URL of an issue card's content
+var classicContentURLPattern = regexp.MustCompile(`^https://api\.github\.com/repos/([^/]+)/([^/]+)/issues/(\d+)$`)
+
+// ConvertClassicProjectColumns converts classic board cards into import items.
+// The column name becomes the Status field; note cards become draft issues
+// titled by their first line, and issue cards link to the existing issue.
+func ConvertClassicProjectColumns(columns []ghclient.ClassicProjectColumn) []parser.ImportItem {
+	var items []parser.ImportItem
+
+	for _, column := range columns {
+		for _, card := range column.Cards {
+			item := parser.ImportItem{
+				Fields: map[string]interface{}{"Status": column.Name},
+			}
+
+			if card.ContentURL != "" {
+				// Pull requests are issues in the REST API, so both use the issues URL
+				if matches := classicContentURLPattern.FindStringSubmatch(card.ContentURL); matches != nil {
+					item.URL = fmt.Sprintf("https://github.com/%s/%s/issues/%s", matches[1], matches[2], matches[3])
+					item.Title = fmt.Sprintf("%s/%s#%s", matches[1], matches[2], matches[3])
+				} else {
+					item.URL = card.ContentURL
+					item.Title = card.ContentURL
+				}
+			} else {
+				note := strings.TrimSpace(card.Note)
+				title, body, _ := strings.Cut(note, "\n")
+				item.Title = strings.TrimSpace(title)
+				item.Notes = strings.TrimSpace(body)
+				item.Content = parser.ItemContent{Type: "DraftIssue", Title: item.Title, Body: item.Notes}
+			}
+
+			if item.Title == "" {
+				continue // Skip empty note cards
+			}
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
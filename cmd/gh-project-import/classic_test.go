@@ -0,0 +1,93 @@
+// Tests for classic project board migration
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// classicBoardClient is a ghclient.Client stub that serves a fixed classic board
+type classicBoardClient struct {
+	ghclient.Client
+	columns []ghclient.ClassicProjectColumn
+}
+
+func (c *classicBoardClient) GetClassicProjectColumns(ctx context.Context, owner, repo string, number int) ([]ghclient.ClassicProjectColumn, error) {
+	return c.columns, nil
+}
+
+func TestParseClassicProjectIdentifier(t *testing.T) {
+	tests := []struct {
+		identifier  string
+		expectError bool
+	}{
+		{"owner/repo/3", false},
+		{"owner/repo", true},
+		{"owner/repo/abc", true},
+		{"owner/repo/0", true},
+		{"/repo/1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			owner, repo, number, err := ParseClassicProjectIdentifier(tt.identifier)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if owner != "owner" || repo != "repo" || number != 3 {
+				t.Errorf("Unexpected result: %s %s %d", owner, repo, number)
+			}
+		})
+	}
+}
+
+func TestFetchClassicProjectItems(t *testing.T) {
+	client := &classicBoardClient{
+		columns: []ghclient.ClassicProjectColumn{
+			{Name: "To do", Cards: []ghclient.ClassicProjectCard{
+				{Note: "Write release notes\nInclude the migration guide"},
+				{Note: "   "},
+			}},
+			{Name: "In progress", Cards: []ghclient.ClassicProjectCard{
+				{ContentURL: "https://api.github.com/repos/octo/app/issues/42"},
+			}},
+		},
+	}
+
+	items, err := FetchClassicProjectItems(context.Background(), client, "octo/app/1")
+	if err != nil {
+		t.Fatalf("Failed to fetch classic project: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	note := items[0]
+	if note.Title != "Write release notes" || parser.GetItemBody(note) != "Include the migration guide" {
+		t.Errorf("Unexpected note conversion: %+v", note)
+	}
+	if parser.GetItemType(note) != "DraftIssue" || note.Fields["Status"] != "To do" {
+		t.Errorf("Expected draft issue with Status 'To do', got %+v", note)
+	}
+
+	issue := items[1]
+	if issue.URL != "https://github.com/octo/app/issues/42" {
+		t.Errorf("Expected issue URL, got %q", issue.URL)
+	}
+	if parser.GetItemType(issue) != "Issue" || issue.Fields["Status"] != "In progress" {
+		t.Errorf("Expected issue with Status 'In progress', got %+v", issue)
+	}
+	if err := parser.ValidateImportItems(items); err != nil {
+		t.Errorf("Expected converted items to be valid, got: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Clean subcommand for emptying a destination project
+// Bulk deletes or archives a project's items, e.g. between test imports
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// CleanConfig holds the options for the clean subcommand
+type CleanConfig struct {
+	Project      string
+	ArchivedOnly bool
+	DraftsOnly   bool
+	Archive      bool
+	Yes          bool
+
+	// noTerminal is set when stdin isn't a terminal
+	noTerminal bool
+}
+
+// newCleanCmd creates the clean subcommand
+func newCleanCmd() *cobra.Command {
+	var config CleanConfig
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Delete or archive a project's items",
+		Long: `Delete every item of a project, or archive them with --archive. Use
+--archived-only or --drafts-only to limit which items are removed. Deleting
+an issue or pull request item only removes it from the project; deleted draft
+issues are gone for good.
+
+You are asked to confirm before anything is changed, unless --yes is given.
+
+Examples:
+  gh project-import clean --project "owner/test-project"
+  gh project-import clean --project "owner/test-project" --drafts-only --yes
+  gh project-import clean --project "owner/project-name" --archive`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			config.noTerminal = !term.IsTerminal(os.Stdin)
+			return runClean(cmd.Context(), client, config, os.Stdin)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().BoolVar(&config.ArchivedOnly, "archived-only", false, "Only delete items that are already archived")
+	cmd.Flags().BoolVar(&config.DraftsOnly, "drafts-only", false, "Only remove draft issues")
+	cmd.Flags().BoolVar(&config.Archive, "archive", false, "Archive the items instead of deleting them")
+	cmd.Flags().BoolVarP(&config.Yes, "yes", "y", false, "Don't ask for confirmation")
+
+	cmd.MarkFlagRequired("project")
+	cmd.MarkFlagsMutuallyExclusive("archived-only", "archive")
+
+	return cmd
+}
+
+// runClean removes the selected items, asking on in for confirmation first
+// unless config.Yes is set
+func runClean(ctx context.Context, client ghclient.Client, config CleanConfig, in io.Reader) error {
+	if config.ArchivedOnly && config.Archive {
+		return fmt.Errorf("cannot use --archived-only with --archive")
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	items, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	items = selectCleanItems(items, config)
+
+	verb, remove := "Delete", client.DeleteProjectItem
+	if config.Archive {
+		verb, remove = "Archive", client.ArchiveProjectItem
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("No items to %s in project '%s'\n", strings.ToLower(verb), project.Title)
+		return nil
+	}
+
+	if err := confirmChanges(in, os.Stdout, config.Yes, config.noTerminal, fmt.Sprintf("%s %s from project '%s'?", verb, countNoun(len(items), "item", "items"), project.Title)); err != nil {
+		return err
+	}
+
+	removed, failed := 0, 0
+	for _, item := range items {
+		if err := remove(ctx, project.ID, item.ID); err != nil {
+			slog.Error("Failed to "+strings.ToLower(verb)+" item", "item", item.ID, "title", ghclient.GetString(item.Content, "title"), "error", err)
+			failed++
+			continue
+		}
+		removed++
+	}
+
+	fmt.Println(report.Success("%sd %d items", verb, removed))
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d items", strings.ToLower(verb), failed)
+	}
+	return nil
+}
+
+// selectCleanItems returns the items the clean subcommand should remove.
+// Archiving skips items that are already archived.
+func selectCleanItems(items []ghclient.ProjectItem, config CleanConfig) []ghclient.ProjectItem {
+	var selected []ghclient.ProjectItem
+	for _, item := range items {
+		if config.ArchivedOnly && !item.Archived {
+			continue
+		}
+		if config.Archive && item.Archived {
+			continue
+		}
+		if config.DraftsOnly && ghclient.GetString(item.Content, "type") != "DraftIssue" {
+			continue
+		}
+		selected = append(selected, item)
+	}
+	return selected
+}
@@ -0,0 +1,76 @@
+// Tests for the clean subcommand
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// cleanClient is a ghclient.Client stub that records the items it removes
+type cleanClient struct {
+	*projectItemsClient
+	deleted  []string
+	archived []string
+}
+
+func (c *cleanClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	c.deleted = append(c.deleted, itemID)
+	return nil
+}
+
+func (c *cleanClient) ArchiveProjectItem(ctx context.Context, projectID, itemID string) error {
+	c.archived = append(c.archived, itemID)
+	return nil
+}
+
+func newCleanClient() *cleanClient {
+	return &cleanClient{projectItemsClient: &projectItemsClient{
+		schemaClient: &schemaClient{project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"}},
+		items: []ghclient.ProjectItem{
+			{ID: "PVTI_draft", Content: map[string]interface{}{"type": "DraftIssue"}},
+			{ID: "PVTI_issue", Content: map[string]interface{}{"type": "Issue"}},
+			{ID: "PVTI_archived", Content: map[string]interface{}{"type": "DraftIssue"}, Archived: true},
+		},
+	}}
+}
+
+func TestRunClean(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   CleanConfig
+		answer   string
+		deleted  string
+		archived string
+		aborted  bool
+	}{
+		{"confirmed", CleanConfig{}, "y\n", "PVTI_draft,PVTI_issue,PVTI_archived", "", false},
+		{"declined", CleanConfig{}, "\n", "", "", true},
+		{"no answer", CleanConfig{}, "", "", "", true},
+		{"yes flag", CleanConfig{Yes: true, DraftsOnly: true}, "", "PVTI_draft,PVTI_archived", "", false},
+		{"archived only", CleanConfig{Yes: true, ArchivedOnly: true}, "", "PVTI_archived", "", false},
+		{"archive", CleanConfig{Yes: true, Archive: true}, "", "", "PVTI_draft,PVTI_issue", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newCleanClient()
+			tt.config.Project = "owner/project"
+			captureStdout(t, func() {
+				err := runClean(context.Background(), client, tt.config, strings.NewReader(tt.answer))
+				if (tt.aborted && !errors.Is(err, errAborted)) || (!tt.aborted && err != nil) {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			})
+			if deleted := strings.Join(client.deleted, ","); deleted != tt.deleted {
+				t.Errorf("Expected deleted %q, got %q", tt.deleted, deleted)
+			}
+			if archived := strings.Join(client.archived, ","); archived != tt.archived {
+				t.Errorf("Expected archived %q, got %q", tt.archived, archived)
+			}
+		})
+	}
+}
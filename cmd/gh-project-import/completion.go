@@ -0,0 +1,134 @@
+// Dynamic shell completions
+// Completes --project with the owner's projects and field flags with the destination project's fields
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the API requests made to complete a flag, so a
+// slow network doesn't freeze the shell
+const completionTimeout = 5 * time.Second
+
+// fieldFlags are the flags whose values are names of the destination
+// project's fields. Each is comma-separated if listed as true.
+var fieldFlags = map[string]bool{
+	"only-fields": true,
+	"skip-fields": true,
+	"order-by":    false,
+}
+
+// registerCompletions registers the dynamic completions on cmd and its
+// subcommands, for whichever of the flags each command has
+func registerCompletions(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("project") != nil {
+		cmd.RegisterFlagCompletionFunc("project", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return withCompletionClient(func(ctx context.Context, client ghclient.Client) ([]string, cobra.ShellCompDirective) {
+				return completeProjects(ctx, client, toComplete)
+			})
+		})
+	}
+	for name, list := range fieldFlags {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			project, _ := cmd.Flags().GetString("project")
+			if project == "" {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return withCompletionClient(func(ctx context.Context, client ghclient.Client) ([]string, cobra.ShellCompDirective) {
+				return completeFields(ctx, client, project, toComplete, list)
+			})
+		})
+	}
+
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// withCompletionClient runs fn with a new client and a context that expires
+// after completionTimeout. Without a client nothing is completed.
+func withCompletionClient(fn func(context.Context, ghclient.Client) ([]string, cobra.ShellCompDirective)) ([]string, cobra.ShellCompDirective) {
+	client, err := newClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	return fn(ctx, client)
+}
+
+// completeProjects completes a project identifier. Before the slash the
+// signed-in user is suggested as the owner; after it, the titles of the
+// owner's open projects that start with what has been typed.
+func completeProjects(ctx context.Context, client ghclient.Client, toComplete string) ([]string, cobra.ShellCompDirective) {
+	owner, prefix, found := strings.Cut(toComplete, "/")
+	if !found {
+		login, err := client.GetUser(ctx)
+		if err != nil || !strings.HasPrefix(strings.ToLower(login), strings.ToLower(owner)) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return []string{login + "/"}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	projects, err := client.ListProjects(ctx, owner)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, project := range projects {
+		if !project.Closed && strings.HasPrefix(strings.ToLower(project.Title), strings.ToLower(prefix)) {
+			completions = append(completions, owner+"/"+project.Title)
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFields completes field names of the project. For comma-separated
+// flags the last name is completed and the names already given are left out.
+func completeFields(ctx context.Context, client ghclient.Client, identifier, toComplete string, list bool) ([]string, cobra.ShellCompDirective) {
+	project, err := client.FindProject(ctx, identifier)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	given, prefix := "", toComplete
+	used := make(map[string]bool)
+	if list {
+		if index := strings.LastIndex(toComplete, ","); index >= 0 {
+			given, prefix = toComplete[:index+1], toComplete[index+1:]
+			for _, name := range strings.Split(given, ",") {
+				used[strings.ToLower(strings.TrimSpace(name))] = true
+			}
+		}
+	}
+
+	var completions []string
+	for _, field := range fields {
+		name := strings.ToLower(field.Name)
+		if !used[name] && strings.HasPrefix(name, strings.ToLower(prefix)) {
+			completions = append(completions, given+field.Name)
+		}
+	}
+	sort.Strings(completions)
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if list {
+		// Another name may follow after a comma
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return completions, directive
+}
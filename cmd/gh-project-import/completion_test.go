@@ -0,0 +1,58 @@
+// Tests for dynamic shell completions
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteProjects(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+	client.AddProject("acme", ghclient.Project{Title: "Release Train"})
+	client.AddProject("acme", ghclient.Project{Title: "Retired", Closed: true})
+	client.AddProject("acme", ghclient.Project{Title: "Backlog"})
+
+	completions, directive := completeProjects(context.Background(), client, "acme/re")
+	if want := []string{"acme/Release Train"}; !reflect.DeepEqual(completions, want) {
+		t.Errorf("Expected %v, got %v", want, completions)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Unexpected directive %v", directive)
+	}
+
+	completions, directive = completeProjects(context.Background(), client, "oct")
+	if want := []string{"octocat/"}; !reflect.DeepEqual(completions, want) || directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Errorf("Expected the signed-in user as owner, got %v (%v)", completions, directive)
+	}
+}
+
+func TestCompleteFields(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F1", Name: "Status", Type: "SINGLE_SELECT"},
+		ghclient.ProjectField{ID: "F2", Name: "Sprint", Type: "ITERATION"},
+		ghclient.ProjectField{ID: "F3", Name: "Priority", Type: "SINGLE_SELECT"},
+	)
+
+	completions, _ := completeFields(context.Background(), client, "acme/Roadmap", "s", false)
+	if want := []string{"Sprint", "Status"}; !reflect.DeepEqual(completions, want) {
+		t.Errorf("Expected %v, got %v", want, completions)
+	}
+
+	completions, directive := completeFields(context.Background(), client, "acme/Roadmap", "Status,", true)
+	if want := []string{"Status,Priority", "Status,Sprint"}; !reflect.DeepEqual(completions, want) {
+		t.Errorf("Expected %v, got %v", want, completions)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Error("Expected no space after a field of a comma-separated list")
+	}
+
+	if completions, _ := completeFields(context.Background(), client, "acme/Missing", "", false); completions != nil {
+		t.Errorf("Expected no completions for a missing project, got %v", completions)
+	}
+}
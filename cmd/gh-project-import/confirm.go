@@ -0,0 +1,110 @@
+// Confirmation prompts before changing a project
+// Imports, archives and cleans summarize what they will change and ask first unless --yes is given
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// confirm asks a yes/no question on out and reports whether the answer
+// read from in was yes. Anything else, including no input, is a no.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// errAborted is returned when the confirmation prompt is declined
+var errAborted = errors.New("aborted, nothing was changed")
+
+// confirmChanges asks question on out unless yes is set. With noTerminal,
+// e.g. under cron or in CI, nobody can answer, so it fails with a usage
+// error rather than waiting for an answer or reading the missing one as a
+// no. A declined prompt returns errAborted, so the command exits with a
+// non-zero code.
+func confirmChanges(in io.Reader, out io.Writer, yes, noTerminal bool, question string) error {
+	if yes {
+		return nil
+	}
+	if noTerminal {
+		return withExitCode(exitUsage, fmt.Errorf("cannot ask for confirmation without a terminal; pass --yes to make the changes"))
+	}
+	if !confirm(in, out, question) {
+		return withExitCode(exitUsage, errAborted)
+	}
+	return nil
+}
+
+// importSummary counts the changes an import will make
+type importSummary struct {
+	drafts      int
+	content     int
+	fieldValues int
+}
+
+// add counts the changes importing items will make. Fields the project
+// doesn't have are skipped by the import and aren't counted.
+func (s *importSummary) add(items []parser.ImportItem, fieldMap map[string]ghclient.ProjectField) {
+	for _, item := range items {
+		if parser.GetItemType(item) == "DraftIssue" {
+			s.drafts++
+		} else {
+			s.content++
+		}
+
+		for name := range item.Fields {
+			// The milestone is set on the issue rather than as a field value
+			if _, exists := fieldMap[name]; exists && !mapping.IsMilestoneField(name) {
+				s.fieldValues++
+			}
+		}
+	}
+}
+
+// question asks to confirm the changes, e.g. "Create 182 draft issues, add 46
+// issues and pull requests, set 913 field values in project 'Roadmap'?"
+func (s importSummary) question(project string) string {
+	var changes []string
+	if s.drafts > 0 {
+		changes = append(changes, "create "+countNoun(s.drafts, "draft issue", "draft issues"))
+	}
+	if s.content > 0 {
+		changes = append(changes, "add "+countNoun(s.content, "issue or pull request", "issues and pull requests"))
+	}
+	if s.fieldValues > 0 {
+		changes = append(changes, "set "+countNoun(s.fieldValues, "field value", "field values"))
+	}
+
+	question := strings.Join(changes, ", ")
+	return strings.ToUpper(question[:1]) + question[1:] + fmt.Sprintf(" in project '%s'?", project)
+}
+
+// countNoun writes count followed by the singular or plural noun, e.g.
+// "1 draft issue" or "3 draft issues"
+func countNoun(count int, singular, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// confirmImport asks on in whether to make the changes in summary, unless
+// --yes was given, as confirmChanges does. The question goes to stderr when
+// stdout is quiet or reserved for TSV rows.
+func confirmImport(in io.Reader, config Config, summary importSummary, project string) error {
+	var out io.Writer = os.Stdout
+	if config.Quiet {
+		out = os.Stderr
+	}
+	return confirmChanges(in, out, config.Yes, config.noTerminal, summary.question(project))
+}
@@ -0,0 +1,130 @@
+// Tests for confirmation prompts
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestConfirm(t *testing.T) {
+	for answer, expected := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		var out bytes.Buffer
+		if got := confirm(strings.NewReader(answer), &out, "Continue?"); got != expected {
+			t.Errorf("Expected %q to confirm %v, got %v", answer, expected, got)
+		}
+		if out.String() != "Continue? [y/N] " {
+			t.Errorf("Unexpected prompt %q", out.String())
+		}
+	}
+}
+
+func TestConfirmChanges(t *testing.T) {
+	var out bytes.Buffer
+	if err := confirmChanges(strings.NewReader("y\n"), &out, false, false, "Continue?"); err != nil {
+		t.Errorf("Expected yes to confirm, got %v", err)
+	}
+	if err := confirmChanges(strings.NewReader("n\n"), &out, false, false, "Continue?"); !errors.Is(err, errAborted) || exitCode(err) != exitUsage {
+		t.Errorf("Expected no to abort with exit code %d, got %v", exitUsage, err)
+	}
+
+	// Without a terminal nobody can answer, so only --yes goes ahead. The
+	// pipe is never written or closed, so reading it would wait forever.
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	out.Reset()
+	if err := confirmChanges(stdin, &out, false, true, "Continue?"); err == nil || errors.Is(err, errAborted) || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("Expected an error asking for --yes, got %v", err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("Expected no prompt without a terminal, got %q", out.String())
+	}
+	if err := confirmChanges(stdin, &out, true, true, "Continue?"); err != nil {
+		t.Errorf("Expected --yes to go ahead without a terminal, got %v", err)
+	}
+}
+
+func TestRunImportWithoutTerminalDoesNotWait(t *testing.T) {
+	source := writeRowsCSV(t, "One,")
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("owner", ghclient.Project{Title: "project"})
+	useClient(t, fake)
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory", noTerminal: true}
+
+	// Like a CI job's stdin: not a terminal, and never at EOF
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	done := make(chan error, 1)
+	go func() { done <- runImport(context.Background(), config, stdin) }()
+
+	select {
+	case err := <-done:
+		if exitCode(err) != exitUsage || !strings.Contains(fmt.Sprint(err), "--yes") {
+			t.Errorf("Expected a usage error asking for --yes, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the import to fail instead of waiting for an answer")
+	}
+	if len(project.Items) != 0 {
+		t.Errorf("Expected nothing to be imported, got %d items", len(project.Items))
+	}
+}
+
+func TestImportSummaryQuestion(t *testing.T) {
+	fieldMap := map[string]ghclient.ProjectField{
+		"Status":    {ID: "F_status", Name: "Status"},
+		"Milestone": {ID: "F_milestone", Name: "Milestone"},
+	}
+	items := []parser.ImportItem{
+		{Title: "Draft", Fields: map[string]interface{}{"Status": "Todo", "Unknown": "x"}},
+		{Title: "Issue", URL: "https://github.com/owner/repo/issues/1", Fields: map[string]interface{}{"Status": "Done", "Milestone": "v1"}},
+		{Title: "Another draft"},
+	}
+
+	var summary importSummary
+	summary.add(items, fieldMap)
+	expected := "Create 2 draft issues, add 1 issue or pull request, set 2 field values in project 'Roadmap'?"
+	if got := summary.question("Roadmap"); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	drafts := importSummary{drafts: 3}
+	if got := drafts.question("Roadmap"); got != "Create 3 draft issues in project 'Roadmap'?" {
+		t.Errorf("Expected only the drafts to be mentioned, got %q", got)
+	}
+
+	single := importSummary{drafts: 1, content: 2, fieldValues: 1}
+	if got := single.question("Roadmap"); got != "Create 1 draft issue, add 2 issues and pull requests, set 1 field value in project 'Roadmap'?" {
+		t.Errorf("Expected singular nouns for counts of one, got %q", got)
+	}
+}
+
+func TestRunChunkedImportAsksForConfirmation(t *testing.T) {
+	source := writeRowsCSV(t, "One,", "Two,")
+	client := &chunkClient{}
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, ChunkSize: 1}
+
+	err := runChunkedImport(context.Background(), client, config, strings.NewReader("n\n"), itemPreparation{sizeLimits: mapping.DefaultSizeLimits})
+	if !errors.Is(err, errAborted) || exitCode(err) != exitUsage {
+		t.Fatalf("Expected the import to be aborted with exit code %d, got %v", exitUsage, err)
+	}
+	if len(client.drafts) != 0 {
+		t.Errorf("Expected nothing to be imported when declined, got %v", client.drafts)
+	}
+
+	if err := runChunkedImport(context.Background(), client, config, strings.NewReader("y\n"), itemPreparation{sizeLimits: mapping.DefaultSizeLimits}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(client.drafts, ",") != "One,Two" {
+		t.Errorf("Expected both items to be imported once confirmed, got %v", client.drafts)
+	}
+}
@@ -0,0 +1,293 @@
+// Diff subcommand comparing sources to a project
+// Shows which source items are new, which differ from their project item, and which project items aren't in the sources
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// DiffConfig holds the options for the diff subcommand
+type DiffConfig struct {
+	Sources       []string
+	Project       string
+	Profile       string
+	Mapping       string
+	StrictOptions bool
+	KeepTemp      bool
+	CSV           parser.CSVDialect
+	SourceFormat  string
+	SourceHeaders []string
+	ExitCode      bool
+}
+
+// FieldChange is a value that differs between a source item and its project item
+type FieldChange struct {
+	Field   string
+	Project string
+	Source  string
+}
+
+// ItemDiff is a source item that differs from the project item it matches
+type ItemDiff struct {
+	Item    parser.ImportItem
+	Changes []FieldChange
+}
+
+// ProjectDiff holds the differences between the sources and a project
+type ProjectDiff struct {
+	New       []parser.ImportItem
+	Changed   []ItemDiff
+	Unchanged int
+	Absent    []ghclient.ProjectItem // Project items no source item matches
+}
+
+// Empty reports whether the sources match the project
+func (d ProjectDiff) Empty() bool {
+	return len(d.New) == 0 && len(d.Changed) == 0 && len(d.Absent) == 0
+}
+
+// newDiffCmd creates the diff subcommand
+func newDiffCmd() *cobra.Command {
+	var config DiffConfig
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare sources to the items already in a project",
+		Long: `Compare the sources to a project and show which items are new, which exist
+with different field values, and which project items are absent from the
+sources. Nothing is modified.
+
+Issues and pull requests are matched by URL and draft issues by title. Only
+the fields present in a source item are compared.
+
+Examples:
+  gh project-import diff --source items.csv --project "owner/project-name"
+  gh project-import diff --source items.csv --project "owner/project-name" --exit-code`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runDiff(cmd.Context(), client, config, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&config.Sources, "source", "s", nil, "Source file, glob pattern or http(s) URL to compare; repeat to compare several (required)")
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringVar(&config.Profile, "profile", "", "Read the source as an export from another tool")
+	cmd.Flags().StringVar(&config.Mapping, "mapping", "", "JSON file mapping CSV column names to importer columns, with optional single-select option aliases")
+	cmd.Flags().BoolVar(&config.StrictOptions, "strict-options", false, "Match single-select values to options only when the case is exact")
+	cmd.Flags().BoolVar(&config.KeepTemp, "keep-temp", false, "Keep the temporary copies of downloaded or decompressed sources for debugging")
+	cmd.Flags().BoolVar(&config.ExitCode, "exit-code", false, "Exit non-zero when the sources and the project differ")
+	addCSVDialectFlags(cmd, &config.CSV)
+	addSourceFormatFlags(cmd, &config.SourceFormat, &config.SourceHeaders)
+
+	cmd.MarkFlagRequired("source")
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runDiff reads the sources and the project's items and writes their
+// differences to w
+func runDiff(ctx context.Context, client ghclient.Client, config DiffConfig, w io.Writer) error {
+	options := parser.SourceOptions{Profile: config.Profile, MappingFile: config.Mapping, KeepTemp: config.KeepTemp, CSV: config.CSV, Format: config.SourceFormat, Headers: config.SourceHeaders, Timeout: clientOptions.RequestTimeout}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	matcher := mapping.OptionMatcher{Strict: config.StrictOptions}
+	var err error
+	if config.Mapping != "" {
+		matcher.Aliases, err = parser.LoadOptionAliases(config.Mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	items, err := parser.ParseSourcesContext(ctx, config.Sources, options)
+	if err != nil {
+		return err
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+	projectItems, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	diff := computeProjectDiff(items, projectItems, fieldMap, matcher)
+
+	fmt.Fprintf(w, "--- project %s\n+++ %s\n", project.Title, strings.Join(config.Sources, ", "))
+	writeProjectDiff(w, diff)
+
+	if config.ExitCode && !diff.Empty() {
+		return fmt.Errorf("the sources differ from project %s", project.Title)
+	}
+	return nil
+}
+
+// sourceItemKey identifies a source item the way roundtripKey identifies a
+// project item: by URL, or by title for draft issues
+func sourceItemKey(item parser.ImportItem) string {
+	if item.URL != "" {
+		return "url:" + item.URL
+	}
+	if item.Content.URL != "" {
+		return "url:" + item.Content.URL
+	}
+	return "title:" + item.Title
+}
+
+// computeProjectDiff matches the source items to the project's items and
+// compares their values. Items sharing a key are paired in order.
+func computeProjectDiff(items []parser.ImportItem, projectItems []ghclient.ProjectItem, fieldMap map[string]ghclient.ProjectField, matcher mapping.OptionMatcher) ProjectDiff {
+	var diff ProjectDiff
+
+	existing := make(map[string][]ghclient.ProjectItem)
+	for _, item := range projectItems {
+		key := roundtripKey(item)
+		existing[key] = append(existing[key], item)
+	}
+
+	for _, item := range items {
+		key := sourceItemKey(item)
+		if len(existing[key]) == 0 {
+			diff.New = append(diff.New, item)
+			continue
+		}
+		projectItem := existing[key][0]
+		existing[key] = existing[key][1:]
+
+		if changes := compareItemFields(item, projectItem, fieldMap, matcher); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ItemDiff{Item: item, Changes: changes})
+		} else {
+			diff.Unchanged++
+		}
+	}
+
+	// Project items left unmatched, in project order
+	for _, item := range projectItems {
+		key := roundtripKey(item)
+		if len(existing[key]) > 0 && existing[key][0].ID == item.ID {
+			diff.Absent = append(diff.Absent, item)
+			existing[key] = existing[key][1:]
+		}
+	}
+
+	return diff
+}
+
+// compareItemFields lists the values of a source item that differ from its
+// project item. Fields the project doesn't have are left to validation.
+func compareItemFields(item parser.ImportItem, projectItem ghclient.ProjectItem, fieldMap map[string]ghclient.ProjectField, matcher mapping.OptionMatcher) []FieldChange {
+	var changes []FieldChange
+
+	if body := parser.GetItemBody(item); body != "" && parser.GetItemType(item) == "DraftIssue" {
+		if projectBody := ghclient.GetString(projectItem.Content, "body"); body != projectBody {
+			changes = append(changes, FieldChange{Field: "body", Project: projectBody, Source: body})
+		}
+	}
+
+	names := make([]string, 0, len(item.Fields))
+	for name := range item.Fields {
+		if _, exists := fieldMap[name]; exists && !mapping.IsMilestoneField(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fieldMap[name]
+		value := formatDiffValue(item.Fields[name], field, matcher)
+		projectValue := formatDiffValue(projectItem.Fields[name], field, mapping.OptionMatcher{})
+		if value != projectValue {
+			changes = append(changes, FieldChange{Field: name, Project: projectValue, Source: value})
+		}
+	}
+
+	return changes
+}
+
+// formatDiffValue renders a value as the project would store it, so that
+// e.g. "in progress" and "In Progress" or 3 and "3.0" compare equal
+func formatDiffValue(value interface{}, field ghclient.ProjectField, matcher mapping.OptionMatcher) string {
+	str := formatRoundtripValue(value)
+	switch field.Type {
+	case "NUMBER":
+		if num, err := strconv.ParseFloat(str, 64); err == nil {
+			return strconv.FormatFloat(num, 'f', -1, 64)
+		}
+	case "DATE":
+		if date, _, found := strings.Cut(str, "T"); found {
+			return date
+		}
+	case "SINGLE_SELECT":
+		if option, ok := matcher.Find(field, str); ok {
+			return option.Name
+		}
+	case "ITERATION":
+		if iteration, ok := mapping.FindIteration(field.Iterations, str); ok {
+			return iteration.Title
+		}
+	}
+	return str
+}
+
+// describeSourceItem names a source item in the diff
+func describeSourceItem(item parser.ImportItem) string {
+	if key := sourceItemKey(item); strings.HasPrefix(key, "url:") {
+		return fmt.Sprintf("%q %s", item.Title, strings.TrimPrefix(key, "url:"))
+	}
+	return fmt.Sprintf("%q (draft issue)", item.Title)
+}
+
+// describeProjectItem names a project item in the diff
+func describeProjectItem(item ghclient.ProjectItem) string {
+	title := ghclient.GetString(item.Content, "title")
+	if url := ghclient.GetString(item.Content, "url"); url != "" {
+		return fmt.Sprintf("%q %s", title, url)
+	}
+	return fmt.Sprintf("%q (draft issue)", title)
+}
+
+// writeProjectDiff writes the differences in a unified diff style: "+" for
+// new items and source values, "-" for absent items and project values
+func writeProjectDiff(w io.Writer, diff ProjectDiff) {
+	for _, item := range diff.New {
+		fmt.Fprintf(w, "+ %s\n", describeSourceItem(item))
+	}
+	for _, changed := range diff.Changed {
+		fmt.Fprintf(w, "~ %s\n", describeSourceItem(changed.Item))
+		for _, change := range changed.Changes {
+			fmt.Fprintf(w, "-     %s: %q\n", change.Field, change.Project)
+			fmt.Fprintf(w, "+     %s: %q\n", change.Field, change.Source)
+		}
+	}
+	for _, item := range diff.Absent {
+		fmt.Fprintf(w, "- %s\n", describeProjectItem(item))
+	}
+
+	fmt.Fprintf(w, "%d new, %d changed, %d unchanged, %d only in project\n", len(diff.New), len(diff.Changed), diff.Unchanged, len(diff.Absent))
+}
@@ -0,0 +1,84 @@
+// Tests for the diff subcommand
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestComputeProjectDiff(t *testing.T) {
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range templateFields {
+		fieldMap[field.Name] = field
+	}
+	projectItems := []ghclient.ProjectItem{
+		{ID: "PVTI_1", Content: map[string]interface{}{"type": "Issue", "title": "Login bug", "url": "https://github.com/o/r/issues/1"}, Fields: map[string]interface{}{"Status": "Todo", "Estimate": 3.0}},
+		{ID: "PVTI_2", Content: map[string]interface{}{"type": "DraftIssue", "title": "Write docs", "body": "Old body"}, Fields: map[string]interface{}{"Status": "Todo", "Due": "2024-05-01"}},
+		{ID: "PVTI_3", Content: map[string]interface{}{"type": "DraftIssue", "title": "Stale idea"}, Fields: map[string]interface{}{}},
+	}
+	items := []parser.ImportItem{
+		// Unchanged once the values are normalized
+		{Title: "Login bug", URL: "https://github.com/o/r/issues/1", Fields: map[string]interface{}{"Status": "todo", "Estimate": int64(3)}},
+		{Title: "Write docs", Notes: "New body", Fields: map[string]interface{}{"Status": "In Progress", "Due": "2024-05-01T00:00:00Z", "Sprint": "2024-05-02"}},
+		{Title: "Brand new", Fields: map[string]interface{}{}},
+	}
+
+	diff := computeProjectDiff(items, projectItems, fieldMap, mapping.OptionMatcher{})
+
+	if len(diff.New) != 1 || diff.New[0].Title != "Brand new" {
+		t.Errorf("Expected one new item, got %+v", diff.New)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("Expected one unchanged item, got %d", diff.Unchanged)
+	}
+	if len(diff.Absent) != 1 || diff.Absent[0].ID != "PVTI_3" {
+		t.Errorf("Expected the stale item to be absent from the sources, got %+v", diff.Absent)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("Expected one changed item, got %+v", diff.Changed)
+	}
+	expected := []FieldChange{
+		{Field: "body", Project: "Old body", Source: "New body"},
+		{Field: "Sprint", Project: "", Source: "Sprint 2"},
+		{Field: "Status", Project: "Todo", Source: "In Progress"},
+	}
+	changes := diff.Changed[0].Changes
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected changes %+v, got %+v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected change %+v, got %+v", expected[i], changes[i])
+		}
+	}
+}
+
+func TestRunDiffExitCode(t *testing.T) {
+	client := &projectItemsClient{
+		schemaClient: &schemaClient{project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"}, fields: templateFields},
+		items:        []ghclient.ProjectItem{{ID: "PVTI_1", Content: map[string]interface{}{"type": "DraftIssue", "title": "Task"}, Fields: map[string]interface{}{"Status": "Todo"}}},
+	}
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title,Status\nTask,In Progress\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runDiff(context.Background(), client, DiffConfig{Sources: []string{source}, Project: "owner/project"}, &out); err != nil {
+		t.Fatalf("Expected no error without --exit-code, got: %v", err)
+	}
+	if !contains(out.String(), "~ \"Task\" (draft issue)\n-     Status: \"Todo\"\n+     Status: \"In Progress\"\n") {
+		t.Errorf("Unexpected diff output:\n%s", out.String())
+	}
+
+	if err := runDiff(context.Background(), client, DiffConfig{Sources: []string{source}, Project: "owner/project", ExitCode: true}, &out); err == nil {
+		t.Error("Expected an error with --exit-code when the sources differ")
+	}
+}
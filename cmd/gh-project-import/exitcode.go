@@ -0,0 +1,106 @@
+// Exit codes
+// Distinguishes why a run failed, so scripts can tell a partial import from one that failed entirely
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// Exit codes of the command
+const (
+	exitOK      = 0 // Success
+	exitUsage   = 1 // Invalid flags or arguments, or any other error
+	exitInvalid = 2 // The source couldn't be parsed or failed validation
+	exitAuth    = 3 // No GitHub client could be created, authentication failed or the token lacks permission
+	exitPartial = 4 // Some items were imported, but the run stopped early, some projects failed or too many items failed
+	exitFailed  = 5 // No items were imported
+)
+
+// exitError is an error that ends the command with a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode makes err end the command with code. A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for the error a command returned
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if errors.Is(err, ghclient.ErrTokenPermission) {
+		return exitAuth
+	}
+	return exitUsage
+}
+
+// failureLimit is how many failed items a run tolerates before it exits with
+// exitPartial. The zero value, the default, tolerates none.
+type failureLimit struct {
+	count   int
+	percent float64
+	flag    string
+}
+
+// parseFailureLimit parses --max-failures, a count such as 10 or a
+// percentage of the items such as 2.5%. An empty value tolerates no failed
+// items.
+func parseFailureLimit(value string) (failureLimit, error) {
+	if value == "" {
+		return failureLimit{}, nil
+	}
+	limit := failureLimit{flag: "--max-failures " + value}
+	if number, found := strings.CutSuffix(value, "%"); found {
+		percent, err := strconv.ParseFloat(number, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return failureLimit{}, fmt.Errorf("invalid --max-failures %q (expected a count or a percentage between 0%% and 100%%)", value)
+		}
+		limit.percent = percent
+		return limit, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return failureLimit{}, fmt.Errorf("invalid --max-failures %q (expected a count or a percentage such as 5%%)", value)
+	}
+	limit.count = count
+	return limit, nil
+}
+
+// Exceeded reports whether failed of total items is more than the limit allows
+func (l failureLimit) Exceeded(failed, total int) bool {
+	if failed == 0 {
+		return false
+	}
+	if l.percent > 0 {
+		return float64(failed)*100 > l.percent*float64(total)
+	}
+	return failed > l.count
+}
+
+func (l failureLimit) String() string {
+	return l.flag
+}
@@ -0,0 +1,145 @@
+// Tests for exit codes
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestExitCode(t *testing.T) {
+	partial := withExitCode(exitPartial, errors.New("import stopped early"))
+	tests := map[error]int{
+		nil:                                     exitOK,
+		errors.New("unknown flag"):              exitUsage,
+		partial:                                 exitPartial,
+		fmt.Errorf("acme/roadmap: %w", partial): exitPartial,
+		errors.Join(errors.New("other"), withExitCode(exitFailed, errors.New("none imported"))): exitFailed,
+		fmt.Errorf("failed to find project: %w", ghclient.ErrTokenPermission):                   exitAuth,
+	}
+	for err, expected := range tests {
+		if code := exitCode(err); code != expected {
+			t.Errorf("Expected exit code %d for %v, got %d", expected, err, code)
+		}
+	}
+	if withExitCode(exitFailed, nil) != nil {
+		t.Error("Expected a nil error to stay nil")
+	}
+}
+
+func TestRunImportExitCodes(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title\nPlan launch\nWrite docs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(fake *ghclient.FakeClient, sources ...string) int {
+		useClient(t, fake)
+		config := Config{Sources: sources, Project: "acme/Roadmap", Yes: true, Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		var err error
+		captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		return exitCode(err)
+	}
+	newFake := func() *ghclient.FakeClient {
+		fake := ghclient.NewFakeClient()
+		fake.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+		return fake
+	}
+
+	if code := run(newFake(), source); code != exitOK {
+		t.Errorf("Expected exit code %d for a successful import, got %d", exitOK, code)
+	}
+	if code := run(newFake(), filepath.Join(t.TempDir(), "missing.csv")); code != exitInvalid {
+		t.Errorf("Expected exit code %d for a missing source, got %d", exitInvalid, code)
+	}
+
+	fake := newFake()
+	fake.Errors["GetUser"] = errors.New("bad credentials")
+	if code := run(fake, source); code != exitAuth {
+		t.Errorf("Expected exit code %d for an authentication failure, got %d", exitAuth, code)
+	}
+
+	fake = newFake()
+	fake.Errors["FindProject"] = fmt.Errorf("%w: Resource not accessible by personal access token", ghclient.ErrTokenPermission)
+	if code := run(fake, source); code != exitAuth {
+		t.Errorf("Expected exit code %d for a token without access to the project, got %d", exitAuth, code)
+	}
+
+	fake = newFake()
+	fake.Errors["CreateDraftIssue"] = errors.New("rate limit exceeded")
+	if code := run(fake, source); code != exitFailed {
+		t.Errorf("Expected exit code %d when every item fails, got %d", exitFailed, code)
+	}
+}
+
+func TestFailureLimit(t *testing.T) {
+	tests := []struct {
+		value    string
+		failed   int
+		total    int
+		exceeded bool
+	}{
+		{"", 1, 500, true},
+		{"", 0, 500, false},
+		{"100%", 99, 100, false},
+		{"0", 1, 500, true},
+		{"0", 0, 500, false},
+		{"3", 3, 500, false},
+		{"3", 4, 500, true},
+		{"2%", 10, 500, false},
+		{"2%", 11, 500, true},
+		{"0%", 1, 500, true},
+	}
+	for _, tt := range tests {
+		limit, err := parseFailureLimit(tt.value)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tt.value, err)
+		}
+		if exceeded := limit.Exceeded(tt.failed, tt.total); exceeded != tt.exceeded {
+			t.Errorf("Expected %d of %d failed items to exceed %q: %v, got %v", tt.failed, tt.total, tt.value, tt.exceeded, exceeded)
+		}
+	}
+
+	for _, value := range []string{"-1", "ten", "150%", "x%"} {
+		if _, err := parseFailureLimit(value); err == nil {
+			t.Errorf("Expected an error for --max-failures %q", value)
+		}
+	}
+}
+
+func TestFailureLimitImport(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+	fake.AddIssue("https://github.com/acme/app/issues/1", "Fix login", "")
+	items := []parser.ImportItem{
+		{Title: "Plan launch"},
+		{Title: "Fix login", URL: "https://github.com/acme/app/issues/1"},
+		{Title: "Gone", URL: "https://github.com/acme/app/issues/2"},
+	}
+
+	for _, tt := range []struct {
+		config Config
+		code   int
+	}{
+		{Config{}, exitPartial},
+		{Config{MaxFailures: "1"}, exitOK},
+		{Config{MaxFailures: "30%"}, exitPartial},
+	} {
+		var err error
+		captureStdout(t, func() {
+			err = importItems(context.Background(), fake, &project.Project, items, map[string]ghclient.ProjectField{}, tt.config)
+		})
+		if code := exitCode(err); code != tt.code {
+			t.Errorf("Expected exit code %d with %+v, got %d (%v)", tt.code, tt.config, code, err)
+		}
+	}
+}
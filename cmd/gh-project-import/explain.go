@@ -0,0 +1,172 @@
+// Explain subcommand for debugging how a single source item would be imported
+// Walks through parsing, field mapping and value conversion without writing anything
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// ExplainConfig holds the options for the explain subcommand
+type ExplainConfig struct {
+	Source        string
+	Project       string
+	Item          int
+	Profile       string
+	Mapping       string
+	KeepTemp      bool
+	CSV           parser.CSVDialect
+	StrictOptions bool
+}
+
+// newExplainCmd creates the explain subcommand
+func newExplainCmd() *cobra.Command {
+	var config ExplainConfig
+
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Show how a single item would be parsed, mapped and converted",
+		Long: `Print every step of the import pipeline for one item in the source file,
+including the parsed values, the project field each value maps to, and the
+converted value that would be sent to GitHub. No changes are made.
+
+Examples:
+  gh project-import explain --source items.json --item 17 --project "owner/project-name"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runExplain(cmd.Context(), client, config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Source, "source", "s", "", "Source file containing the item (required)")
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Destination project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().IntVarP(&config.Item, "item", "i", 0, "1-based position of the item in the source file (required)")
+	cmd.Flags().StringVar(&config.Profile, "profile", "", "Read the source as an export from another tool")
+	cmd.Flags().StringVar(&config.Mapping, "mapping", "", "JSON file mapping CSV column names to importer columns, with optional single-select option aliases")
+	cmd.Flags().BoolVar(&config.StrictOptions, "strict-options", false, "Match single-select values to options only when the case is exact")
+	cmd.Flags().BoolVar(&config.KeepTemp, "keep-temp", false, "Keep the temporary copies of downloaded or decompressed sources for debugging")
+	addCSVDialectFlags(cmd, &config.CSV)
+
+	cmd.MarkFlagRequired("source")
+	cmd.MarkFlagRequired("project")
+	cmd.MarkFlagRequired("item")
+
+	return cmd
+}
+
+// runExplain prints the import pipeline for the selected item
+func runExplain(ctx context.Context, client ghclient.Client, config ExplainConfig) error {
+	items, err := parser.ParseSourceFileContext(ctx, config.Source, parser.SourceOptions{Profile: config.Profile, MappingFile: config.Mapping, KeepTemp: config.KeepTemp, CSV: config.CSV, Timeout: clientOptions.RequestTimeout})
+	if err != nil {
+		return err
+	}
+
+	matcher := mapping.OptionMatcher{Strict: config.StrictOptions}
+	if config.Mapping != "" {
+		matcher.Aliases, err = parser.LoadOptionAliases(config.Mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.Item < 1 || config.Item > len(items) {
+		return fmt.Errorf("item %d out of range: %s contains %d items", config.Item, config.Source, len(items))
+	}
+	item := items[config.Item-1]
+
+	// Step 1: parsing
+	fmt.Printf("Item %d of %d from %s\n", config.Item, len(items), config.Source)
+	fmt.Println("\n1. Parsed values")
+	fmt.Printf("  title:      %q\n", item.Title)
+	fmt.Printf("  url:        %q\n", item.URL)
+	fmt.Printf("  type:       %s\n", parser.GetItemType(item))
+	fmt.Printf("  body:       %q\n", parser.GetItemBody(item))
+	if len(item.Assignees) > 0 {
+		fmt.Printf("  assignees:  %v\n", item.Assignees)
+	}
+	if len(item.Labels) > 0 {
+		fmt.Printf("  labels:     %v\n", item.Labels)
+	}
+
+	if err := parser.ValidateImportItem(item); err != nil {
+		fmt.Println("  " + report.Failure("item is invalid and would not be imported: %v", err))
+	}
+
+	fieldNames := make([]string, 0, len(item.Fields))
+	for name := range item.Fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	if len(fieldNames) == 0 {
+		fmt.Println("  (no custom fields)")
+	}
+	for _, name := range fieldNames {
+		value := item.Fields[name]
+		fmt.Printf("  field %q = %v (%T)\n", name, value, value)
+	}
+
+	// Step 2: project schema
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+
+	fmt.Printf("\n2. Field mapping to project %q (%d fields)\n", project.Title, len(fields))
+	for _, name := range fieldNames {
+		if mapping.IsMilestoneField(name) {
+			fmt.Printf("  %q → milestone of the issue or pull request, not a project field\n", name)
+			continue
+		}
+		field, exists := fieldMap[name]
+		if !exists {
+			fmt.Printf("  %q → not found in project, would be skipped\n", name)
+			continue
+		}
+		fmt.Printf("  %q → %s (%s, ID: %s)\n", name, field.Name, field.Type, field.ID)
+	}
+
+	// Step 3: value conversion
+	fmt.Println("\n3. Value conversion")
+	for _, name := range fieldNames {
+		field, exists := fieldMap[name]
+		if !exists || mapping.IsMilestoneField(name) {
+			continue
+		}
+
+		converted, err := mapping.ConvertFieldValueWith(item.Fields[name], field, matcher)
+		if err != nil {
+			fmt.Printf("  %q ✗ %v, would be skipped\n", name, err)
+			continue
+		}
+
+		encoded, err := json.Marshal(converted)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%v", converted))
+		}
+		fmt.Printf("  %q ✓ %s\n", name, encoded)
+	}
+
+	return nil
+}
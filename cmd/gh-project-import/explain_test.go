@@ -0,0 +1,57 @@
+// Tests for the explain subcommand
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// schemaClient is a ghclient.Client stub that serves a fixed project and field schema
+type schemaClient struct {
+	ghclient.Client
+	project *ghclient.Project
+	fields  []ghclient.ProjectField
+}
+
+func (c *schemaClient) FindProject(ctx context.Context, identifier string) (*ghclient.Project, error) {
+	return c.project, nil
+}
+
+func (c *schemaClient) GetProjectFields(ctx context.Context, projectID string) ([]ghclient.ProjectField, error) {
+	return c.fields, nil
+}
+
+func TestRunExplain(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "explain.json")
+	jsonContent := `[
+		{"title": "First"},
+		{"title": "Second", "Status": "Todo", "Estimate": "lots", "Missing": "x"}
+	]`
+	if err := os.WriteFile(jsonFile, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	client := &schemaClient{
+		project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"},
+		fields: []ghclient.ProjectField{
+			{ID: "field1", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "opt1", Name: "Todo"}}},
+			{ID: "field2", Name: "Estimate", Type: "NUMBER"},
+		},
+	}
+
+	if err := runExplain(context.Background(), client, ExplainConfig{Source: jsonFile, Project: "owner/project", Item: 2}); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	for _, index := range []int{0, 3} {
+		err := runExplain(context.Background(), client, ExplainConfig{Source: jsonFile, Project: "owner/project", Item: index})
+		if err == nil || !contains(err.Error(), "out of range") {
+			t.Errorf("Expected out of range error for item %d, got: %v", index, err)
+		}
+	}
+}
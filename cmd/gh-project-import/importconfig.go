@@ -0,0 +1,108 @@
+// Config files for repeatable imports
+// A YAML file supplies default values for command-line flags
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultImportConfigFile is read from the working directory when --config isn't given
+const defaultImportConfigFile = ".project-import.yaml"
+
+// ImportConfigFile holds the settings read from a config file. Keys are flag
+// names without the leading dashes; "mapping" may also be an inline map of
+// CSV column names to importer columns instead of a file name.
+type ImportConfigFile struct {
+	Flags   map[string]interface{}
+	Columns map[string]string
+}
+
+// LoadImportConfigFile reads a YAML config file
+func LoadImportConfigFile(path string) (*ImportConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	file := &ImportConfigFile{Flags: make(map[string]interface{})}
+	for key, value := range raw {
+		if key == "mapping" {
+			if columns, ok := value.(map[string]interface{}); ok {
+				file.Columns = make(map[string]string, len(columns))
+				for column, target := range columns {
+					file.Columns[strings.ToLower(strings.TrimSpace(column))] = fmt.Sprint(target)
+				}
+				continue
+			}
+		}
+		file.Flags[key] = value
+	}
+
+	return file, nil
+}
+
+// applyImportConfigFile sets every flag named in the config file that wasn't
+// given on the command line. Keys must be flags of the root command; flags a
+// subcommand doesn't have are ignored for it.
+func applyImportConfigFile(root, cmd *cobra.Command, file *ImportConfigFile, path string) error {
+	keys := make([]string, 0, len(file.Flags))
+	for key := range file.Flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "config" || (root.Flags().Lookup(key) == nil && root.PersistentFlags().Lookup(key) == nil) {
+			return fmt.Errorf("unknown setting %q in config file %s", key, path)
+		}
+
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		values := []interface{}{file.Flags[key]}
+		if list, ok := file.Flags[key].([]interface{}); ok {
+			values = list
+		}
+		for _, value := range values {
+			if err := cmd.Flags().Set(key, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("invalid value for %q in config file %s: %w", key, path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadConfigForCommand applies the config file named by --config, or the
+// default file in the working directory when it exists, and returns the
+// inline column mapping it holds
+func loadConfigForCommand(root, cmd *cobra.Command, path string) (map[string]string, error) {
+	if path == "" {
+		if _, err := os.Stat(defaultImportConfigFile); err != nil {
+			return nil, nil
+		}
+		path = defaultImportConfigFile
+	}
+
+	file, err := LoadImportConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyImportConfigFile(root, cmd, file, path); err != nil {
+		return nil, err
+	}
+	return file.Columns, nil
+}
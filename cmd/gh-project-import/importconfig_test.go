@@ -0,0 +1,90 @@
+// Tests for YAML config files
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyImportConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.yaml")
+	content := `project: my-org/Roadmap
+source: weekly.csv
+profile: jira
+dry-run: true
+max-warnings: 5
+only-fields:
+  - Status
+  - Iteration
+mapping:
+  Story Points: Estimate
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cmd.Flags().StringVar(&config.Project, "project", "", "")
+	cmd.Flags().StringArrayVar(&config.Sources, "source", nil, "")
+	cmd.Flags().StringVar(&config.Profile, "profile", "", "")
+	cmd.Flags().BoolVar(&config.DryRun, "dry-run", false, "")
+	cmd.Flags().IntVar(&config.MaxWarnings, "max-warnings", -1, "")
+	cmd.Flags().StringSliceVar(&config.OnlyFields, "only-fields", nil, "")
+
+	// Flags given on the command line win over the config file
+	if err := cmd.ParseFlags([]string{"--source", "override.csv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := loadConfigForCommand(cmd, cmd, path)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if config.Project != "my-org/Roadmap" || config.Profile != "jira" || !config.DryRun || config.MaxWarnings != 5 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if len(config.Sources) != 1 || config.Sources[0] != "override.csv" {
+		t.Errorf("Expected command line source to win, got %q", config.Sources)
+	}
+	if len(config.OnlyFields) != 2 || config.OnlyFields[1] != "Iteration" {
+		t.Errorf("Unexpected only-fields: %v", config.OnlyFields)
+	}
+	if columns["story points"] != "Estimate" {
+		t.Errorf("Expected inline mapping, got %v", columns)
+	}
+}
+
+func TestApplyImportConfigFileUnknownSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.yaml")
+	if err := os.WriteFile(path, []byte("projcet: typo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("project", "", "")
+
+	if _, err := loadConfigForCommand(cmd, cmd, path); err == nil || !contains(err.Error(), `unknown setting "projcet"`) {
+		t.Errorf("Expected unknown setting error, got: %v", err)
+	}
+}
+
+func TestInlineColumnMapping(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(csvFile, []byte("Name,Story Points\nFirst,3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := parser.ParseSourceFile(csvFile, parser.SourceOptions{Columns: map[string]string{"name": "Title", "story points": "Estimate"}})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if items[0].Title != "First" || items[0].Fields["Estimate"] != int64(3) {
+		t.Errorf("Unexpected item: %+v", items[0])
+	}
+}
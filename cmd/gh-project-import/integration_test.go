@@ -3,12 +3,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
 )
 
+// title of the recording user's project in the EndToEndImport snapshots
+const testProjectTitle = "Import Test Project"
+
 func TestEndToEndImportWorkflow(t *testing.T) {
 	// Create temporary files
 	tmpDir := t.TempDir()
@@ -59,18 +68,21 @@ Feature Request,https://github.com/cli/cli/pull/3,Done,Medium,5,New feature impl
 	tests := []struct {
 		name        string
 		sourceFile  string
+		snapshot    string
 		expectItems int
 		expectError bool
 	}{
 		{
 			name:        "JSON import workflow",
 			sourceFile:  jsonFile,
+			snapshot:    "EndToEndImportJSON",
 			expectItems: 3,
 			expectError: false,
 		},
 		{
 			name:        "CSV import workflow",
 			sourceFile:  csvFile,
+			snapshot:    "EndToEndImportCSV",
 			expectItems: 3,
 			expectError: false,
 		},
@@ -79,13 +91,13 @@ Feature Request,https://github.com/cli/cli/pull/3,Done,Medium,5,New feature impl
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Parse the file
-			var items []ImportItem
+			var items []parser.ImportItem
 			var err error
 
 			if filepath.Ext(tt.sourceFile) == ".json" {
-				items, err = ParseJSONFile(tt.sourceFile)
+				items, err = parser.ParseJSONFile(tt.sourceFile)
 			} else {
-				items, err = ParseCSVFile(tt.sourceFile)
+				items, err = parser.ParseCSVFile(tt.sourceFile)
 			}
 
 			if err != nil {
@@ -97,7 +109,7 @@ Feature Request,https://github.com/cli/cli/pull/3,Done,Medium,5,New feature impl
 			}
 
 			// Validate items
-			err = ValidateImportItems(items)
+			err = parser.ValidateImportItems(items)
 			if tt.expectError && err == nil {
 				t.Errorf("Expected validation error but got none")
 			}
@@ -106,44 +118,49 @@ Feature Request,https://github.com/cli/cli/pull/3,Done,Medium,5,New feature impl
 			}
 
 			// Test with snapshot client
-			client, err := NewSnapshotGitHubClient("EndToEndWorkflow")
+			client, err := ghclient.NewSnapshotClient(tt.snapshot)
 			if err != nil {
 				t.Fatalf("Failed to create snapshot client: %v", err)
 			}
-			defer client.Close()
+			client.RequireAllUsed = true
+			defer func() {
+				if err := client.Close(); err != nil {
+					t.Error(err)
+				}
+			}()
 
 			// 1. Find project by name
-			project, err := client.FindProject(fmt.Sprintf("%s/%s", testUsername, testProjectTitle))
+			project, err := client.FindProject(context.Background(), fmt.Sprintf("%s/%s", client.Login(), testProjectTitle))
 			if err != nil {
 				t.Fatalf("Failed to find project: %v", err)
 			}
 			t.Logf("Found project by name: %s", project.Title)
 
-			fields, err := client.GetProjectFields(project.ID)
+			fields, err := client.GetProjectFields(context.Background(), project.ID)
 			if err != nil {
 				t.Fatalf("Failed to get project fields: %v", err)
 			}
 
-			fieldMap := make(map[string]ProjectField)
+			fieldMap := make(map[string]ghclient.ProjectField)
 			for _, field := range fields {
 				fieldMap[field.Name] = field
 			}
 
 			// Test field validation
-			warnings := validateItemFields(items, fieldMap, Config{Verbose: true})
+			warnings := mapping.ValidateItemFields(items, fieldMap, mapping.OptionMatcher{}, true)
 			if len(warnings) > 0 {
 				t.Logf("Field validation warnings: %v", warnings)
 			}
 
 			// Simulate the import process (without actually calling GitHub API)
 			for i, item := range items {
-				itemType := GetItemType(item)
+				itemType := parser.GetItemType(item)
 				t.Logf("Item %d: %s (%s)", i+1, item.Title, itemType)
 
 				// Test field conversion
 				for fieldName, fieldValue := range item.Fields {
 					if field, exists := fieldMap[fieldName]; exists {
-						convertedValue, err := convertFieldValue(fieldValue, field)
+						convertedValue, err := mapping.ConvertFieldValue(fieldValue, field)
 						if err != nil {
 							t.Logf("Field conversion warning for %s: %v", fieldName, err)
 						} else {
@@ -153,10 +170,49 @@ Feature Request,https://github.com/cli/cli/pull/3,Done,Medium,5,New feature impl
 				}
 			}
 
-			err = importItems(client, project, items, fieldMap, Config{})
+			err = importItems(context.Background(), client, project, items, fieldMap, Config{})
 			if err != nil {
 				t.Fatalf("Failed integrated importItems: %v", err)
 			}
 		})
 	}
 }
+
+// TestImportThroughFakeServer runs an import through the real client against
+// the fake GitHub API server
+func TestImportThroughFakeServer(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_todo", Name: "Todo"}, {ID: "O_done", Name: "Done"}}},
+		ghclient.ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"},
+	)
+	fake.AddIssue("https://github.com/acme/app/issues/2", "Fix login", "")
+
+	client, err := ghclient.NewFakeServer(fake).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []parser.ImportItem{
+		{Title: "Plan launch", Fields: map[string]interface{}{"Status": "Todo", "Estimate": 3}},
+		{Title: "Fix login", URL: "https://github.com/acme/app/issues/2", Fields: map[string]interface{}{"Status": "Done"}},
+	}
+	config := Config{Project: "acme/Roadmap", Yes: true, Quiet: true}
+
+	captureStdout(t, func() {
+		err = importToDestinations(context.Background(), client, items, config, strings.NewReader(""))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(project.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(project.Items))
+	}
+	if project.Items[0].Fields["Status"] != "Todo" || project.Items[0].Fields["Estimate"] != float64(3) {
+		t.Errorf("Unexpected draft issue fields %v", project.Items[0].Fields)
+	}
+	if project.Items[1].Content["url"] != "https://github.com/acme/app/issues/2" || project.Items[1].Fields["Status"] != "Done" {
+		t.Errorf("Unexpected issue item %+v", project.Items[1])
+	}
+}
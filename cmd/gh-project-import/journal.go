@@ -0,0 +1,261 @@
+// Run journals and the undo subcommand
+// Records the items each import run creates so the whole run can be deleted later
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// Journal records the project items created by an import run
+type Journal struct {
+	ID           string        `json:"id"`
+	Sources      []string      `json:"sources,omitempty"`
+	FromClassic  string        `json:"from_classic,omitempty"`
+	FromQuery    string        `json:"from_query,omitempty"`
+	Project      string        `json:"project"`
+	ProjectID    string        `json:"project_id"`
+	ProjectTitle string        `json:"project_title"`
+	Items        []JournalItem `json:"items"`
+	Started      time.Time     `json:"started"`
+	Updated      time.Time     `json:"updated"`
+	// Undone is set once undo has deleted every item
+	Undone *time.Time `json:"undone,omitempty"`
+
+	existing map[string]bool // Items already in the project before the run
+	recorded map[string]bool
+}
+
+// JournalItem is a project item created by the run
+type JournalItem struct {
+	Item  int    `json:"item"` // 1-based position of the item in the source
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// journalDir is the directory holding run journals:
+// $XDG_DATA_HOME/gh-project-import/runs, by default under ~/.local/share
+func journalDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find the run journal directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "gh-project-import", "runs"), nil
+}
+
+// journalPath returns the file of the journal of a run
+func journalPath(runID string) (string, error) {
+	// Run IDs name a file in the journal directory and nothing else
+	if runID == "" || filepath.Base(runID) != runID || runID == "." || runID == ".." {
+		return "", fmt.Errorf("invalid run ID %q", runID)
+	}
+	dir, err := journalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, runID+".json"), nil
+}
+
+// newRunID returns a unique, sortable ID for a run, e.g. 20261016-153012-4f2a
+func newRunID() string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// newJournal creates the journal of a run into a project holding the existing
+// items. Nothing is written until the run creates an item.
+func newJournal(config Config, project *ghclient.Project, existing map[string]bool) *Journal {
+	now := time.Now()
+	return &Journal{
+		ID:           newRunID(),
+		Sources:      config.Sources,
+		FromClassic:  config.FromClassic,
+		FromQuery:    config.FromQuery,
+		Project:      config.Project,
+		ProjectID:    project.ID,
+		ProjectTitle: project.Title,
+		Items:        []JournalItem{},
+		Started:      now,
+		Updated:      now,
+		existing:     existing,
+		recorded:     make(map[string]bool),
+	}
+}
+
+// LoadJournal reads the journal of a run
+func LoadJournal(runID string) (*Journal, error) {
+	path, err := journalPath(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no journal found for run %s in %s", runID, filepath.Dir(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run journal %s: %w", path, err)
+	}
+
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse run journal %s: %w", path, err)
+	}
+	return &journal, nil
+}
+
+// Record notes an item created by the run, reporting whether it was new to
+// the project
+func (j *Journal) Record(index int, itemID, title string) bool {
+	// A source may name the same issue twice, which adds it only once
+	if itemID == "" || j.existing[itemID] || j.recorded[itemID] {
+		return false
+	}
+	j.recorded[itemID] = true
+	j.Items = append(j.Items, JournalItem{Item: index, ID: itemID, Title: title})
+	return true
+}
+
+// Remove forgets an item that has been deleted
+func (j *Journal) Remove(itemID string) {
+	for i, item := range j.Items {
+		if item.ID == itemID {
+			j.Items = append(j.Items[:i], j.Items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Save writes the journal to the journal directory
+func (j *Journal) Save() error {
+	j.Updated = time.Now()
+
+	path, err := journalPath(j.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create run journal directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run journal: %w", err)
+	}
+	if err := writeFileAtomically(path, data); err != nil {
+		return fmt.Errorf("failed to write run journal: %w", err)
+	}
+	return nil
+}
+
+// UndoConfig holds the options for the undo subcommand
+type UndoConfig struct {
+	RunID string
+	Yes   bool
+
+	// noTerminal is set when stdin isn't a terminal
+	noTerminal bool
+}
+
+// newUndoCmd creates the undo subcommand
+func newUndoCmd() *cobra.Command {
+	var config UndoConfig
+
+	cmd := &cobra.Command{
+		Use:   "undo <run-id>",
+		Short: "Delete the items an earlier import created",
+		Long: `Delete every project item created by an earlier import run. Each import
+records the items it creates in a journal under
+~/.local/share/gh-project-import/runs (or $XDG_DATA_HOME), and prints the run
+ID to pass here when it finishes. Items that were already in the project
+before the run are never deleted.
+
+Deleted draft issues are gone for good; deleting an issue or pull request item
+only removes it from the project. Items that can't be deleted stay in the
+journal, so running undo again retries them.
+
+You are asked to confirm before anything is changed, unless --yes is given.
+
+Examples:
+  gh project-import undo 20261016-153012-4f2a
+  gh project-import undo 20261016-153012-4f2a --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.RunID = args[0]
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			config.noTerminal = !term.IsTerminal(os.Stdin)
+			return runUndo(cmd.Context(), client, config, os.Stdin)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&config.Yes, "yes", "y", false, "Don't ask for confirmation")
+
+	return cmd
+}
+
+// runUndo deletes the items recorded in a run's journal, most recent first,
+// asking on in for confirmation first unless config.Yes is set
+func runUndo(ctx context.Context, client ghclient.Client, config UndoConfig, in io.Reader) error {
+	journal, err := LoadJournal(config.RunID)
+	if err != nil {
+		return err
+	}
+
+	if len(journal.Items) == 0 {
+		fmt.Printf("Run %s has no items left to undo\n", journal.ID)
+		return nil
+	}
+
+	if err := confirmChanges(in, os.Stdout, config.Yes, config.noTerminal, fmt.Sprintf("Delete %s created by run %s from project '%s'?", countNoun(len(journal.Items), "item", "items"), journal.ID, journal.ProjectTitle)); err != nil {
+		return err
+	}
+
+	items := slices.Clone(journal.Items)
+	deleted, failed := 0, 0
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if err := client.DeleteProjectItem(ctx, journal.ProjectID, item.ID); err != nil {
+			slog.Error("Failed to delete item", "item", item.Item, "id", item.ID, "title", item.Title, "error", err)
+			failed++
+			continue
+		}
+		journal.Remove(item.ID)
+		deleted++
+	}
+
+	if len(journal.Items) == 0 {
+		now := time.Now()
+		journal.Undone = &now
+	}
+	if err := journal.Save(); err != nil {
+		slog.Warn(err.Error())
+	}
+
+	fmt.Println(report.Success("Deleted %d items", deleted))
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d items; run undo again to retry them", failed)
+	}
+	return nil
+}
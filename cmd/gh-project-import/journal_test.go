@@ -0,0 +1,120 @@
+// Tests for run journals and the undo subcommand
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestImportItemsWritesJournal(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "First"}, {Title: "fail"}, {Title: "Second"}}
+
+	client := &rollbackClient{}
+	output := captureStdout(t, func() {
+		importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, Config{Project: "owner/project", Journal: true})
+	})
+
+	runID := output[strings.Index(output, "undo ")+len("undo ") : len(output)-1]
+	journal, err := LoadJournal(runID)
+	if err != nil {
+		t.Fatalf("Expected the run journal to be written, got: %v\n%s", err, output)
+	}
+	expected := []JournalItem{{Item: 1, ID: "PVTI_1", Title: "First"}, {Item: 3, ID: "PVTI_2", Title: "Second"}}
+	if !reflect.DeepEqual(journal.Items, expected) || journal.ProjectID != "PVT_test" {
+		t.Errorf("Expected the created items to be recorded, got %+v", journal)
+	}
+}
+
+func TestJournalSkipsExistingItems(t *testing.T) {
+	journal := newJournal(Config{}, &ghclient.Project{ID: "PVT_test"}, map[string]bool{"PVTI_existing": true})
+
+	recorded := []bool{
+		journal.Record(1, "PVTI_existing", "Existing"),
+		journal.Record(2, "PVTI_new", "New"),
+		journal.Record(3, "PVTI_new", "Same issue again"),
+		journal.Record(4, "", "Failed"),
+	}
+	if !reflect.DeepEqual(recorded, []bool{false, true, false, false}) || len(journal.Items) != 1 {
+		t.Errorf("Expected only the new item to be recorded, got %v %+v", recorded, journal.Items)
+	}
+}
+
+// undoClient is a ghclient.Client stub that records deletions and fails to
+// delete the items in fail
+type undoClient struct {
+	ghclient.Client
+	fail    map[string]bool
+	deleted []string
+}
+
+func (c *undoClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	if c.fail[itemID] {
+		return errors.New("boom")
+	}
+	c.deleted = append(c.deleted, itemID)
+	return nil
+}
+
+func TestRunUndo(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	journal := newJournal(Config{}, &ghclient.Project{ID: "PVT_test", Title: "Test Project"}, nil)
+	for i, id := range []string{"PVTI_1", "PVTI_2", "PVTI_3"} {
+		journal.Record(i+1, id, id)
+	}
+	if err := journal.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &undoClient{fail: map[string]bool{"PVTI_2": true}}
+	captureStdout(t, func() {
+		if err := runUndo(context.Background(), client, UndoConfig{RunID: journal.ID}, strings.NewReader("n\n")); !errors.Is(err, errAborted) {
+			t.Errorf("Expected the undo to be aborted, got %v", err)
+		}
+		if len(client.deleted) != 0 {
+			t.Errorf("Expected nothing to be deleted without confirmation, got %v", client.deleted)
+		}
+
+		err := runUndo(context.Background(), client, UndoConfig{RunID: journal.ID, Yes: true}, strings.NewReader(""))
+		if err == nil || !strings.Contains(err.Error(), "failed to delete 1 items") {
+			t.Errorf("Expected the failed item to be reported, got: %v", err)
+		}
+	})
+	if strings.Join(client.deleted, ",") != "PVTI_3,PVTI_1" {
+		t.Errorf("Expected the items to be deleted most recent first, got %v", client.deleted)
+	}
+
+	// The item that failed is kept so undo can retry it
+	client.fail = nil
+	captureStdout(t, func() {
+		if err := runUndo(context.Background(), client, UndoConfig{RunID: journal.ID, Yes: true}, nil); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	undone, err := LoadJournal(journal.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(client.deleted, ",") != "PVTI_3,PVTI_1,PVTI_2" || len(undone.Items) != 0 || undone.Undone == nil {
+		t.Errorf("Expected the retry to finish the undo, got %v %+v", client.deleted, undone)
+	}
+}
+
+func TestLoadJournalRejectsPaths(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	for _, runID := range []string{"", "..", "../secrets", "runs/20261016"} {
+		if _, err := LoadJournal(runID); err == nil || !strings.Contains(err.Error(), "invalid run ID") {
+			t.Errorf("Expected %q to be rejected, got: %v", runID, err)
+		}
+	}
+	if _, err := LoadJournal("20261016-000000-0000"); err == nil || !strings.Contains(err.Error(), "no journal found") {
+		t.Errorf("Expected a missing journal to be reported, got: %v", err)
+	}
+}
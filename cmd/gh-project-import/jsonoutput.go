@@ -0,0 +1,79 @@
+// JSON output of an import (--output json)
+// Collects the run reports of an import and writes them as the one JSON document on stdout
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// runResults collects the reports of the runs of an --output json import
+type runResults struct {
+	reports []report.RunReport
+	// routed is set when items are routed to several projects, which makes
+	// the document an array with a report per project
+	routed bool
+}
+
+// add records the report of a finished run, or of a dry run
+func (r *runResults) add(runReport report.RunReport) {
+	r.reports = append(r.reports, runReport)
+}
+
+// failedImport is the document of an import that stopped before a run
+// finished, e.g. on invalid flags or sources, or that was aborted at the
+// confirmation prompt
+type failedImport struct {
+	Status string `json:"status"`
+	Text   string `json:"text"`
+	Error  string `json:"error,omitempty"`
+}
+
+// write writes the JSON document of the import that returned err: the run
+// report, an array of them when routed, or a failedImport when no run finished
+func (r *runResults) write(w io.Writer, err error) {
+	var document interface{}
+	switch {
+	case len(r.reports) == 0 && (err == nil || errors.Is(err, errAborted)):
+		document = failedImport{Status: report.StatusAborted, Text: "Aborted, nothing was changed"}
+	case len(r.reports) == 0 && err != nil:
+		document = failedImport{Status: report.StatusFailed, Text: err.Error(), Error: err.Error()}
+	case r.routed:
+		document = r.reports
+	default:
+		document = r.reports[0]
+	}
+
+	data, marshalErr := json.MarshalIndent(document, "", "  ")
+	if marshalErr != nil {
+		slog.Error("Failed to write the JSON output", "error", marshalErr)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// dryRunReport describes a dry run that would import total items into project
+func dryRunReport(config Config, project *ghclient.Project, total int) report.RunReport {
+	now := time.Now()
+	return report.RunReport{
+		Text:         fmt.Sprintf("Would import %d items to \"%s\"", total, project.Title),
+		Status:       report.StatusDryRun,
+		Sources:      config.Sources,
+		FromClassic:  config.FromClassic,
+		FromQuery:    config.FromQuery,
+		Project:      config.Project,
+		ProjectTitle: project.Title,
+		Total:        total,
+		Failures:     []report.ReportFailure{},
+		CreatedItems: []report.CreatedItem{},
+		Started:      now,
+		Finished:     now,
+	}
+}
@@ -0,0 +1,84 @@
+// Tests for --output json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// runJSONImport runs an --output json import with config and decodes the one
+// JSON document it writes to stdout into document
+func runJSONImport(t *testing.T, config Config, document interface{}) error {
+	t.Helper()
+	config.Output = "json"
+	config.Project = "acme/Roadmap"
+	config.Yes = true
+	config.MaxWarnings = -1
+	config.Oversize = mapping.OversizeTruncate
+	config.HoursPerDay, config.DaysPerWeek = 8, 5
+	config.Cache = "memory"
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runImport(context.Background(), config, strings.NewReader(""))
+	})
+	decoder := json.NewDecoder(strings.NewReader(out))
+	if decodeErr := decoder.Decode(document); decodeErr != nil {
+		t.Fatalf("Expected a JSON document on stdout, got %q: %v", out, decodeErr)
+	}
+	if decoder.More() {
+		t.Errorf("Expected only one JSON document on stdout, got %q", out)
+	}
+	return err
+}
+
+func TestRunImportJSONOutput(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	fake.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+	useClient(t, fake)
+
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title,Status\nPlan launch,Todo\nWrite docs,Todo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var runReport report.RunReport
+	if err := runJSONImport(t, Config{Sources: []string{source}}, &runReport); err != nil {
+		t.Fatal(err)
+	}
+	if runReport.Status != report.StatusSucceeded || runReport.Created != 2 || len(runReport.CreatedItems) != 2 {
+		t.Errorf("Expected the report of the run, got %+v", runReport)
+	}
+
+	var dryRun report.RunReport
+	if err := runJSONImport(t, Config{Sources: []string{source}, DryRun: true}, &dryRun); err != nil {
+		t.Fatal(err)
+	}
+	if dryRun.Status != report.StatusDryRun || dryRun.Total != 2 || dryRun.Created != 0 {
+		t.Errorf("Expected the report of a dry run, got %+v", dryRun)
+	}
+
+	var failed failedImport
+	err := runJSONImport(t, Config{Sources: []string{filepath.Join(t.TempDir(), "missing.csv")}}, &failed)
+	if err == nil {
+		t.Fatal("Expected an error for a missing source")
+	}
+	if failed.Status != report.StatusFailed || failed.Error != err.Error() {
+		t.Errorf("Expected the error in the JSON document, got %+v", failed)
+	}
+}
+
+func TestRunImportJSONOutputRejectsVerbose(t *testing.T) {
+	config := Config{Sources: []string{"items.csv"}, Project: "acme/Roadmap", Output: "json", Verbose: true}
+	if err := runImport(context.Background(), config, strings.NewReader("")); err == nil || !strings.Contains(err.Error(), "--verbose") {
+		t.Errorf("Expected --verbose to be rejected, got %v", err)
+	}
+}
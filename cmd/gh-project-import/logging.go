@@ -0,0 +1,139 @@
+// Structured logging with levels and text or JSON output
+// Diagnostics go to stderr through log/slog so stdout stays reserved for results
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// parseLogLevel converts a --log-level value into a slog level
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unsupported log level %q (expected debug, info, warn or error)", level)
+}
+
+// configureLogging installs the default logger writing to w. The text format
+// is meant for people at a terminal, with colored levels when color is set;
+// json emits one object per line.
+func configureLogging(w io.Writer, level, format string, color bool) error {
+	logLevel, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+
+	var handler slog.Handler
+	switch format {
+	case "", "text":
+		handler = &cliHandler{w: w, level: logLevel, color: color, mu: &sync.Mutex{}}
+	case "json":
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel})
+	default:
+		return fmt.Errorf("unsupported log format %q (expected text or json)", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// cliHandler formats records as "LEVEL: message key=value ...", omitting the
+// timestamp and the level prefix for info messages
+type cliHandler struct {
+	w     io.Writer
+	level slog.Level
+	color bool
+	attrs []slog.Attr
+	mu    *sync.Mutex
+}
+
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *cliHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString(h.label("ERROR:", "\033[31m"))
+	case record.Level >= slog.LevelWarn:
+		line.WriteString(h.label("WARNING:", "\033[33m"))
+	case record.Level < slog.LevelInfo:
+		line.WriteString(h.label("DEBUG:", "\033[2m"))
+	}
+	line.WriteString(record.Message)
+
+	writeAttr := func(attr slog.Attr) bool {
+		fmt.Fprintf(&line, " %s=%v", attr.Key, formatLogValue(attr.Value))
+		return true
+	}
+	for _, attr := range h.attrs {
+		writeAttr(attr)
+	}
+	record.Attrs(writeAttr)
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &cliHandler{w: h.w, level: h.level, color: h.color, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...), mu: h.mu}
+}
+
+// label formats the level prefix of a line, in color when enabled
+func (h *cliHandler) label(text, color string) string {
+	if h.color {
+		return color + text + "\033[0m "
+	}
+	return text + " "
+}
+
+// WithGroup is not used by this tool; groups are flattened into the attributes
+func (h *cliHandler) WithGroup(name string) slog.Handler {
+	return h
+}
+
+// formatLogValue quotes string values containing spaces
+func formatLogValue(value slog.Value) string {
+	s := value.Resolve().String()
+	if value.Kind() == slog.KindString && (s == "" || strings.ContainsAny(s, " \t\n\"")) {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// configureTrace sends the --trace-api trace of API requests to stderr for
+// "-" or to the file at path, which is replaced
+func configureTrace(path string) error {
+	switch path {
+	case "":
+		clientOptions.Trace = nil
+	case "-":
+		clientOptions.Trace = os.Stderr
+	default:
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to open --trace-api file: %w", err)
+		}
+		// Left open for the rest of the process; writes aren't buffered
+		clientOptions.Trace = file
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Tests for structured logging
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestConfigureLoggingText(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var out strings.Builder
+	if err := configureLogging(&out, "info", "text", false); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Debug("hidden")
+	slog.Info("Importing item", "item", 3, "title", "Fix login")
+	slog.Warn("Failed to set field", "field", "Status")
+	slog.Error("Failed to import item", "item", 4)
+
+	expected := "Importing item item=3 title=\"Fix login\"\n" +
+		"WARNING: Failed to set field field=Status\n" +
+		"ERROR: Failed to import item item=4\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestConfigureLoggingColor(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var out strings.Builder
+	if err := configureLogging(&out, "info", "text", true); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Info("Importing item")
+	slog.Error("Failed to import item", "item", 4)
+
+	expected := "Importing item\n\033[31mERROR:\033[0m Failed to import item item=4\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, out.String())
+	}
+}
+
+func TestConfigureLoggingJSON(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var out strings.Builder
+	if err := configureLogging(&out, "debug", "json", false); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.Debug("GraphQL mutation", "operation", "addProjectV2DraftIssue", "variables", map[string]interface{}{"projectId": "PVT_1"})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(out.String()), &record); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", out.String(), err)
+	}
+	if record["level"] != "DEBUG" || record["msg"] != "GraphQL mutation" || record["operation"] != "addProjectV2DraftIssue" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if variables, ok := record["variables"].(map[string]interface{}); !ok || variables["projectId"] != "PVT_1" {
+		t.Errorf("Expected variables to be logged, got %v", record["variables"])
+	}
+}
+
+func TestConfigureLoggingInvalid(t *testing.T) {
+	var out strings.Builder
+	if err := configureLogging(&out, "loud", "text", false); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+	if err := configureLogging(&out, "info", "xml", false); err == nil {
+		t.Error("Expected error for invalid format")
+	}
+}
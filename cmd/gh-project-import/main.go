@@ -0,0 +1,1583 @@
+// GitHub Project Import Extension - imports items from JSON/CSV files into GitHub Projects v2
+// This extension enables bulk import and migration of project items with field values
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/importer"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// Build information, set by the Makefile through -ldflags
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// clientOptions configures the clients of every command: the root command's
+// flags fill it in before a command runs
+var clientOptions ghclient.ClientOptions
+
+// newClient creates the GitHub client of every command. Tests replace it, e.g.
+// with useClient, to run a command against a FakeClient or SnapshotClient.
+var newClient = func() (ghclient.Client, error) {
+	return ghclient.NewClient(clientOptions)
+}
+
+type Config struct {
+	Sources     []string
+	Project     string
+	DryRun      bool
+	Yes         bool
+	Verbose     bool
+	Quiet       bool
+	ItemTimeout time.Duration
+	Checkpoint  string
+	Resume      bool
+	Profile     string
+	Mapping     string
+	MaxWarnings int
+	Output      string
+	FromClassic string
+	FromQuery   string
+	OnlyFields  []string
+	SkipFields  []string
+
+	FallbackToDraft  bool
+	CreateMilestones bool
+	StrictOptions    bool
+
+	Limit  int
+	Skip   int
+	Sample int
+
+	MaxSizes []string
+	Oversize string
+
+	KeepTemp  bool
+	ChunkSize int
+	CSV       parser.CSVDialect
+
+	// BatchSize items are imported between pauses of BatchPause, to stay
+	// under the secondary rate limits of long runs
+	BatchSize  int
+	BatchPause time.Duration
+
+	// SourceFormat overrides the format of the sources; SourceHeaders are
+	// sent when downloading URL sources
+	SourceFormat  string
+	SourceHeaders []string
+
+	// Convert lists FIELD=FORMAT markup conversions, e.g. body=jira
+	Convert []string
+
+	// ProjectNumber picks the project of the --project owner by number
+	ProjectNumber int
+	// IncludeClosed allows importing into a closed project; Reopen reopens
+	// it before the import starts
+	IncludeClosed bool
+	Reopen        bool
+
+	// Routes send the items whose field matches to another project, as
+	// FIELD=PATTERN->PROJECT
+	Routes []string
+
+	// Defaults lists FIELD=VALUE values for items that don't set the field
+	Defaults []string
+
+	// HookTransform is a program each item is piped through
+	HookTransform string
+
+	// TitleTemplate and BodyTemplate rebuild titles and bodies from the row
+	TitleTemplate string
+	BodyTemplate  string
+
+	// Durations lists FIELD=UNIT duration conversions, e.g. Estimate=hours
+	Durations     []string
+	HoursPerDay   float64
+	DaysPerWeek   float64
+	HoursPerPoint float64
+
+	RollbackOnFailure bool
+	ErrorFile         string
+	Cache             string
+	// Report is a Markdown summary of the run to write when it finishes
+	Report string
+
+	// MaxFailures is how many items, as N or N%, may fail before the run
+	// exits non-zero; by default none may
+	MaxFailures string
+
+	// StrictFields checks every field value and fails validation on any
+	// value that would be skipped
+	StrictFields bool
+
+	// Journal records the items the run creates for the undo subcommand
+	Journal bool
+
+	// MatchKey makes items that match an existing project item by url,
+	// title or field:NAME update it instead of being created again
+	MatchKey string
+	// ExternalIDField is the text field the external_id column is stored
+	// in, created if the project doesn't have it; it implies matching on it
+	ExternalIDField string
+
+	// Mode is one-way, or two-way to also write the project's values of
+	// matched items back to the source; Conflict picks the side kept when
+	// they differ and ConflictReport lists those values
+	Mode           string
+	Conflict       string
+	ConflictReport string
+
+	// ProvenanceField is a text field stamped on every imported item with
+	// its source and the run ID, created if the project doesn't have it
+	ProvenanceField string
+	// Metrics prints API request and per-item timing metrics after the run
+	Metrics bool
+	// OnCompleteURL and OnCompleteCmd receive the JSON run report when the
+	// import finishes
+	OnCompleteURL string
+	OnCompleteCmd string
+
+	// PreserveOrder places each imported item after the previous one;
+	// OrderBy sorts the items by a field first and implies it
+	PreserveOrder bool
+	OrderBy       string
+
+	// ArchiveAfter lists the FIELD=PATTERN conditions of items to archive
+	// once imported
+	ArchiveAfter []string
+
+	// Columns is an inline column mapping from the config file
+	Columns map[string]string
+	// OptionAliases are the single-select option aliases of the mapping file
+	OptionAliases map[string]map[string]string
+
+	// results collects the run reports for --output json
+	results *runResults
+	// noTerminal is set when stdin isn't a terminal, so prompts fail rather
+	// than wait for an answer nobody can type
+	noTerminal bool
+}
+
+// failureLimit returns the --max-failures limit, which runImport has validated
+func (c Config) failureLimit() failureLimit {
+	limit, _ := parseFailureLimit(c.MaxFailures)
+	return limit
+}
+
+// optionMatcher matches single-select values to options for the run
+func (c Config) optionMatcher() mapping.OptionMatcher {
+	return mapping.OptionMatcher{Aliases: c.OptionAliases, Strict: c.StrictOptions}
+}
+
+// importOptions controls how each item is imported
+func (c Config) importOptions() importer.Options {
+	return importer.Options{
+		FallbackToDraft:  c.FallbackToDraft,
+		CreateMilestones: c.CreateMilestones,
+		StrictOptions:    c.StrictOptions,
+		OptionAliases:    c.OptionAliases,
+		ArchiveAfter:     c.ArchiveAfter,
+	}
+}
+
+// sourceOptions controls how the sources are read. Downloads share the
+// --request-timeout of API requests.
+func (c Config) sourceOptions() parser.SourceOptions {
+	return parser.SourceOptions{Profile: c.Profile, MappingFile: c.Mapping, Columns: c.Columns, KeepTemp: c.KeepTemp, CSV: c.CSV, Format: c.SourceFormat, Headers: c.SourceHeaders, Timeout: clientOptions.RequestTimeout}
+}
+
+// sourceName describes where the items are read from
+func (c Config) sourceName() string {
+	if c.FromClassic != "" {
+		return "classic project " + c.FromClassic
+	}
+	if c.FromQuery != "" {
+		return fmt.Sprintf("search %q", c.FromQuery)
+	}
+	return strings.Join(c.Sources, ", ")
+}
+
+func main() {
+	var config Config
+	var logLevel, logFormat, configFile, traceAPI, colorMode, token string
+	var timeout time.Duration
+	var rootCmd *cobra.Command
+
+	// Ctrl-C cancels the context of the command, which abandons the API
+	// requests in flight and stops an import with a summary of what was
+	// done. A second Ctrl-C exits right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	cancelTimeout := func() {}
+
+	rootCmd = &cobra.Command{
+		Use:   "project-import",
+		Short: "Import items from JSON/CSV files into GitHub Projects v2",
+		Long: `Import multiple items to a GitHub Projects v2 board from a JSON or CSV file.
+This tool helps automate bulk additions, synchronization, or migration between projects.
+
+Examples:
+  gh project-import --source items.json --project "owner/project-name"
+  gh project-import --source items.csv --project "123" --dry-run`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			columns, err := loadConfigForCommand(rootCmd, cmd, configFile)
+			if err != nil {
+				return err
+			}
+			config.Columns = columns
+
+			// --verbose and --quiet pick the level unless it is given explicitly
+			level := logLevel
+			if level == "" {
+				switch {
+				case config.Verbose:
+					level = "debug"
+				case config.Quiet:
+					level = "error"
+				default:
+					level = "info"
+				}
+			}
+			if report.Color, err = report.UseColor(colorMode, term.IsTerminal(os.Stdout)); err != nil {
+				return err
+			}
+			// Validated above, and stderr may be a terminal when stdout isn't
+			stderrColor, _ := report.UseColor(colorMode, term.IsTerminal(os.Stderr))
+			if err := configureLogging(os.Stderr, level, logFormat, stderrColor); err != nil {
+				return err
+			}
+			if err := configureTrace(traceAPI); err != nil {
+				return err
+			}
+
+			// A token from --token or the environment is used as is, without
+			// asking gh, whose keyring isn't there in CI containers
+			var source string
+			clientOptions.Token, source = ghclient.ResolveToken(token)
+			if clientOptions.Token != "" {
+				slog.Debug("Using token", "kind", ghclient.TokenKind(clientOptions.Token), "source", source)
+			}
+
+			if timeout > 0 {
+				timeoutCtx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(timeoutCtx)
+				cancelTimeout = cancel
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Checked before runImport buffers stdin, which hides the file
+			config.noTerminal = !term.IsTerminal(os.Stdin)
+			return runImport(cmd.Context(), config, os.Stdin)
+		},
+	}
+
+	ghclient.Version = version
+	rootCmd.Version = fmt.Sprintf("%s (commit %s, built %s)", version, commit, buildTime)
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "YAML file with default flag values (default "+defaultImportConfigFile+" if it exists)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn or error (default info, or debug with --verbose)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Color output: auto (when writing to a terminal and NO_COLOR isn't set), always or never")
+	rootCmd.PersistentFlags().StringVar(&traceAPI, "trace-api", "", "Log every API request with its variables, response time and errors to stderr, or with --trace-api=FILE to a file")
+	rootCmd.PersistentFlags().Lookup("trace-api").NoOptDefVal = "-"
+	rootCmd.PersistentFlags().StringVar(&token, "token", "", "GitHub token to use instead of gh's credentials (default $GH_TOKEN or $GITHUB_TOKEN)")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.RequestTag, "request-tag", "", "Label appended to the User-Agent of API requests, e.g. to identify a scheduled job in audit logs")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Maximum time for the whole run, after which an import stops with a summary (0 disables the limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientOptions.RequestTimeout, "request-timeout", 2*time.Minute, "Maximum time to wait for a single API request (0 disables the limit)")
+
+	rootCmd.Flags().StringArrayVarP(&config.Sources, "source", "s", nil, "Source file, glob pattern or http(s) URL with items to import, optionally compressed (.gz or .zip); repeat to import several")
+	rootCmd.Flags().BoolVar(&config.KeepTemp, "keep-temp", false, "Keep the temporary copies of downloaded or decompressed sources for debugging")
+	addCSVDialectFlags(rootCmd, &config.CSV)
+	addSourceFormatFlags(rootCmd, &config.SourceFormat, &config.SourceHeaders)
+	rootCmd.Flags().IntVar(&config.BatchSize, "batch-size", 0, "Pause after every N items to stay under GitHub's secondary rate limits on long runs (0 never pauses)")
+	rootCmd.Flags().DurationVar(&config.BatchPause, "batch-pause", 30*time.Second, "How long to pause between batches of --batch-size items")
+	rootCmd.Flags().IntVar(&config.ChunkSize, "chunk-size", 0, "Stream the source and validate and import it N items at a time, keeping memory flat for very large CSV or NDJSON files (0 reads the whole source first)")
+	rootCmd.Flags().StringVar(&config.FromClassic, "from-classic", "", "Migrate the cards of a classic project board instead of reading a source file (format: owner/repo/project-number)")
+	rootCmd.Flags().StringVar(&config.FromQuery, "from-query", "", "Add the issues and pull requests an issue search finds instead of reading a source file, e.g. \"repo:acme/api is:issue label:triage\"")
+	rootCmd.Flags().StringArrayVar(&config.Defaults, "default", nil, "Field value for items that don't set the field, as FIELD=VALUE (repeatable)")
+	rootCmd.Flags().StringVarP(&config.Project, "project", "p", "", "Destination project identifier (format: owner/project-name or project-number) (required)")
+	rootCmd.Flags().StringArrayVar(&config.Routes, "route", nil, "Send the items whose field matches to another project, as FIELD=PATTERN->PROJECT; other items go to --project (repeatable)")
+	rootCmd.Flags().IntVar(&config.ProjectNumber, "project-number", 0, "Import into the project with this number, owned by the --project owner, instead of matching its title")
+	rootCmd.Flags().BoolVar(&config.IncludeClosed, "include-closed", false, "Allow importing into a closed project")
+	rootCmd.Flags().BoolVar(&config.Reopen, "reopen", false, "Reopen the project before importing if it is closed")
+	rootCmd.Flags().BoolVar(&config.DryRun, "dry-run", false, "Preview what would be imported without making changes")
+	rootCmd.Flags().BoolVarP(&config.Yes, "yes", "y", false, "Don't ask for confirmation before importing")
+	rootCmd.Flags().BoolVarP(&config.Verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.Flags().BoolVarP(&config.Quiet, "quiet", "q", false, "Suppress non-error output")
+	rootCmd.Flags().StringVarP(&config.Output, "output", "o", "text", "Output format: text, tsv for one tab-separated line per item (status, item id, url, title), or json for the run report as one JSON document")
+	rootCmd.Flags().BoolVar(&config.FallbackToDraft, "fallback-to-draft", false, "Import issues and pull requests whose URL can't be found as draft issues instead of failing")
+	rootCmd.Flags().BoolVar(&config.CreateMilestones, "create-milestones", false, "Create the milestones named in a Milestone column that don't exist in the item's repository")
+	rootCmd.Flags().BoolVar(&config.StrictOptions, "strict-options", false, "Match single-select values to options only when the case is exact (by default \"in progress\" matches \"In Progress\")")
+	rootCmd.Flags().DurationVar(&config.ItemTimeout, "item-timeout", 5*time.Minute, "Maximum time to spend importing a single item (0 disables the limit)")
+
+	rootCmd.Flags().StringVar(&config.Profile, "profile", "", "Read the source as an export from another tool (available: "+strings.Join(parser.ImportProfileNames(), ", ")+")")
+	rootCmd.Flags().StringVar(&config.Mapping, "mapping", "", "JSON file mapping CSV column names to importer columns, overriding the profile defaults, with optional single-select option aliases")
+	rootCmd.Flags().IntVar(&config.Skip, "skip", 0, "Skip the first N items in the source")
+	rootCmd.Flags().IntVar(&config.Limit, "limit", 0, "Import at most N items (0 for no limit)")
+	rootCmd.Flags().IntVar(&config.Sample, "sample", 0, "Import a random sample of N items, e.g. to trial a large import")
+	rootCmd.Flags().StringSliceVar(&config.OnlyFields, "only-fields", nil, "Only set the named fields (comma-separated, glob patterns allowed)")
+	rootCmd.Flags().StringSliceVar(&config.SkipFields, "skip-fields", nil, "Never set the named fields (comma-separated, glob patterns allowed)")
+	rootCmd.Flags().StringArrayVar(&config.Convert, "convert", nil, "Convert a field's markup to Markdown, as FIELD=FORMAT with FORMAT one of "+strings.Join(mapping.TextConverterNames(), ", ")+"; use title or body for the item's title and body (repeatable)")
+	rootCmd.Flags().StringVar(&config.HookTransform, "hook-transform", "", "Program each item is piped through as JSON; it writes back the item to import, or nothing to skip it")
+	rootCmd.Flags().StringVar(&config.TitleTemplate, "title-template", "", "Go template for the item titles over the row, e.g. \"[{{.Fields.Team}}] {{.Title}}\"")
+	rootCmd.Flags().StringVar(&config.BodyTemplate, "body-template", "", "Go template for the item bodies over the row, e.g. to keep columns the project has no field for in the body")
+	rootCmd.Flags().StringArrayVar(&config.Durations, "duration", nil, "Convert durations such as 1w 2d 3h or 90m in a number field, as FIELD=UNIT with UNIT one of hours, days or points (repeatable)")
+	rootCmd.Flags().Float64Var(&config.HoursPerDay, "hours-per-day", 8, "Hours in a working day, for --duration")
+	rootCmd.Flags().Float64Var(&config.DaysPerWeek, "days-per-week", 5, "Days in a working week, for --duration")
+	rootCmd.Flags().Float64Var(&config.HoursPerPoint, "hours-per-point", 0, "Hours in a story point, for --duration FIELD=points")
+	rootCmd.Flags().StringSliceVar(&config.MaxSizes, "max-size", nil, "Maximum length in characters of a value, as name=size for title, body or a field (defaults: title=256, body=65536)")
+	rootCmd.Flags().StringVar(&config.Oversize, "oversize", mapping.OversizeTruncate, "What to do with values over their --max-size: truncate, or fail before importing")
+	rootCmd.Flags().IntVar(&config.MaxWarnings, "max-warnings", -1, "Abort before importing if validation produces more warnings than this (-1 for no limit)")
+	rootCmd.Flags().BoolVar(&config.StrictFields, "strict-fields", false, "Check every field value before importing and abort if any would be skipped, instead of warning")
+	rootCmd.Flags().BoolVar(&config.RollbackOnFailure, "rollback-on-failure", false, "Delete the items created by this run if any item fails or the run is interrupted")
+	rootCmd.Flags().StringVar(&config.MaxFailures, "max-failures", "", "Exit non-zero only when more items than this fail to import, as a count or a percentage such as 5% (default 0: any failed item exits with code 4)")
+	rootCmd.Flags().BoolVar(&config.PreserveOrder, "preserve-order", false, "Place each imported item after the previous one so the project lists them in source order")
+	rootCmd.Flags().StringVar(&config.OrderBy, "order-by", "", "Sort the items by this field before importing them, in the order of its options for single-select fields; implies --preserve-order")
+	rootCmd.Flags().StringArrayVar(&config.ArchiveAfter, "archive-after", nil, "Archive the imported items whose field matches, as FIELD=PATTERN or FIELD!=PATTERN, once their fields are set (repeatable, all must match)")
+	rootCmd.Flags().StringVar(&config.MatchKey, "match-key", "", "Update the project item an item matches instead of adding it again, matching by url, title or field:NAME (e.g. field:\"Jira Key\")")
+	rootCmd.Flags().StringVar(&config.ExternalIDField, "external-id-field", "", "Text field to store each item's external_id column in, updating the item that already has its ID instead of adding it again (created if the project doesn't have it)")
+	rootCmd.Flags().StringVar(&config.Mode, "mode", modeOneWay, "Sync mode: one-way, or two-way to also write the project's values of matched items back to a CSV source")
+	rootCmd.Flags().StringVar(&config.Conflict, "conflict", conflictSourceWins, "Side kept when a two-way sync finds different values: source-wins, project-wins or newest-wins")
+	rootCmd.Flags().StringVar(&config.ConflictReport, "conflict-report", "", "CSV file listing the values a two-way sync found different and the side kept")
+	rootCmd.Flags().StringVar(&config.ProvenanceField, "provenance-field", "", "Text field to set on every imported item to its source file and run ID, e.g. \"Imported From\" (created if the project doesn't have it)")
+	rootCmd.Flags().BoolVar(&config.Journal, "journal", true, "Record the items created by the run so \"undo\" can delete them again (--journal=false disables)")
+	rootCmd.Flags().BoolVar(&config.Metrics, "metrics", false, "Print the number of API requests, the GraphQL rate limit points used, the average time per item and the slowest requests after the run")
+	rootCmd.Flags().StringVar(&config.OnCompleteURL, "on-complete-url", "", "POST the JSON run report to this webhook URL when the import finishes")
+	rootCmd.Flags().StringVar(&config.OnCompleteCmd, "on-complete-cmd", "", "Run this program with the JSON run report on stdin when the import finishes")
+	rootCmd.Flags().StringVar(&config.ErrorFile, "error-file", "", "Write the items that failed, with their errors, to this .csv, .json or .ndjson file for re-importing")
+	rootCmd.Flags().StringVar(&config.Report, "report", "", "Write a Markdown summary of the run, with the field mapping, failed items and links to created items, to this file")
+	rootCmd.Flags().StringVar(&config.Cache, "cache", "memory", "Cache for issue lookups: memory, none, file:PATH or redis://host:port[/db] to share it between runs and hosts")
+	rootCmd.Flags().StringVar(&config.Checkpoint, "checkpoint", defaultCheckpointFile, "File used to record import progress (empty disables checkpointing)")
+	rootCmd.Flags().BoolVar(&config.Resume, "resume", false, "Resume an interrupted import, skipping items already recorded in the checkpoint")
+
+	rootCmd.MarkFlagsOneRequired("source", "from-classic", "from-query")
+	rootCmd.MarkFlagsMutuallyExclusive("source", "from-classic", "from-query")
+	rootCmd.MarkFlagsMutuallyExclusive("profile", "from-classic", "from-query")
+	rootCmd.MarkFlagsMutuallyExclusive("mapping", "from-classic", "from-query")
+	rootCmd.MarkFlagRequired("project")
+
+	rootCmd.AddCommand(newExplainCmd())
+	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newSchemaCmd())
+	rootCmd.AddCommand(newArchiveCmd())
+	rootCmd.AddCommand(newCleanCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newTemplateCmd())
+	rootCmd.AddCommand(newValidateCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newChangelogCmd())
+	rootCmd.AddCommand(newRoundtripCmd())
+	registerCompletions(rootCmd)
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	if err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+// addCSVDialectFlags adds the flags describing how CSV sources are written
+func addCSVDialectFlags(cmd *cobra.Command, dialect *parser.CSVDialect) {
+	cmd.Flags().StringVar(&dialect.Delimiter, "delimiter", ",", "Field delimiter of CSV sources: a single character, or tab")
+	cmd.Flags().StringVar(&dialect.Encoding, "encoding", "utf-8", "Text encoding of CSV sources: "+strings.Join(parser.CSVEncodingNames(), ", ")+" (a byte order mark takes precedence)")
+	cmd.Flags().BoolVar(&dialect.NoHeader, "no-header", false, "CSV sources have no header row; columns are named 1, 2, 3... for --mapping")
+	cmd.Flags().BoolVar(&dialect.LazyQuotes, "lazy-quotes", false, "Accept stray quotes in CSV fields instead of failing")
+}
+
+// addSourceFormatFlags adds the flags for sources whose name doesn't give
+// their format, such as URLs of internal services
+func addSourceFormatFlags(cmd *cobra.Command, format *string, headers *[]string) {
+	cmd.Flags().StringVar(format, "source-format", "", "Format of the sources: json, ndjson, csv, toml or markdown (default: from the file name, or the Content-Type of URLs)")
+	cmd.Flags().StringArrayVar(headers, "source-header", nil, "HTTP header sent when downloading URL sources, as \"Name: value\", e.g. for authentication; $VARIABLES in the value are expanded (repeatable)")
+}
+
+// runImport imports the items of config.Sources or config.FromClassic,
+// asking on in for confirmation first unless config.Yes is set
+func runImport(ctx context.Context, config Config, in io.Reader) (err error) {
+	// One reader serves every prompt, so buffering for the first doesn't
+	// swallow the answers to the others
+	in = bufio.NewReader(in)
+
+	// Validate flags
+	if config.Verbose && config.Quiet {
+		return fmt.Errorf("cannot use both --verbose and --quiet flags")
+	}
+	switch config.Output {
+	case "", "text":
+	case "tsv":
+		if config.Verbose {
+			return fmt.Errorf("cannot use --verbose with --output tsv")
+		}
+		// Only the TSV rows are written to stdout
+		config.Quiet = true
+	case "json":
+		if config.Verbose {
+			return fmt.Errorf("cannot use --verbose with --output json")
+		}
+		// Only the JSON document is written to stdout, once the import is over
+		config.Quiet = true
+		config.results = &runResults{}
+		defer func() { config.results.write(os.Stdout, err) }()
+	default:
+		return fmt.Errorf("unsupported output format %q (expected text, tsv or json)", config.Output)
+	}
+	if config.ProjectNumber < 0 {
+		return fmt.Errorf("--project-number must not be negative")
+	}
+	if _, err := strconv.Atoi(config.Project); err == nil && config.ProjectNumber > 0 {
+		return fmt.Errorf("--project-number needs the project owner in --project, e.g. --project acme/roadmap")
+	}
+	if config.Resume && config.Checkpoint == "" {
+		return fmt.Errorf("--resume requires a --checkpoint file")
+	}
+	if config.Limit < 0 || config.Skip < 0 || config.Sample < 0 {
+		return fmt.Errorf("--limit, --skip and --sample must not be negative")
+	}
+	if config.ChunkSize < 0 {
+		return fmt.Errorf("--chunk-size must not be negative")
+	}
+	if config.BatchSize < 0 || config.BatchPause < 0 {
+		return fmt.Errorf("--batch-size and --batch-pause must not be negative")
+	}
+	if config.ChunkSize > 0 && config.Sample > 0 {
+		return fmt.Errorf("cannot use --sample with --chunk-size: drawing a sample needs every item in memory")
+	}
+	if config.ChunkSize > 0 && (config.FromClassic != "" || config.FromQuery != "") {
+		return fmt.Errorf("--chunk-size only applies to --source imports")
+	}
+	if config.ChunkSize > 0 && len(config.Routes) > 0 {
+		return fmt.Errorf("cannot use --route with --chunk-size: routing needs every item in memory")
+	}
+	if config.ChunkSize > 0 && config.OrderBy != "" {
+		return fmt.Errorf("cannot use --order-by with --chunk-size: sorting needs every item in memory")
+	}
+	if config.OrderBy != "" {
+		config.PreserveOrder = true
+	}
+	if config.Sample > 0 && config.Resume {
+		return fmt.Errorf("cannot use --resume with --sample: a new random sample is drawn on every run")
+	}
+	if err := mapping.ValidateFieldPatterns(append(config.OnlyFields, config.SkipFields...)); err != nil {
+		return err
+	}
+	if err := config.sourceOptions().Validate(); err != nil {
+		return err
+	}
+	if config.ErrorFile != "" {
+		if err := report.ValidateErrorFilePath(config.ErrorFile); err != nil {
+			return err
+		}
+	}
+	if _, err := report.ParseCompletionHooks(config.OnCompleteURL, config.OnCompleteCmd); err != nil {
+		return err
+	}
+	if _, err := parseFailureLimit(config.MaxFailures); err != nil {
+		return err
+	}
+	if config.ExternalIDField != "" {
+		key := "field:" + config.ExternalIDField
+		if config.MatchKey != "" && config.MatchKey != key {
+			return fmt.Errorf("cannot use --match-key with --external-id-field, which matches items by %s", key)
+		}
+		if config.ExternalIDField == config.ProvenanceField {
+			return fmt.Errorf("--external-id-field and --provenance-field must be different fields")
+		}
+		config.MatchKey = key
+	}
+	if config.MatchKey != "" {
+		if _, err := importer.ParseMatchKey(config.MatchKey); err != nil {
+			return err
+		}
+	}
+	if err := config.validateSync(); err != nil {
+		return err
+	}
+	if config.Oversize != mapping.OversizeTruncate && config.Oversize != mapping.OversizeFail {
+		return fmt.Errorf("unsupported --oversize policy %q (expected truncate or fail)", config.Oversize)
+	}
+	prep, err := parseItemPreparation(config)
+	if err != nil {
+		return err
+	}
+	if _, err := mapping.ParseItemFilter(config.ArchiveAfter); err != nil {
+		return err
+	}
+	if _, err := ParseRoutes(config.Routes); err != nil {
+		return err
+	}
+	if config.Mapping != "" {
+		config.OptionAliases, err = parser.LoadOptionAliases(config.Mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.Metrics {
+		// Set before any client is created so every request is counted
+		clientOptions.Metrics = ghclient.NewMetrics()
+	}
+
+	if !config.Quiet {
+		fmt.Printf("Starting import from %s to project %s\n", config.sourceName(), config.Project)
+		if config.DryRun {
+			fmt.Println("Running in dry-run mode - no changes will be made")
+		}
+	}
+
+	if config.ChunkSize > 0 {
+		return runChunkedImport(ctx, nil, config, in, prep)
+	}
+
+	// Read the items to import
+	var items []parser.ImportItem
+	var client ghclient.Client
+
+	if config.FromClassic != "" || config.FromQuery != "" {
+		// Reading a classic board or a search needs the API, so the client is
+		// created up front
+		client, err = newClient()
+		if err != nil {
+			return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+		}
+	}
+	if config.FromClassic != "" {
+		items, err = FetchClassicProjectItems(ctx, client, config.FromClassic)
+		if err != nil {
+			return fmt.Errorf("failed to read classic project %s: %w", config.FromClassic, err)
+		}
+	} else if config.FromQuery != "" {
+		items, err = FetchQueryItems(ctx, client, config.FromQuery)
+		if err != nil {
+			return err
+		}
+		if !config.Quiet {
+			fmt.Printf("Found %d issues and pull requests\n", len(items))
+		}
+	} else {
+		items, err = parser.ParseSourcesContext(ctx, config.Sources, config.sourceOptions())
+		if err != nil {
+			return withExitCode(exitInvalid, err)
+		}
+	}
+
+	if config.Skip > 0 || config.Limit > 0 || config.Sample > 0 {
+		total := len(items)
+		items = selectItems(items, config.Skip, config.Limit, config.Sample, rand.Perm)
+		if !config.Quiet {
+			fmt.Printf("Selected %d of %d items\n", len(items), total)
+		}
+	}
+
+	items, truncations, err := prep.prepare(ctx, items, 0, config)
+	if err != nil {
+		return withExitCode(exitInvalid, err)
+	}
+	if len(truncations) > 0 {
+		// Data loss must always be visible, so the report goes to stderr
+		// when stdout is quiet or reserved for TSV rows
+		var out io.Writer = os.Stdout
+		if config.Quiet {
+			out = os.Stderr
+		}
+		defer report.PrintTruncations(out, truncations)
+	}
+
+	// Validate items
+	if err := parser.ValidateImportItems(items); err != nil {
+		return withExitCode(exitInvalid, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if !config.Quiet {
+		fmt.Printf("Parsed %d items from source file\n", len(items))
+	}
+	for i, item := range items {
+		slog.Debug("Parsed item", "item", i+1, "title", item.Title, "type", parser.GetItemType(item))
+	}
+
+	return importToDestinations(ctx, client, items, config, in)
+}
+
+// itemPreparation holds the parsed options that reshape items after they are
+// read and before they are validated
+type itemPreparation struct {
+	hook       mapping.TransformHook
+	defaults   mapping.FieldDefaults
+	converters map[string]string
+	durations  mapping.DurationConversions
+	templates  mapping.ItemTemplates
+	sizeLimits map[string]int
+}
+
+// parseItemPreparation parses the options that reshape items
+func parseItemPreparation(config Config) (itemPreparation, error) {
+	var prep itemPreparation
+	var err error
+	if prep.hook, err = mapping.ParseTransformHook(config.HookTransform); err != nil {
+		return prep, err
+	}
+	if prep.defaults, err = mapping.ParseFieldDefaults(config.Defaults); err != nil {
+		return prep, err
+	}
+	if prep.converters, err = mapping.ParseTextConverters(config.Convert); err != nil {
+		return prep, err
+	}
+	if prep.durations, err = mapping.ParseDurationConversions(config.Durations, config.HoursPerDay, config.DaysPerWeek, config.HoursPerPoint); err != nil {
+		return prep, err
+	}
+	if prep.templates, err = mapping.ParseItemTemplates(config.TitleTemplate, config.BodyTemplate); err != nil {
+		return prep, err
+	}
+	if prep.sizeLimits, err = mapping.ParseSizeLimits(config.MaxSizes); err != nil {
+		return prep, err
+	}
+	return prep, nil
+}
+
+// prepare passes items whose first item is at position offset through the
+// --hook-transform program, then reshapes them. It returns the items the hook
+// kept.
+func (p itemPreparation) prepare(ctx context.Context, items []parser.ImportItem, offset int, config Config) ([]parser.ImportItem, []mapping.Truncation, error) {
+	items, err := p.hook.Apply(ctx, items, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.reshape(items, offset, config)
+}
+
+// reshape moves the external IDs of items whose first item is at position
+// offset into the --external-id-field and applies the --default values,
+// --only-fields/--skip-fields, the --convert and --duration conversions, the
+// title and body templates and the size limits. Unlike the hook, these steps
+// give the same items every time.
+func (p itemPreparation) reshape(items []parser.ImportItem, offset int, config Config) ([]parser.ImportItem, []mapping.Truncation, error) {
+	only := config.OnlyFields
+	if config.ExternalIDField != "" {
+		mapping.MoveExternalIDs(items, config.ExternalIDField)
+		if len(only) > 0 {
+			// Without its ID an item would be added again on the next run
+			only = append(append([]string{}, only...), config.ExternalIDField)
+		}
+	}
+	p.defaults.Apply(items)
+	if len(only) > 0 || len(config.SkipFields) > 0 {
+		mapping.FilterItemFields(items, only, config.SkipFields)
+	}
+	if err := mapping.ApplyTextConverters(items, offset, p.converters); err != nil {
+		return nil, nil, err
+	}
+	if err := p.durations.Apply(items, offset); err != nil {
+		return nil, nil, err
+	}
+	if err := p.templates.Apply(items, offset); err != nil {
+		return nil, nil, err
+	}
+	truncations, err := mapping.ApplySizeLimitsFrom(items, offset, p.sizeLimits, config.Oversize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, truncations, nil
+}
+
+// reportValidationIssues prints the field validation findings and enforces
+// --max-warnings and --strict-fields
+func reportValidationIssues(issues []mapping.ValidationIssue, config Config) error {
+	if len(issues) > 0 && !config.Quiet {
+		report.PrintValidationIssues(issues, report.Color)
+	}
+
+	if config.StrictFields {
+		if skipped := mapping.CountValidationIssues(issues, mapping.SeverityWarning) + mapping.CountValidationIssues(issues, mapping.SeverityError); skipped > 0 {
+			return withExitCode(exitInvalid, fmt.Errorf("validation found %d field values that would be skipped; --strict-fields imports nothing until they are fixed", skipped))
+		}
+	}
+
+	if config.MaxWarnings >= 0 {
+		if warnings := mapping.CountValidationIssues(issues, mapping.SeverityWarning); warnings > config.MaxWarnings {
+			return withExitCode(exitInvalid, fmt.Errorf("validation produced %d warnings, more than the %d allowed by --max-warnings", warnings, config.MaxWarnings))
+		}
+	}
+	return nil
+}
+
+// validateFields checks the field values of items against the project
+// fields: the first value of each field, or every value with --strict-fields
+func (c Config) validateFields(items []parser.ImportItem, offset int, seenFields map[string]bool, fieldMap map[string]ghclient.ProjectField) []mapping.ValidationIssue {
+	if c.StrictFields {
+		return mapping.ValidateEveryItemFieldFrom(items, offset, seenFields, fieldMap, c.optionMatcher(), c.Verbose)
+	}
+	return mapping.ValidateItemFieldsFrom(items, offset, seenFields, fieldMap, c.optionMatcher(), c.Verbose)
+}
+
+// resolveDestination authenticates, creating a client unless one is given,
+// and looks up the destination project and its fields keyed by name
+func resolveDestination(ctx context.Context, client ghclient.Client, config Config, in io.Reader) (ghclient.Client, *ghclient.Project, map[string]ghclient.ProjectField, error) {
+	// Initialize GitHub client
+	slog.Debug("Authenticating with GitHub API")
+
+	if client == nil {
+		var err error
+		client, err = newClient()
+		if err != nil {
+			return nil, nil, nil, withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+		}
+	}
+
+	// Get current user info
+	user, err := client.GetUser(ctx)
+	if err != nil {
+		return nil, nil, nil, withExitCode(exitAuth, fmt.Errorf("failed to authenticate with GitHub: %w", err))
+	}
+
+	slog.Debug("Authenticated", "user", user)
+
+	// Find the destination project
+	slog.Debug("Resolving destination project", "project", config.Project)
+
+	project, err := resolveProject(ctx, client, config, in)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to find project: %w", err)
+	}
+
+	slog.Debug("Found project", "title", project.Title, "id", project.ID)
+
+	// Get project field schema
+	slog.Debug("Retrieving project field schema")
+
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	slog.Debug("Found project fields", "count", len(fields))
+	for _, field := range fields {
+		optionNames := make([]string, len(field.Options))
+		for i, opt := range field.Options {
+			optionNames[i] = opt.Name
+		}
+		slog.Debug("Project field", "name", field.Name, "type", field.Type, "options", strings.Join(optionNames, ", "))
+	}
+
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+
+	if config.ExternalIDField != "" {
+		field, ok := fieldMap[config.ExternalIDField]
+		if ok && field.Type != "TEXT" {
+			return nil, nil, nil, withExitCode(exitInvalid, fmt.Errorf("--external-id-field %q is a %s field; it must be a text field", field.Name, field.Type))
+		}
+		if !ok {
+			// Validated as the text field startImportRun creates once the
+			// import is confirmed
+			fieldMap[config.ExternalIDField] = ghclient.ProjectField{Name: config.ExternalIDField, Type: "TEXT"}
+		}
+	}
+	// Nothing would match a field the project doesn't have, and every item
+	// would be added again
+	if key, err := importer.ParseMatchKey(config.MatchKey); err == nil && key.Kind == "field" {
+		if _, ok := fieldMap[key.Field]; !ok {
+			return nil, nil, nil, withExitCode(exitInvalid, fmt.Errorf("--match-key field %q not found in project", key.Field))
+		}
+	}
+	if field, ok := fieldMap[config.ProvenanceField]; ok && field.Type != "TEXT" {
+		return nil, nil, nil, withExitCode(exitInvalid, fmt.Errorf("--provenance-field %q is a %s field; it must be a text field", field.Name, field.Type))
+	}
+
+	return client, project, fieldMap, nil
+}
+
+// openCache wraps the client with the --cache resolution cache, if any. The
+// returned function closes the cache.
+func openCache(client ghclient.Client, config Config) (ghclient.Client, func(), error) {
+	cache, err := ghclient.OpenResolutionCache(config.Cache)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cache == nil {
+		return client, func() {}, nil
+	}
+	closeCache := func() {
+		if err := cache.Close(); err != nil {
+			slog.Warn(err.Error())
+		}
+	}
+	return ghclient.NewCachedClient(client, cache), closeCache, nil
+}
+
+// selectItems applies --skip, --limit and --sample, in that order. Sampled
+// items keep their order in the source; perm supplies the random permutation.
+func selectItems(items []parser.ImportItem, skip, limit, sample int, perm func(int) []int) []parser.ImportItem {
+	if skip >= len(items) {
+		return nil
+	}
+	items = items[skip:]
+
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	if sample > 0 && sample < len(items) {
+		picked := perm(len(items))[:sample]
+		sort.Ints(picked)
+
+		sampled := make([]parser.ImportItem, len(picked))
+		for i, index := range picked {
+			sampled[i] = items[index]
+		}
+		items = sampled
+	}
+
+	return items
+}
+
+// importItems handles the actual import of items to a project and, with
+// --mode two-way, writes the project's values back to the source
+func importItems(ctx context.Context, client ghclient.Client, project *ghclient.Project, items []parser.ImportItem, fieldMap map[string]ghclient.ProjectField, config Config) error {
+	run, err := startImportRun(ctx, client, project, fieldMap, config, len(items))
+	if err != nil {
+		return err
+	}
+	var sync *projectSync
+	if config.Mode == modeTwoWay {
+		if sync, err = newProjectSync(items, run.importer.Existing, fieldMap, config); err != nil {
+			return err
+		}
+	}
+	run.importChunk(ctx, items, 0)
+	err = run.finish(ctx)
+	if sync != nil {
+		// The project's values are written back even when some items failed
+		if syncErr := sync.writeBack(); syncErr != nil && err == nil {
+			err = syncErr
+		}
+	}
+	return err
+}
+
+// importRun holds the progress of an import, which may be fed its items in
+// several chunks when the source is streamed
+type importRun struct {
+	client   ghclient.Client
+	project  *ghclient.Project
+	fieldMap map[string]ghclient.ProjectField
+	importer *importer.Importer
+	config   Config
+	total    int
+
+	checkpoint     *Checkpoint
+	rollback       *Rollback
+	journal        *Journal
+	wasInterrupted bool // Ctrl-C or --timeout stopped the run early
+
+	successCount int
+	updatedCount int // Of successCount, the items --match-key updated
+	errorCount   int
+	timeoutCount int
+	resumedCount int
+	lastItemID   string // Most recently imported item, for --preserve-order
+	movedItems   []string
+	failures     []report.FailedItem
+	created      []report.CreatedItem
+	timedItems   int           // Items imported or failed, for --metrics
+	itemTime     time.Duration // Time spent on them
+	batchItems   int           // Items imported or failed since the last --batch-pause
+	started      time.Time
+
+	// unsavedItems are the items recorded in the checkpoint since it was
+	// last saved, at checkpointSaved
+	unsavedItems    int
+	checkpointSaved time.Time
+
+	// provenance is the --provenance-field set on every imported item to its
+	// source and the runID
+	provenance *ghclient.ProjectField
+	runID      string
+}
+
+// startImportRun reopens a closed project with --reopen, opens the
+// checkpoint and prepares rollback and the run journal for an import of
+// total items
+func startImportRun(ctx context.Context, client ghclient.Client, project *ghclient.Project, fieldMap map[string]ghclient.ProjectField, config Config, total int) (*importRun, error) {
+	im, err := importer.New(client, project, fieldMap, config.importOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if project.Closed && config.Reopen {
+		if err := client.ReopenProject(ctx, project.ID); err != nil {
+			return nil, err
+		}
+		project.Closed = false
+		if !config.Quiet {
+			fmt.Println(report.Success("Reopened project '%s'", project.Title))
+		}
+	}
+
+	run := &importRun{
+		client:   client,
+		project:  project,
+		fieldMap: fieldMap,
+		importer: im,
+		config:   config,
+		total:    total,
+		started:  time.Now(),
+	}
+
+	if config.Checkpoint != "" {
+		run.checkpoint, err = openCheckpoint(config, total)
+		if err != nil {
+			return nil, err
+		}
+	}
+	run.saveCheckpoint()
+
+	if config.RollbackOnFailure || config.Journal || config.MatchKey != "" {
+		existing, items, err := existingProjectItems(ctx, client, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if config.RollbackOnFailure {
+			run.rollback = newRollback(existing)
+		}
+		if config.Journal {
+			run.journal = newJournal(config, project, existing)
+		}
+		if config.MatchKey != "" {
+			// Validated by runImport
+			key, _ := importer.ParseMatchKey(config.MatchKey)
+			im.Existing = importer.IndexProjectItems(items, key)
+			slog.Debug("Matching source items to project items", "match", key, "items", im.Existing.Len())
+			if im.Existing.Duplicates > 0 {
+				slog.Warn("Several project items share a match key; matching source items update the first", "match", key, "duplicates", im.Existing.Duplicates)
+			}
+		}
+	}
+
+	if field := fieldMap[config.ExternalIDField]; config.ExternalIDField != "" && field.ID == "" {
+		created, err := createTextField(ctx, client, project, config.ExternalIDField, "--external-id-field", config.Quiet)
+		if err != nil {
+			return nil, err
+		}
+		fieldMap[created.Name] = *created
+	}
+
+	run.runID = newRunID()
+	if run.journal != nil {
+		run.runID = run.journal.ID
+	}
+	if config.ProvenanceField != "" {
+		run.provenance, err = provenanceField(ctx, client, project, fieldMap, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
+
+// provenanceField returns the --provenance-field of the project, creating it
+// as a text field if the project doesn't have it
+func provenanceField(ctx context.Context, client ghclient.Client, project *ghclient.Project, fieldMap map[string]ghclient.ProjectField, config Config) (*ghclient.ProjectField, error) {
+	if field, ok := fieldMap[config.ProvenanceField]; ok {
+		return &field, nil
+	}
+	return createTextField(ctx, client, project, config.ProvenanceField, "--provenance-field", config.Quiet)
+}
+
+// createTextField adds the text field name to the project for the option flag
+func createTextField(ctx context.Context, client ghclient.Client, project *ghclient.Project, name, flag string, quiet bool) (*ghclient.ProjectField, error) {
+	field, err := client.CreateProjectField(ctx, project.ID, name, "TEXT")
+	if err != nil {
+		return nil, err
+	}
+	if !quiet {
+		fmt.Println(report.Success("Created text field '%s' for %s", field.Name, flag))
+	}
+	return field, nil
+}
+
+// stampProvenance sets the --provenance-field of an imported item to its
+// source and the run ID, e.g. "items.csv, run 20261016-153012-4f2a". A
+// failure is logged rather than failing the item.
+func (r *importRun) stampProvenance(ctx context.Context, position int, itemID string, item parser.ImportItem) {
+	source := r.config.sourceName()
+	if len(r.config.Sources) == 1 {
+		source = filepath.Base(r.config.Sources[0])
+	}
+	// Items of several sources name their file in their origin, "FILE item N"
+	if i := strings.LastIndex(item.Origin, " item "); i > 0 {
+		source = filepath.Base(item.Origin[:i])
+	}
+	value := map[string]interface{}{"text": fmt.Sprintf("%s, run %s", source, r.runID)}
+	if err := r.client.SetProjectItemFieldValue(ctx, r.project.ID, itemID, r.provenance.ID, value); err != nil {
+		slog.Warn("Failed to set the provenance field", "item", position, "field", r.provenance.Name, "error", err)
+	}
+}
+
+// The checkpoint is saved after checkpointSaveItems items or once
+// checkpointSaveInterval has passed, whichever comes first, and when the run
+// finishes or is interrupted. Saving after every item rewrites the whole file
+// each time, which adds up to gigabytes for a run of 100,000 items.
+const (
+	checkpointSaveItems    = 100
+	checkpointSaveInterval = 5 * time.Second
+)
+
+// saveCheckpoint persists progress; a failure to write it shouldn't abort the import
+func (r *importRun) saveCheckpoint() {
+	if r.checkpoint == nil {
+		return
+	}
+	r.unsavedItems = 0
+	r.checkpointSaved = time.Now()
+	if err := r.checkpoint.Save(r.config.Checkpoint); err != nil {
+		slog.Warn(err.Error())
+	}
+}
+
+// checkpointProgress saves the checkpoint after an item when enough items or
+// time have passed since it was last saved
+func (r *importRun) checkpointProgress() {
+	r.unsavedItems++
+	if r.unsavedItems >= checkpointSaveItems || time.Since(r.checkpointSaved) >= checkpointSaveInterval {
+		r.saveCheckpoint()
+	}
+}
+
+// saveJournal writes the run journal; a failure to write it shouldn't abort the import
+func (r *importRun) saveJournal() {
+	if err := r.journal.Save(); err != nil {
+		slog.Warn(err.Error())
+	}
+}
+
+// importChunk imports items whose first item is at position offset among
+// all items of the run. It returns false once the run has been interrupted.
+func (r *importRun) importChunk(ctx context.Context, items []parser.ImportItem, offset int) bool {
+	config := r.config
+	r.importer.Parents.AddRows(items)
+	r.prefetchContent(ctx, items, offset)
+
+	for i, item := range items {
+		position := offset + i + 1
+
+		if ctx.Err() != nil {
+			r.wasInterrupted = true
+			slog.Warn(interruptionMessage(ctx), "completed", position-1, "total", r.total)
+			return false
+		}
+
+		if r.checkpoint != nil && r.checkpoint.IsCompleted(position) {
+			r.resumedCount++
+			if config.Output == "tsv" {
+				report.PrintTSVRow("skipped", "", item)
+			}
+			slog.Debug("Skipping item already imported in a previous run", "item", position, "total", r.total)
+			continue
+		}
+
+		if config.BatchSize > 0 && r.batchItems == config.BatchSize && !r.pauseBatch(ctx) {
+			r.wasInterrupted = true
+			slog.Warn(interruptionMessage(ctx), "completed", position-1, "total", r.total)
+			return false
+		}
+
+		if !config.Quiet {
+			fmt.Printf("Importing item %d/%d...\n", position, r.total)
+		}
+		slog.Debug("Importing item", "item", position, "title", item.Title, "type", parser.GetItemType(item))
+
+		start := time.Now()
+		result, err := importSingleItemWithTimeout(ctx, r.importer, item, config)
+		itemID := result.ItemID
+		if r.rollback != nil {
+			r.rollback.Record(position, itemID)
+		}
+		if r.journal != nil && r.journal.Record(position, itemID, item.Title) {
+			// Saved after every item so a crashed run can still be undone
+			r.saveJournal()
+		}
+		if err != nil && ctx.Err() != nil {
+			// The item was cut short rather than failing, so it isn't
+			// recorded and a resumed run imports it again
+			r.wasInterrupted = true
+			slog.Warn(interruptionMessage(ctx), "completed", position-1, "total", r.total)
+			return false
+		}
+		r.timedItems++
+		r.itemTime += time.Since(start)
+		r.batchItems++
+		if result.FellBackToDraft && !config.Quiet {
+			fmt.Printf("  %s not found, imported as a draft issue\n", item.URL)
+		}
+		if result.MovedTo != "" {
+			r.movedItems = append(r.movedItems, fmt.Sprintf("%s → %s", item.URL, result.MovedTo))
+			slog.Debug("Issue has moved", "from", item.URL, "to", result.MovedTo)
+		}
+		if config.Output == "tsv" {
+			switch {
+			case errors.Is(err, errItemTimeout):
+				report.PrintTSVRow("timeout", itemID, item)
+			case err != nil:
+				report.PrintTSVRow("failed", itemID, item)
+			case result.Updated:
+				report.PrintTSVRow("updated", itemID, item)
+			default:
+				report.PrintTSVRow("imported", itemID, item)
+			}
+		}
+		if r.checkpoint != nil {
+			if err != nil {
+				r.checkpoint.MarkFailed(position, item.Title, err)
+			} else {
+				r.checkpoint.MarkCompleted(position)
+			}
+			r.checkpointProgress()
+		}
+		if err != nil {
+			r.errorCount++
+			r.failures = append(r.failures, report.FailedItem{Item: item, Err: err})
+			if errors.Is(err, errItemTimeout) {
+				r.timeoutCount++
+				slog.Warn("Item did not finish in time, skipping", "item", position, "title", item.Title, "timeout", config.ItemTimeout)
+				continue
+			}
+			attrs := []any{"item", position, "title", item.Title, "type", parser.GetItemType(item), "error", err}
+			if item.Origin != "" {
+				attrs = append(attrs, "source", item.Origin)
+			}
+			slog.Error("Failed to import item", attrs...)
+			continue
+		}
+
+		r.successCount++
+		if r.provenance != nil {
+			r.stampProvenance(ctx, position, itemID, item)
+		}
+		if result.Updated {
+			// The item was already in the project, so it keeps its place
+			r.updatedCount++
+			slog.Debug("Item updated", "item", position, "id", itemID)
+			continue
+		}
+		created := report.CreatedItem{ItemID: itemID, Title: item.Title, URL: item.URL}
+		if result.FellBackToDraft {
+			created.URL = ""
+		} else if result.MovedTo != "" {
+			created.URL = result.MovedTo
+		}
+		r.created = append(r.created, created)
+		slog.Debug("Item imported", "item", position, "id", itemID)
+
+		if config.PreserveOrder {
+			r.placeAfterLastItem(ctx, position, itemID)
+		}
+	}
+
+	return true
+}
+
+// prefetchContent resolves the issues and pull requests of a chunk in
+// batched lookups through the --cache, so importing them doesn't look each
+// one up on its own. Items already imported or matched by --match-key are
+// left out. A failed lookup is only logged: each item is looked up again as
+// it's imported.
+func (r *importRun) prefetchContent(ctx context.Context, items []parser.ImportItem, offset int) {
+	cached, ok := r.client.(*ghclient.CachedClient)
+	if !ok {
+		return
+	}
+
+	var urls []string
+	for i, item := range items {
+		if item.URL == "" || (r.checkpoint != nil && r.checkpoint.IsCompleted(offset+i+1)) {
+			continue
+		}
+		if itemType := parser.GetItemType(item); itemType != "Issue" && itemType != "PullRequest" {
+			continue
+		}
+		if r.importer.Existing != nil {
+			if _, ok := r.importer.Existing.Find(item); ok {
+				continue
+			}
+		}
+		urls = append(urls, item.URL)
+	}
+	if len(urls) == 0 {
+		return
+	}
+
+	found, err := cached.Prefetch(ctx, urls)
+	if err != nil {
+		slog.Warn("Failed to prefetch issues and pull requests", "error", err)
+		return
+	}
+	slog.Debug("Prefetched issues and pull requests", "requested", len(urls), "found", found)
+}
+
+// pauseBatch waits --batch-pause before the next batch of --batch-size
+// items. It returns false if the run is interrupted while waiting.
+func (r *importRun) pauseBatch(ctx context.Context) bool {
+	r.batchItems = 0
+	if r.config.BatchPause <= 0 {
+		return true
+	}
+	if !r.config.Quiet {
+		fmt.Printf("Pausing for %s after %d items...\n", r.config.BatchPause, r.config.BatchSize)
+	}
+	slog.Debug("Pausing between batches", "batch", r.config.BatchSize, "pause", r.config.BatchPause)
+
+	timer := time.NewTimer(r.config.BatchPause)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// placeAfterLastItem moves an imported item just after the item imported
+// before it, so the project shows them in source order. The first item stays
+// where it was added. A failure is logged rather than failing the item.
+func (r *importRun) placeAfterLastItem(ctx context.Context, position int, itemID string) {
+	if r.lastItemID != "" {
+		if err := r.client.SetProjectItemPosition(ctx, r.project.ID, itemID, r.lastItemID); err != nil {
+			slog.Warn("Failed to set item position", "item", position, "error", err)
+		}
+	}
+	r.lastItemID = itemID
+}
+
+// finish rolls back if needed, records the end of the run and prints the summary
+func (r *importRun) finish(ctx context.Context) error {
+	config := r.config
+	project := r.project
+
+	if r.rollback != nil && (r.errorCount > 0 || r.wasInterrupted) {
+		if !config.Quiet {
+			fmt.Println("Rolling back items created by this run...")
+		}
+		// The run's context is cancelled when it was interrupted, but the
+		// rollback must still go through
+		deleted, rollbackErrs := r.rollback.Run(context.WithoutCancel(ctx), r.client, project.ID, r.checkpoint, r.journal)
+		for _, err := range rollbackErrs {
+			slog.Error("Failed to roll back", "error", err)
+		}
+		if !config.Quiet {
+			fmt.Println(report.Success("Rolled back %d items", deleted))
+		}
+		if len(rollbackErrs) > 0 {
+			slog.Warn("Some items could not be rolled back and remain in the project", "count", len(rollbackErrs))
+		}
+		r.successCount -= deleted
+		var kept []report.CreatedItem
+		for _, item := range r.created {
+			if !r.rollback.Deleted(item.ItemID) {
+				kept = append(kept, item)
+			}
+		}
+		r.created = kept
+		if r.journal != nil && deleted > 0 {
+			r.saveJournal()
+		}
+	}
+
+	if r.checkpoint != nil {
+		r.checkpoint.Finished = !r.wasInterrupted
+		r.saveCheckpoint()
+	}
+
+	if !config.Quiet {
+		if r.wasInterrupted {
+			fmt.Println(report.Warning("%s before every item was imported", interruptionMessage(ctx)))
+		}
+		if r.resumedCount > 0 {
+			fmt.Println(report.Success("Skipped %d items already imported in a previous run", r.resumedCount))
+		}
+		if r.errorCount > 0 {
+			fmt.Println(report.Success("Imported %d items to \"%s\"", r.successCount, project.Title))
+			fmt.Println(report.Warning("%d items failed to import", r.errorCount))
+			if r.timeoutCount > 0 {
+				fmt.Println(report.Warning("%d items timed out", r.timeoutCount))
+			}
+			if !config.Verbose {
+				fmt.Printf("Run with --verbose for detailed error information\n")
+			}
+		} else {
+			fmt.Println(report.Success("Imported %d items to \"%s\"", r.successCount, project.Title))
+		}
+		if r.updatedCount > 0 {
+			fmt.Println(report.Success("Updated %d existing items matched by --match-key %s", r.updatedCount, config.MatchKey))
+		}
+
+		if len(r.created) > 0 {
+			fmt.Println(report.Success("Created items:"))
+			for i, item := range r.created {
+				if i == maxListedItems {
+					fmt.Printf("   ... and %d more (--report lists them all)\n", len(r.created)-i)
+					break
+				}
+				fmt.Printf("   - %s\n", createdItemLine(item))
+			}
+		}
+
+		if len(r.movedItems) > 0 {
+			fmt.Println(report.Success("Followed %d moved issues and pull requests to their new location:", len(r.movedItems)))
+			for _, moved := range r.movedItems {
+				fmt.Printf("   - %s\n", moved)
+			}
+		}
+
+		if unknown := r.importer.Users.Unknown(); len(unknown) > 0 {
+			fmt.Println(report.Warning("%d logins don't match a GitHub user, so their user fields were left empty:", len(unknown)))
+			for _, login := range unknown {
+				fmt.Printf("   - %s\n", login)
+			}
+		}
+		if created := r.importer.Milestones.Created(); len(created) > 0 {
+			fmt.Println(report.Success("Created %d milestones:", len(created)))
+			for _, name := range created {
+				fmt.Printf("   - %s\n", name)
+			}
+		}
+		if missing := r.importer.Milestones.Missing(); len(missing) > 0 {
+			fmt.Println(report.Warning("%d milestones don't exist, so they were not set (--create-milestones creates them):", len(missing)))
+			for _, name := range missing {
+				fmt.Printf("   - %s\n", name)
+			}
+		}
+		if drafts := r.importer.Milestones.Drafts(); drafts > 0 {
+			fmt.Println(report.Warning("%d draft issues have a milestone, which only issues and pull requests can have", drafts))
+		}
+		if linked := r.importer.Parents.Linked(); linked > 0 {
+			fmt.Println(report.Success("Added %d issues as sub-issues of their parent", linked))
+		}
+
+		// Field value statistics
+		fields := r.importer.Fields
+		total := fields.Total()
+		if total.Set > 0 {
+			fmt.Println(report.Success("Set %d field values", total.Set))
+		}
+		if skipped := total.SkippedTotal(); skipped > 0 {
+			fmt.Println(report.Warning("Skipped %d field values:", skipped))
+			for _, fieldName := range fields.Fields() {
+				if count := fields.Field(fieldName); count.SkippedTotal() > 0 {
+					fmt.Printf("   - \"%s\": %s, %d set\n", fieldName, count.SkipSummary(), count.Set)
+				}
+			}
+		}
+	}
+
+	if config.ErrorFile != "" {
+		if err := report.WriteErrorFile(config.ErrorFile, r.failures); err != nil {
+			slog.Warn(err.Error())
+		} else if len(r.failures) > 0 && !config.Quiet {
+			fmt.Println(report.Success("Wrote %d failed items to %s", len(r.failures), config.ErrorFile))
+		}
+	}
+
+	if config.Report != "" {
+		if err := report.WriteMarkdownReport(config.Report, r.summary(ctx)); err != nil {
+			slog.Warn(err.Error())
+		} else if !config.Quiet {
+			fmt.Println(report.Success("Wrote the run report to %s", config.Report))
+		}
+	}
+
+	if r.journal != nil && len(r.journal.Items) > 0 && !config.Quiet {
+		fmt.Println(report.Success("Recorded %d created items as run %s; delete them again with: gh project-import undo %s", len(r.journal.Items), r.journal.ID, r.journal.ID))
+	}
+
+	if config.Metrics {
+		var metrics ghclient.MetricsSummary
+		if clientOptions.Metrics != nil {
+			metrics = clientOptions.Metrics.Summary()
+		}
+		var out io.Writer = os.Stdout
+		if config.Quiet {
+			out = os.Stderr
+		}
+		report.PrintMetrics(out, metrics, r.timedItems, r.itemTime)
+	}
+
+	report.PrintResultLine(os.Stderr, r.successCount-r.updatedCount, r.updatedCount, r.errorCount, r.resumedCount, config.Checkpoint)
+	if config.results != nil {
+		config.results.add(r.report(ctx))
+	}
+
+	r.notify(ctx)
+
+	if r.wasInterrupted {
+		code := exitPartial
+		if r.successCount == 0 && r.resumedCount == 0 {
+			code = exitFailed
+		}
+		return withExitCode(code, fmt.Errorf("import stopped early: %w", ctx.Err()))
+	}
+
+	// Return an error if there were failures and no successes
+	if r.successCount == 0 && r.errorCount > 0 {
+		return withExitCode(exitFailed, fmt.Errorf("failed to import any items"))
+	}
+
+	// A partial import exits with exitPartial unless --max-failures tolerates it
+	if limit := config.failureLimit(); limit.Exceeded(r.errorCount, r.successCount+r.errorCount) {
+		if limit.flag == "" {
+			return withExitCode(exitPartial, fmt.Errorf("%d of %d items failed to import", r.errorCount, r.successCount+r.errorCount))
+		}
+		return withExitCode(exitPartial, fmt.Errorf("%d of %d items failed to import, more than %s allows", r.errorCount, r.successCount+r.errorCount, limit))
+	}
+
+	return nil
+}
+
+// notify sends the run report to the --on-complete-url and --on-complete-cmd
+// hooks. A hook that fails is logged without failing the run.
+func (r *importRun) notify(ctx context.Context) {
+	hooks, err := report.ParseCompletionHooks(r.config.OnCompleteURL, r.config.OnCompleteCmd)
+	if err != nil {
+		slog.Warn(err.Error())
+		return
+	}
+	// The run's context is cancelled when it was interrupted, which is just
+	// when a notification matters most
+	for _, err := range hooks.Notify(context.WithoutCancel(ctx), r.report(ctx)) {
+		slog.Warn("Failed to send the run report", "error", err)
+	}
+}
+
+// report describes the finished run
+func (r *importRun) report(ctx context.Context) report.RunReport {
+	finished := time.Now()
+	status := report.RunStatus(r.successCount, r.errorCount, r.wasInterrupted)
+
+	text := fmt.Sprintf("Imported %d of %d items to \"%s\"", r.successCount, r.total, r.project.Title)
+	if r.errorCount > 0 {
+		text += fmt.Sprintf(", %d failed", r.errorCount)
+	}
+	if r.wasInterrupted {
+		text += fmt.Sprintf(" (%s)", strings.ToLower(interruptionMessage(ctx)))
+	}
+
+	runReport := report.RunReport{
+		Text:         text,
+		Status:       status,
+		Sources:      r.config.Sources,
+		FromClassic:  r.config.FromClassic,
+		FromQuery:    r.config.FromQuery,
+		Project:      r.config.Project,
+		ProjectTitle: r.project.Title,
+		Total:        r.total,
+		Created:      r.successCount - r.updatedCount,
+		Updated:      r.updatedCount,
+		Failed:       r.errorCount,
+		TimedOut:     r.timeoutCount,
+		Skipped:      r.resumedCount,
+		Failures:     report.NewReportFailures(r.failures),
+		CreatedItems: append([]report.CreatedItem{}, r.created...),
+		ErrorFile:    r.config.ErrorFile,
+		Started:      r.started,
+		Finished:     finished,
+		Seconds:      finished.Sub(r.started).Seconds(),
+	}
+	if r.journal != nil && len(r.journal.Items) > 0 {
+		runReport.RunID = r.journal.ID
+	}
+	if r.config.Checkpoint != "" {
+		runReport.Checkpoint, _ = filepath.Abs(r.config.Checkpoint)
+	}
+	return runReport
+}
+
+// maxListedItems is how many created items the summary lists
+const maxListedItems = 50
+
+// createdItemLine describes a created item: the issue or pull request URL,
+// or the item ID of a draft issue, which has no URL of its own
+func createdItemLine(item report.CreatedItem) string {
+	if item.URL != "" {
+		return item.URL
+	}
+	return fmt.Sprintf("draft issue %s %q", item.ItemID, item.Title)
+}
+
+// summary describes the finished run for the --report file
+func (r *importRun) summary(ctx context.Context) report.Summary {
+	summary := report.Summary{
+		Run:        r.report(ctx),
+		ProjectURL: r.project.URL,
+	}
+	for _, fieldName := range r.importer.Fields.Fields() {
+		count := r.importer.Fields.Field(fieldName)
+		field := report.FieldSummary{Source: fieldName, Set: count.Set, Skipped: count.SkippedTotal(), SkipReasons: count.SkipSummary()}
+		if projectField, exists := r.fieldMap[fieldName]; exists {
+			field.Field = projectField.Name
+			field.Type = projectField.Type
+		}
+		summary.Fields = append(summary.Fields, field)
+	}
+	return summary
+}
+
+// interruptionMessage tells whether Ctrl-C or --timeout ended a run
+func interruptionMessage(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "Timed out"
+	}
+	return "Interrupted"
+}
+
+// errItemTimeout classifies failures caused by an item exceeding --item-timeout
+var errItemTimeout = errors.New("item import timed out")
+
+// importSingleItemWithTimeout imports a single item, giving up once the
+// configured per-item deadline passes. The deadline cancels the item's API
+// requests, and the import returns once they have stopped, so nothing of
+// the item is still changing the project or the importer when the next item
+// starts. The result has the ID of an item created before the deadline.
+func importSingleItemWithTimeout(ctx context.Context, im *importer.Importer, item parser.ImportItem, config Config) (importer.ItemResult, error) {
+	if config.ItemTimeout <= 0 {
+		return im.ImportItem(ctx, item)
+	}
+
+	itemCtx, cancel := context.WithTimeout(ctx, config.ItemTimeout)
+	defer cancel()
+	result, err := im.ImportItem(itemCtx, item)
+
+	// Only the item's own deadline is a timeout; the run's context ending
+	// means the run was interrupted
+	if err != nil && ctx.Err() == nil && errors.Is(itemCtx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%w after %s", errItemTimeout, config.ItemTimeout)
+	}
+	return result, err
+}
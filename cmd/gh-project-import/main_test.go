@@ -0,0 +1,1272 @@
+// Unit tests for the GitHub Project Import Extension
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/importer"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid config",
+			config: Config{
+				Sources: []string{"test.json"},
+				Project: "owner/project",
+				DryRun:  true,
+			},
+			expectError: false,
+		},
+		{
+			name: "verbose and quiet both set",
+			config: Config{
+				Sources: []string{"test.json"},
+				Project: "owner/project",
+				Verbose: true,
+				Quiet:   true,
+			},
+			expectError: true,
+			errorMsg:    "cannot use both --verbose and --quiet flags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test the validation logic
+			if tt.config.Verbose && tt.config.Quiet {
+				if !tt.expectError {
+					t.Errorf("Expected error for verbose and quiet both set")
+				}
+			}
+		})
+	}
+}
+
+func TestFileFormatDetection(t *testing.T) {
+	tests := []struct {
+		filename    string
+		isJSON      bool
+		isCSV       bool
+		expectError bool
+	}{
+		{"test.json", true, false, false},
+		{"test.csv", false, true, false},
+		{"test.txt", false, false, true},
+		{"test.JSON", true, false, false}, // Case insensitive
+		{"test.CSV", false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			isJSON := false
+			isCSV := false
+			isSupported := false
+
+			filename := tt.filename
+			if filepath.Ext(filename) == ".json" || filepath.Ext(filename) == ".JSON" {
+				isJSON = true
+				isSupported = true
+			} else if filepath.Ext(filename) == ".csv" || filepath.Ext(filename) == ".CSV" {
+				isCSV = true
+				isSupported = true
+			}
+
+			if isJSON != tt.isJSON {
+				t.Errorf("Expected isJSON=%v, got %v", tt.isJSON, isJSON)
+			}
+			if isCSV != tt.isCSV {
+				t.Errorf("Expected isCSV=%v, got %v", tt.isCSV, isCSV)
+			}
+			if !isSupported && !tt.expectError {
+				t.Errorf("Expected unsupported format to cause error")
+			}
+		})
+	}
+}
+
+func TestValidateImportItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       []parser.ImportItem
+		expectError bool
+	}{
+		{
+			name:        "empty items",
+			items:       []parser.ImportItem{},
+			expectError: true,
+		},
+		{
+			name: "valid items",
+			items: []parser.ImportItem{
+				{Title: "Test Item 1"},
+				{Title: "Test Item 2"},
+			},
+			expectError: false,
+		},
+		{
+			name: "item without title",
+			items: []parser.ImportItem{
+				{Title: "Test Item 1"},
+				{Title: ""}, // Missing title
+			},
+			expectError: true,
+		},
+		{
+			name: "item with invalid URL",
+			items: []parser.ImportItem{
+				{Title: "Test Item", URL: "https://example.com/not-github"},
+			},
+			expectError: true,
+		},
+		{
+			name: "item with valid GitHub URL",
+			items: []parser.ImportItem{
+				{Title: "Test Item", URL: "https://github.com/owner/repo/issues/1"},
+			},
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parser.ValidateImportItems(tt.items)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetItemType(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     parser.ImportItem
+		expected string
+	}{
+		{
+			name: "draft issue (no URL)",
+			item: parser.ImportItem{
+				Title: "Test Draft",
+			},
+			expected: "DraftIssue",
+		},
+		{
+			name: "existing issue",
+			item: parser.ImportItem{
+				Title: "Test Issue",
+				URL:   "https://github.com/owner/repo/issues/123",
+			},
+			expected: "Issue",
+		},
+		{
+			name: "pull request",
+			item: parser.ImportItem{
+				Title: "Test PR",
+				URL:   "https://github.com/owner/repo/pull/456",
+			},
+			expected: "PullRequest",
+		},
+		{
+			name: "content type specified",
+			item: parser.ImportItem{
+				Title:   "Test",
+				Content: parser.ItemContent{Type: "Issue"},
+			},
+			expected: "Issue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parser.GetItemType(tt.item)
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetItemBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     parser.ImportItem
+		expected string
+	}{
+		{
+			name: "content body",
+			item: parser.ImportItem{
+				Content: parser.ItemContent{Body: "Content body text"},
+				Notes:   "Notes text",
+			},
+			expected: "Content body text",
+		},
+		{
+			name: "notes fallback",
+			item: parser.ImportItem{
+				Content: parser.ItemContent{Body: ""},
+				Notes:   "Notes text",
+			},
+			expected: "Notes text",
+		},
+		{
+			name: "empty body",
+			item: parser.ImportItem{
+				Content: parser.ItemContent{Body: ""},
+				Notes:   "",
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parser.GetItemBody(tt.item)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestConvertFieldValue(t *testing.T) {
+	// Create a test field for each type
+	textField := ghclient.ProjectField{Name: "Text Field", Type: "TEXT"}
+	numberField := ghclient.ProjectField{Name: "Number Field", Type: "NUMBER"}
+	dateField := ghclient.ProjectField{Name: "Date Field", Type: "DATE"}
+	selectField := ghclient.ProjectField{
+		Name: "Select Field",
+		Type: "SINGLE_SELECT",
+		Options: []ghclient.ProjectFieldOption{
+			{ID: "1", Name: "Option 1"},
+			{ID: "2", Name: "Option 2"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		value       interface{}
+		field       ghclient.ProjectField
+		expectError bool
+	}{
+		// Text field tests
+		{"text field with string", "hello", textField, false},
+		{"text field with number", 42, textField, false},
+
+		// Number field tests
+		{"number field with int", 42, numberField, false},
+		{"number field with float", 42.5, numberField, false},
+		{"number field with string number", "42", numberField, false},
+		{"number field with invalid string", "not a number", numberField, true},
+
+		// Date field tests
+		{"date field with ISO date", "2023-01-01", dateField, false},
+		{"date field with full ISO", "2023-01-01T10:00:00Z", dateField, false},
+		{"date field with number", 123, dateField, true},
+
+		// Single select tests
+		{"select field with valid option", "Option 1", selectField, false},
+		{"select field with invalid option", "Option 3", selectField, true},
+		{"select field with number", 123, selectField, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mapping.ConvertFieldValue(tt.value, tt.field)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestJSONParsing(t *testing.T) {
+	// Create a temporary JSON file for testing
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "test.json")
+
+	jsonContent := `[
+		{
+			"title": "Test Item 1",
+			"status": "Open",
+			"estimate": 3
+		},
+		{
+			"title": "Test Item 2",
+			"url": "https://github.com/owner/repo/issues/123",
+			"assignees": ["user1", "user2"]
+		}
+	]`
+
+	err := os.WriteFile(jsonFile, []byte(jsonContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := parser.ParseJSONFile(jsonFile)
+	if err != nil {
+		t.Fatalf("Failed to parse JSON file: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(items))
+	}
+
+	// Test first item
+	if items[0].Title != "Test Item 1" {
+		t.Errorf("Expected title 'Test Item 1', got '%s'", items[0].Title)
+	}
+
+	if items[0].Fields["status"] != "Open" {
+		t.Errorf("Expected status 'Open', got %v", items[0].Fields["status"])
+	}
+
+	// Test second item
+	if items[1].Title != "Test Item 2" {
+		t.Errorf("Expected title 'Test Item 2', got '%s'", items[1].Title)
+	}
+
+	if len(items[1].Assignees) != 2 {
+		t.Errorf("Expected 2 assignees, got %d", len(items[1].Assignees))
+	}
+}
+
+func TestNDJSONParsing(t *testing.T) {
+	// Create a temporary NDJSON file for testing
+	tmpDir := t.TempDir()
+	ndjsonFile := filepath.Join(tmpDir, "test.ndjson")
+
+	ndjsonContent := `{"title": "Test Item 1", "status": "Open", "estimate": 3}
+
+{"title": "Test Item 2", "url": "https://github.com/owner/repo/issues/123", "assignees": ["user1", "user2"]}
+`
+
+	err := os.WriteFile(ndjsonFile, []byte(ndjsonContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := parser.ParseNDJSONFile(ndjsonFile)
+	if err != nil {
+		t.Fatalf("Failed to parse NDJSON file: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	if items[0].Fields["status"] != "Open" {
+		t.Errorf("Expected status 'Open', got %v", items[0].Fields["status"])
+	}
+
+	if len(items[1].Assignees) != 2 {
+		t.Errorf("Expected 2 assignees, got %d", len(items[1].Assignees))
+	}
+
+	// Invalid lines report the line number
+	badFile := filepath.Join(tmpDir, "bad.jsonl")
+	if err := os.WriteFile(badFile, []byte("{\"title\": \"ok\"}\n{not json}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	_, err = parser.ParseNDJSONFile(badFile)
+	if err == nil || !contains(err.Error(), "line 2") {
+		t.Errorf("Expected error mentioning line 2, got %v", err)
+	}
+}
+
+func TestCSVParsing(t *testing.T) {
+	// Create a temporary CSV file for testing
+	tmpDir := t.TempDir()
+	csvFile := filepath.Join(tmpDir, "test.csv")
+
+	csvContent := `Title,Status,Estimate,Assignees
+Test Item 1,Open,3,user1
+Test Item 2,Closed,5,"user1,user2"
+Test Item 3,In Progress,2,`
+
+	err := os.WriteFile(csvFile, []byte(csvContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := parser.ParseCSVFile(csvFile)
+	if err != nil {
+		t.Fatalf("Failed to parse CSV file: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Errorf("Expected 3 items, got %d", len(items))
+	}
+
+	// Test first item
+	if items[0].Title != "Test Item 1" {
+		t.Errorf("Expected title 'Test Item 1', got '%s'", items[0].Title)
+	}
+
+	if items[0].Fields["Status"] != "Open" {
+		t.Errorf("Expected status 'Open', got %v", items[0].Fields["Status"])
+	}
+
+	// Test item with multiple assignees
+	if len(items[1].Assignees) != 2 {
+		t.Errorf("Expected 2 assignees, got %d", len(items[1].Assignees))
+	}
+}
+
+func TestComplexFieldValidation(t *testing.T) {
+	// Create test items with various field configurations
+	items := []parser.ImportItem{
+		{
+			Title: "Item with valid fields",
+			Fields: map[string]interface{}{
+				"Status":   "Todo",
+				"Priority": "High",
+				"Estimate": 5,
+				"Notes":    "Test notes",
+				"Due Date": "2024-12-31",
+			},
+		},
+		{
+			Title: "Item with invalid fields",
+			Fields: map[string]interface{}{
+				"Status":      "InvalidStatus", // Invalid single-select option
+				"Priority":    "ValidPriority", // This should be valid
+				"Estimate":    "not-a-number",  // Invalid number
+				"Due Date":    "invalid-date",  // Invalid date format
+				"NonExistent": "value",         // Field doesn't exist
+			},
+		},
+	}
+
+	fields := []ghclient.ProjectField{
+		{ID: "field1", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{
+			{ID: "opt1", Name: "Todo"},
+			{ID: "opt2", Name: "In Progress"},
+			{ID: "opt3", Name: "Done"},
+		}},
+		{ID: "field2", Name: "Priority", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{
+			{ID: "opt4", Name: "Low"},
+			{ID: "opt5", Name: "Medium"},
+			{ID: "opt6", Name: "High"},
+		}},
+		{ID: "field3", Name: "Estimate", Type: "NUMBER"},
+		{ID: "field4", Name: "Notes", Type: "TEXT"},
+		{ID: "field5", Name: "Due Date", Type: "DATE"},
+	}
+
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+
+	warnings := mapping.ValidateItemFields(items, fieldMap, mapping.OptionMatcher{}, true)
+
+	// We should get warnings for invalid values
+	if len(warnings) == 0 {
+		t.Error("Expected validation warnings but got none")
+	}
+
+	// Check specific warning types
+	hasMissingFieldWarning := false
+
+	for _, warning := range warnings {
+		if warning.Severity == mapping.SeverityWarning && contains(warning.Message, "NonExistent") {
+			hasMissingFieldWarning = true
+		}
+		// Log all warnings for debugging
+		t.Logf("Warning: %s", warning)
+	}
+
+	if !hasMissingFieldWarning {
+		t.Error("Expected warning about non-existent field")
+	}
+
+	// Test that invalid values are detected during conversion (not in pre-validation)
+	testFieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		testFieldMap[field.Name] = field
+	}
+
+	// Test conversion errors directly
+	statusField := testFieldMap["Status"]
+	_, err := mapping.ConvertFieldValue("InvalidStatus", statusField)
+	if err == nil {
+		t.Error("Expected error for invalid single-select option")
+	}
+
+	estimateField := testFieldMap["Estimate"]
+	_, err = mapping.ConvertFieldValue("not-a-number", estimateField)
+	if err == nil {
+		t.Error("Expected error for invalid number format")
+	}
+
+	t.Logf("Validation warnings: %v", warnings)
+}
+
+func TestValidationSeverities(t *testing.T) {
+	items := []parser.ImportItem{
+		{Title: "Normalized", Fields: map[string]interface{}{"Estimate": "3", "Due Date": "2024-12-31"}},
+		{Title: "Skipped", Fields: map[string]interface{}{"Missing": "x"}},
+		{Title: "Broken", Content: parser.ItemContent{Type: "Issue"}},
+	}
+
+	fieldMap := map[string]ghclient.ProjectField{
+		"Estimate": {ID: "field1", Name: "Estimate", Type: "NUMBER"},
+		"Due Date": {ID: "field2", Name: "Due Date", Type: "DATE"},
+	}
+
+	issues := mapping.ValidateItemFields(items, fieldMap, mapping.OptionMatcher{}, false)
+
+	if got := mapping.CountValidationIssues(issues, mapping.SeverityNotice); got != 2 {
+		t.Errorf("Expected 2 notices, got %d: %v", got, issues)
+	}
+	if got := mapping.CountValidationIssues(issues, mapping.SeverityWarning); got != 1 {
+		t.Errorf("Expected 1 warning, got %d: %v", got, issues)
+	}
+	if got := mapping.CountValidationIssues(issues, mapping.SeverityError); got != 1 {
+		t.Errorf("Expected 1 error, got %d: %v", got, issues)
+	}
+}
+
+// Helper function to check if string contains substring
+func contains(str, substr string) bool {
+	return strings.Contains(str, substr)
+}
+
+// slowDraftClient is a ghclient.Client stub whose draft creation blocks for a
+// configurable delay. Methods not overridden panic if called.
+type slowDraftClient struct {
+	ghclient.Client
+	delay time.Duration
+}
+
+func (c *slowDraftClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	select {
+	case <-time.After(c.delay):
+		return "PVTI_draft", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestImportSingleItemWithTimeout(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	item := parser.ImportItem{Title: "Slow item"}
+	fieldMap := map[string]ghclient.ProjectField{}
+
+	// Item finishes within the deadline
+	client := &slowDraftClient{delay: 0}
+	im, _ := importer.New(client, project, fieldMap, importer.Options{})
+	_, err := importSingleItemWithTimeout(context.Background(), im, item, Config{ItemTimeout: time.Second})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	// Item exceeds the deadline
+	client = &slowDraftClient{delay: 200 * time.Millisecond}
+	im, _ = importer.New(client, project, fieldMap, importer.Options{})
+	_, err = importSingleItemWithTimeout(context.Background(), im, item, Config{ItemTimeout: 10 * time.Millisecond})
+	if !errors.Is(err, errItemTimeout) {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+
+	// A failed item doesn't stop the rest of the run
+	err = importItems(context.Background(), client, project, []parser.ImportItem{item, item}, fieldMap, Config{Quiet: true, ItemTimeout: 10 * time.Millisecond})
+	if err == nil || !contains(err.Error(), "failed to import any items") {
+		t.Errorf("Expected all items to fail with timeouts, got: %v", err)
+	}
+}
+
+// lingeringDraftClient is a ghclient.Client stub whose draft creation only
+// returns a while after its context is cancelled, like a request that is
+// slow to stop
+type lingeringDraftClient struct {
+	ghclient.Client
+	linger   time.Duration
+	returned bool
+}
+
+func (c *lingeringDraftClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	<-ctx.Done()
+	time.Sleep(c.linger)
+	c.returned = true
+	return "", ctx.Err()
+}
+
+// TestImportSingleItemWithTimeoutWaitsForItem checks, when run with -race,
+// that a timed out item is no longer running once the next one can start
+func TestImportSingleItemWithTimeoutWaitsForItem(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	client := &lingeringDraftClient{linger: 1500 * time.Millisecond}
+	im, _ := importer.New(client, project, map[string]ghclient.ProjectField{}, importer.Options{})
+
+	_, err := importSingleItemWithTimeout(context.Background(), im, parser.ImportItem{Title: "Slow item"}, Config{ItemTimeout: 10 * time.Millisecond})
+	if !errors.Is(err, errItemTimeout) {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+	if !client.returned {
+		t.Error("Expected the item's import to have returned before the timeout was reported")
+	}
+}
+
+// interruptingClient is a ghclient.Client stub that creates drafts and cancels
+// the run once it has created a number of them, as Ctrl-C would
+type interruptingClient struct {
+	chunkClient
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *interruptingClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	id, err := c.chunkClient.CreateDraftIssue(ctx, projectID, title, body)
+	if len(c.drafts) == c.after {
+		c.cancel()
+	}
+	return id, err
+}
+
+func TestImportItemsInterrupted(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "One"}, {Title: "Two"}, {Title: "Three"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &interruptingClient{after: 2, cancel: cancel}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = importItems(ctx, client, project, items, map[string]ghclient.ProjectField{}, Config{})
+	})
+	if !errors.Is(err, context.Canceled) || exitCode(err) != exitPartial {
+		t.Errorf("Expected the run to stop as cancelled with a partial import, got: %v", err)
+	}
+	if len(client.drafts) != 2 {
+		t.Errorf("Expected the run to stop after 2 items, got %v", client.drafts)
+	}
+	if !contains(output, "Interrupted before every item was imported") || !contains(output, "Imported 2 items") {
+		t.Errorf("Expected a summary of the partial run, got:\n%s", output)
+	}
+
+	// An item cut short by the interruption doesn't count as failed
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := &slowDraftClient{delay: time.Minute}
+	output = captureStdout(t, func() {
+		err = importItems(ctx, slow, project, items, map[string]ghclient.ProjectField{}, Config{ItemTimeout: time.Minute})
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the run to time out, got: %v", err)
+	}
+	if !contains(output, "Timed out before every item was imported") || contains(output, "failed to import") {
+		t.Errorf("Expected a timed out run without failed items, got:\n%s", output)
+	}
+}
+
+func TestImportItemsBatchPause(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "One"}, {Title: "Two"}, {Title: "Three"}, {Title: "Four"}, {Title: "Five"}}
+
+	client := &chunkClient{}
+	var err error
+	output := captureStdout(t, func() {
+		err = importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, Config{BatchSize: 2, BatchPause: time.Millisecond})
+	})
+	if err != nil || len(client.drafts) != 5 {
+		t.Fatalf("Expected every item to be imported, got %v with %v", err, client.drafts)
+	}
+	// Nothing is left to wait for after the last batch
+	if pauses := strings.Count(output, "Pausing for 1ms after 2 items"); pauses != 2 {
+		t.Errorf("Expected 2 pauses, got %d in:\n%s", pauses, output)
+	}
+	if first, pause := strings.Index(output, "Importing item 3/5"), strings.Index(output, "Pausing"); pause < 0 || pause > first {
+		t.Errorf("Expected a pause before the third item, got:\n%s", output)
+	}
+
+	// The run can be interrupted while it waits
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	client = &chunkClient{}
+	captureStdout(t, func() {
+		err = importItems(ctx, client, project, items, map[string]ghclient.ProjectField{}, Config{BatchSize: 2, BatchPause: time.Hour})
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || exitCode(err) != exitPartial || len(client.drafts) != 2 {
+		t.Errorf("Expected the run to stop during the pause after 2 items, got %v with %v", err, client.drafts)
+	}
+}
+
+// captureStdout runs fn and returns everything it printed to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read captured output: %v", err)
+	}
+	return string(out)
+}
+
+func TestImportItemsTSVOutput(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{
+		{Title: "First\tdraft"},
+		{Title: "Second draft"},
+	}
+
+	client := &countingDraftClient{}
+	out := captureStdout(t, func() {
+		if err := importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, Config{Quiet: true, Output: "tsv"}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+
+	expected := "imported\tPVTI_1\t\tFirst draft\nimported\tPVTI_2\t\tSecond draft\n"
+	if out != expected {
+		t.Errorf("Expected TSV output %q, got %q", expected, out)
+	}
+}
+
+func TestFilterItemFields(t *testing.T) {
+	newItems := func() []parser.ImportItem {
+		return []parser.ImportItem{
+			{Title: "A", Fields: map[string]interface{}{"Status": "Todo", "Iteration": "Sprint 1", "Estimate": 3, "Estimate (days)": 1}},
+			{Title: "B", Fields: map[string]interface{}{"status": "Done", "Priority": "High"}},
+		}
+	}
+
+	fieldNames := func(item parser.ImportItem) []string {
+		var names []string
+		for name := range item.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		only     []string
+		skip     []string
+		expected [][]string
+	}{
+		{
+			name:     "only",
+			only:     []string{"Status", "iteration"},
+			expected: [][]string{{"Iteration", "Status"}, {"status"}},
+		},
+		{
+			name:     "skip glob",
+			skip:     []string{"Estimate*"},
+			expected: [][]string{{"Iteration", "Status"}, {"Priority", "status"}},
+		},
+		{
+			name:     "only and skip",
+			only:     []string{"*"},
+			skip:     []string{"priority"},
+			expected: [][]string{{"Estimate", "Estimate (days)", "Iteration", "Status"}, {"status"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := newItems()
+			mapping.FilterItemFields(items, tt.only, tt.skip)
+			for i, item := range items {
+				if got := fieldNames(item); !reflect.DeepEqual(got, tt.expected[i]) {
+					t.Errorf("Item %d: expected fields %v, got %v", i, tt.expected[i], got)
+				}
+			}
+		})
+	}
+
+	if err := mapping.ValidateFieldPatterns([]string{"Status", "[bad"}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestSelectItems(t *testing.T) {
+	items := make([]parser.ImportItem, 10)
+	for i := range items {
+		items[i] = parser.ImportItem{Title: fmt.Sprintf("Item %d", i+1)}
+	}
+
+	titles := func(items []parser.ImportItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Title)
+		}
+		return result
+	}
+
+	// reversePerm makes sampling deterministic: it picks the last items
+	reversePerm := func(n int) []int {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = n - 1 - i
+		}
+		return perm
+	}
+
+	tests := []struct {
+		name                string
+		skip, limit, sample int
+		expected            []string
+	}{
+		{"no selection", 0, 0, 0, titles(items)},
+		{"limit", 0, 2, 0, []string{"Item 1", "Item 2"}},
+		{"skip", 8, 0, 0, []string{"Item 9", "Item 10"}},
+		{"skip and limit", 3, 2, 0, []string{"Item 4", "Item 5"}},
+		{"skip past end", 20, 0, 0, nil},
+		{"sample keeps source order", 0, 0, 3, []string{"Item 8", "Item 9", "Item 10"}},
+		{"sample within limit", 0, 5, 2, []string{"Item 4", "Item 5"}},
+		{"sample larger than items", 0, 2, 5, []string{"Item 1", "Item 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titles(selectItems(items, tt.skip, tt.limit, tt.sample, reversePerm))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// movedIssueClient is a ghclient.Client stub that resolves every issue to a transferred copy
+type movedIssueClient struct {
+	ghclient.Client
+}
+
+func (c *movedIssueClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"node_id":  "I_moved",
+		"html_url": "https://github.com/new-org/repo/issues/12",
+	}, nil
+}
+
+func (c *movedIssueClient) CreateProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	return "PVTI_moved", nil
+}
+
+func TestImportMovedIssue(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	item := parser.ImportItem{Title: "Transferred", URL: "https://github.com/old-org/repo/issues/7", Content: parser.ItemContent{Type: "Issue"}}
+
+	out := captureStdout(t, func() {
+		if err := importItems(context.Background(), &movedIssueClient{}, project, []parser.ImportItem{item}, map[string]ghclient.ProjectField{}, Config{}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+
+	expected := "https://github.com/old-org/repo/issues/7 → https://github.com/new-org/repo/issues/12"
+	if !contains(out, expected) {
+		t.Errorf("Expected report to contain %q, got:\n%s", expected, out)
+	}
+}
+
+func TestImportItemsPrintsMetrics(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "First"}, {Title: "fail"}, {Title: "Second"}}
+
+	output := captureStdout(t, func() {
+		importItems(context.Background(), &rollbackClient{}, project, items, map[string]ghclient.ProjectField{}, Config{Metrics: true})
+	})
+	if !contains(output, "Metrics:") || !contains(output, "(3 items)") {
+		t.Errorf("Expected metrics covering every item, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		importItems(context.Background(), &rollbackClient{}, project, items, map[string]ghclient.ProjectField{}, Config{})
+	})
+	if contains(output, "Metrics:") {
+		t.Errorf("Expected no metrics without --metrics, got:\n%s", output)
+	}
+}
+
+func TestImportItemsPostsRunReport(t *testing.T) {
+	var posted report.RunReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("Expected a JSON report: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "First"}, {Title: "fail"}, {Title: "Second"}}
+
+	captureStdout(t, func() {
+		importItems(context.Background(), &rollbackClient{}, project, items, map[string]ghclient.ProjectField{}, Config{OnCompleteURL: server.URL})
+	})
+	if posted.Status != report.StatusPartial || posted.Total != 3 || posted.Created != 2 || posted.Failed != 1 {
+		t.Errorf("Expected the report of a partly failed run, got %+v", posted)
+	}
+	if len(posted.Failures) != 1 || posted.Failures[0].Title != "fail" {
+		t.Errorf("Expected the failed item in the report, got %+v", posted.Failures)
+	}
+	if posted.Text != `Imported 2 of 3 items to "Test Project", 1 failed` {
+		t.Errorf("Unexpected summary text %q", posted.Text)
+	}
+	if len(posted.CreatedItems) != 2 || posted.CreatedItems[0].ItemID != "PVTI_1" || posted.CreatedItems[1].Title != "Second" {
+		t.Errorf("Expected the created items in the report, got %+v", posted.CreatedItems)
+	}
+}
+
+func TestImportItemsListsCreatedItems(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+	fake.AddIssue("https://github.com/acme/app/issues/2", "Fix login", "")
+	items := []parser.ImportItem{{Title: "Plan launch"}, {Title: "Fix login", URL: "https://github.com/acme/app/issues/2"}}
+
+	output := captureStdout(t, func() {
+		importItems(context.Background(), fake, &project.Project, items, map[string]ghclient.ProjectField{}, Config{})
+	})
+	draftID := fake.Project(project.ID).Items[0].ID
+	for _, expected := range []string{"✓ Created items:", "   - draft issue " + draftID + ` "Plan launch"`, "   - https://github.com/acme/app/issues/2"} {
+		if !contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	many := make([]parser.ImportItem, maxListedItems+2)
+	for i := range many {
+		many[i].Title = fmt.Sprintf("Item %d", i+1)
+	}
+	output = captureStdout(t, func() {
+		importItems(context.Background(), fake, &project.Project, many, map[string]ghclient.ProjectField{}, Config{})
+	})
+	if !contains(output, "   ... and 2 more (--report lists them all)") || contains(output, `"Item 51"`) {
+		t.Errorf("Expected the list to stop after %d items, got:\n%s", maxListedItems, output)
+	}
+}
+
+func TestImportItemsWritesMarkdownReport(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap", URL: "https://github.com/orgs/acme/projects/1"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "TEXT"},
+	)
+	fake.AddIssue("https://github.com/acme/app/issues/2", "Second", "")
+	items := []parser.ImportItem{
+		{Title: "First", Fields: map[string]interface{}{"Status": "Todo", "Owner": "hubot"}},
+		{Title: "Missing", URL: "https://github.com/acme/app/issues/404", Fields: map[string]interface{}{"Status": "Todo"}},
+		{Title: "Second", URL: "https://github.com/acme/app/issues/2"},
+	}
+	fieldMap := map[string]ghclient.ProjectField{"Status": project.Fields[0]}
+	path := filepath.Join(t.TempDir(), "report.md")
+
+	captureStdout(t, func() {
+		importItems(context.Background(), fake, &project.Project, items, fieldMap, Config{Report: path})
+	})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the report to be written: %v", err)
+	}
+	for _, expected := range []string{
+		"| Owner | *not in project* |  | 0 | 1 (1 missing field) |",
+		"| Status | Status | TEXT | 1 | 0 |",
+		"| [Missing](<https://github.com/acme/app/issues/404>) |",
+		"- [First](<https://github.com/orgs/acme/projects/1>) (draft issue)",
+		"- [Second](<https://github.com/acme/app/issues/2>)",
+	} {
+		if !contains(string(data), expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, data)
+		}
+	}
+}
+
+// useClient makes the commands use client instead of a real GitHub client
+// for the rest of the test
+func useClient(t *testing.T, client ghclient.Client) {
+	t.Helper()
+	previous := newClient
+	newClient = func() (ghclient.Client, error) { return client, nil }
+	t.Cleanup(func() { newClient = previous })
+}
+
+func TestRunImport(t *testing.T) {
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_todo", Name: "Todo"}}},
+	)
+	useClient(t, fake)
+
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title,Status\nPlan launch,Todo\nWrite docs,Todo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+	var err error
+	captureStdout(t, func() {
+		err = runImport(context.Background(), config, strings.NewReader(""))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Items) != 2 || project.Items[1].Fields["Status"] != "Todo" {
+		t.Errorf("Expected 2 items with a status, got %+v", project.Items)
+	}
+}
+
+func TestRunImportProvenanceField(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "items.csv")
+	if err := os.WriteFile(source, []byte("Title\nPlan launch\nWrite docs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(fields ...ghclient.ProjectField) (*ghclient.FakeProject, error) {
+		fake := ghclient.NewFakeClient()
+		project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"}, fields...)
+		useClient(t, fake)
+		config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, ProvenanceField: "Imported From", MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		var err error
+		captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		return project, err
+	}
+
+	project, err := run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Fields) != 1 || project.Fields[0].Name != "Imported From" || project.Fields[0].Type != "TEXT" {
+		t.Fatalf("Expected the text field to be created, got %+v", project.Fields)
+	}
+	stamp := regexp.MustCompile(`^items\.csv, run \d{8}-\d{6}-[0-9a-f]{4}$`)
+	for _, item := range project.Items {
+		if value, _ := item.Fields["Imported From"].(string); !stamp.MatchString(value) {
+			t.Errorf("Expected %q to be stamped with the source and run ID, got %q", item.Content["title"], value)
+		}
+	}
+
+	// An existing field is used rather than created again
+	project, err = run(ghclient.ProjectField{ID: "F_from", Name: "Imported From", Type: "TEXT"})
+	if err != nil || len(project.Fields) != 1 || project.Items[0].Fields["Imported From"] == nil {
+		t.Errorf("Expected the existing field to be stamped, got %v with fields %+v", err, project.Fields)
+	}
+
+	project, err = run(ghclient.ProjectField{ID: "F_from", Name: "Imported From", Type: "NUMBER"})
+	if exitCode(err) != exitInvalid || len(project.Items) != 0 {
+		t.Errorf("Expected a field that isn't text to fail validation, got %v with %d items", err, len(project.Items))
+	}
+}
+
+func TestRunImportPrefetchesIssues(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"})
+	fake.AddIssue("https://github.com/acme/app/issues/1", "Fix login", "")
+	fake.AddIssue("https://github.com/acme/app/issues/2", "Fix logout", "")
+	useClient(t, fake)
+	if err := os.WriteFile(source, []byte("Title,URL\nFix login,https://github.com/acme/app/issues/1\nPlan launch,\nFix logout,https://github.com/acme/app/issues/2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+	var err error
+	captureStdout(t, func() {
+		err = runImport(context.Background(), config, strings.NewReader(""))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(project.Items))
+	}
+	lookups := 0
+	for _, call := range fake.Calls {
+		switch call {
+		case "GetIssueOrPR":
+			t.Errorf("Expected the issues to come from the prefetch, got %v", fake.Calls)
+		case "GetIssuesOrPRs":
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected a single batched lookup, got %d", lookups)
+	}
+}
+
+func TestRunImportMatchKey(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "items.csv")
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_key", Name: "Jira Key", Type: "TEXT"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "TEXT"},
+	)
+	useClient(t, fake)
+	run := func(contents, matchKey string) error {
+		if err := os.WriteFile(source, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, MatchKey: matchKey, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		var err error
+		captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		return err
+	}
+
+	if err := run("Title,Jira Key,Status\nPlan launch,ABC-1,Todo\nWrite docs,ABC-2,Todo\n", "field:Jira Key"); err != nil {
+		t.Fatal(err)
+	}
+	// The second run renames ABC-1, moves ABC-2 along and adds ABC-3
+	if err := run("Title,Jira Key,Status\nPlan the launch,ABC-1,Todo\nWrite docs,ABC-2,Done\nShip it,ABC-3,Todo\n", "field:Jira Key"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(project.Items) != 3 {
+		t.Fatalf("Expected matching items to be updated rather than added again, got %d items", len(project.Items))
+	}
+	expected := []struct{ title, status string }{{"Plan the launch", "Todo"}, {"Write docs", "Done"}, {"Ship it", "Todo"}}
+	for i, item := range project.Items {
+		if item.Content["title"] != expected[i].title || item.Fields["Status"] != expected[i].status {
+			t.Errorf("Item %d: expected %q with status %s, got %v with %v", i, expected[i].title, expected[i].status, item.Content["title"], item.Fields["Status"])
+		}
+	}
+
+	if err := run("Title\nPlan launch\n", "field:Missing"); exitCode(err) != exitInvalid {
+		t.Errorf("Expected a key field missing from the project to fail validation, got %v", err)
+	}
+	if err := run("Title\nPlan launch\n", "id"); err == nil {
+		t.Error("Expected an error for an unknown match key")
+	}
+	if len(project.Items) != 3 {
+		t.Errorf("Expected failed runs not to add items, got %d items", len(project.Items))
+	}
+}
+
+func TestRunImportExternalIDField(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	fake := ghclient.NewFakeClient()
+	project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "TEXT"},
+	)
+	useClient(t, fake)
+	run := func(contents string, modify func(*Config)) error {
+		if err := os.WriteFile(source, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, ExternalIDField: "External ID", MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		if modify != nil {
+			modify(&config)
+		}
+		var err error
+		captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		return err
+	}
+
+	// The field is created by the first run, and kept despite --only-fields
+	onlyStatus := func(c *Config) { c.OnlyFields = []string{"Status"}; c.StrictFields = true }
+	if err := run("Title,external_id,Status\nPlan launch,101,Todo\nWrite docs,102,Todo\n", onlyStatus); err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Fields) != 2 || project.Fields[1].Name != "External ID" || project.Fields[1].Type != "TEXT" {
+		t.Fatalf("Expected the text field to be created, got %+v", project.Fields)
+	}
+	if err := run("Title,external_id,Status\nPlan the launch,101,Done\nWrite docs,102,Todo\nShip it,103,Todo\n", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(project.Items) != 3 {
+		t.Fatalf("Expected items with a known ID to be updated rather than added again, got %d items", len(project.Items))
+	}
+	expected := []struct{ title, id, status string }{{"Plan the launch", "101", "Done"}, {"Write docs", "102", "Todo"}, {"Ship it", "103", "Todo"}}
+	for i, item := range project.Items {
+		if item.Content["title"] != expected[i].title || item.Fields["External ID"] != expected[i].id || item.Fields["Status"] != expected[i].status {
+			t.Errorf("Item %d: expected %+v, got %v with fields %v", i, expected[i], item.Content["title"], item.Fields)
+		}
+	}
+
+	if err := run("Title\nPlan launch\n", func(c *Config) { c.MatchKey = "title" }); err == nil {
+		t.Error("Expected --match-key on another key to be rejected")
+	}
+	if err := run("Title\nPlan launch\n", func(c *Config) { c.ExternalIDField = "Status"; c.MatchKey = "" }); err != nil {
+		t.Errorf("Expected an existing text field to be used, got %v", err)
+	}
+	fake.AddProject("acme", ghclient.Project{Title: "Numbers"}, ghclient.ProjectField{ID: "F_n", Name: "External ID", Type: "NUMBER"})
+	if err := run("Title\nPlan launch\n", func(c *Config) { c.Project = "acme/Numbers" }); exitCode(err) != exitInvalid {
+		t.Errorf("Expected a field that isn't text to fail validation, got %v", err)
+	}
+}
+
+func TestRunImportStrictFields(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title,Status\nPlan launch,Todo\nWrite docs,Blocked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, chunkSize := range []int{0, 1} {
+		run := func(strict bool) (*ghclient.FakeProject, error) {
+			fake := ghclient.NewFakeClient()
+			project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+				ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_todo", Name: "Todo"}}},
+			)
+			useClient(t, fake)
+			config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory", ChunkSize: chunkSize, StrictFields: strict}
+			var err error
+			captureStdout(t, func() {
+				err = runImport(context.Background(), config, strings.NewReader(""))
+			})
+			return project, err
+		}
+
+		// Only the first Status value is checked without --strict-fields
+		project, err := run(false)
+		if err != nil || len(project.Items) != 2 {
+			t.Fatalf("chunk size %d: expected 2 items imported without --strict-fields, got %d items and error %v", chunkSize, len(project.Items), err)
+		}
+
+		project, err = run(true)
+		if exitCode(err) != exitInvalid || !strings.Contains(err.Error(), "--strict-fields") {
+			t.Errorf("chunk size %d: expected a --strict-fields validation error, got %v", chunkSize, err)
+		}
+		if len(project.Items) != 0 {
+			t.Errorf("chunk size %d: expected nothing imported with --strict-fields, got %+v", chunkSize, project.Items)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Ordering of imported items
+// Sorts items by a field for --order-by, so that --preserve-order lays them out on the board in that order
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// orderKey is the sort key of an item's value
+type orderKey struct {
+	missing bool
+	rank    int // Position of the single-select option, or -1
+	number  float64
+	numeric bool
+	text    string
+}
+
+// less orders values by option position, then as numbers, then as text,
+// with missing values last
+func (k orderKey) less(other orderKey) bool {
+	switch {
+	case k.missing != other.missing:
+		return other.missing
+	case (k.rank >= 0) != (other.rank >= 0):
+		return k.rank >= 0
+	case k.rank != other.rank:
+		return k.rank < other.rank
+	case k.numeric && other.numeric:
+		return k.number < other.number
+	case k.numeric != other.numeric:
+		return k.numeric
+	default:
+		return k.text < other.text
+	}
+}
+
+// sortItemsByField orders items by the value of the named field, matched
+// ignoring case, keeping the source order of equal values. Single-select
+// values sort in the order of the project's options and iterations by start
+// date.
+func sortItemsByField(items []parser.ImportItem, name string, fieldMap map[string]ghclient.ProjectField, matcher mapping.OptionMatcher) {
+	var field ghclient.ProjectField
+	for fieldName, candidate := range fieldMap {
+		if strings.EqualFold(fieldName, name) {
+			field = candidate
+		}
+	}
+
+	keys := make([]orderKey, len(items))
+	for i, item := range items {
+		keys[i] = itemOrderKey(lookupField(item, name), field, matcher)
+	}
+
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return keys[indexes[a]].less(keys[indexes[b]])
+	})
+
+	sorted := make([]parser.ImportItem, len(items))
+	for i, index := range indexes {
+		sorted[i] = items[index]
+	}
+	copy(items, sorted)
+}
+
+// itemOrderKey builds the sort key of a value of field
+func itemOrderKey(value string, field ghclient.ProjectField, matcher mapping.OptionMatcher) orderKey {
+	value = strings.TrimSpace(value)
+	key := orderKey{missing: value == "", rank: -1, text: strings.ToLower(value)}
+
+	switch field.Type {
+	case "SINGLE_SELECT":
+		if option, ok := matcher.Find(field, value); ok {
+			for i, candidate := range field.Options {
+				if candidate.ID == option.ID {
+					key.rank = i
+				}
+			}
+		}
+	case "ITERATION":
+		if iteration, ok := mapping.FindIteration(field.Iterations, value); ok {
+			key.text = iteration.StartDate
+		}
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		key.number, key.numeric = number, true
+	}
+	return key
+}
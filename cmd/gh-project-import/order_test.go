@@ -0,0 +1,74 @@
+// Tests for ordering imported items
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func itemTitles(items []parser.ImportItem) string {
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = item.Title
+	}
+	return strings.Join(titles, ",")
+}
+
+func TestSortItemsByField(t *testing.T) {
+	fieldMap := map[string]ghclient.ProjectField{
+		"Priority": {Name: "Priority", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "p1", Name: "High"}, {ID: "p2", Name: "Medium"}, {ID: "p3", Name: "Low"}}},
+	}
+
+	items := []parser.ImportItem{
+		{Title: "A", Fields: map[string]interface{}{"Priority": "Low"}},
+		{Title: "B", Fields: map[string]interface{}{}},
+		{Title: "C", Fields: map[string]interface{}{"Priority": "high"}},
+		{Title: "D", Fields: map[string]interface{}{"Priority": "Low"}},
+		{Title: "E", Fields: map[string]interface{}{"Priority": "Medium"}},
+	}
+	sortItemsByField(items, "priority", fieldMap, mapping.OptionMatcher{})
+	if titles := itemTitles(items); titles != "C,E,A,D,B" {
+		t.Errorf("Expected option order with ties in source order, got %s", titles)
+	}
+
+	items = []parser.ImportItem{
+		{Title: "A", Fields: map[string]interface{}{"Rank": int64(10)}},
+		{Title: "B", Fields: map[string]interface{}{"Rank": "2"}},
+		{Title: "C", Fields: map[string]interface{}{"Rank": "n/a"}},
+		{Title: "D", Fields: map[string]interface{}{"Rank": 2.5}},
+	}
+	sortItemsByField(items, "Rank", fieldMap, mapping.OptionMatcher{})
+	if titles := itemTitles(items); titles != "B,D,A,C" {
+		t.Errorf("Expected numbers in numeric order before text, got %s", titles)
+	}
+}
+
+// positionClient is a ghclient.Client stub that records item positions
+type positionClient struct {
+	chunkClient
+	moves []string
+}
+
+func (c *positionClient) SetProjectItemPosition(ctx context.Context, projectID, itemID, afterID string) error {
+	c.moves = append(c.moves, fmt.Sprintf("%s>%s", afterID, itemID))
+	return nil
+}
+
+func TestImportItemsPreserveOrder(t *testing.T) {
+	client := &positionClient{}
+	project := &ghclient.Project{ID: "PVT_1"}
+	items := []parser.ImportItem{{Title: "One"}, {Title: "Two"}, {Title: "Three"}}
+
+	if err := importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, Config{Quiet: true, PreserveOrder: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if moves := strings.Join(client.moves, ","); moves != "PVTI_1>PVTI_2,PVTI_2>PVTI_3" {
+		t.Errorf("Expected each item to follow the previous one, got %s", moves)
+	}
+}
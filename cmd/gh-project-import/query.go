@@ -0,0 +1,47 @@
+// Search query sources
+// Converts the issues and pull requests an issue search finds into import items (--from-query)
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// searchQuery returns the query to run for --from-query. Results are sorted
+// oldest first unless the query picks its own order, so a resumed run sees
+// the items in the same order.
+func searchQuery(query string) string {
+	for _, term := range strings.Fields(query) {
+		if strings.HasPrefix(term, "sort:") {
+			return query
+		}
+	}
+	return strings.TrimSpace(query) + " sort:created-asc"
+}
+
+// FetchQueryItems runs an issue search and converts the results into import
+// items that add the existing issues and pull requests to the project
+func FetchQueryItems(ctx context.Context, client ghclient.Client, query string) ([]parser.ImportItem, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("invalid --from-query: empty query")
+	}
+
+	results, err := client.SearchIssues(ctx, searchQuery(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %q: %w", query, err)
+	}
+
+	items := make([]parser.ImportItem, len(results))
+	for i, result := range results {
+		items[i] = parser.ImportItem{
+			Title:  result.Title,
+			URL:    result.URL,
+			Fields: map[string]interface{}{},
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,43 @@
+// Tests for search query sources
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"repo:acme/api is:issue label:triage", "repo:acme/api is:issue label:triage sort:created-asc"},
+		{"repo:acme/api sort:updated-desc", "repo:acme/api sort:updated-desc"},
+	}
+	for _, tt := range tests {
+		if got := searchQuery(tt.query); got != tt.want {
+			t.Errorf("searchQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFetchQueryItems(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.Searches["repo:acme/api is:issue label:triage sort:created-asc"] = []ghclient.SearchResult{
+		{Title: "Crash on login", URL: "https://github.com/acme/api/issues/4", Repository: "acme/api", Type: "Issue"},
+		{Title: "Fix login", URL: "https://github.com/acme/api/pull/5", Repository: "acme/api", Type: "PullRequest"},
+	}
+
+	items, err := FetchQueryItems(context.Background(), client, "repo:acme/api is:issue label:triage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Title != "Crash on login" || items[1].URL != "https://github.com/acme/api/pull/5" {
+		t.Errorf("Expected the search results as items, got %+v", items)
+	}
+
+	if _, err := FetchQueryItems(context.Background(), client, "  "); err == nil {
+		t.Error("Expected an error for an empty query")
+	}
+}
@@ -0,0 +1,118 @@
+// Destination project resolution
+// Finds the project to import into by number, by approximate title or by asking which of several matches to use
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// resolveProject finds the --project to import into. --project-number picks
+// a project of the --project owner by number; otherwise the title is matched
+// with ghclient.MatchProject. A title that matches several projects is
+// settled by asking on in, and so is a single match that differs by more
+// than case. Closed projects are only used with
+// --include-closed or --reopen; the import reopens them with --reopen.
+func resolveProject(ctx context.Context, client ghclient.Client, config Config, in io.Reader) (*ghclient.Project, error) {
+	identifier := config.Project
+	owner, name, hasOwner := strings.Cut(config.Project, "/")
+
+	if config.ProjectNumber > 0 {
+		identifier = fmt.Sprintf("%s/#%d", owner, config.ProjectNumber)
+	} else if hasOwner && !strings.HasPrefix(name, "#") {
+		projects, err := client.ListProjects(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		project, err := ghclient.MatchProject(projects, owner, name, config.IncludeClosed || config.Reopen)
+		var ambiguous *ghclient.AmbiguousProjectError
+		if errors.As(err, &ambiguous) {
+			project, err = chooseProject(in, config, ambiguous)
+		} else if err == nil && !strings.EqualFold(project.Title, name) {
+			project, err = confirmClosestProject(in, config, owner, name, project)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if project.Title != name {
+			slog.Info("Using the closest matching project", "project", config.Project, "title", project.Title, "number", project.Number)
+		}
+		// The project is looked up again by number so the guard rails
+		// see it resolved like any other identifier
+		identifier = fmt.Sprintf("%s/#%d", owner, project.Number)
+	}
+
+	project, err := client.FindProject(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if project.Closed {
+		switch {
+		case config.Reopen:
+			slog.Info("The project is closed and will be reopened before importing", "project", project.Title)
+		case !config.IncludeClosed:
+			return nil, fmt.Errorf("project %q is closed (use --reopen to reopen it before importing, or --include-closed to import into it as is)", project.Title)
+		}
+	}
+	return project, nil
+}
+
+// chooseProject asks on in which of the projects matching an ambiguous title
+// to use. With --yes, without a terminal or without a valid answer, it fails
+// and lists the candidates instead.
+func chooseProject(in io.Reader, config Config, ambiguous *ghclient.AmbiguousProjectError) (*ghclient.Project, error) {
+	failure := fmt.Errorf("%w; pick one with --project-number", ambiguous)
+	if config.Yes || config.noTerminal {
+		return nil, failure
+	}
+
+	var out io.Writer = os.Stdout
+	if config.Quiet {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "%d projects of %s match %q:\n", len(ambiguous.Candidates), ambiguous.Owner, ambiguous.Name)
+	for i, project := range ambiguous.Candidates {
+		closed := ""
+		if project.Closed {
+			closed = " (closed)"
+		}
+		fmt.Fprintf(out, "  %d) #%d %s%s\n", i+1, project.Number, project.Title, closed)
+	}
+	fmt.Fprintf(out, "Project to import into [1-%d]: ", len(ambiguous.Candidates))
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(ambiguous.Candidates) {
+		return nil, failure
+	}
+	return &ambiguous.Candidates[choice-1], nil
+}
+
+// confirmClosestProject asks on in whether to use project, the only project
+// whose title approximately matches name. With --yes or without a terminal
+// nobody can confirm the guess, so it fails and names the project for
+// --project-number instead.
+func confirmClosestProject(in io.Reader, config Config, owner, name string, project *ghclient.Project) (*ghclient.Project, error) {
+	failure := fmt.Errorf("no project of %s is titled %q; the closest match is #%d %q, pick it with --project-number %d", owner, name, project.Number, project.Title, project.Number)
+	if config.Yes || config.noTerminal {
+		return nil, failure
+	}
+
+	var out io.Writer = os.Stdout
+	if config.Quiet {
+		out = os.Stderr
+	}
+	if !confirm(in, out, fmt.Sprintf("No project of %s is titled %q. Import into #%d %q?", owner, name, project.Number, project.Title)) {
+		return nil, failure
+	}
+	return project, nil
+}
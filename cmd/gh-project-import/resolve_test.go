@@ -0,0 +1,139 @@
+// Tests for destination project resolution
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestResolveProject(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.AddProject("acme", ghclient.Project{Title: "Roadmap 2024", Number: 1, Closed: true})
+	client.AddProject("acme", ghclient.Project{Title: "Roadmap 2025", Number: 2})
+	client.AddProject("acme", ghclient.Project{Title: "Roadmap Q3", Number: 3})
+
+	tests := []struct {
+		config Config
+		input  string
+		want   int // Project number, 0 for an error
+	}{
+		{Config{Project: "acme/Roadmap 2025"}, "", 2},
+		{Config{Project: "acme/roadmap 2024"}, "", 0},
+		{Config{Project: "acme/roadmap 2024", IncludeClosed: true}, "", 1},
+		{Config{Project: "acme/roadmap"}, "2\n", 3},
+		{Config{Project: "acme/roadmap"}, "7\n", 0},
+		{Config{Project: "acme/roadmap", Yes: true}, "2\n", 0},
+		{Config{Project: "acme/Q3"}, "y\n", 3},
+		{Config{Project: "acme/Q3"}, "", 0},
+		{Config{Project: "acme/Q3", Yes: true}, "y\n", 0},
+		{Config{Project: "acme/anything", ProjectNumber: 3}, "", 3},
+		{Config{Project: "acme", ProjectNumber: 1}, "", 0},
+		{Config{Project: "acme", ProjectNumber: 1, IncludeClosed: true}, "", 1},
+		{Config{Project: "acme/roadmap 2024", Reopen: true}, "", 1},
+		{Config{Project: "acme", ProjectNumber: 1, Reopen: true}, "", 1},
+	}
+	for _, tt := range tests {
+		var project *ghclient.Project
+		var err error
+		captureStdout(t, func() {
+			project, err = resolveProject(context.Background(), client, tt.config, strings.NewReader(tt.input))
+		})
+		got := 0
+		if err == nil {
+			got = project.Number
+		}
+		if got != tt.want {
+			t.Errorf("resolveProject(%+v, %q) = #%d (%v), want #%d", tt.config, tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestRunImportReopensClosedProject(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(source, []byte("Title\nPlan launch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(dryRun bool) (*ghclient.FakeProject, string) {
+		fake := ghclient.NewFakeClient()
+		project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap", Closed: true})
+		useClient(t, fake)
+		config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Reopen: true, DryRun: dryRun, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		var err error
+		output := captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return project, output
+	}
+
+	project, _ := run(true)
+	if !project.Closed {
+		t.Error("Expected a dry run to leave the project closed")
+	}
+
+	project, output := run(false)
+	if project.Closed || len(project.Items) != 1 {
+		t.Errorf("Expected the project to be reopened and the item imported, got closed=%v with %d items", project.Closed, len(project.Items))
+	}
+	if !strings.Contains(output, "Reopened project 'Roadmap'") {
+		t.Errorf("Expected the reopening to be reported, got:\n%s", output)
+	}
+}
+
+func TestResolveProjectClosestMatchNamesProjectNumber(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.AddProject("acme", ghclient.Project{Title: "Platform Board", Number: 4})
+
+	_, err := resolveProject(context.Background(), client, Config{Project: "acme/plat bo", Yes: true}, strings.NewReader(""))
+	if err == nil || !strings.Contains(err.Error(), `closest match is #4 "Platform Board", pick it with --project-number 4`) {
+		t.Errorf("Expected the closest match to be named, got %v", err)
+	}
+
+	// A pipe that is never written stands in for a CI job's stdin, so a
+	// prompt would wait forever
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	output := captureStdout(t, func() {
+		_, err = resolveProject(context.Background(), client, Config{Project: "acme/plat bo", noTerminal: true}, stdin)
+	})
+	if err == nil || output != "" {
+		t.Errorf("Expected a failure without a prompt when there is no terminal, got %v and %q", err, output)
+	}
+
+	client.AddProject("acme", ghclient.Project{Title: "Platform Boards", Number: 5})
+	output = captureStdout(t, func() {
+		_, err = resolveProject(context.Background(), client, Config{Project: "acme/plat bo", noTerminal: true}, stdin)
+	})
+	var ambiguous *ghclient.AmbiguousProjectError
+	if !errors.As(err, &ambiguous) || output != "" {
+		t.Errorf("Expected an ambiguous title to fail without a prompt when there is no terminal, got %v and %q", err, output)
+	}
+}
+
+func TestChooseProjectListsCandidates(t *testing.T) {
+	ambiguous := &ghclient.AmbiguousProjectError{Owner: "acme", Name: "roadmap", Candidates: []ghclient.Project{
+		{Number: 2, Title: "Roadmap 2025"},
+		{Number: 3, Title: "Roadmap Q3"},
+	}}
+
+	var err error
+	output := captureStdout(t, func() {
+		_, err = chooseProject(strings.NewReader(""), Config{}, ambiguous)
+	})
+	if !strings.Contains(output, "1) #2 Roadmap 2025") || !strings.Contains(output, "2) #3 Roadmap Q3") {
+		t.Errorf("Expected the candidates to be listed, got %q", output)
+	}
+	if err == nil || !strings.Contains(err.Error(), "--project-number") {
+		t.Errorf("Expected no answer to fail with a --project-number hint, got %v", err)
+	}
+}
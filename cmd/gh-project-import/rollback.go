@@ -0,0 +1,86 @@
+// Rollback of items created by a failed or interrupted import
+// Records the project items added during a run so they can be deleted again
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// Rollback records the project items created during an import run
+type Rollback struct {
+	existing map[string]bool // Items already in the project before the run
+	created  []createdItem
+	deleted  map[string]bool // Items Run has deleted
+}
+
+// createdItem is a project item added by the run
+type createdItem struct {
+	index  int // 1-based position of the item in the source
+	itemID string
+}
+
+// existingProjectItems snapshots the items already in the project, and
+// their IDs. Adding an issue that is already in a project returns the
+// existing item, which must never be deleted as if the run had created it.
+func existingProjectItems(ctx context.Context, client ghclient.Client, projectID string) (map[string]bool, []ghclient.ProjectItem, error) {
+	items, err := client.ListProjectItems(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list existing project items: %w", err)
+	}
+
+	existing := make(map[string]bool, len(items))
+	for _, item := range items {
+		existing[item.ID] = true
+	}
+	return existing, items, nil
+}
+
+// newRollback prepares to roll back a run into a project holding the
+// existing items
+func newRollback(existing map[string]bool) *Rollback {
+	return &Rollback{existing: existing, deleted: make(map[string]bool)}
+}
+
+// Record notes an item created by the run
+func (r *Rollback) Record(index int, itemID string) {
+	if itemID == "" || r.existing[itemID] {
+		return
+	}
+	r.created = append(r.created, createdItem{index: index, itemID: itemID})
+}
+
+// Run deletes every recorded item, most recent first, and removes them from
+// the checkpoint so that a resumed run imports them again, and from the run
+// journal. It returns the number of items deleted and the errors for those
+// that couldn't be.
+func (r *Rollback) Run(ctx context.Context, client ghclient.Client, projectID string, checkpoint *Checkpoint, journal *Journal) (int, []error) {
+	deleted := 0
+	var errs []error
+
+	for i := len(r.created) - 1; i >= 0; i-- {
+		item := r.created[i]
+		if err := client.DeleteProjectItem(ctx, projectID, item.itemID); err != nil {
+			errs = append(errs, fmt.Errorf("item %d (%s): %w", item.index, item.itemID, err))
+			continue
+		}
+		deleted++
+		r.deleted[item.itemID] = true
+		if checkpoint != nil {
+			checkpoint.Unmark(item.index)
+		}
+		if journal != nil {
+			journal.Remove(item.itemID)
+		}
+	}
+
+	r.created = nil
+	return deleted, errs
+}
+
+// Deleted reports whether Run deleted the item
+func (r *Rollback) Deleted(itemID string) bool {
+	return r.deleted[itemID]
+}
@@ -0,0 +1,88 @@
+// Tests for rolling back items created by a failed import
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// rollbackClient is a ghclient.Client stub that creates numbered drafts, fails
+// drafts titled "fail", and records deletions
+type rollbackClient struct {
+	ghclient.Client
+	existing []ghclient.ProjectItem
+	created  int
+	deleted  []string
+}
+
+func (c *rollbackClient) ListProjectItems(ctx context.Context, projectID string) ([]ghclient.ProjectItem, error) {
+	return c.existing, nil
+}
+
+func (c *rollbackClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	if title == "fail" {
+		return "", errors.New("boom")
+	}
+	c.created++
+	return "PVTI_" + string(rune('0'+c.created)), nil
+}
+
+func (c *rollbackClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	c.deleted = append(c.deleted, itemID)
+	return nil
+}
+
+func TestImportItemsRollbackOnFailure(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	items := []parser.ImportItem{{Title: "First"}, {Title: "fail"}, {Title: "Second"}}
+
+	client := &rollbackClient{}
+	captureStdout(t, func() {
+		importItems(context.Background(), client, project, items, map[string]ghclient.ProjectField{}, Config{Quiet: true, RollbackOnFailure: true})
+	})
+
+	if expected := []string{"PVTI_2", "PVTI_1"}; !reflect.DeepEqual(client.deleted, expected) {
+		t.Errorf("Expected %v to be rolled back, got %v", expected, client.deleted)
+	}
+
+	// Nothing is rolled back when every item succeeds
+	client = &rollbackClient{}
+	captureStdout(t, func() {
+		if err := importItems(context.Background(), client, project, items[:1], map[string]ghclient.ProjectField{}, Config{Quiet: true, RollbackOnFailure: true}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+	if len(client.deleted) != 0 {
+		t.Errorf("Expected no rollback, got %v", client.deleted)
+	}
+}
+
+func TestRollbackKeepsExistingItems(t *testing.T) {
+	client := &rollbackClient{existing: []ghclient.ProjectItem{{ID: "PVTI_existing"}}}
+	existing, _, err := existingProjectItems(context.Background(), client, "PVT_test")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	rollback := newRollback(existing)
+
+	rollback.Record(1, "PVTI_existing")
+	rollback.Record(2, "PVTI_new")
+	rollback.Record(3, "")
+
+	checkpoint := &Checkpoint{Completed: []int{1, 2}}
+	deleted, errs := rollback.Run(context.Background(), client, "PVT_test", checkpoint, nil)
+	if deleted != 1 || len(errs) != 0 {
+		t.Errorf("Expected 1 item deleted without errors, got %d, %v", deleted, errs)
+	}
+	if !reflect.DeepEqual(client.deleted, []string{"PVTI_new"}) {
+		t.Errorf("Expected only the new item to be deleted, got %v", client.deleted)
+	}
+	if !reflect.DeepEqual(checkpoint.Completed, []int{1}) {
+		t.Errorf("Expected rolled back item to be removed from the checkpoint, got %v", checkpoint.Completed)
+	}
+}
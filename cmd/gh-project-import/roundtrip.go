@@ -0,0 +1,220 @@
+// Roundtrip subcommand for checking the importer against a real project
+// Exports a project, re-imports it into a temporary copy and reports every value that didn't survive
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// RoundtripConfig holds the options for the roundtrip subcommand
+type RoundtripConfig struct {
+	Project     string
+	KeepSandbox bool
+}
+
+// RoundtripDifference is a value that changed or was lost in the round trip
+type RoundtripDifference struct {
+	Title     string
+	Field     string
+	Original  string
+	Roundtrip string
+}
+
+// newRoundtripCmd creates the roundtrip subcommand
+func newRoundtripCmd() *cobra.Command {
+	var config RoundtripConfig
+
+	cmd := &cobra.Command{
+		Use:   "roundtrip",
+		Short: "Re-import a project into a temporary copy and report lossy conversions",
+		Long: `Export a project's items, import them into a temporary sandbox project with
+the same fields, and compare the two. Every title, body and field value that
+didn't survive the round trip is reported, so you can check how the importer
+handles your project before a production migration.
+
+The sandbox is created under the same owner and deleted afterwards unless
+--keep-sandbox is given. The source project is only read.
+
+Examples:
+  gh project-import roundtrip --project "owner/project-name"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runRoundtrip(cmd.Context(), client, config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().BoolVar(&config.KeepSandbox, "keep-sandbox", false, "Keep the sandbox project for inspection instead of deleting it")
+
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runRoundtrip exports the project, re-imports it into a sandbox copy and
+// prints the differences. It fails when anything was lost.
+func runRoundtrip(ctx context.Context, client ghclient.Client, config RoundtripConfig) error {
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	original, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d items from \"%s\"\n", len(original), project.Title)
+
+	sandbox, err := client.CopyProject(ctx, project.ID, project.Title+" (roundtrip sandbox)")
+	if err != nil {
+		return fmt.Errorf("failed to create sandbox project: %w", err)
+	}
+	if config.KeepSandbox {
+		fmt.Printf("Sandbox project: %s\n", sandbox.URL)
+	} else {
+		// The sandbox is deleted even when the run was interrupted, and
+		// its URL is printed if that fails so it can be deleted by hand
+		defer func() {
+			if err := client.DeleteProject(context.WithoutCancel(ctx), sandbox.ID); err != nil {
+				fmt.Println(report.Warning("Failed to delete the sandbox project %s: %v", sandbox.URL, err))
+			}
+		}()
+	}
+
+	fields, err := client.GetProjectFields(ctx, sandbox.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sandbox fields: %w", err)
+	}
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+
+	items := make([]parser.ImportItem, len(original))
+	for i, item := range original {
+		items[i] = projectItemToImportItem(item)
+	}
+	if len(items) > 0 {
+		if err := importItems(ctx, client, sandbox, items, fieldMap, Config{Project: sandbox.URL, Quiet: true}); err != nil {
+			return err
+		}
+	}
+
+	imported, err := client.ListProjectItems(ctx, sandbox.ID)
+	if err != nil {
+		return err
+	}
+
+	differences := compareRoundtrip(original, imported)
+	if len(differences) == 0 {
+		fmt.Println(report.Success("All %d items survived the round trip unchanged", len(original)))
+		return nil
+	}
+
+	fmt.Println(report.Warning("%d values changed in the round trip:", len(differences)))
+	for _, difference := range differences {
+		fmt.Printf("   - %q %s: %q → %q\n", difference.Title, difference.Field, difference.Original, difference.Roundtrip)
+	}
+	return fmt.Errorf("round trip found %d lossy conversions", len(differences))
+}
+
+// projectItemToImportItem converts an exported project item into the item
+// the importer would read from an export file
+func projectItemToImportItem(item ghclient.ProjectItem) parser.ImportItem {
+	content := parser.ItemContent{
+		Type:  ghclient.GetString(item.Content, "type"),
+		Title: ghclient.GetString(item.Content, "title"),
+		Body:  ghclient.GetString(item.Content, "body"),
+		URL:   ghclient.GetString(item.Content, "url"),
+	}
+
+	imported := parser.ImportItem{
+		Title:   content.Title,
+		URL:     content.URL,
+		Content: content,
+		Fields:  make(map[string]interface{}),
+	}
+	for name, value := range item.Fields {
+		imported.Fields[name] = value
+	}
+	return imported
+}
+
+// roundtripKey identifies an item in both projects. Issues and pull requests
+// are matched by URL and draft issues by title.
+func roundtripKey(item ghclient.ProjectItem) string {
+	if url := ghclient.GetString(item.Content, "url"); url != "" {
+		return "url:" + url
+	}
+	return "title:" + ghclient.GetString(item.Content, "title")
+}
+
+// compareRoundtrip lists the values that differ between the original items
+// and their re-imported copies. Items sharing a key are paired in order.
+func compareRoundtrip(original, imported []ghclient.ProjectItem) []RoundtripDifference {
+	copies := make(map[string][]ghclient.ProjectItem)
+	for _, item := range imported {
+		key := roundtripKey(item)
+		copies[key] = append(copies[key], item)
+	}
+
+	var differences []RoundtripDifference
+	for _, item := range original {
+		title := ghclient.GetString(item.Content, "title")
+		key := roundtripKey(item)
+		if len(copies[key]) == 0 {
+			differences = append(differences, RoundtripDifference{Title: title, Field: "item", Original: "present", Roundtrip: "missing"})
+			continue
+		}
+		roundtripped := copies[key][0]
+		copies[key] = copies[key][1:]
+
+		if body, copyBody := ghclient.GetString(item.Content, "body"), ghclient.GetString(roundtripped.Content, "body"); body != copyBody {
+			differences = append(differences, RoundtripDifference{Title: title, Field: "body", Original: body, Roundtrip: copyBody})
+		}
+
+		names := make(map[string]bool)
+		for name := range item.Fields {
+			names[name] = true
+		}
+		for name := range roundtripped.Fields {
+			names[name] = true
+		}
+		sortedNames := make([]string, 0, len(names))
+		for name := range names {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+
+		for _, name := range sortedNames {
+			value, copyValue := formatRoundtripValue(item.Fields[name]), formatRoundtripValue(roundtripped.Fields[name])
+			if value != copyValue {
+				differences = append(differences, RoundtripDifference{Title: title, Field: name, Original: value, Roundtrip: copyValue})
+			}
+		}
+	}
+	return differences
+}
+
+// formatRoundtripValue renders a field value for comparison and display
+func formatRoundtripValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(sortedCopy(v), ", ")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
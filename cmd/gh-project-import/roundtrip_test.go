@@ -0,0 +1,176 @@
+// Tests for the roundtrip subcommand
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// sandboxClient is an in-memory ghclient.Client stub holding the items and fields
+// of each project. Copies get sandboxFields, so a test can leave fields out.
+type sandboxClient struct {
+	ghclient.Client
+	project       *ghclient.Project
+	fields        map[string][]ghclient.ProjectField
+	items         map[string][]ghclient.ProjectItem
+	sandboxFields []ghclient.ProjectField
+	deleted       []string
+	deleteErr     error
+}
+
+func (c *sandboxClient) FindProject(ctx context.Context, identifier string) (*ghclient.Project, error) {
+	return c.project, nil
+}
+
+func (c *sandboxClient) GetProjectFields(ctx context.Context, projectID string) ([]ghclient.ProjectField, error) {
+	return c.fields[projectID], nil
+}
+
+func (c *sandboxClient) ListProjectItems(ctx context.Context, projectID string) ([]ghclient.ProjectItem, error) {
+	return c.items[projectID], nil
+}
+
+func (c *sandboxClient) CopyProject(ctx context.Context, projectID, title string) (*ghclient.Project, error) {
+	c.fields["PVT_sandbox"] = c.sandboxFields
+	return &ghclient.Project{ID: "PVT_sandbox", Title: title, URL: "https://github.com/orgs/my-org/projects/99"}, nil
+}
+
+func (c *sandboxClient) DeleteProject(ctx context.Context, projectID string) error {
+	if c.deleteErr != nil {
+		return c.deleteErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.deleted = append(c.deleted, projectID)
+	return nil
+}
+
+func (c *sandboxClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	id := fmt.Sprintf("PVTI_%d", len(c.items[projectID])+1)
+	c.items[projectID] = append(c.items[projectID], ghclient.ProjectItem{
+		ID:      id,
+		Content: map[string]interface{}{"type": "DraftIssue", "title": title, "body": body},
+		Fields:  map[string]interface{}{},
+	})
+	return id, nil
+}
+
+func (c *sandboxClient) SetProjectItemFieldValue(ctx context.Context, projectID, itemID, fieldID string, value interface{}) error {
+	var field ghclient.ProjectField
+	for _, f := range c.fields[projectID] {
+		if f.ID == fieldID {
+			field = f
+		}
+	}
+	converted := value.(map[string]interface{})
+	for i, item := range c.items[projectID] {
+		if item.ID != itemID {
+			continue
+		}
+		switch field.Type {
+		case "SINGLE_SELECT":
+			for _, option := range field.Options {
+				if option.ID == converted["singleSelectOptionId"] {
+					c.items[projectID][i].Fields[field.Name] = option.Name
+				}
+			}
+		case "NUMBER":
+			c.items[projectID][i].Fields[field.Name] = converted["number"]
+		default:
+			c.items[projectID][i].Fields[field.Name] = converted["text"]
+		}
+	}
+	return nil
+}
+
+func newSandboxClient() *sandboxClient {
+	status := ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_todo", Name: "Todo"}}}
+	estimate := ghclient.ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"}
+	return &sandboxClient{
+		project: &ghclient.Project{ID: "PVT_1", Title: "Roadmap"},
+		fields:  map[string][]ghclient.ProjectField{"PVT_1": {status, estimate}},
+		items: map[string][]ghclient.ProjectItem{"PVT_1": {{
+			ID:      "PVTI_a",
+			Content: map[string]interface{}{"type": "DraftIssue", "title": "Plan launch", "body": "Details"},
+			Fields:  map[string]interface{}{"Status": "Todo", "Estimate": float64(3)},
+		}}},
+		sandboxFields: []ghclient.ProjectField{status, estimate},
+	}
+}
+
+func TestRoundtripLossless(t *testing.T) {
+	client := newSandboxClient()
+
+	var err error
+	output := captureStdout(t, func() {
+		err = runRoundtrip(context.Background(), client, RoundtripConfig{Project: "my-org/Roadmap"})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "survived the round trip unchanged") {
+		t.Errorf("Expected lossless report, got: %s", output)
+	}
+	if len(client.deleted) != 1 || client.deleted[0] != "PVT_sandbox" {
+		t.Errorf("Expected the sandbox to be deleted, got %v", client.deleted)
+	}
+}
+
+func TestRoundtripDeletesSandboxAfterCancel(t *testing.T) {
+	client := newSandboxClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	captureStdout(t, func() {
+		runRoundtrip(ctx, client, RoundtripConfig{Project: "my-org/Roadmap"})
+	})
+	if len(client.deleted) != 1 || client.deleted[0] != "PVT_sandbox" {
+		t.Errorf("Expected the sandbox to be deleted despite the cancelled context, got %v", client.deleted)
+	}
+}
+
+func TestRoundtripPrintsSandboxURLWhenDeleteFails(t *testing.T) {
+	client := newSandboxClient()
+	client.deleteErr = fmt.Errorf("forbidden")
+
+	output := captureStdout(t, func() {
+		runRoundtrip(context.Background(), client, RoundtripConfig{Project: "my-org/Roadmap"})
+	})
+	if !strings.Contains(output, "Failed to delete the sandbox project https://github.com/orgs/my-org/projects/99: forbidden") {
+		t.Errorf("Expected the sandbox URL to be printed, got: %s", output)
+	}
+}
+
+func TestRoundtripReportsLostValues(t *testing.T) {
+	client := newSandboxClient()
+	// The Estimate field is missing from the copy, so its value can't be imported
+	client.sandboxFields = client.sandboxFields[:1]
+
+	var err error
+	output := captureStdout(t, func() {
+		err = runRoundtrip(context.Background(), client, RoundtripConfig{Project: "my-org/Roadmap", KeepSandbox: true})
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 lossy conversions") {
+		t.Errorf("Expected one lossy conversion, got: %v", err)
+	}
+	if !strings.Contains(output, `"Plan launch" Estimate: "3" → ""`) {
+		t.Errorf("Expected the lost estimate to be reported, got: %s", output)
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("Expected --keep-sandbox to keep the sandbox, got deletions %v", client.deleted)
+	}
+}
+
+func TestCompareRoundtripMissingItem(t *testing.T) {
+	original := []ghclient.ProjectItem{{Content: map[string]interface{}{"title": "Fix bug", "url": "https://github.com/o/r/issues/1"}}}
+
+	differences := compareRoundtrip(original, nil)
+	if len(differences) != 1 || differences[0].Field != "item" || differences[0].Roundtrip != "missing" {
+		t.Errorf("Expected the item to be reported missing, got %+v", differences)
+	}
+}
@@ -0,0 +1,239 @@
+// Multi-project fan-out
+// Splits the items of one source across several destination projects by their field values (--route)
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// Route sends the items whose fields match a filter to another project
+type Route struct {
+	Filter  mapping.ItemFilter
+	Project string
+}
+
+// ParseRoutes parses --route rules written as FIELD=PATTERN->PROJECT or
+// FIELD!=PATTERN->PROJECT, e.g. "Team=Platform->acme/platform-board"
+func ParseRoutes(rules []string) ([]Route, error) {
+	routes := make([]Route, 0, len(rules))
+	for _, rule := range rules {
+		index := strings.LastIndex(rule, "->")
+		if index < 0 {
+			return nil, fmt.Errorf("invalid --route %q (expected FIELD=PATTERN->PROJECT)", rule)
+		}
+		project := strings.TrimSpace(rule[index+2:])
+		if project == "" {
+			return nil, fmt.Errorf("invalid --route %q: missing project", rule)
+		}
+		filter, err := mapping.ParseItemFilter([]string{strings.TrimSpace(rule[:index])})
+		if err != nil {
+			return nil, fmt.Errorf("invalid --route %q: %w", rule, err)
+		}
+		routes = append(routes, Route{Filter: filter, Project: project})
+	}
+	return routes, nil
+}
+
+// destinationGroup is the items an import sends to one project
+type destinationGroup struct {
+	Project string
+	Items   []parser.ImportItem
+}
+
+// routeItems sends each item to the project of the first route it matches,
+// or to defaultProject if it matches none. Items keep their order within a
+// group; the default project comes first, then the routes' projects in the
+// order they were given, and projects without items are left out unless no
+// project has any.
+func routeItems(items []parser.ImportItem, routes []Route, defaultProject string) []destinationGroup {
+	groups := []destinationGroup{{Project: defaultProject}}
+	index := map[string]int{defaultProject: 0}
+	for _, route := range routes {
+		if _, exists := index[route.Project]; !exists {
+			index[route.Project] = len(groups)
+			groups = append(groups, destinationGroup{Project: route.Project})
+		}
+	}
+
+	for _, item := range items {
+		project := defaultProject
+		for _, route := range routes {
+			if route.Filter.Match(item.Fields) {
+				project = route.Project
+				break
+			}
+		}
+		group := &groups[index[project]]
+		group.Items = append(group.Items, item)
+	}
+
+	var result []destinationGroup
+	for _, group := range groups {
+		if len(group.Items) > 0 {
+			result = append(result, group)
+		}
+	}
+	if len(result) == 0 {
+		return groups[:1]
+	}
+	return result
+}
+
+// destinationFileChars matches the characters of a project identifier that
+// don't belong in a file name
+var destinationFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// destinationPath names the file of a routed project after the file of the
+// default project, e.g. errors.acme-platform-board.csv for errors.csv
+func destinationPath(path, project string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	name := strings.Trim(destinationFileChars.ReplaceAllString(project, "-"), "-")
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// forDestination returns the config for importing into a routed project,
+// which gets its own checkpoint, error file and report so each project's run can be
+// resumed and retried on its own
+func (c Config) forDestination(project string) Config {
+	if project == c.Project {
+		return c
+	}
+	c.Project = project
+	c.ProjectNumber = 0
+	c.Checkpoint = destinationPath(c.Checkpoint, project)
+	c.ErrorFile = destinationPath(c.ErrorFile, project)
+	c.Report = destinationPath(c.Report, project)
+	return c
+}
+
+// destination is a resolved project and the items to import into it
+type destination struct {
+	config   Config
+	project  *ghclient.Project
+	fieldMap map[string]ghclient.ProjectField
+	items    []parser.ImportItem
+}
+
+// importToDestinations routes the items to their projects, resolves and
+// validates every project before changing any of them, and then imports
+// each project's items as a run of its own. A project that fails doesn't
+// stop the others.
+func importToDestinations(ctx context.Context, client ghclient.Client, items []parser.ImportItem, config Config, in io.Reader) error {
+	routes, err := ParseRoutes(config.Routes)
+	if err != nil {
+		return err
+	}
+	groups := routeItems(items, routes, config.Project)
+	routed := len(groups) > 1
+	if config.results != nil {
+		config.results.routed = routed
+	}
+	if routed && !config.Quiet {
+		fmt.Printf("Routing items to %d projects:\n", len(groups))
+		for _, group := range groups {
+			fmt.Printf("   - %s: %d items\n", group.Project, len(group.Items))
+		}
+	}
+
+	var destinations []destination
+	for _, group := range groups {
+		d := destination{config: config.forDestination(group.Project), items: group.Items}
+		client, d.project, d.fieldMap, err = resolveDestination(ctx, client, d.config, in)
+		if err != nil {
+			if routed {
+				return fmt.Errorf("%s: %w", group.Project, err)
+			}
+			return err
+		}
+
+		// Validate field compatibility
+		slog.Debug("Analyzing field compatibility", "project", group.Project)
+
+		if err := reportValidationIssues(config.validateFields(d.items, 0, make(map[string]bool), d.fieldMap), config); err != nil {
+			return err
+		}
+
+		if config.OrderBy != "" {
+			sortItemsByField(d.items, config.OrderBy, d.fieldMap, config.optionMatcher())
+		}
+		destinations = append(destinations, d)
+	}
+
+	if config.DryRun {
+		for _, d := range destinations {
+			if config.Output == "tsv" {
+				for _, item := range d.items {
+					report.PrintTSVRow("dry-run", "", item)
+				}
+				continue
+			}
+			if config.results != nil {
+				config.results.add(dryRunReport(d.config, d.project, len(d.items)))
+				continue
+			}
+			fmt.Printf("DRY RUN: Would import %d items to project '%s'\n", len(d.items), d.project.Title)
+		}
+		return nil
+	}
+
+	// Every project is confirmed before the first import starts. A declined
+	// project is skipped; the run is only aborted when every one is.
+	var confirmed []destination
+	for _, d := range destinations {
+		var summary importSummary
+		summary.add(d.items, d.fieldMap)
+		err := confirmImport(in, config, summary, d.project.Title)
+		if err != nil && !errors.Is(err, errAborted) {
+			return err
+		}
+		if err == nil {
+			confirmed = append(confirmed, d)
+		}
+	}
+	if len(confirmed) == 0 {
+		return withExitCode(exitUsage, errAborted)
+	}
+
+	client, closeCache, err := openCache(client, config)
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	// Import items to the projects
+	var errs []error
+	imported := 0
+	for _, d := range confirmed {
+		err := importItems(ctx, client, d.project, d.items, d.fieldMap, d.config)
+		if err == nil {
+			imported++
+			continue
+		}
+		if !routed {
+			return err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", d.config.Project, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) > 0 && imported > 0 {
+		return withExitCode(exitPartial, errors.Join(errs...))
+	}
+	return errors.Join(errs...)
+}
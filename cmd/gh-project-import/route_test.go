@@ -0,0 +1,107 @@
+// Tests for multi-project fan-out
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes([]string{"Team=Platform->acme/platform-board", "Area != api* -> 7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 || routes[0].Project != "acme/platform-board" || routes[1].Project != "7" {
+		t.Errorf("Unexpected routes %+v", routes)
+	}
+
+	for _, rule := range []string{"Team=Platform", "Team=Platform->", "Platform->acme/board", "=x->acme/board"} {
+		if _, err := ParseRoutes([]string{rule}); err == nil {
+			t.Errorf("Expected an error for --route %q", rule)
+		}
+	}
+}
+
+func TestRouteItems(t *testing.T) {
+	routes, err := ParseRoutes([]string{"Team=Platform->acme/platform", "Team=Web*->acme/web", "Area=UI->acme/web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []parser.ImportItem{
+		{Title: "A", Fields: map[string]interface{}{"Team": "platform"}},
+		{Title: "B", Fields: map[string]interface{}{"Team": "Data"}},
+		{Title: "C", Fields: map[string]interface{}{"Team": "Web Apps"}},
+		{Title: "D", Fields: map[string]interface{}{"Area": "UI"}},
+		{Title: "E", Fields: map[string]interface{}{"Team": "Platform", "Area": "UI"}},
+	}
+
+	groups := routeItems(items, routes, "acme/roadmap")
+	var got []string
+	for _, group := range groups {
+		var titles []string
+		for _, item := range group.Items {
+			titles = append(titles, item.Title)
+		}
+		got = append(got, group.Project+":"+strings.Join(titles, ","))
+	}
+	want := "acme/roadmap:B acme/platform:A,E acme/web:C,D"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, " "))
+	}
+
+	if groups := routeItems(nil, routes, "acme/roadmap"); len(groups) != 1 || groups[0].Project != "acme/roadmap" {
+		t.Errorf("Expected the default project without items, got %+v", groups)
+	}
+}
+
+func TestConfigForDestination(t *testing.T) {
+	config := Config{Project: "acme/roadmap", Checkpoint: "run.json", ErrorFile: "out/errors.csv", Report: "report.md"}
+	if got := config.forDestination("acme/roadmap"); got.Checkpoint != "run.json" || got.ErrorFile != "out/errors.csv" {
+		t.Errorf("Expected the default project to keep its files, got %+v", got)
+	}
+	got := config.forDestination("acme/Platform Board")
+	if got.Project != "acme/Platform Board" || got.Checkpoint != "run.acme-Platform-Board.json" || got.ErrorFile != filepath.Join("out", "errors.acme-Platform-Board.csv") || got.Report != "report.acme-Platform-Board.md" {
+		t.Errorf("Unexpected routed config %+v", got)
+	}
+}
+
+func TestImportToDestinations(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	team := ghclient.ProjectField{ID: "F_team", Name: "Team", Type: "TEXT"}
+	roadmap := client.AddProject("acme", ghclient.Project{Title: "Roadmap"}, team)
+	platform := client.AddProject("acme", ghclient.Project{Title: "Platform"}, team)
+
+	items := []parser.ImportItem{
+		{Title: "A", Fields: map[string]interface{}{"Team": "Platform"}},
+		{Title: "B", Fields: map[string]interface{}{"Team": "Data"}},
+		{Title: "C", Fields: map[string]interface{}{"Team": "Platform"}},
+	}
+	config := Config{Project: "acme/Roadmap", Routes: []string{"Team=Platform->acme/Platform"}, Yes: true, Quiet: true}
+
+	var err error
+	captureStdout(t, func() {
+		err = importToDestinations(context.Background(), client, items, config, strings.NewReader(""))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roadmap.Items) != 1 || len(platform.Items) != 2 {
+		t.Errorf("Expected 1 item in Roadmap and 2 in Platform, got %d and %d", len(roadmap.Items), len(platform.Items))
+	}
+
+	config.Routes = []string{"Team=Data->acme/Missing"}
+	captureStdout(t, func() {
+		err = importToDestinations(context.Background(), client, items, config, strings.NewReader(""))
+	})
+	if err == nil || !strings.Contains(err.Error(), "acme/Missing") {
+		t.Errorf("Expected an error naming the missing project, got %v", err)
+	}
+	if len(roadmap.Items) != 1 {
+		t.Errorf("Expected nothing imported when a project can't be resolved, got %d items", len(roadmap.Items))
+	}
+}
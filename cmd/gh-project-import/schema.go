@@ -0,0 +1,109 @@
+// Schema subcommand for inspecting a project's fields
+// Prints field names, types, single-select options and iterations for scripts and mapping files
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// SchemaConfig holds the options for the schema subcommand
+type SchemaConfig struct {
+	Project string
+	Output  string
+}
+
+// ProjectSchema is a project and its fields, as printed by the schema subcommand
+type ProjectSchema struct {
+	Project *ghclient.Project       `json:"project"`
+	Fields  []ghclient.ProjectField `json:"fields"`
+}
+
+// newSchemaCmd creates the schema subcommand
+func newSchemaCmd() *cobra.Command {
+	var config SchemaConfig
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print a project's field schema",
+		Long: `Print every field of a project with its ID, type, single-select options and
+iterations, so scripts and mapping files can be written against the project
+without querying the GraphQL API by hand.
+
+Examples:
+  gh project-import schema --project "owner/project-name"
+  gh project-import schema --project "owner/project-name" --output json | jq '.fields[].name'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runSchema(cmd.Context(), client, config, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringVarP(&config.Output, "output", "o", "text", "Output format: text or json")
+
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runSchema fetches the project's fields and writes them to w
+func runSchema(ctx context.Context, client ghclient.Client, config SchemaConfig, w io.Writer) error {
+	if config.Output != "text" && config.Output != "json" {
+		return fmt.Errorf("unsupported output %q (expected text or json)", config.Output)
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	schema := ProjectSchema{Project: project, Fields: fields}
+	if schema.Fields == nil {
+		schema.Fields = []ghclient.ProjectField{}
+	}
+
+	if config.Output == "json" {
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	writeSchemaText(w, schema)
+	return nil
+}
+
+// writeSchemaText writes the schema as an indented list
+func writeSchemaText(w io.Writer, schema ProjectSchema) {
+	fmt.Fprintf(w, "Project: %s (#%d, %s)\n", schema.Project.Title, schema.Project.Number, schema.Project.ID)
+	for _, field := range schema.Fields {
+		fmt.Fprintf(w, "\n%s\n  type: %s\n  id:   %s\n", field.Name, field.Type, field.ID)
+		for _, option := range field.Options {
+			fmt.Fprintf(w, "  - %s (%s)\n", option.Name, option.ID)
+		}
+		for _, iteration := range field.Iterations {
+			status := ""
+			if iteration.Completed {
+				status = ", completed"
+			}
+			fmt.Fprintf(w, "  - %s: %s, %d days%s (%s)\n", iteration.Title, iteration.StartDate, iteration.Duration, status, iteration.ID)
+		}
+	}
+}
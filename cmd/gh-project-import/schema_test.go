@@ -0,0 +1,48 @@
+// Tests for the schema subcommand
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestRunSchema(t *testing.T) {
+	client := &schemaClient{
+		project: &ghclient.Project{ID: "PVT_test", Number: 3, Title: "Test Project"},
+		fields:  templateFields,
+	}
+
+	var out bytes.Buffer
+	if err := runSchema(context.Background(), client, SchemaConfig{Project: "owner/project", Output: "json"}, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var schema ProjectSchema
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("Expected JSON output, got: %v\n%s", err, out.String())
+	}
+	if schema.Project.Number != 3 || len(schema.Fields) != len(templateFields) {
+		t.Fatalf("Expected the project and all fields, got %+v", schema)
+	}
+	if status := schema.Fields[1]; status.Type != "SINGLE_SELECT" || len(status.Options) != 2 || status.Options[1].ID != "o2" {
+		t.Errorf("Expected the Status options with their IDs, got %+v", status)
+	}
+	if sprint := schema.Fields[4]; len(sprint.Iterations) != 2 || !sprint.Iterations[0].Completed {
+		t.Errorf("Expected the Sprint iterations, got %+v", sprint)
+	}
+
+	out.Reset()
+	if err := runSchema(context.Background(), client, SchemaConfig{Project: "owner/project", Output: "text"}, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !contains(out.String(), "  - In Progress (o2)\n") || !contains(out.String(), "  - Sprint 1: 2024-04-15, 14 days, completed (i1)\n") {
+		t.Errorf("Unexpected text output:\n%s", out.String())
+	}
+
+	if err := runSchema(context.Background(), client, SchemaConfig{Project: "owner/project", Output: "yaml"}, &out); err == nil {
+		t.Error("Expected an error for an unsupported output")
+	}
+}
@@ -0,0 +1,225 @@
+// Stats subcommand for project analytics snapshots
+// Summarizes how a project's items are distributed across statuses, iterations and assignees
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// noValue is the bucket used for items without a value for a dimension
+const noValue = "(none)"
+
+// StatsConfig holds the options for the stats subcommand
+type StatsConfig struct {
+	Project     string
+	Format      string
+	StatusField string
+}
+
+// ProjectStats holds the distributions computed for a project
+type ProjectStats struct {
+	Project     string             `json:"project"`
+	TotalItems  int                `json:"total_items"`
+	ByType      map[string]int     `json:"by_type"`
+	ByStatus    map[string]int     `json:"by_status"`
+	ByIteration map[string]int     `json:"by_iteration"`
+	ByAssignee  map[string]int     `json:"by_assignee"`
+	NumberSums  map[string]float64 `json:"number_sums"`
+}
+
+// newStatsCmd creates the stats subcommand
+func newStatsCmd() *cobra.Command {
+	var config StatsConfig
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report item distributions for a project as JSON or CSV",
+		Long: `Compute a snapshot of a project's items: counts per type, status, iteration
+and assignee, and the sum of every number field (such as estimates).
+
+Examples:
+  gh project-import stats --project "owner/project-name"
+  gh project-import stats --project "owner/project-name" --format csv > stats.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runStats(cmd.Context(), client, config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringVarP(&config.Format, "format", "f", "json", "Output format: json or csv")
+	cmd.Flags().StringVar(&config.StatusField, "status-field", "Status", "Single-select field used for the status distribution")
+
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runStats fetches the project items and prints their distributions
+func runStats(ctx context.Context, client ghclient.Client, config StatsConfig) error {
+	if config.Format != "json" && config.Format != "csv" {
+		return fmt.Errorf("unsupported format %q (expected json or csv)", config.Format)
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	items, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	stats := computeProjectStats(project, fields, items, config.StatusField)
+
+	if config.Format == "csv" {
+		return writeStatsCSV(stats)
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// computeProjectStats builds the distributions for a project's items
+func computeProjectStats(project *ghclient.Project, fields []ghclient.ProjectField, items []ghclient.ProjectItem, statusField string) ProjectStats {
+	stats := ProjectStats{
+		Project:     project.Title,
+		TotalItems:  len(items),
+		ByType:      make(map[string]int),
+		ByStatus:    make(map[string]int),
+		ByIteration: make(map[string]int),
+		ByAssignee:  make(map[string]int),
+		NumberSums:  make(map[string]float64),
+	}
+
+	var iterationFields, numberFields []string
+	for _, field := range fields {
+		switch field.Type {
+		case "ITERATION":
+			iterationFields = append(iterationFields, field.Name)
+		case "NUMBER":
+			numberFields = append(numberFields, field.Name)
+			stats.NumberSums[field.Name] = 0
+		}
+	}
+
+	for _, item := range items {
+		stats.ByType[ghclient.GetString(item.Content, "type")]++
+
+		status := noValue
+		if value, ok := item.Fields[statusField].(string); ok && value != "" {
+			status = value
+		}
+		stats.ByStatus[status]++
+
+		// Projects normally have a single iteration field; use the first one set
+		iteration := noValue
+		for _, name := range iterationFields {
+			if value, ok := item.Fields[name].(string); ok && value != "" {
+				iteration = value
+				break
+			}
+		}
+		stats.ByIteration[iteration]++
+
+		assignees := projectItemAssignees(item)
+		if len(assignees) == 0 {
+			stats.ByAssignee[noValue]++
+		}
+		for _, assignee := range assignees {
+			stats.ByAssignee[assignee]++
+		}
+
+		for _, name := range numberFields {
+			if value, ok := item.Fields[name].(float64); ok {
+				stats.NumberSums[name] += value
+			}
+		}
+	}
+
+	return stats
+}
+
+// projectItemAssignees returns the logins assigned to an item's issue or pull request
+func projectItemAssignees(item ghclient.ProjectItem) []string {
+	switch assignees := item.Content["assignees"].(type) {
+	case []string:
+		return assignees
+	case []interface{}:
+		// Items decoded from JSON (e.g. snapshots) hold untyped slices
+		var logins []string
+		for _, assignee := range assignees {
+			if login, ok := assignee.(string); ok {
+				logins = append(logins, login)
+			}
+		}
+		return logins
+	}
+	return nil
+}
+
+// writeStatsCSV prints the stats as dimension,value,count rows
+func writeStatsCSV(stats ProjectStats) error {
+	writer := csv.NewWriter(os.Stdout)
+	writer.Write([]string{"dimension", "value", "count"})
+	writer.Write([]string{"total", stats.Project, strconv.Itoa(stats.TotalItems)})
+
+	counts := []struct {
+		dimension string
+		values    map[string]int
+	}{
+		{"type", stats.ByType},
+		{"status", stats.ByStatus},
+		{"iteration", stats.ByIteration},
+		{"assignee", stats.ByAssignee},
+	}
+	for _, c := range counts {
+		for _, key := range sortedKeys(c.values) {
+			writer.Write([]string{c.dimension, key, strconv.Itoa(c.values[key])})
+		}
+	}
+
+	sumNames := make([]string, 0, len(stats.NumberSums))
+	for name := range stats.NumberSums {
+		sumNames = append(sumNames, name)
+	}
+	sort.Strings(sumNames)
+	for _, name := range sumNames {
+		writer.Write([]string{"sum", name, strconv.FormatFloat(stats.NumberSums[name], 'f', -1, 64)})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// sortedKeys returns the keys of a count map in sorted order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+// Tests for the stats subcommand
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// projectItemsClient is a ghclient.Client stub that also serves a fixed list of project items
+type projectItemsClient struct {
+	*schemaClient
+	items []ghclient.ProjectItem
+}
+
+func (c *projectItemsClient) ListProjectItems(ctx context.Context, projectID string) ([]ghclient.ProjectItem, error) {
+	return c.items, nil
+}
+
+func TestComputeProjectStats(t *testing.T) {
+	project := &ghclient.Project{ID: "PVT_test", Title: "Test Project"}
+	fields := []ghclient.ProjectField{
+		{Name: "Status", Type: "SINGLE_SELECT"},
+		{Name: "Sprint", Type: "ITERATION"},
+		{Name: "Estimate", Type: "NUMBER"},
+	}
+	items := []ghclient.ProjectItem{
+		{
+			Content: map[string]interface{}{"type": "Issue", "assignees": []string{"octocat", "hubot"}},
+			Fields:  map[string]interface{}{"Status": "Todo", "Sprint": "Sprint 1", "Estimate": 3.0},
+		},
+		{
+			Content: map[string]interface{}{"type": "DraftIssue"},
+			Fields:  map[string]interface{}{"Status": "Todo", "Estimate": 2.5},
+		},
+		{
+			Content: map[string]interface{}{"type": "PullRequest", "assignees": []interface{}{"octocat"}},
+			Fields:  map[string]interface{}{"Status": "Done", "Sprint": "Sprint 1"},
+		},
+	}
+
+	stats := computeProjectStats(project, fields, items, "Status")
+
+	if stats.TotalItems != 3 {
+		t.Errorf("Expected 3 items, got %d", stats.TotalItems)
+	}
+	if stats.ByStatus["Todo"] != 2 || stats.ByStatus["Done"] != 1 {
+		t.Errorf("Unexpected status distribution: %v", stats.ByStatus)
+	}
+	if stats.ByIteration["Sprint 1"] != 2 || stats.ByIteration[noValue] != 1 {
+		t.Errorf("Unexpected iteration distribution: %v", stats.ByIteration)
+	}
+	if stats.ByAssignee["octocat"] != 2 || stats.ByAssignee["hubot"] != 1 || stats.ByAssignee[noValue] != 1 {
+		t.Errorf("Unexpected assignee distribution: %v", stats.ByAssignee)
+	}
+	if stats.NumberSums["Estimate"] != 5.5 {
+		t.Errorf("Expected Estimate sum 5.5, got %v", stats.NumberSums["Estimate"])
+	}
+	if stats.ByType["Issue"] != 1 || stats.ByType["DraftIssue"] != 1 || stats.ByType["PullRequest"] != 1 {
+		t.Errorf("Unexpected type distribution: %v", stats.ByType)
+	}
+}
+
+func TestRunStatsFormats(t *testing.T) {
+	client := &projectItemsClient{
+		schemaClient: &schemaClient{
+			project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"},
+			fields:  []ghclient.ProjectField{{Name: "Estimate", Type: "NUMBER"}},
+		},
+		items: []ghclient.ProjectItem{
+			{Content: map[string]interface{}{"type": "DraftIssue"}, Fields: map[string]interface{}{"Status": "Todo", "Estimate": 2.0}},
+		},
+	}
+
+	out := captureStdout(t, func() {
+		if err := runStats(context.Background(), client, StatsConfig{Project: "owner/project", Format: "csv", StatusField: "Status"}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+	if !contains(out, "status,Todo,1\n") || !contains(out, "sum,Estimate,2\n") {
+		t.Errorf("Unexpected CSV output:\n%s", out)
+	}
+
+	if err := runStats(context.Background(), client, StatsConfig{Project: "owner/project", Format: "xml"}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
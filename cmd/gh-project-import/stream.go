@@ -0,0 +1,286 @@
+// Chunked imports for very large sources
+// Streams items through parse, validate and import a chunk at a time so memory use stays flat
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// errStopReading stops a source stream early without being reported as an error
+var errStopReading = errors.New("stop reading source")
+
+// runChunkedImport imports the sources --chunk-size items at a time. The
+// sources are read twice: first every chunk is validated, so an invalid item
+// still stops the run before anything is created, then each chunk is read
+// again and imported. Only CSV and NDJSON sources are read row by row; other
+// formats are parsed whole and then handed out in chunks. The --hook-transform
+// program only runs in the first pass, and the later passes read the items it
+// returned from a hookSpool. A client is created unless one is given.
+// Confirmation is asked for on in. Items are numbered by their position among
+// the items --hook-transform keeps.
+func runChunkedImport(ctx context.Context, client ghclient.Client, config Config, in io.Reader, prep itemPreparation) error {
+	options := config.sourceOptions()
+
+	client, project, fieldMap, err := resolveDestination(ctx, client, config, in)
+	if err != nil {
+		return err
+	}
+
+	// rereadChunks reads the items again for the passes after validation
+	rereadChunks := func(fn func(chunk []parser.ImportItem) error) error {
+		return forEachChunk(ctx, config, options, fn)
+	}
+	var spool *hookSpool
+	if len(prep.hook.Command) > 0 {
+		if spool, err = newHookSpool(); err != nil {
+			return err
+		}
+		defer spool.Close()
+		rereadChunks = func(fn func(chunk []parser.ImportItem) error) error {
+			return spool.forEachChunk(config.ChunkSize, fn)
+		}
+	}
+
+	total := 0
+	var truncations []mapping.Truncation
+	var validationIssues []mapping.ValidationIssue
+	var summary importSummary
+	seenFields := make(map[string]bool)
+	err = forEachChunk(ctx, config, options, func(chunk []parser.ImportItem) error {
+		chunk, err := prep.hook.Apply(ctx, chunk, total)
+		if err != nil {
+			return withExitCode(exitInvalid, err)
+		}
+		if spool != nil {
+			if err := spool.add(chunk); err != nil {
+				return err
+			}
+		}
+		chunk, chunkTruncations, err := prep.reshape(chunk, total, config)
+		if err != nil {
+			return withExitCode(exitInvalid, err)
+		}
+		truncations = append(truncations, chunkTruncations...)
+
+		if err := parser.ValidateImportItemsFrom(chunk, total); err != nil {
+			return withExitCode(exitInvalid, fmt.Errorf("validation failed: %w", err))
+		}
+		validationIssues = append(validationIssues, config.validateFields(chunk, total, seenFields, fieldMap)...)
+		summary.add(chunk, fieldMap)
+		total += len(chunk)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return withExitCode(exitInvalid, fmt.Errorf("validation failed: no items found to import"))
+	}
+
+	if len(truncations) > 0 {
+		// As in a regular run, data loss is always reported
+		var out io.Writer = os.Stdout
+		if config.Quiet {
+			out = os.Stderr
+		}
+		defer report.PrintTruncations(out, truncations)
+	}
+
+	if !config.Quiet {
+		fmt.Printf("Validated %d items from source file in chunks of %d\n", total, config.ChunkSize)
+	}
+	if err := reportValidationIssues(validationIssues, config); err != nil {
+		return err
+	}
+
+	if config.DryRun {
+		if config.Output == "tsv" {
+			position := 0
+			return rereadChunks(func(chunk []parser.ImportItem) error {
+				chunk, _, err := prep.reshape(chunk, position, config)
+				if err != nil {
+					return err
+				}
+				position += len(chunk)
+				for _, item := range chunk {
+					report.PrintTSVRow("dry-run", "", item)
+				}
+				return nil
+			})
+		}
+		if config.results != nil {
+			config.results.add(dryRunReport(config, project, total))
+			return nil
+		}
+		fmt.Printf("DRY RUN: Would import %d items to project '%s'\n", total, project.Title)
+		return nil
+	}
+
+	if err := confirmImport(in, config, summary, project.Title); err != nil {
+		return err
+	}
+
+	client, closeCache, err := openCache(client, config)
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	run, err := startImportRun(ctx, client, project, fieldMap, config, total)
+	if err != nil {
+		return err
+	}
+	position := 0
+	err = rereadChunks(func(chunk []parser.ImportItem) error {
+		chunk, _, err := prep.reshape(chunk, position, config)
+		if err != nil {
+			return err
+		}
+		if !run.importChunk(ctx, chunk, position) {
+			return errStopReading
+		}
+		position += len(chunk)
+		return nil
+	})
+
+	// The run is finished even if the source can't be read again, so the
+	// checkpoint and summary reflect what was imported
+	finishErr := run.finish(ctx)
+	if err != nil && !errors.Is(err, errStopReading) {
+		return err
+	}
+	return finishErr
+}
+
+// forEachChunk streams the sources, applying --skip and --limit, and calls fn
+// with up to --chunk-size items at a time. The chunk's backing array is
+// reused, so fn must not keep it. An error from fn stops reading and is
+// returned, as is ctx's error once a download is cancelled.
+func forEachChunk(ctx context.Context, config Config, options parser.SourceOptions, fn func(chunk []parser.ImportItem) error) error {
+	chunk := make([]parser.ImportItem, 0, config.ChunkSize)
+	read := 0
+	limitReached := false
+
+	err := parser.StreamSourcesContext(ctx, config.Sources, options, func(item parser.ImportItem) error {
+		read++
+		if read <= config.Skip {
+			return nil
+		}
+		if config.Limit > 0 && read > config.Skip+config.Limit {
+			limitReached = true
+			return errStopReading
+		}
+
+		chunk = append(chunk, item)
+		if len(chunk) < config.ChunkSize {
+			return nil
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		chunk = chunk[:0]
+		return nil
+	})
+	if err != nil && !(limitReached && errors.Is(err, errStopReading)) {
+		return err
+	}
+
+	if len(chunk) > 0 {
+		return fn(chunk)
+	}
+	return nil
+}
+
+// hookSpool keeps the items the --hook-transform program returned in the
+// first pass of a chunked import, as NDJSON in a temporary file. The later
+// passes read them back, so the hook runs once per item and the items
+// imported are the ones that were validated.
+type hookSpool struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// spooledItem is a line of a hookSpool: the item in the source format, which
+// parser.ParseItemRecord reads back, and its origin, which that format lacks
+type spooledItem struct {
+	Record map[string]interface{} `json:"record"`
+	Origin string                 `json:"origin,omitempty"`
+}
+
+// newHookSpool creates an empty spool in the temporary directory
+func newHookSpool() (*hookSpool, error) {
+	file, err := os.CreateTemp("", "gh-project-import-hook-*.ndjson")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a file for the --hook-transform output: %w", err)
+	}
+	return &hookSpool{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// add appends items to the spool
+func (s *hookSpool) add(items []parser.ImportItem) error {
+	encoder := json.NewEncoder(s.writer)
+	for _, item := range items {
+		if err := encoder.Encode(spooledItem{Record: parser.ItemRecord(item), Origin: item.Origin}); err != nil {
+			return fmt.Errorf("failed to save the --hook-transform output: %w", err)
+		}
+	}
+	return nil
+}
+
+// forEachChunk reads the spooled items back from the start and calls fn with
+// up to size items at a time, like the package's forEachChunk
+func (s *hookSpool) forEachChunk(size int, fn func(chunk []parser.ImportItem) error) error {
+	if err := s.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to save the --hook-transform output: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	chunk := make([]parser.ImportItem, 0, size)
+	decoder := json.NewDecoder(bufio.NewReader(s.file))
+	for {
+		var spooled spooledItem
+		if err := decoder.Decode(&spooled); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read the --hook-transform output back: %w", err)
+		}
+		item, err := parser.ParseItemRecord(spooled.Record)
+		if err != nil {
+			return fmt.Errorf("failed to read the --hook-transform output back: %w", err)
+		}
+		item.Origin = spooled.Origin
+
+		chunk = append(chunk, item)
+		if len(chunk) < size {
+			continue
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		chunk = chunk[:0]
+	}
+
+	if len(chunk) > 0 {
+		return fn(chunk)
+	}
+	return nil
+}
+
+// Close removes the spool
+func (s *hookSpool) Close() error {
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
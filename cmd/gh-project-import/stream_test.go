@@ -0,0 +1,179 @@
+// Tests for chunked imports of large sources
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// chunkClient is a ghclient.Client stub that records the drafts it creates
+type chunkClient struct {
+	ghclient.Client
+	drafts []string
+}
+
+func (c *chunkClient) GetUser(ctx context.Context) (string, error) {
+	return "octocat", nil
+}
+
+func (c *chunkClient) FindProject(ctx context.Context, identifier string) (*ghclient.Project, error) {
+	return &ghclient.Project{ID: "PVT_1", Title: "Backlog"}, nil
+}
+
+func (c *chunkClient) ListProjects(ctx context.Context, owner string) ([]ghclient.Project, error) {
+	return []ghclient.Project{{ID: "PVT_1", Number: 1, Title: "project"}}, nil
+}
+
+func (c *chunkClient) GetProjectFields(ctx context.Context, projectID string) ([]ghclient.ProjectField, error) {
+	return nil, nil
+}
+
+func (c *chunkClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	c.drafts = append(c.drafts, title)
+	return fmt.Sprintf("PVTI_%d", len(c.drafts)), nil
+}
+
+// writeRowsCSV writes a CSV source with the given rows below a title,url header
+func writeRowsCSV(t *testing.T, rows ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "items.csv")
+	content := "title,url\n" + strings.Join(rows, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunChunkedImport(t *testing.T) {
+	source := writeRowsCSV(t, "One,", "Two,", "Three,", "Four,", "Five,")
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	client := &chunkClient{}
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, Yes: true, ChunkSize: 2, Skip: 1, Limit: 3, Checkpoint: checkpointPath}
+
+	if err := runChunkedImport(context.Background(), client, config, nil, itemPreparation{sizeLimits: mapping.DefaultSizeLimits}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(client.drafts, ",") != "Two,Three,Four" {
+		t.Errorf("Expected the selected items to be imported in order, got %v", client.drafts)
+	}
+
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Total != 3 || len(checkpoint.Completed) != 3 || checkpoint.Completed[2] != 3 || !checkpoint.Finished {
+		t.Errorf("Expected 3 completed items numbered across chunks, got %+v", checkpoint)
+	}
+}
+
+func TestRunChunkedImportValidatesBeforeImporting(t *testing.T) {
+	source := writeRowsCSV(t, "One,", "Two,", "Three,", "Bad,https://gitlab.com/o/r/-/issues/1")
+	client := &chunkClient{}
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, ChunkSize: 2}
+
+	err := runChunkedImport(context.Background(), client, config, nil, itemPreparation{sizeLimits: mapping.DefaultSizeLimits})
+	if err == nil || !strings.Contains(err.Error(), "item 4") {
+		t.Errorf("Expected a validation error for item 4, got: %v", err)
+	}
+	if len(client.drafts) != 0 {
+		t.Errorf("Expected nothing to be imported, got %v", client.drafts)
+	}
+}
+
+func TestRunChunkedImportWithTransformHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script needs a POSIX shell")
+	}
+	script := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ninput=$(cat)\ncase \"$input\" in *'\"title\":\"Drop'*) ;; *) echo \"$input\" ;; esac\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook, err := mapping.ParseTransformHook(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := writeRowsCSV(t, "One,", "Drop me,", "Three,", "Four,")
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	client := &chunkClient{}
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, Yes: true, ChunkSize: 2, Checkpoint: checkpointPath}
+
+	if err := runChunkedImport(context.Background(), client, config, nil, itemPreparation{hook: hook, sizeLimits: mapping.DefaultSizeLimits}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(client.drafts, ",") != "One,Three,Four" {
+		t.Errorf("Expected the dropped item to be skipped, got %v", client.drafts)
+	}
+
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Total != 3 || len(checkpoint.Completed) != 3 {
+		t.Errorf("Expected the kept items to be numbered consecutively, got %+v", checkpoint)
+	}
+}
+
+func TestRunChunkedImportRunsTransformHookOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script needs a POSIX shell")
+	}
+	// The hook numbers its runs in the titles, so a second run of it would
+	// import different items than were validated
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "hook.sh")
+	hookScript := "#!/bin/sh\necho run >> " + runs + "\nn=$(wc -l < " + runs + " | tr -d ' ')\nsed \"s/\\\"title\\\":\\\"\\([^\\\"]*\\)\\\"/\\\"title\\\":\\\"\\1 #$n\\\"/\"\n"
+	if err := os.WriteFile(script, []byte(hookScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook, err := mapping.ParseTransformHook(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := writeRowsCSV(t, "One,", "Two,", "Three,")
+	client := &chunkClient{}
+	config := Config{Sources: []string{source}, Project: "owner/project", Quiet: true, Yes: true, ChunkSize: 2}
+	if err := runChunkedImport(context.Background(), client, config, nil, itemPreparation{hook: hook, sizeLimits: mapping.DefaultSizeLimits}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(client.drafts, ",") != "One #1,Two #2,Three #3" {
+		t.Errorf("Expected the items the hook returned in the validation pass, got %v", client.drafts)
+	}
+	log, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(log), "run"); n != 3 {
+		t.Errorf("Expected the hook to run once per item, ran %d times", n)
+	}
+}
+
+func TestStreamCSVFileStopsOnConsumerError(t *testing.T) {
+	source := writeRowsCSV(t, "One,", "Two,", "Three,")
+
+	var titles []string
+	err := parser.StreamSources([]string{source}, parser.SourceOptions{}, func(item parser.ImportItem) error {
+		titles = append(titles, item.Title)
+		if len(titles) == 2 {
+			return errStopReading
+		}
+		return nil
+	})
+	if err != errStopReading {
+		t.Errorf("Expected the consumer's error unchanged, got: %v", err)
+	}
+	if len(titles) != 2 {
+		t.Errorf("Expected reading to stop after 2 items, got %v", titles)
+	}
+}
@@ -0,0 +1,225 @@
+// Two-way sync
+// Reconciles source items with the project items they match (--mode two-way) and writes the values the project wins back to the source
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/importer"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// Sync modes and the strategies that settle a value the source and the
+// project disagree on
+const (
+	modeOneWay = "one-way"
+	modeTwoWay = "two-way"
+
+	conflictSourceWins  = "source-wins"
+	conflictProjectWins = "project-wins"
+	conflictNewestWins  = "newest-wins" // By the project item's updatedAt and the source's modification time
+)
+
+// SyncConflict is a value the source and the project disagree on, and the
+// side whose value was kept, "source" or "project"
+type SyncConflict struct {
+	Item   parser.ImportItem
+	Change FieldChange
+	Kept   string
+}
+
+// projectSync holds what a two-way sync takes back from the project: the
+// values to write to the source, keyed by project item ID and then column
+type projectSync struct {
+	source    string
+	existing  *importer.ExistingItems
+	config    Config
+	values    map[string]map[string]string
+	conflicts []SyncConflict
+}
+
+// validateSync checks the --mode and --conflict options. A two-way sync
+// writes to the source, so it needs a single local CSV file read with its
+// own column names, and a --match-key to pair its rows with project items.
+func (c Config) validateSync() error {
+	switch c.Conflict {
+	case "", conflictSourceWins, conflictProjectWins, conflictNewestWins:
+	default:
+		return fmt.Errorf("unsupported --conflict strategy %q (expected source-wins, project-wins or newest-wins)", c.Conflict)
+	}
+
+	switch c.Mode {
+	case "", modeOneWay:
+		if c.ConflictReport != "" || (c.Conflict != "" && c.Conflict != conflictSourceWins) {
+			return fmt.Errorf("--conflict and --conflict-report need --mode two-way")
+		}
+		return nil
+	case modeTwoWay:
+	default:
+		return fmt.Errorf("unsupported --mode %q (expected one-way or two-way)", c.Mode)
+	}
+
+	if c.MatchKey == "" {
+		return fmt.Errorf("--mode two-way needs --match-key or --external-id-field to pair source items with project items")
+	}
+	if len(c.Sources) != 1 || c.FromClassic != "" || c.FromQuery != "" {
+		return fmt.Errorf("--mode two-way needs a single --source file to write back to")
+	}
+	source := c.Sources[0]
+	if strings.Contains(source, "://") || strings.ContainsAny(source, "*?[") {
+		return fmt.Errorf("--mode two-way needs a local source file, not %s", source)
+	}
+	if !strings.EqualFold(filepath.Ext(source), ".csv") && !strings.EqualFold(c.SourceFormat, parser.FormatCSV) {
+		return fmt.Errorf("--mode two-way can only write back to CSV sources")
+	}
+	if c.Profile != "" || c.Mapping != "" || len(c.Columns) > 0 {
+		return fmt.Errorf("cannot use --mode two-way with --profile, --mapping or a column mapping: values are written back to the source's own columns")
+	}
+	if c.ChunkSize > 0 || len(c.Routes) > 0 {
+		return fmt.Errorf("cannot use --mode two-way with --chunk-size or --route")
+	}
+	return nil
+}
+
+// newProjectSync reconciles items with the project items they match. Where
+// a value differs, the --conflict strategy picks the side to keep: a project
+// value is taken out of the item, so the import leaves it alone, and noted
+// to be written back to the source. Project values of fields an item leaves
+// empty are written back too.
+func newProjectSync(items []parser.ImportItem, existing *importer.ExistingItems, fieldMap map[string]ghclient.ProjectField, config Config) (*projectSync, error) {
+	source := config.Sources[0]
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	s := &projectSync{source: source, existing: existing, config: config, values: make(map[string]map[string]string)}
+
+	for i := range items {
+		item := &items[i]
+		projectItem, ok := existing.Find(*item)
+		if !ok {
+			continue
+		}
+		values := make(map[string]string)
+
+		for _, change := range compareItemFields(*item, projectItem, fieldMap, config.optionMatcher()) {
+			kept := "source"
+			if keepProjectValue(config.Conflict, info.ModTime(), projectItem.UpdatedAt) {
+				kept = "project"
+				values[change.Field] = change.Project
+				if change.Field == "body" {
+					item.Content.Body, item.Notes = change.Project, ""
+				} else {
+					delete(item.Fields, change.Field)
+				}
+			}
+			s.conflicts = append(s.conflicts, SyncConflict{Item: *item, Change: change, Kept: kept})
+		}
+
+		for name, field := range fieldMap {
+			value := projectItem.Fields[name]
+			if _, set := item.Fields[name]; set || value == nil || mapping.IsMilestoneField(name) {
+				continue
+			}
+			values[name] = formatDiffValue(value, field, mapping.OptionMatcher{})
+		}
+
+		if len(values) > 0 {
+			s.values[projectItem.ID] = values
+		}
+	}
+	return s, nil
+}
+
+// keepProjectValue reports whether strategy keeps the project's value of an
+// item last changed at updated over the source's, last changed at modified.
+// An item whose time is unknown is older than any source.
+func keepProjectValue(strategy string, modified, updated time.Time) bool {
+	switch strategy {
+	case conflictProjectWins:
+		return true
+	case conflictNewestWins:
+		return updated.After(modified)
+	}
+	return false
+}
+
+// writeBack writes the values taken from the project to the source's rows,
+// matching each row to its project item by the --match-key, and writes the
+// --conflict-report. It prints what changed unless quiet.
+func (s *projectSync) writeBack() error {
+	dialect := s.config.CSV
+	changed, err := parser.UpdateCSVFile(s.source, dialect, func(row parser.ImportItem) map[string]string {
+		if s.config.ExternalIDField != "" {
+			mapping.MoveExternalIDs([]parser.ImportItem{row}, s.config.ExternalIDField)
+		}
+		if projectItem, ok := s.existing.Find(row); ok {
+			return s.values[projectItem.ID]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.config.ConflictReport != "" {
+		if err := writeConflictReport(s.config.ConflictReport, s.conflicts); err != nil {
+			return fmt.Errorf("failed to write conflict report: %w", err)
+		}
+	}
+
+	if !s.config.Quiet {
+		if changed > 0 {
+			fmt.Println(report.Success("Wrote %d values from the project to %s", changed, s.source))
+		}
+		if len(s.conflicts) > 0 {
+			kept := 0
+			for _, conflict := range s.conflicts {
+				if conflict.Kept == "project" {
+					kept++
+				}
+			}
+			fmt.Println(report.Warning("%d values differed: kept %d from the source and %d from the project (%s)", len(s.conflicts), len(s.conflicts)-kept, kept, s.strategy()))
+			if s.config.ConflictReport == "" {
+				fmt.Println("Run with --conflict-report FILE to list them")
+			}
+		}
+	}
+	return nil
+}
+
+// strategy names the --conflict strategy in effect
+func (s *projectSync) strategy() string {
+	if s.config.Conflict == "" {
+		return conflictSourceWins
+	}
+	return s.config.Conflict
+}
+
+// writeConflictReport writes the conflicts to a CSV file in source order, one
+// row per value with the item, the field, both values and the side kept
+func writeConflictReport(path string, conflicts []SyncConflict) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"title", "url", "field", "source", "project", "kept"})
+	for _, conflict := range conflicts {
+		writer.Write([]string{conflict.Item.Title, conflict.Item.URL, conflict.Change.Field, conflict.Change.Source, conflict.Change.Project, conflict.Kept})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
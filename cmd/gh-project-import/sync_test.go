@@ -0,0 +1,126 @@
+// Tests for two-way sync
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestValidateSync(t *testing.T) {
+	valid := Config{Sources: []string{"items.csv"}, Mode: modeTwoWay, MatchKey: "field:Jira Key"}
+	if err := valid.validateSync(); err != nil {
+		t.Fatalf("Expected a valid two-way sync, got %v", err)
+	}
+	if err := (Config{Sources: []string{"items.json"}, Conflict: conflictSourceWins}).validateSync(); err != nil {
+		t.Errorf("Expected a one-way import to need nothing more, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown mode", func(c *Config) { c.Mode = "both" }},
+		{"unknown strategy", func(c *Config) { c.Conflict = "last-wins" }},
+		{"strategy without two-way", func(c *Config) { c.Mode = modeOneWay; c.Conflict = conflictProjectWins }},
+		{"report without two-way", func(c *Config) { c.Mode = ""; c.ConflictReport = "conflicts.csv" }},
+		{"no match key", func(c *Config) { c.MatchKey = "" }},
+		{"several sources", func(c *Config) { c.Sources = []string{"a.csv", "b.csv"} }},
+		{"glob", func(c *Config) { c.Sources = []string{"*.csv"} }},
+		{"remote", func(c *Config) { c.Sources = []string{"https://example.com/items.csv"} }},
+		{"JSON", func(c *Config) { c.Sources = []string{"items.json"} }},
+		{"profile", func(c *Config) { c.Profile = "jira" }},
+		{"chunked", func(c *Config) { c.ChunkSize = 100 }},
+	}
+	for _, tt := range tests {
+		config := valid
+		tt.modify(&config)
+		if err := config.validateSync(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestRunImportTwoWay(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "items.csv")
+	conflictReport := filepath.Join(dir, "conflicts.csv")
+	sourceTime := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// Each run starts from a project that blocked ABC-1 after the
+	// spreadsheet was saved and gave ABC-2 a priority, and a spreadsheet
+	// that finished ABC-1
+	run := func(strategy string, updated time.Time) (*ghclient.FakeProject, string, error) {
+		fake := ghclient.NewFakeClient()
+		project := fake.AddProject("acme", ghclient.Project{Title: "Roadmap"},
+			ghclient.ProjectField{ID: "F_key", Name: "Jira Key", Type: "TEXT"},
+			ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "TEXT"},
+			ghclient.ProjectField{ID: "F_priority", Name: "Priority", Type: "TEXT"},
+		)
+		project.Items = []ghclient.ProjectItem{
+			{ID: "PVTI_1", Content: map[string]interface{}{"type": "DraftIssue", "id": "DI_1", "title": "Plan launch"}, Fields: map[string]interface{}{"Jira Key": "ABC-1", "Status": "Blocked"}, UpdatedAt: updated},
+			{ID: "PVTI_2", Content: map[string]interface{}{"type": "DraftIssue", "id": "DI_2", "title": "Write docs"}, Fields: map[string]interface{}{"Jira Key": "ABC-2", "Status": "Todo", "Priority": "High"}, UpdatedAt: updated},
+		}
+		useClient(t, fake)
+
+		if err := os.WriteFile(source, []byte("Title,external_id,Status,Priority\nPlan launch,ABC-1,Done,\nWrite docs,ABC-2,Todo,\nShip it,ABC-3,Todo,Low\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(source, sourceTime, sourceTime); err != nil {
+			t.Fatal(err)
+		}
+		config := Config{Sources: []string{source}, Project: "acme/Roadmap", Yes: true, Quiet: true, ExternalIDField: "Jira Key", Mode: modeTwoWay, Conflict: strategy, ConflictReport: conflictReport, MaxWarnings: -1, Oversize: mapping.OversizeTruncate, HoursPerDay: 8, DaysPerWeek: 5, Cache: "memory"}
+		var err error
+		captureStdout(t, func() {
+			err = runImport(context.Background(), config, strings.NewReader(""))
+		})
+		data, _ := os.ReadFile(source)
+		return project, string(data), err
+	}
+
+	tests := []struct {
+		strategy string
+		updated  time.Time
+		status   string // ABC-1's status in both the project and the source afterwards
+	}{
+		{conflictSourceWins, sourceTime.Add(time.Hour), "Done"},
+		{conflictProjectWins, sourceTime.Add(-time.Hour), "Blocked"},
+		{conflictNewestWins, sourceTime.Add(time.Hour), "Blocked"},
+		{conflictNewestWins, sourceTime.Add(-time.Hour), "Done"},
+		{conflictNewestWins, time.Time{}, "Done"},
+	}
+	for _, tt := range tests {
+		project, contents, err := run(tt.strategy, tt.updated)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.strategy, err)
+		}
+		if len(project.Items) != 3 {
+			t.Fatalf("%s: expected the new item to be added, got %d items", tt.strategy, len(project.Items))
+		}
+		if status := project.Items[0].Fields["Status"]; status != tt.status {
+			t.Errorf("%s at %v: expected the project to end with %s, got %v", tt.strategy, tt.updated, tt.status, status)
+		}
+		expected := "Title,external_id,Status,Priority\nPlan launch,ABC-1," + tt.status + ",\nWrite docs,ABC-2,Todo,High\nShip it,ABC-3,Todo,Low\n"
+		if contents != expected {
+			t.Errorf("%s at %v: expected the source to become:\n%s\ngot:\n%s", tt.strategy, tt.updated, expected, contents)
+		}
+
+		report, err := os.ReadFile(conflictReport)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept := "source"
+		if tt.status == "Blocked" {
+			kept = "project"
+		}
+		if want := "title,url,field,source,project,kept\nPlan launch,,Status,Done,Blocked," + kept + "\n"; string(report) != want {
+			t.Errorf("%s: expected the conflict report:\n%s\ngot:\n%s", tt.strategy, want, report)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Template subcommand for starting a source file
+// Generates a CSV or JSON file with a column for every importable field of the destination project
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/spf13/cobra"
+)
+
+// TemplateConfig holds the options for the template subcommand
+type TemplateConfig struct {
+	Project string
+	Format  string
+}
+
+// templateColumn is a column of a generated template
+type templateColumn struct {
+	Name    string
+	Example interface{} // Value in the example row; "" leaves it empty
+	Comment string      // The values the column accepts
+}
+
+// newTemplateCmd creates the template subcommand
+func newTemplateCmd() *cobra.Command {
+	var config TemplateConfig
+
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Generate a starter source file for a project",
+		Long: `Inspect the destination project's fields and print a CSV or JSON file with a
+column for each field that can be imported, notes listing the valid
+single-select options and iterations, and an example row to replace.
+
+The notes are "# " lines above the CSV header and "//" keys in JSON, both of
+which are ignored on import.
+
+Examples:
+  gh project-import template --project "owner/project-name" > items.csv
+  gh project-import template --project "owner/project-name" --format json > items.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runTemplate(cmd.Context(), client, config, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringVarP(&config.Format, "format", "f", "csv", "Output format: csv or json")
+
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runTemplate fetches the project's fields and writes a template to w
+func runTemplate(ctx context.Context, client ghclient.Client, config TemplateConfig, w io.Writer) error {
+	if config.Format != "csv" && config.Format != "json" {
+		return fmt.Errorf("unsupported format %q (expected csv or json)", config.Format)
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	columns := templateColumns(fields, config.Format, time.Now())
+	if config.Format == "json" {
+		return writeTemplateJSON(w, columns)
+	}
+	return writeTemplateCSV(w, project, columns)
+}
+
+// templateColumns lists the importer's own columns followed by a column for
+// each project field the importer can set, in the project's order. Dates in
+// the example row are today's.
+func templateColumns(fields []ghclient.ProjectField, format string, today time.Time) []templateColumn {
+	// JSON sources read a draft issue's body from "notes"
+	body := "body"
+	if format == "json" {
+		body = "notes"
+	}
+
+	columns := []templateColumn{
+		{Name: "title", Example: "Example item", Comment: "required"},
+		{Name: "url", Example: "", Comment: "issue or pull request URL; leave empty to create a draft issue"},
+		{Name: body, Example: "Describe the item here", Comment: "draft issue body"},
+	}
+
+	for _, field := range fields {
+		switch field.Type {
+		case "TEXT":
+			columns = append(columns, templateColumn{Name: field.Name, Example: "", Comment: "text"})
+		case "NUMBER":
+			columns = append(columns, templateColumn{Name: field.Name, Example: 1, Comment: "number"})
+		case "DATE":
+			columns = append(columns, templateColumn{Name: field.Name, Example: today.Format("2006-01-02"), Comment: "date (YYYY-MM-DD)"})
+		case "SINGLE_SELECT":
+			column := templateColumn{Name: field.Name, Example: "", Comment: "one of: " + strings.Join(optionNames(field.Options), " | ")}
+			if len(field.Options) > 0 {
+				column.Example = field.Options[0].Name
+			}
+			columns = append(columns, column)
+		case "ITERATION":
+			column := templateColumn{Name: field.Name, Example: "", Comment: "one of: " + strings.Join(iterationTitles(field.Iterations), " | ") + ", or a date within an iteration"}
+			for _, iteration := range field.Iterations {
+				if !iteration.Completed {
+					column.Example = iteration.Title
+					break
+				}
+			}
+			columns = append(columns, column)
+		case "MILESTONE":
+			columns = append(columns, templateColumn{Name: "Milestone", Example: "", Comment: "milestone title in the item's repository (issues and pull requests only)"})
+		}
+	}
+
+	return columns
+}
+
+// optionNames returns the names of single-select options
+func optionNames(options []ghclient.ProjectFieldOption) []string {
+	names := make([]string, len(options))
+	for i, option := range options {
+		names[i] = option.Name
+	}
+	return names
+}
+
+// iterationTitles returns the titles of iterations
+func iterationTitles(iterations []ghclient.IterationOption) []string {
+	titles := make([]string, len(iterations))
+	for i, iteration := range iterations {
+		titles[i] = iteration.Title
+	}
+	return titles
+}
+
+// writeTemplateCSV writes the columns as "# " notes, a header row and an
+// example row
+func writeTemplateCSV(w io.Writer, project *ghclient.Project, columns []templateColumn) error {
+	fmt.Fprintf(w, "# Import template for project %q: replace the example row with one row per item\n", project.Title)
+	for _, column := range columns {
+		fmt.Fprintf(w, "# %s: %s\n", column.Name, column.Comment)
+	}
+
+	header := make([]string, len(columns))
+	example := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+		example[i] = fmt.Sprint(column.Example)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	writer.Write(example)
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTemplateJSON writes the columns as an array holding the example item,
+// with a "// name" key describing each column before it
+func writeTemplateJSON(w io.Writer, columns []templateColumn) error {
+	lines := make([]string, 0, 2*len(columns))
+	for _, column := range columns {
+		for _, pair := range [][2]interface{}{{"// " + column.Name, column.Comment}, {column.Name, column.Example}} {
+			key, err := json.Marshal(pair[0])
+			if err != nil {
+				return fmt.Errorf("failed to marshal template: %w", err)
+			}
+			value, err := json.Marshal(pair[1])
+			if err != nil {
+				return fmt.Errorf("failed to marshal template: %w", err)
+			}
+			lines = append(lines, fmt.Sprintf("    %s: %s", key, value))
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "[\n  {\n%s\n  }\n]\n", strings.Join(lines, ",\n"))
+	return err
+}
@@ -0,0 +1,105 @@
+// Tests for the template subcommand
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// templateFields is a project schema with every kind of field
+var templateFields = []ghclient.ProjectField{
+	{ID: "f0", Name: "Title", Type: "TITLE"},
+	{ID: "f1", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "o1", Name: "Todo"}, {ID: "o2", Name: "In Progress"}}},
+	{ID: "f2", Name: "Estimate", Type: "NUMBER"},
+	{ID: "f3", Name: "Due", Type: "DATE"},
+	{ID: "f4", Name: "Sprint", Type: "ITERATION", Iterations: []ghclient.IterationOption{{ID: "i1", Title: "Sprint 1", StartDate: "2024-04-15", Duration: 14, Completed: true}, {ID: "i2", Title: "Sprint 2", StartDate: "2024-04-29", Duration: 14}}},
+	{ID: "f5", Name: "Summary", Type: "TEXT"},
+	{ID: "f6", Name: "Milestone", Type: "MILESTONE"},
+	{ID: "f7", Name: "Reviewers", Type: "REVIEWERS"},
+}
+
+func TestTemplateColumns(t *testing.T) {
+	today := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	columns := templateColumns(templateFields, "csv", today)
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	expected := []string{"title", "url", "body", "Status", "Estimate", "Due", "Sprint", "Summary", "Milestone"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected columns %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected columns %v, got %v", expected, names)
+			break
+		}
+	}
+
+	if columns[3].Comment != "one of: Todo | In Progress" || columns[3].Example != "Todo" {
+		t.Errorf("Unexpected Status column: %+v", columns[3])
+	}
+	if columns[5].Example != "2024-05-01" {
+		t.Errorf("Expected today's date as the example, got %v", columns[5].Example)
+	}
+	if columns[6].Example != "Sprint 2" {
+		t.Errorf("Expected the first open iteration as the example, got %v", columns[6].Example)
+	}
+	if templateColumns(templateFields, "json", today)[2].Name != "notes" {
+		t.Errorf("Expected JSON templates to use notes for the body")
+	}
+}
+
+func TestRunTemplateRoundTrip(t *testing.T) {
+	client := &schemaClient{
+		project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"},
+		fields:  templateFields,
+	}
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range templateFields {
+		fieldMap[field.Name] = field
+	}
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runTemplate(context.Background(), client, TemplateConfig{Project: "owner/project", Format: format}, &out); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !contains(out.String(), "one of: Todo | In Progress") {
+				t.Errorf("Expected the Status options in the template, got:\n%s", out.String())
+			}
+
+			path := filepath.Join(t.TempDir(), "template."+format)
+			if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			items, err := parser.ParseSourceFile(path, parser.SourceOptions{})
+			if err != nil {
+				t.Fatalf("Expected the template to parse, got: %v\n%s", err, out.String())
+			}
+			if len(items) != 1 || items[0].Title != "Example item" || parser.GetItemBody(items[0]) != "Describe the item here" {
+				t.Fatalf("Expected the example item, got %+v", items)
+			}
+			for _, issue := range mapping.ValidateItemFields(items, fieldMap, mapping.OptionMatcher{}, false) {
+				if issue.Severity != mapping.SeverityNotice {
+					t.Errorf("Expected the example row to be valid, got: %+v", issue)
+				}
+			}
+		})
+	}
+
+	err := runTemplate(context.Background(), client, TemplateConfig{Project: "owner/project", Format: "xml"}, &bytes.Buffer{})
+	if err == nil || !contains(err.Error(), "unsupported format") {
+		t.Errorf("Expected an unsupported format error, got: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Validate subcommand for checking sources before an import
+// Parses the sources and checks them against the live project schema without modifying anything, for use as a CI gate
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/internal/report"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+// ValidateConfig holds the options for the validate subcommand
+type ValidateConfig struct {
+	Sources       []string
+	Project       string
+	Profile       string
+	Mapping       string
+	StrictOptions bool
+	KeepTemp      bool
+	CSV           parser.CSVDialect
+	SourceFormat  string
+	SourceHeaders []string
+	MaxSizes      []string
+	MaxWarnings   int
+	Verbose       bool
+}
+
+// newValidateCmd creates the validate subcommand
+func newValidateCmd() *cobra.Command {
+	var config ValidateConfig
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check sources against a project without importing them",
+		Long: `Parse the sources and check every item and field value against the
+destination project's live schema. The project is only read, so a token
+without write access is enough. Exits non-zero when there are errors or more
+warnings than --max-warnings, which makes it suitable as a CI check on
+backlog files kept in git.
+
+Examples:
+  gh project-import validate --source backlog.csv --project "owner/project-name"
+  gh project-import validate --source "backlog/*.json" --project "owner/project-name" --max-warnings 5`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return withExitCode(exitAuth, fmt.Errorf("failed to create GitHub client: %w", err))
+			}
+			return runValidate(cmd.Context(), client, config)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&config.Sources, "source", "s", nil, "Source file, glob pattern or http(s) URL to check; repeat to check several (required)")
+	cmd.Flags().StringVarP(&config.Project, "project", "p", "", "Destination project identifier (format: owner/project-name or project-number) (required)")
+	cmd.Flags().StringVar(&config.Profile, "profile", "", "Read the source as an export from another tool")
+	cmd.Flags().StringVar(&config.Mapping, "mapping", "", "JSON file mapping CSV column names to importer columns, with optional single-select option aliases")
+	cmd.Flags().BoolVar(&config.StrictOptions, "strict-options", false, "Match single-select values to options only when the case is exact")
+	cmd.Flags().BoolVar(&config.KeepTemp, "keep-temp", false, "Keep the temporary copies of downloaded or decompressed sources for debugging")
+	cmd.Flags().StringSliceVar(&config.MaxSizes, "max-size", nil, "Maximum length in characters of a value, as name=size for title, body or a field (defaults: title=256, body=65536)")
+	cmd.Flags().IntVar(&config.MaxWarnings, "max-warnings", 0, "Fail if there are more warnings than this (-1 for no limit)")
+	cmd.Flags().BoolVarP(&config.Verbose, "verbose", "v", false, "Also list the fields that are compatible")
+	addCSVDialectFlags(cmd, &config.CSV)
+	addSourceFormatFlags(cmd, &config.SourceFormat, &config.SourceHeaders)
+
+	cmd.MarkFlagRequired("source")
+	cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// runValidate checks the sources against the project and fails if they
+// would not import cleanly. Only the project and its fields are read.
+func runValidate(ctx context.Context, client ghclient.Client, config ValidateConfig) error {
+	options := parser.SourceOptions{Profile: config.Profile, MappingFile: config.Mapping, KeepTemp: config.KeepTemp, CSV: config.CSV, Format: config.SourceFormat, Headers: config.SourceHeaders, Timeout: clientOptions.RequestTimeout}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	sizeLimits, err := mapping.ParseSizeLimits(config.MaxSizes)
+	if err != nil {
+		return err
+	}
+	matcher := mapping.OptionMatcher{Strict: config.StrictOptions}
+	if config.Mapping != "" {
+		matcher.Aliases, err = parser.LoadOptionAliases(config.Mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	items, err := parser.ParseSourcesContext(ctx, config.Sources, options)
+	if err != nil {
+		return withExitCode(exitInvalid, err)
+	}
+	if len(items) == 0 {
+		return withExitCode(exitInvalid, fmt.Errorf("no items found to import"))
+	}
+
+	project, err := client.FindProject(ctx, config.Project)
+	if err != nil {
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project fields: %w", err)
+	}
+	fieldMap := make(map[string]ghclient.ProjectField)
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+	}
+
+	var issues []mapping.ValidationIssue
+	for i, item := range items {
+		// Missing titles are reported by ValidateEveryItemField
+		if item.Title == "" {
+			continue
+		}
+		if err := parser.ValidateImportItem(item); err != nil {
+			issues = append(issues, mapping.ValidationIssue{Severity: mapping.SeverityError, Message: fmt.Sprintf("Item %d%s: %v", i+1, itemOrigin(item), err)})
+		}
+	}
+
+	issues = append(issues, mapping.ValidateEveryItemField(items, fieldMap, matcher, config.Verbose)...)
+
+	// Values over their limit would be truncated on import
+	truncations, err := mapping.ApplySizeLimits(items, sizeLimits, mapping.OversizeTruncate)
+	if err != nil {
+		return err
+	}
+	for _, t := range truncations {
+		issues = append(issues, mapping.ValidationIssue{Severity: mapping.SeverityWarning, Message: fmt.Sprintf("Item %d ('%s'): %s is %d characters, over the limit of %d", t.Item, t.Title, t.Field, t.Original, t.Limit)})
+	}
+
+	if len(issues) > 0 {
+		report.PrintValidationIssues(issues, report.Color)
+	}
+
+	errors := mapping.CountValidationIssues(issues, mapping.SeverityError)
+	warnings := mapping.CountValidationIssues(issues, mapping.SeverityWarning)
+	if errors > 0 || (config.MaxWarnings >= 0 && warnings > config.MaxWarnings) {
+		return withExitCode(exitInvalid, fmt.Errorf("validation failed with %d errors and %d warnings (--max-warnings %d)", errors, warnings, config.MaxWarnings))
+	}
+
+	fmt.Println(report.Success("%d items are valid for project '%s'", len(items), project.Title))
+	return nil
+}
+
+// itemOrigin describes where an item was read from, if known
+func itemOrigin(item parser.ImportItem) string {
+	if item.Origin == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", item.Origin)
+}
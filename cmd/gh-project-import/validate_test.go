@@ -0,0 +1,59 @@
+// Tests for the validate subcommand
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestRunValidate(t *testing.T) {
+	// schemaClient has no mutations, so any write would panic
+	client := &schemaClient{
+		project: &ghclient.Project{ID: "PVT_test", Title: "Test Project"},
+		fields:  templateFields,
+	}
+	tmpDir := t.TempDir()
+	writeSource := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	valid := writeSource("valid.csv", "Title,Status,Estimate\nOne,Todo,1\nTwo,in progress,2\n")
+	out := captureStdout(t, func() {
+		if err := runValidate(context.Background(), client, ValidateConfig{Sources: []string{valid}, Project: "owner/project"}); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+	if !contains(out, "2 items are valid") {
+		t.Errorf("Expected a success message, got:\n%s", out)
+	}
+
+	// The bad option is in the second item, past the first Status value
+	invalid := writeSource("invalid.csv", "Title,Status,URL\nOne,Todo,\nTwo,Blocked,\nThree,In Progress,https://gitlab.com/o/r/-/issues/1\n")
+	out = captureStdout(t, func() {
+		err := runValidate(context.Background(), client, ValidateConfig{Sources: []string{invalid}, Project: "owner/project", MaxWarnings: -1})
+		if err == nil || !contains(err.Error(), "1 errors and 1 warnings") {
+			t.Errorf("Expected the invalid URL and option to fail validation, got: %v", err)
+		}
+	})
+	if !contains(out, "'Blocked' not found (used in item 2") || !contains(out, "Item 3") {
+		t.Errorf("Expected both problems to be listed, got:\n%s", out)
+	}
+
+	warning := writeSource("warning.csv", "Title,Team\nOne,Core\n")
+	captureStdout(t, func() {
+		if err := runValidate(context.Background(), client, ValidateConfig{Sources: []string{warning}, Project: "owner/project"}); err == nil {
+			t.Error("Expected a warning to fail validation by default")
+		}
+		if err := runValidate(context.Background(), client, ValidateConfig{Sources: []string{warning}, Project: "owner/project", MaxWarnings: 1}); err != nil {
+			t.Errorf("Expected one warning to be allowed, got: %v", err)
+		}
+	})
+}
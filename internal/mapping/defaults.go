@@ -0,0 +1,76 @@
+// Default field values
+// Fills in fields that items leave empty (--default)
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// FieldDefault is a value given to items that don't set the field
+type FieldDefault struct {
+	Field string
+	Value interface{}
+}
+
+// FieldDefaults fill in missing field values. The zero value changes nothing.
+type FieldDefaults []FieldDefault
+
+// ParseFieldDefaults parses --default values of the form FIELD=VALUE. Values
+// that look like numbers become numbers, as in CSV sources.
+func ParseFieldDefaults(values []string) (FieldDefaults, error) {
+	var defaults FieldDefaults
+	seen := make(map[string]bool)
+	for _, value := range values {
+		field, text, ok := strings.Cut(value, "=")
+		field, text = strings.TrimSpace(field), strings.TrimSpace(text)
+		if !ok || field == "" || text == "" {
+			return nil, fmt.Errorf("invalid --default %q (expected FIELD=VALUE, e.g. Status=Todo)", value)
+		}
+		if seen[strings.ToLower(field)] {
+			return nil, fmt.Errorf("--default is given twice for field %q", field)
+		}
+		seen[strings.ToLower(field)] = true
+
+		var fieldValue interface{} = text
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			if number == float64(int64(number)) {
+				fieldValue = int64(number)
+			} else {
+				fieldValue = number
+			}
+		}
+		defaults = append(defaults, FieldDefault{Field: field, Value: fieldValue})
+	}
+	return defaults, nil
+}
+
+// Apply sets the default values on the items that have no value for the
+// field, matching field names case-insensitively
+func (d FieldDefaults) Apply(items []parser.ImportItem) {
+	for i := range items {
+		item := &items[i]
+		for _, def := range d {
+			if hasFieldValue(item.Fields, def.Field) {
+				continue
+			}
+			if item.Fields == nil {
+				item.Fields = make(map[string]interface{})
+			}
+			item.Fields[def.Field] = def.Value
+		}
+	}
+}
+
+// hasFieldValue reports whether fields holds a non-empty value for name
+func hasFieldValue(fields map[string]interface{}, name string) bool {
+	for key, value := range fields {
+		if strings.EqualFold(key, name) && value != nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
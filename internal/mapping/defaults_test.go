@@ -0,0 +1,39 @@
+// Tests for default field values
+package mapping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestFieldDefaults(t *testing.T) {
+	defaults, err := ParseFieldDefaults([]string{"Status=Todo", "Estimate = 3", "Team=Platform Core"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items := []parser.ImportItem{
+		{Title: "No fields"},
+		{Title: "Has status", Fields: map[string]interface{}{"status": "Done", "Team": ""}},
+	}
+	defaults.Apply(items)
+
+	want := map[string]interface{}{"Status": "Todo", "Estimate": int64(3), "Team": "Platform Core"}
+	if !reflect.DeepEqual(items[0].Fields, want) {
+		t.Errorf("Expected %v, got %v", want, items[0].Fields)
+	}
+	want = map[string]interface{}{"status": "Done", "Estimate": int64(3), "Team": "Platform Core"}
+	if !reflect.DeepEqual(items[1].Fields, want) {
+		t.Errorf("Expected the item's own status to be kept, got %v", items[1].Fields)
+	}
+}
+
+func TestParseFieldDefaultsErrors(t *testing.T) {
+	for _, values := range [][]string{{"Status"}, {"=Todo"}, {"Status="}, {"Status=Todo", "status=Done"}} {
+		if _, err := ParseFieldDefaults(values); err == nil {
+			t.Errorf("Expected an error for %q", values)
+		}
+	}
+}
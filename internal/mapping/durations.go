@@ -0,0 +1,151 @@
+// Duration conversions for estimate and time-tracking fields
+// Reads Jira and Tempo style durations such as "1w 2d 3h" or "90m" into numbers of hours, days or points
+package mapping
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// Units a duration can be converted to with --duration
+const (
+	DurationHours  = "hours"
+	DurationDays   = "days"
+	DurationPoints = "points"
+)
+
+// DurationConversions converts duration strings in number fields. Fields maps
+// lowercased field names to the unit they are converted to. Weeks and days
+// are working weeks and days, as in Jira's time tracking.
+type DurationConversions struct {
+	Fields        map[string]string
+	HoursPerDay   float64
+	DaysPerWeek   float64
+	HoursPerPoint float64
+}
+
+// ParseDurationConversions parses --duration values of the form FIELD=UNIT
+// and checks the conversion factors the units need
+func ParseDurationConversions(values []string, hoursPerDay, daysPerWeek, hoursPerPoint float64) (DurationConversions, error) {
+	conversions := DurationConversions{HoursPerDay: hoursPerDay, DaysPerWeek: daysPerWeek, HoursPerPoint: hoursPerPoint}
+	if hoursPerDay <= 0 || daysPerWeek <= 0 {
+		return conversions, fmt.Errorf("--hours-per-day and --days-per-week must be positive")
+	}
+
+	for _, value := range values {
+		field, unit, ok := strings.Cut(value, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		unit = strings.ToLower(strings.TrimSpace(unit))
+		if !ok || field == "" {
+			return conversions, fmt.Errorf("invalid --duration %q (expected FIELD=UNIT, e.g. Estimate=hours)", value)
+		}
+		switch unit {
+		case DurationHours, DurationDays:
+		case DurationPoints:
+			if hoursPerPoint <= 0 {
+				return conversions, fmt.Errorf("--duration %q needs --hours-per-point", value)
+			}
+		default:
+			return conversions, fmt.Errorf("unknown --duration unit %q (available: %s, %s, %s)", unit, DurationHours, DurationDays, DurationPoints)
+		}
+		if conversions.Fields == nil {
+			conversions.Fields = make(map[string]string)
+		}
+		conversions.Fields[field] = unit
+	}
+	return conversions, nil
+}
+
+// Apply replaces the duration strings in the configured fields with numbers.
+// Values that are already plain numbers are taken to be in the field's unit
+// and left alone. offset is the position of the first item in the source,
+// used in error messages.
+func (c DurationConversions) Apply(items []parser.ImportItem, offset int) error {
+	if len(c.Fields) == 0 {
+		return nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		for name, value := range item.Fields {
+			unit, ok := c.Fields[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			str, ok := value.(string)
+			if !ok || strings.TrimSpace(str) == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+				continue
+			}
+
+			hours, err := c.parseHours(str)
+			if err != nil {
+				return fmt.Errorf("item %d (%q): cannot convert %s to %s: %w", offset+i+1, item.Title, name, unit, err)
+			}
+			item.Fields[name] = c.convert(hours, unit)
+		}
+	}
+	return nil
+}
+
+// convert expresses a number of hours in unit, rounded to two decimals
+func (c DurationConversions) convert(hours float64, unit string) float64 {
+	switch unit {
+	case DurationDays:
+		hours /= c.HoursPerDay
+	case DurationPoints:
+		hours /= c.HoursPerPoint
+	}
+	return math.Round(hours*100) / 100
+}
+
+// durationPartPattern matches one part of a duration, e.g. "2d", "1.5 hours" or "90m"
+var durationPartPattern = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*([a-z]+)`)
+
+// durationUnitNames maps the spellings of duration units to w, d, h, m or s
+var durationUnitNames = map[string]byte{
+	"w": 'w', "wk": 'w', "wks": 'w', "week": 'w', "weeks": 'w',
+	"d": 'd', "day": 'd', "days": 'd',
+	"h": 'h', "hr": 'h', "hrs": 'h', "hour": 'h', "hours": 'h',
+	"m": 'm', "min": 'm', "mins": 'm', "minute": 'm', "minutes": 'm',
+	"s": 's', "sec": 's', "secs": 's', "second": 's', "seconds": 's',
+}
+
+// parseHours reads a duration such as "1w 2d 3h", "1h30m" or "90m" as a number of hours
+func (c DurationConversions) parseHours(value string) (float64, error) {
+	rest := strings.TrimSpace(value)
+	hours := 0.0
+	for rest != "" {
+		match := durationPartPattern.FindStringSubmatch(rest)
+		unit, known := byte(0), false
+		if match != nil {
+			unit, known = durationUnitNames[strings.ToLower(match[2])]
+		}
+		if !known {
+			return 0, fmt.Errorf("%q is not a duration (expected e.g. 1w 2d 3h or 90m)", value)
+		}
+
+		amount, _ := strconv.ParseFloat(match[1], 64)
+		switch unit {
+		case 'w':
+			hours += amount * c.DaysPerWeek * c.HoursPerDay
+		case 'd':
+			hours += amount * c.HoursPerDay
+		case 'h':
+			hours += amount
+		case 'm':
+			hours += amount / 60
+		case 's':
+			hours += amount / 3600
+		}
+		rest = strings.TrimLeft(rest[len(match[0]):], " ,")
+	}
+	return hours, nil
+}
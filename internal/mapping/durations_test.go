@@ -0,0 +1,87 @@
+// Tests for duration conversions in number fields
+package mapping
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestParseHours(t *testing.T) {
+	conversions := DurationConversions{HoursPerDay: 8, DaysPerWeek: 5}
+	tests := []struct {
+		value    string
+		expected float64
+	}{
+		{"1w 2d 3h", 59},
+		{"90m", 1.5},
+		{"1h30m", 1.5},
+		{"2 days, 4 hours", 20},
+		{"1.5h", 1.5},
+		{"7200s", 2},
+	}
+	for _, tt := range tests {
+		hours, err := conversions.parseHours(tt.value)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tt.value, err)
+			continue
+		}
+		if hours != tt.expected {
+			t.Errorf("parseHours(%q) = %v, expected %v", tt.value, hours, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"soon", "3 months", "1w two days"} {
+		if _, err := conversions.parseHours(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseDurationConversions(t *testing.T) {
+	conversions, err := ParseDurationConversions([]string{"Estimate=Hours", "Time Spent=days"}, 8, 5, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conversions.Fields["estimate"] != DurationHours || conversions.Fields["time spent"] != DurationDays {
+		t.Errorf("Unexpected fields: %v", conversions.Fields)
+	}
+
+	for _, value := range []string{"Estimate", "=hours", "Estimate=fortnights", "Estimate=points"} {
+		if _, err := ParseDurationConversions([]string{value}, 8, 5, 0); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+	if _, err := ParseDurationConversions(nil, 0, 5, 0); err == nil {
+		t.Error("Expected an error for a zero-hour working day")
+	}
+}
+
+func TestApplyDurationConversions(t *testing.T) {
+	conversions, err := ParseDurationConversions([]string{"estimate=points", "time spent=days"}, 6, 4, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items := []parser.ImportItem{
+		{Title: "Plan", Fields: map[string]interface{}{"Estimate": "1w", "Time Spent": "9h", "Notes": "2d"}},
+		{Title: "Ship", Fields: map[string]interface{}{"Estimate": "3", "Time Spent": float64(2)}},
+	}
+
+	if err := conversions.Apply(items, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A 4-day week of 6-hour days is 24 hours, or 6 points of 4 hours
+	if items[0].Fields["Estimate"] != float64(6) || items[0].Fields["Time Spent"] != 1.5 || items[0].Fields["Notes"] != "2d" {
+		t.Errorf("Unexpected first item: %v", items[0].Fields)
+	}
+	// Plain numbers are already in the field's unit
+	if items[1].Fields["Estimate"] != "3" || items[1].Fields["Time Spent"] != float64(2) {
+		t.Errorf("Expected plain numbers to be kept, got %v", items[1].Fields)
+	}
+
+	err = conversions.Apply([]parser.ImportItem{{Title: "Bad", Fields: map[string]interface{}{"Estimate": "a while"}}}, 4)
+	if err == nil || !strings.Contains(err.Error(), "item 5") {
+		t.Errorf("Expected an error naming item 5, got: %v", err)
+	}
+}
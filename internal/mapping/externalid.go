@@ -0,0 +1,49 @@
+// External IDs
+// Moves the external_id column of items into the text field that keeps it in the project (--external-id-field)
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ExternalIDColumn is the source column holding the ID an item has in the
+// system it came from
+const ExternalIDColumn = "external_id"
+
+// MoveExternalIDs moves the external_id column of each item, matched
+// case-insensitively, into field. IDs are stored as text, so an ID a source
+// read as a number keeps its digits, e.g. 1200 rather than 1.2e+03. It
+// returns the number of items that have an ID.
+func MoveExternalIDs(items []parser.ImportItem, field string) int {
+	count := 0
+	for _, item := range items {
+		for name, value := range item.Fields {
+			if !strings.EqualFold(name, ExternalIDColumn) {
+				continue
+			}
+			delete(item.Fields, name)
+			if id := externalIDText(value); id != "" {
+				item.Fields[field] = id
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// externalIDText formats an external ID as text
+func externalIDText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
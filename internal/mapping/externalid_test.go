@@ -0,0 +1,36 @@
+// Tests for external IDs
+package mapping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestMoveExternalIDs(t *testing.T) {
+	items := []parser.ImportItem{
+		{Title: "CSV", Fields: map[string]interface{}{"external_id": " ABC-1 ", "Status": "Todo"}},
+		{Title: "Number", Fields: map[string]interface{}{"External_ID": int64(1200)}},
+		{Title: "JSON number", Fields: map[string]interface{}{"external_id": float64(1200000)}},
+		{Title: "Empty", Fields: map[string]interface{}{"external_id": ""}},
+		{Title: "None"},
+	}
+
+	if count := MoveExternalIDs(items, "Jira Key"); count != 3 {
+		t.Errorf("Expected 3 items with an ID, got %d", count)
+	}
+
+	expected := []map[string]interface{}{
+		{"Jira Key": "ABC-1", "Status": "Todo"},
+		{"Jira Key": "1200"},
+		{"Jira Key": "1200000"},
+		{},
+		nil,
+	}
+	for i, item := range items {
+		if !reflect.DeepEqual(item.Fields, expected[i]) {
+			t.Errorf("%s: expected %v, got %v", item.Title, expected[i], item.Fields)
+		}
+	}
+}
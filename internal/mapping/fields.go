@@ -0,0 +1,254 @@
+// Field value conversion for the Projects v2 API
+// Converts source values to the input each field type expects, and checks items against the project's fields before import
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ErrNoSuchOption matches the conversion errors of values that are none of
+// the options of a single-select field or iterations of an iteration field
+var ErrNoSuchOption = errors.New("no such option")
+
+// optionNotFound is a conversion error matching ErrNoSuchOption
+type optionNotFound struct {
+	message string
+}
+
+func (e optionNotFound) Error() string {
+	return e.message
+}
+
+func (e optionNotFound) Is(target error) bool {
+	return target == ErrNoSuchOption
+}
+
+// ConvertFieldValue converts a field value to the appropriate format for the GitHub GraphQL API
+func ConvertFieldValue(value interface{}, field ghclient.ProjectField) (interface{}, error) {
+	return ConvertFieldValueWith(value, field, OptionMatcher{})
+}
+
+// ConvertFieldValueWith converts a field value, matching single-select
+// values to options with matcher
+func ConvertFieldValueWith(value interface{}, field ghclient.ProjectField, matcher OptionMatcher) (interface{}, error) {
+	switch field.Type {
+	case "TEXT":
+		if str, ok := value.(string); ok {
+			return map[string]interface{}{"text": str}, nil
+		}
+		return map[string]interface{}{"text": fmt.Sprintf("%v", value)}, nil
+
+	case "NUMBER":
+		var num float64
+		switch v := value.(type) {
+		case float64:
+			num = v
+		case int64:
+			num = float64(v)
+		case int:
+			num = float64(v)
+		case string:
+			var err error
+			num, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert '%s' to number", v)
+			}
+		default:
+			return nil, fmt.Errorf("cannot convert %T to number", value)
+		}
+		return map[string]interface{}{"number": num}, nil
+
+	case "DATE":
+		if str, ok := value.(string); ok {
+			// Validate ISO date format
+			if !strings.Contains(str, "T") {
+				// Add time if not present
+				str += "T00:00:00Z"
+			}
+			return map[string]interface{}{"date": str}, nil
+		}
+		return nil, fmt.Errorf("date field must be a string in ISO format")
+
+	case "SINGLE_SELECT":
+		if str, ok := value.(string); ok {
+			if option, ok := matcher.Find(field, str); ok {
+				return map[string]interface{}{"singleSelectOptionId": option.ID}, nil
+			}
+			if matcher.Strict {
+				if option, ok := (OptionMatcher{Aliases: matcher.Aliases}).Find(field, str); ok {
+					return nil, optionNotFound{fmt.Sprintf("single-select option '%s' not found (did you mean '%s'? --strict-options requires the exact case)", str, option.Name)}
+				}
+			}
+			return nil, optionNotFound{fmt.Sprintf("single-select option '%s' not found", str)}
+		}
+		return nil, fmt.Errorf("single-select field must be a string")
+
+	case "USER":
+		// setItemFields resolves logins to node IDs before converting the
+		// value, so here the IDs are only known to be logins
+		logins, err := parseLogins(value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"assigneeIds": logins}, nil
+
+	case "ITERATION":
+		if str, ok := value.(string); ok {
+			if iteration, ok := FindIteration(field.Iterations, str); ok {
+				return map[string]interface{}{"iterationId": iteration.ID}, nil
+			}
+			return nil, optionNotFound{fmt.Sprintf("iteration '%s' not found (expected an iteration title or a date within an iteration)", str)}
+		}
+		return nil, fmt.Errorf("iteration field must be a string")
+
+	default:
+		return nil, fmt.Errorf("unsupported field type: %s", field.Type)
+	}
+}
+
+// FindIteration matches a value to an iteration by title, exactly and then
+// ignoring case, or by a date (YYYY-MM-DD or RFC 3339) within the iteration
+func FindIteration(iterations []ghclient.IterationOption, value string) (ghclient.IterationOption, bool) {
+	for _, iteration := range iterations {
+		if iteration.Title == value {
+			return iteration, true
+		}
+	}
+	for _, iteration := range iterations {
+		if strings.EqualFold(iteration.Title, value) {
+			return iteration, true
+		}
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		parsed, rfcErr := time.Parse(time.RFC3339, value)
+		if rfcErr != nil {
+			return ghclient.IterationOption{}, false
+		}
+		date = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	for _, iteration := range iterations {
+		start, err := time.Parse("2006-01-02", iteration.StartDate)
+		if err != nil {
+			continue
+		}
+		if !date.Before(start) && date.Before(start.AddDate(0, 0, iteration.Duration)) {
+			return iteration, true
+		}
+	}
+	return ghclient.IterationOption{}, false
+}
+
+// ValidateItemFields validates that item fields are compatible with project schema
+func ValidateItemFields(items []parser.ImportItem, fieldMap map[string]ghclient.ProjectField, matcher OptionMatcher, verbose bool) []ValidationIssue {
+	return ValidateItemFieldsFrom(items, 0, make(map[string]bool), fieldMap, matcher, verbose)
+}
+
+// ValidateEveryItemField validates like ValidateItemFields, and also checks
+// the values of every later item, which ValidateItemFields skips once a field
+// has been seen
+func ValidateEveryItemField(items []parser.ImportItem, fieldMap map[string]ghclient.ProjectField, matcher OptionMatcher, verbose bool) []ValidationIssue {
+	return ValidateEveryItemFieldFrom(items, 0, make(map[string]bool), fieldMap, matcher, verbose)
+}
+
+// ValidateEveryItemFieldFrom validates a chunk of items like
+// ValidateItemFieldsFrom, and also checks the values of every item of fields
+// already in seenFields, so chunked imports can check every value
+func ValidateEveryItemFieldFrom(items []parser.ImportItem, offset int, seenFields map[string]bool, fieldMap map[string]ghclient.ProjectField, matcher OptionMatcher, verbose bool) []ValidationIssue {
+	// The first value of a field not seen before is checked by ValidateItemFieldsFrom
+	firstUse := make(map[string]int)
+	for i, item := range items {
+		for fieldName := range item.Fields {
+			if _, ok := firstUse[fieldName]; !ok && !seenFields[fieldName] {
+				firstUse[fieldName] = i
+			}
+		}
+	}
+	issues := ValidateItemFieldsFrom(items, offset, seenFields, fieldMap, matcher, verbose)
+
+	for i, item := range items {
+		for fieldName, fieldValue := range item.Fields {
+			field, exists := fieldMap[fieldName]
+			if !exists || IsMilestoneField(fieldName) {
+				continue
+			}
+			if first, ok := firstUse[fieldName]; ok && first == i {
+				continue
+			}
+			if _, err := ConvertFieldValueWith(fieldValue, field, matcher); err != nil {
+				issues = append(issues, ValidationIssue{SeverityWarning, fmt.Sprintf("Field '%s' validation failed: %v (used in item %d: '%s')", fieldName, err, offset+i+1, item.Title)})
+			}
+		}
+	}
+
+	return issues
+}
+
+// ValidateItemFieldsFrom validates a chunk of items whose first item is at
+// position offset in the source. Fields already in seenFields aren't checked
+// again, so each field is reported once however many chunks use it.
+func ValidateItemFieldsFrom(items []parser.ImportItem, offset int, seenFields map[string]bool, fieldMap map[string]ghclient.ProjectField, matcher OptionMatcher, verbose bool) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for i, item := range items {
+		for fieldName, fieldValue := range item.Fields {
+			// Track which fields we've seen
+			if !seenFields[fieldName] && !IsMilestoneField(fieldName) {
+				seenFields[fieldName] = true
+
+				field, exists := fieldMap[fieldName]
+				if !exists {
+					issues = append(issues, ValidationIssue{SeverityWarning, fmt.Sprintf("Field '%s' not found in project (used in item %d: '%s')", fieldName, offset+i+1, item.Title)})
+					continue
+				}
+
+				// Try to validate the field value
+				_, err := ConvertFieldValueWith(fieldValue, field, matcher)
+				if err != nil {
+					issues = append(issues, ValidationIssue{SeverityWarning, fmt.Sprintf("Field '%s' validation failed: %v (used in item %d: '%s')", fieldName, err, offset+i+1, item.Title)})
+					continue
+				}
+
+				// Report values that are accepted only after normalization
+				if str, ok := fieldValue.(string); ok {
+					switch {
+					case field.Type == "NUMBER":
+						issues = append(issues, ValidationIssue{SeverityNotice, fmt.Sprintf("Field '%s' text value '%s' will be converted to a number", fieldName, str)})
+					case field.Type == "DATE" && !strings.Contains(str, "T"):
+						issues = append(issues, ValidationIssue{SeverityNotice, fmt.Sprintf("Field '%s' date '%s' will be set at midnight UTC", fieldName, str)})
+					case field.Type == "SINGLE_SELECT":
+						if option, _ := matcher.Find(field, str); option.Name != str {
+							issues = append(issues, ValidationIssue{SeverityNotice, fmt.Sprintf("Field '%s' value '%s' will be set to option '%s'", fieldName, str, option.Name)})
+						}
+					}
+				}
+
+				if verbose {
+					issues = append(issues, ValidationIssue{SeverityNotice, fmt.Sprintf("Field '%s' (%s) is compatible", fieldName, field.Type)})
+				}
+			}
+		}
+	}
+
+	// Check for problems that will make an item fail to import
+	// Note: GitHub Projects v2 doesn't have traditional "required" fields,
+	// but we can check if common fields like Title are missing
+	for i, item := range items {
+		if item.Title == "" {
+			issues = append(issues, ValidationIssue{SeverityError, fmt.Sprintf("Item %d is missing a title", offset+i+1)})
+		}
+		if itemType := parser.GetItemType(item); (itemType == "Issue" || itemType == "PullRequest") && item.URL == "" {
+			issues = append(issues, ValidationIssue{SeverityError, fmt.Sprintf("Item %d ('%s') has type %s but no URL", offset+i+1, item.Title, itemType)})
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,45 @@
+// Field filters for --only-fields and --skip-fields
+// Drops the custom fields a run shouldn't set, matching names against glob patterns
+package mapping
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ValidateFieldPatterns checks that every field filter is a valid glob pattern
+func ValidateFieldPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid field pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// FilterItemFields removes the custom fields excluded by the --only-fields and
+// --skip-fields patterns. Patterns match field names case-insensitively.
+func FilterItemFields(items []parser.ImportItem, only, skip []string) {
+	for _, item := range items {
+		for name := range item.Fields {
+			if len(only) > 0 && !matchesFieldPattern(name, only) {
+				delete(item.Fields, name)
+			} else if matchesFieldPattern(name, skip) {
+				delete(item.Fields, name)
+			}
+		}
+	}
+}
+
+// matchesFieldPattern reports whether a field name matches any of the patterns
+func matchesFieldPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(name)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Transform hooks
+// Pipes each item through an external program that may change or drop it (--hook-transform)
+package mapping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// TransformHook runs an external program on every item. The program reads
+// the item on stdin as a JSON object in the source format and writes the item
+// to import on stdout, or nothing (or null) to drop it. The zero value leaves
+// items unchanged.
+type TransformHook struct {
+	// Command is the program followed by its arguments, split on whitespace
+	// without a shell
+	Command []string
+}
+
+// ParseTransformHook parses the --hook-transform value
+func ParseTransformHook(command string) (TransformHook, error) {
+	if command == "" {
+		return TransformHook{}, nil
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return TransformHook{}, fmt.Errorf("invalid --hook-transform %q: no command", command)
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return TransformHook{}, fmt.Errorf("invalid --hook-transform: %w", err)
+	}
+	return TransformHook{Command: fields}, nil
+}
+
+// Apply runs the program once per item and returns the items it kept, in
+// order. The program's stderr is passed through; a failed run or output that
+// isn't an item stops the import. offset is the position of the first item
+// in the source, used in error messages.
+func (h TransformHook) Apply(ctx context.Context, items []parser.ImportItem, offset int) ([]parser.ImportItem, error) {
+	if len(h.Command) == 0 {
+		return items, nil
+	}
+
+	kept := items[:0]
+	for i, item := range items {
+		transformed, keep, err := h.run(ctx, item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d (%q): --hook-transform %s: %w", offset+i+1, item.Title, h.Command[0], err)
+		}
+		if keep {
+			kept = append(kept, transformed)
+		}
+	}
+	return kept, nil
+}
+
+// run pipes a single item through the program, reporting whether it was kept
+func (h TransformHook) run(ctx context.Context, item parser.ImportItem) (parser.ImportItem, bool, error) {
+	input, err := json.Marshal(parser.ItemRecord(item))
+	if err != nil {
+		return item, false, fmt.Errorf("failed to encode item: %w", err)
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return item, false, err
+	}
+
+	trimmed := bytes.TrimSpace(output.Bytes())
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return item, false, nil
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(trimmed, &record); err != nil {
+		return item, false, fmt.Errorf("output is not a JSON object: %w", err)
+	}
+	transformed, err := parser.ParseItemRecord(record)
+	if err != nil {
+		return item, false, err
+	}
+	transformed.Origin = item.Origin
+	return transformed, true, nil
+}
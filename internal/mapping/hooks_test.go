@@ -0,0 +1,83 @@
+// Tests for transform hooks
+package mapping
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// writeHookScript writes a shell script that drops items titled "Drop...",
+// fails on items titled "Fail..." and otherwise marks Todo items Done
+func writeHookScript(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script needs a POSIX shell")
+	}
+	script := `#!/bin/sh
+input=$(cat)
+case "$input" in
+  *'"title":"Drop'*) ;;
+  *'"title":"Fail'*) echo "cannot transform" >&2; exit 3 ;;
+  *) echo "$input" | sed 's/"Todo"/"Done"/' ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTransformHookApply(t *testing.T) {
+	hook, err := ParseTransformHook(writeHookScript(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items := []parser.ImportItem{
+		{Title: "Keep", Fields: map[string]interface{}{"Status": "Todo"}, Origin: "items.csv:2"},
+		{Title: "Drop me"},
+		{Title: "Also keep", Labels: []string{"bug"}},
+	}
+	kept, err := hook.Apply(context.Background(), items, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(kept) != 2 || kept[0].Title != "Keep" || kept[1].Title != "Also keep" {
+		t.Fatalf("Expected the dropped item to be removed, got %+v", kept)
+	}
+	if kept[0].Fields["Status"] != "Done" || kept[0].Origin != "items.csv:2" {
+		t.Errorf("Expected the transformed item with its origin, got %+v", kept[0])
+	}
+	if len(kept[1].Labels) != 1 || kept[1].Labels[0] != "bug" {
+		t.Errorf("Expected the labels to survive the round trip, got %+v", kept[1])
+	}
+}
+
+func TestTransformHookErrors(t *testing.T) {
+	if _, err := ParseTransformHook("./does-not-exist.sh"); err == nil {
+		t.Error("Expected an error for a missing program")
+	}
+
+	hook, err := ParseTransformHook(writeHookScript(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = hook.Apply(context.Background(), []parser.ImportItem{{Title: "Fine"}, {Title: "Fail here"}}, 10)
+	if err == nil || !strings.Contains(err.Error(), "item 12") || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Expected the failing item to stop the run, got: %v", err)
+	}
+
+	// A zero hook leaves items alone without running anything
+	items := []parser.ImportItem{{Title: "Untouched"}}
+	if kept, err := (TransformHook{}).Apply(context.Background(), items, 0); err != nil || len(kept) != 1 {
+		t.Errorf("Expected the items unchanged, got %v, %v", kept, err)
+	}
+}
@@ -0,0 +1,99 @@
+// Item filters for archiving
+// Selects items by their field values from FIELD=PATTERN and FIELD!=PATTERN conditions
+package mapping
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ItemFilter selects items whose field values match every condition. The
+// zero value has no conditions and matches nothing.
+type ItemFilter struct {
+	conditions []filterCondition
+}
+
+// filterCondition is one FIELD=PATTERN or FIELD!=PATTERN condition
+type filterCondition struct {
+	field   string // Lowercased field name
+	pattern string // Lowercased glob pattern
+	negate  bool
+}
+
+// ParseItemFilter parses conditions written as FIELD=PATTERN or
+// FIELD!=PATTERN. Field names and values are matched case-insensitively and
+// patterns use shell glob syntax, e.g. "Sprint=2023-*". A field without a
+// value matches the empty pattern.
+func ParseItemFilter(expressions []string) (ItemFilter, error) {
+	var filter ItemFilter
+	for _, expression := range expressions {
+		name, pattern, found := strings.Cut(expression, "=")
+		if !found {
+			return ItemFilter{}, fmt.Errorf("invalid filter %q (expected FIELD=PATTERN or FIELD!=PATTERN)", expression)
+		}
+
+		condition := filterCondition{field: name, pattern: strings.ToLower(strings.TrimSpace(pattern))}
+		if strings.HasSuffix(name, "!") {
+			condition.field = strings.TrimSuffix(name, "!")
+			condition.negate = true
+		}
+		condition.field = strings.ToLower(strings.TrimSpace(condition.field))
+		if condition.field == "" {
+			return ItemFilter{}, fmt.Errorf("invalid filter %q: missing field name", expression)
+		}
+		if _, err := path.Match(condition.pattern, ""); err != nil {
+			return ItemFilter{}, fmt.Errorf("invalid filter %q: %w", expression, err)
+		}
+
+		filter.conditions = append(filter.conditions, condition)
+	}
+	return filter, nil
+}
+
+// Empty reports whether the filter has no conditions
+func (f ItemFilter) Empty() bool {
+	return len(f.conditions) == 0
+}
+
+// Match reports whether the field values satisfy every condition
+func (f ItemFilter) Match(fields map[string]interface{}) bool {
+	if f.Empty() {
+		return false
+	}
+
+	values := make(map[string]string, len(fields))
+	for name, value := range fields {
+		values[strings.ToLower(name)] = strings.ToLower(formatFilterValue(value))
+	}
+
+	for _, condition := range f.conditions {
+		matched, _ := path.Match(condition.pattern, values[condition.field])
+		if matched == condition.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// formatFilterValue renders a source or project field value for matching.
+// Whole numbers have no decimals and lists of users are joined by commas.
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, part := range v {
+			parts[i] = fmt.Sprint(part)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,42 @@
+// Tests for item filters
+package mapping
+
+import "testing"
+
+func TestItemFilterMatch(t *testing.T) {
+	fields := map[string]interface{}{"Status": "Done", "Sprint": "2023-W14", "Estimate": 3.0, "Reviewers": []string{"octocat", "hubot"}}
+
+	tests := []struct {
+		conditions []string
+		expected   bool
+	}{
+		{[]string{"status=done"}, true},
+		{[]string{"Status=Done", "Sprint=2023-*"}, true},
+		{[]string{"Status=Done", "Sprint=2024-*"}, false},
+		{[]string{"Status!=Done"}, false},
+		{[]string{"Status!=Todo"}, true},
+		{[]string{"Estimate=3"}, true},
+		{[]string{"Reviewers=*hubot*"}, true},
+		{[]string{"Team="}, true},
+		{[]string{"Team!="}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseItemFilter(tt.conditions)
+		if err != nil {
+			t.Fatalf("ParseItemFilter(%v) failed: %v", tt.conditions, err)
+		}
+		if matched := filter.Match(fields); matched != tt.expected {
+			t.Errorf("Filter %v: expected %v, got %v", tt.conditions, tt.expected, matched)
+		}
+	}
+}
+
+func TestParseItemFilterErrors(t *testing.T) {
+	for _, condition := range []string{"Status", "=Done", "Status=[", "!=Done"} {
+		if _, err := ParseItemFilter([]string{condition}); err == nil {
+			t.Errorf("Expected an error for %q", condition)
+		}
+	}
+}
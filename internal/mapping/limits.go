@@ -0,0 +1,137 @@
+// Size limits for titles, bodies and text field values
+// Truncates or rejects oversized values and records every truncation for the report
+package mapping
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// DefaultSizeLimits are GitHub's limits on issue titles and bodies, in characters
+var DefaultSizeLimits = map[string]int{
+	"title": 256,
+	"body":  65536,
+}
+
+// Oversize policies for values exceeding their size limit
+const (
+	OversizeTruncate = "truncate"
+	OversizeFail     = "fail"
+)
+
+// Truncation records a value that was shortened to fit its size limit
+type Truncation struct {
+	Item     int // 1-based position of the item in the source
+	Title    string
+	Field    string
+	Original int // Length of the original value in characters
+	Limit    int
+}
+
+// String describes the truncation for the report
+func (t Truncation) String() string {
+	return fmt.Sprintf("item %d (%q): %s truncated from %d to %d characters", t.Item, t.Title, t.Field, t.Original, t.Limit)
+}
+
+// ParseSizeLimits merges name=size overrides into the default limits. Names
+// are "title", "body" or a custom field name, matched case-insensitively.
+func ParseSizeLimits(overrides []string) (map[string]int, error) {
+	limits := make(map[string]int, len(DefaultSizeLimits))
+	for name, size := range DefaultSizeLimits {
+		limits[name] = size
+	}
+
+	for _, override := range overrides {
+		name, value, ok := strings.Cut(override, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid size limit %q (expected name=size)", override)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid size limit %q: size must be a positive number of characters", override)
+		}
+		limits[name] = size
+	}
+
+	return limits, nil
+}
+
+// ApplySizeLimits enforces the size limits on every item. With the truncate
+// policy oversized values are shortened and returned as truncations; with the
+// fail policy an error listing every oversized value is returned instead and
+// no item is modified.
+func ApplySizeLimits(items []parser.ImportItem, limits map[string]int, policy string) ([]Truncation, error) {
+	return ApplySizeLimitsFrom(items, 0, limits, policy)
+}
+
+// ApplySizeLimitsFrom enforces the size limits on a chunk of items whose
+// first item is at position offset in the source
+func ApplySizeLimitsFrom(items []parser.ImportItem, offset int, limits map[string]int, policy string) ([]Truncation, error) {
+	var truncations []Truncation
+
+	for i := range items {
+		item := &items[i]
+
+		check := func(field, value string, shorten func(string)) {
+			limit, ok := limits[strings.ToLower(field)]
+			if !ok {
+				return
+			}
+			if length := utf8.RuneCountInString(value); length > limit {
+				truncations = append(truncations, Truncation{Item: offset + i + 1, Title: item.Title, Field: field, Original: length, Limit: limit})
+				if policy == OversizeTruncate {
+					shorten(truncateRunes(value, limit))
+				}
+			}
+		}
+
+		check("title", item.Title, func(v string) {
+			item.Title = v
+			if item.Content.Title != "" {
+				item.Content.Title = truncateRunes(item.Content.Title, limits["title"])
+			}
+		})
+		check("body", parser.GetItemBody(*item), func(v string) {
+			if item.Content.Body != "" {
+				item.Content.Body = v
+			} else {
+				item.Notes = v
+			}
+		})
+
+		names := make([]string, 0, len(item.Fields))
+		for name := range item.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value, ok := item.Fields[name].(string); ok {
+				check(name, value, func(v string) { item.Fields[name] = v })
+			}
+		}
+	}
+
+	if policy == OversizeFail && len(truncations) > 0 {
+		var lines []string
+		for _, t := range truncations {
+			lines = append(lines, fmt.Sprintf("  item %d (%q): %s is %d characters, limit %d", t.Item, t.Title, t.Field, t.Original, t.Limit))
+		}
+		return nil, fmt.Errorf("%d values exceed their size limit:\n%s", len(truncations), strings.Join(lines, "\n"))
+	}
+
+	return truncations, nil
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,73 @@
+// Tests for size limits on titles, bodies and field values
+package mapping
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestParseSizeLimits(t *testing.T) {
+	limits, err := ParseSizeLimits([]string{"Body=100", "Notes Field=20"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if limits["title"] != 256 || limits["body"] != 100 || limits["notes field"] != 20 {
+		t.Errorf("Unexpected limits: %v", limits)
+	}
+
+	for _, invalid := range []string{"body", "=10", "body=0", "body=many"} {
+		if _, err := ParseSizeLimits([]string{invalid}); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestApplySizeLimits(t *testing.T) {
+	newItems := func() []parser.ImportItem {
+		return []parser.ImportItem{
+			{Title: "Short", Notes: "ok"},
+			{
+				Title:   "A very long title",
+				Content: parser.ItemContent{Type: "DraftIssue", Title: "A very long title", Body: "héllo wörld"},
+				Fields:  map[string]interface{}{"Summary": "abcdefghij", "Estimate": 3},
+			},
+		}
+	}
+	limits := map[string]int{"title": 6, "body": 5, "summary": 4}
+
+	items := newItems()
+	truncations, err := ApplySizeLimits(items, limits, OversizeTruncate)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(truncations) != 3 {
+		t.Fatalf("Expected 3 truncations, got %d: %v", len(truncations), truncations)
+	}
+	if items[1].Title != "A very" || items[1].Content.Title != "A very" {
+		t.Errorf("Expected title to be truncated, got %q / %q", items[1].Title, items[1].Content.Title)
+	}
+	if items[1].Content.Body != "héllo" {
+		t.Errorf("Expected body to be truncated by characters, got %q", items[1].Content.Body)
+	}
+	if items[1].Fields["Summary"] != "abcd" {
+		t.Errorf("Expected Summary to be truncated, got %v", items[1].Fields["Summary"])
+	}
+	if items[0].Title != "Short" || items[0].Notes != "ok" {
+		t.Errorf("Expected first item to be unchanged, got %+v", items[0])
+	}
+	if got := truncations[0].String(); !strings.Contains(got, "title truncated from 17 to 6 characters") {
+		t.Errorf("Unexpected truncation description %q", got)
+	}
+
+	// The fail policy reports every oversized value without changing anything
+	items = newItems()
+	_, err = ApplySizeLimits(items, limits, OversizeFail)
+	if err == nil || !strings.Contains(err.Error(), "3 values exceed their size limit") {
+		t.Errorf("Expected size limit error, got: %v", err)
+	}
+	if items[1].Title != "A very long title" {
+		t.Errorf("Expected title to be unchanged, got %q", items[1].Title)
+	}
+}
@@ -0,0 +1,57 @@
+// Linked pull requests for imported issues
+// A "linked_prs" column adds a closing reference to each pull request, which fills the project's Linked pull requests field
+package mapping
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// LinkPullRequests links the pull requests an item names to the issue at
+// contentURL by appending "Closes <issue URL>" to their body. Pull requests
+// whose body already mentions the issue are left alone, so importing again
+// doesn't add the reference twice. Problems are logged rather than failing
+// the item, as for milestones.
+func LinkPullRequests(ctx context.Context, client ghclient.Client, item parser.ImportItem, contentURL string) {
+	if len(item.LinkedPRs) == 0 {
+		return
+	}
+
+	if contentURL == "" || parser.GetItemType(item) != "Issue" {
+		slog.Warn("Only issues can have linked pull requests, skipping them", "title", item.Title, "linked_prs", strings.Join(item.LinkedPRs, ", "))
+		return
+	}
+
+	for _, prURL := range item.LinkedPRs {
+		owner, repo, err := ghclient.ParseRepositoryURL(prURL)
+		number, ok := ghclient.ParseContentNumber(prURL)
+		if err != nil || !ok || !strings.Contains(prURL, "/pull/") {
+			slog.Warn("Not a pull request URL, skipping", "url", contentURL, "pull_request", prURL)
+			continue
+		}
+
+		body, err := client.GetIssueBody(ctx, owner, repo, number)
+		if err != nil {
+			slog.Warn("Failed to get pull request, skipping", "url", contentURL, "error", err)
+			continue
+		}
+		if strings.Contains(strings.ToLower(body), strings.ToLower(contentURL)) {
+			slog.Debug("Pull request already mentions the issue", "url", contentURL, "pull_request", prURL)
+			continue
+		}
+
+		reference := "Closes " + contentURL
+		if strings.TrimSpace(body) != "" {
+			reference = strings.TrimRight(body, "\n") + "\n\n" + reference
+		}
+		if err := client.SetIssueBody(ctx, owner, repo, number, reference); err != nil {
+			slog.Warn("Failed to link pull request", "url", contentURL, "pull_request", prURL, "error", err)
+			continue
+		}
+		slog.Debug("Linked pull request", "url", contentURL, "pull_request", prURL)
+	}
+}
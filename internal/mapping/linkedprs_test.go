@@ -0,0 +1,61 @@
+// Tests for linking pull requests to imported issues
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// bodyClient is a ghclient.Client stub holding issue and pull request bodies
+// by "owner/repo#number"
+type bodyClient struct {
+	ghclient.Client
+	bodies  map[string]string
+	updates int
+}
+
+func (c *bodyClient) GetIssueBody(ctx context.Context, owner, repo string, number int) (string, error) {
+	return c.bodies[fmt.Sprintf("%s/%s#%d", owner, repo, number)], nil
+}
+
+func (c *bodyClient) SetIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	c.bodies[fmt.Sprintf("%s/%s#%d", owner, repo, number)] = body
+	c.updates++
+	return nil
+}
+
+func TestLinkPullRequests(t *testing.T) {
+	client := &bodyClient{bodies: map[string]string{
+		"octo/app#7": "Adds card payments\n",
+		"octo/app#8": "",
+	}}
+	item := parser.ImportItem{
+		Title:     "Card payments",
+		URL:       "https://github.com/octo/app/issues/2",
+		LinkedPRs: []string{"https://github.com/octo/app/pull/7", "https://github.com/octo/app/pull/8", "https://github.com/octo/app/issues/9"},
+	}
+
+	LinkPullRequests(context.Background(), client, item, item.URL)
+	if body := client.bodies["octo/app#7"]; body != "Adds card payments\n\nCloses https://github.com/octo/app/issues/2" {
+		t.Errorf("Unexpected body: %q", body)
+	}
+	if body := client.bodies["octo/app#8"]; body != "Closes https://github.com/octo/app/issues/2" {
+		t.Errorf("Unexpected body: %q", body)
+	}
+
+	// Linking again leaves the bodies alone
+	LinkPullRequests(context.Background(), client, item, item.URL)
+	if client.updates != 2 {
+		t.Errorf("Expected 2 updates, got %d", client.updates)
+	}
+
+	draft := parser.ImportItem{Title: "Draft", LinkedPRs: []string{"https://github.com/octo/app/pull/7"}}
+	LinkPullRequests(context.Background(), client, draft, "")
+	if client.updates != 2 {
+		t.Errorf("Expected draft issues to be skipped, got %d updates", client.updates)
+	}
+}
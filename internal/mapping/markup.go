@@ -0,0 +1,385 @@
+// Markup converters for text migrated from other tools
+// Turns HTML, Jira wiki markup and Atlassian Document Format into Markdown so bodies and text fields render on GitHub
+package mapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// textConverters maps the formats accepted by --convert to their converters
+var textConverters = map[string]func(string) (string, error){
+	"html": htmlToMarkdown,
+	"jira": jiraToMarkdown,
+	"adf":  adfToMarkdown,
+}
+
+// ParseTextConverters parses --convert values of the form FIELD=FORMAT. The
+// field names "title" and "body" refer to the item's title and body; any
+// other name is a field, matched case-insensitively. The result is keyed by
+// lowercased field name.
+func ParseTextConverters(values []string) (map[string]string, error) {
+	converters := make(map[string]string, len(values))
+	for _, value := range values {
+		field, format, ok := strings.Cut(value, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !ok || field == "" {
+			return nil, fmt.Errorf("invalid --convert %q (expected FIELD=FORMAT, e.g. body=jira)", value)
+		}
+		if _, known := textConverters[format]; !known {
+			return nil, fmt.Errorf("unknown --convert format %q (available: %s)", format, strings.Join(TextConverterNames(), ", "))
+		}
+		converters[field] = format
+	}
+	return converters, nil
+}
+
+// TextConverterNames returns the sorted names of the --convert formats
+func TextConverterNames() []string {
+	names := make([]string, 0, len(textConverters))
+	for name := range textConverters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTextConverters converts the configured titles, bodies and string
+// fields to Markdown. offset is the position of the first item in the source,
+// used in error messages.
+func ApplyTextConverters(items []parser.ImportItem, offset int, converters map[string]string) error {
+	if len(converters) == 0 {
+		return nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		convert := func(field, value string) (string, error) {
+			format, ok := converters[strings.ToLower(field)]
+			if !ok || value == "" {
+				return value, nil
+			}
+			converted, err := textConverters[format](value)
+			if err != nil {
+				return "", fmt.Errorf("item %d (%q): cannot convert %s from %s: %w", offset+i+1, item.Title, field, format, err)
+			}
+			return converted, nil
+		}
+
+		title, err := convert("title", item.Title)
+		if err != nil {
+			return err
+		}
+		if title != item.Title {
+			item.Title = title
+			if item.Content.Title != "" {
+				item.Content.Title = title
+			}
+		}
+
+		if item.Content.Body != "" {
+			if item.Content.Body, err = convert("body", item.Content.Body); err != nil {
+				return err
+			}
+		} else if item.Notes, err = convert("body", item.Notes); err != nil {
+			return err
+		}
+
+		for name, value := range item.Fields {
+			if str, ok := value.(string); ok {
+				converted, err := convert(name, str)
+				if err != nil {
+					return err
+				}
+				item.Fields[name] = converted
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	htmlBreakPattern      = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlBlockEndPattern   = regexp.MustCompile(`(?i)</(p|div|h[1-6]|ul|ol|table|blockquote|pre)>`)
+	htmlHeadingPattern    = regexp.MustCompile(`(?i)<h([1-6])[^>]*>`)
+	htmlListStartPattern  = regexp.MustCompile(`(?i)<(ul|ol)[^>]*>`)
+	htmlListItemPattern   = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlRowEndPattern     = regexp.MustCompile(`(?i)</(li|tr)>`)
+	htmlBoldPattern       = regexp.MustCompile(`(?i)</?(strong|b)>`)
+	htmlItalicPattern     = regexp.MustCompile(`(?i)</?(em|i)>`)
+	htmlCodePattern       = regexp.MustCompile(`(?i)</?code>`)
+	htmlLinkPattern       = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlDroppedPattern    = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlTagPattern        = regexp.MustCompile(`<[^>]*>`)
+	blankLinesPattern     = regexp.MustCompile(`\n{3,}`)
+	trailingSpacesPattern = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// htmlToMarkdown strips HTML down to Markdown, keeping paragraphs, line
+// breaks, headings, list items, emphasis, inline code and links. Other tags
+// are removed and entities decoded.
+func htmlToMarkdown(s string) (string, error) {
+	s = htmlDroppedPattern.ReplaceAllString(s, "")
+	s = htmlLinkPattern.ReplaceAllString(s, "[$2]($1)")
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlHeadingPattern.ReplaceAllStringFunc(s, func(tag string) string {
+		level := htmlHeadingPattern.FindStringSubmatch(tag)[1]
+		return "\n\n" + strings.Repeat("#", int(level[0]-'0')) + " "
+	})
+	s = htmlBlockEndPattern.ReplaceAllString(s, "\n\n")
+	s = htmlListStartPattern.ReplaceAllString(s, "\n")
+	s = htmlListItemPattern.ReplaceAllString(s, "- ")
+	s = htmlRowEndPattern.ReplaceAllString(s, "\n")
+	s = htmlBoldPattern.ReplaceAllString(s, "**")
+	s = htmlItalicPattern.ReplaceAllString(s, "_")
+	s = htmlCodePattern.ReplaceAllString(s, "`")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return tidyMarkdown(s), nil
+}
+
+// tidyMarkdown trims trailing spaces and collapses runs of blank lines
+func tidyMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = trailingSpacesPattern.ReplaceAllString(s, "\n")
+	s = blankLinesPattern.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+var (
+	jiraHeadingPattern   = regexp.MustCompile(`^h([1-6])\.\s+`)
+	jiraBulletPattern    = regexp.MustCompile(`^([*-]+)\s+`)
+	jiraNumberedPattern  = regexp.MustCompile(`^(#+)\s+`)
+	jiraCodeBlockPattern = regexp.MustCompile(`^\{(code|noformat)(?::([^}|]*))?[^}]*\}`)
+	jiraBoldPattern      = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*?)\*([^\w*]|$)`)
+	jiraMonospacePattern = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	jiraStrikePattern    = regexp.MustCompile(`(^|\s)-([^-\s][^-]*?)-(\s|$)`)
+	jiraLinkPattern      = regexp.MustCompile(`\[([^|\]]+)\|([^\]]+)\]`)
+	jiraBareLinkPattern  = regexp.MustCompile(`\[((?:https?|mailto):[^\]|]+)\]`)
+	jiraQuotePattern     = regexp.MustCompile(`^bq\.\s+`)
+	jiraTableHeaderCells = regexp.MustCompile(`\|\|`)
+	jiraColorPattern     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+)
+
+// jiraToMarkdown converts Jira wiki markup: headings, bullet and numbered
+// lists, bold, monospace, strikethrough, links, quotes, tables and code or
+// noformat blocks. Text inside code blocks is left as is.
+func jiraToMarkdown(s string) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	var out []string
+	inCode := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if match := jiraCodeBlockPattern.FindStringSubmatch(trimmed); match != nil {
+			if inCode {
+				out = append(out, "```")
+			} else {
+				out = append(out, "```"+strings.TrimSpace(match[2]))
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+
+		if match := jiraHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("#", int(match[1][0]-'0')) + " " + trimmed[len(match[0]):]
+		} else if match := jiraBulletPattern.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("  ", len(match[1])-1) + "- " + trimmed[len(match[0]):]
+		} else if match := jiraNumberedPattern.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("   ", len(match[1])-1) + "1. " + trimmed[len(match[0]):]
+		} else if jiraQuotePattern.MatchString(trimmed) {
+			trimmed = "> " + jiraQuotePattern.ReplaceAllString(trimmed, "")
+		} else if strings.HasPrefix(trimmed, "||") {
+			// Header row: emit the Markdown header separator after it
+			cells := strings.Count(strings.Trim(trimmed, "|"), "||") + 1
+			trimmed = jiraTableHeaderCells.ReplaceAllString(trimmed, "|")
+			out = append(out, jiraInline(trimmed), "|"+strings.Repeat(" --- |", cells))
+			continue
+		}
+
+		out = append(out, jiraInline(trimmed))
+	}
+	if inCode {
+		out = append(out, "```")
+	}
+
+	return tidyMarkdown(strings.Join(out, "\n")), nil
+}
+
+// jiraInline converts Jira's inline markup on a single line
+func jiraInline(s string) string {
+	// Monospace is converted first so its contents aren't treated as markup
+	var code []string
+	s = jiraMonospacePattern.ReplaceAllStringFunc(s, func(m string) string {
+		code = append(code, jiraMonospacePattern.FindStringSubmatch(m)[1])
+		return fmt.Sprintf("\x00%d\x00", len(code)-1)
+	})
+
+	s = jiraColorPattern.ReplaceAllString(s, "")
+	s = jiraLinkPattern.ReplaceAllString(s, "[$1]($2)")
+	s = jiraBareLinkPattern.ReplaceAllString(s, "<$1>")
+	s = jiraBoldPattern.ReplaceAllString(s, "$1**$2**$3")
+	s = jiraStrikePattern.ReplaceAllString(s, "$1~~$2~~$3")
+
+	for i, c := range code {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), "`"+c+"`", 1)
+	}
+	return s
+}
+
+// adfNode is a node of an Atlassian Document Format document
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Marks   []adfMark              `json:"marks"`
+	Content []adfNode              `json:"content"`
+}
+
+// adfMark is formatting applied to an ADF text node
+type adfMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs"`
+}
+
+// adfToMarkdown converts an Atlassian Document Format JSON document, as
+// returned by Jira Cloud's API for descriptions and rich text fields
+func adfToMarkdown(s string) (string, error) {
+	var doc adfNode
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return "", fmt.Errorf("not an ADF document: %w", err)
+	}
+	if doc.Type != "doc" {
+		return "", fmt.Errorf("not an ADF document: top-level node is %q", doc.Type)
+	}
+
+	var b strings.Builder
+	writeADFBlocks(&b, doc.Content, "")
+	return tidyMarkdown(b.String()), nil
+}
+
+// writeADFBlocks writes block nodes, prefixing each line with indent
+func writeADFBlocks(b *strings.Builder, nodes []adfNode, indent string) {
+	for _, node := range nodes {
+		switch node.Type {
+		case "paragraph":
+			b.WriteString(indent + adfInline(node.Content) + "\n\n")
+		case "heading":
+			level, _ := node.Attrs["level"].(float64)
+			if level < 1 {
+				level = 1
+			}
+			b.WriteString(indent + strings.Repeat("#", int(level)) + " " + adfInline(node.Content) + "\n\n")
+		case "bulletList", "orderedList":
+			marker := "- "
+			if node.Type == "orderedList" {
+				marker = "1. "
+			}
+			for _, listItem := range node.Content {
+				var item strings.Builder
+				writeADFBlocks(&item, listItem.Content, "")
+				lines := strings.Split(strings.TrimSpace(item.String()), "\n")
+				for i, line := range lines {
+					if line == "" {
+						continue
+					}
+					if i == 0 {
+						b.WriteString(indent + marker + line + "\n")
+					} else {
+						b.WriteString(indent + strings.Repeat(" ", len(marker)) + line + "\n")
+					}
+				}
+			}
+			b.WriteString("\n")
+		case "codeBlock":
+			language, _ := node.Attrs["language"].(string)
+			b.WriteString(indent + "```" + language + "\n")
+			for _, text := range node.Content {
+				b.WriteString(text.Text)
+			}
+			b.WriteString("\n" + indent + "```\n\n")
+		case "blockquote":
+			var quote strings.Builder
+			writeADFBlocks(&quote, node.Content, "")
+			for _, line := range strings.Split(strings.TrimSpace(quote.String()), "\n") {
+				b.WriteString(indent + "> " + line + "\n")
+			}
+			b.WriteString("\n")
+		case "rule":
+			b.WriteString(indent + "---\n\n")
+		default:
+			// Unknown blocks (panels, tables, media) keep their text content
+			if len(node.Content) > 0 {
+				writeADFBlocks(b, node.Content, indent)
+			} else if text := adfInline([]adfNode{node}); text != "" {
+				b.WriteString(indent + text + "\n\n")
+			}
+		}
+	}
+}
+
+// adfInline renders inline nodes such as text, mentions and hard breaks
+func adfInline(nodes []adfNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(adfMarkedText(node))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention", "emoji", "status":
+			if text, ok := node.Attrs["text"].(string); ok {
+				b.WriteString(text)
+			} else if text, ok := node.Attrs["shortName"].(string); ok {
+				b.WriteString(text)
+			}
+		case "inlineCard":
+			if url, ok := node.Attrs["url"].(string); ok {
+				b.WriteString("<" + url + ">")
+			}
+		default:
+			b.WriteString(adfInline(node.Content))
+		}
+	}
+	return b.String()
+}
+
+// adfMarkedText applies a text node's marks as Markdown. Surrounding spaces
+// are kept outside the markers, since "** now**" isn't bold in Markdown.
+func adfMarkedText(node adfNode) string {
+	if len(node.Marks) == 0 || strings.TrimSpace(node.Text) == "" {
+		return node.Text
+	}
+	text := strings.TrimSpace(node.Text)
+	leading := node.Text[:strings.Index(node.Text, text)]
+	trailing := node.Text[len(leading)+len(text):]
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "code":
+			text = "`" + text + "`"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			if href, ok := mark.Attrs["href"].(string); ok {
+				text = "[" + text + "](" + href + ")"
+			}
+		}
+	}
+	return leading + text + trailing
+}
@@ -0,0 +1,127 @@
+// Tests for markup conversion of migrated text
+package mapping
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	input := `<h2>Steps</h2><p>Open the <b>settings</b> page &amp; click <a href="https://example.com/x">here</a>.<br/>Then wait.</p>` +
+		`<ul><li>First</li><li>Use <code>--force</code></li></ul><script>alert(1)</script>`
+	expected := "## Steps\n\nOpen the **settings** page & click [here](https://example.com/x).\nThen wait.\n\n- First\n- Use `--force`"
+
+	got, err := htmlToMarkdown(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestJiraToMarkdown(t *testing.T) {
+	input := "h1. Summary\r\n" +
+		"The *login* page fails for {{admin_user}}, see [the logs|https://example.com/logs].\n" +
+		"* First\n" +
+		"** Nested\n" +
+		"# Step one\n" +
+		"bq. Quoted -old- text\n" +
+		"||Name||Value||\n" +
+		"|a|1|\n" +
+		"{code:java}\n" +
+		"int *x* = 1;\n" +
+		"{code}"
+	expected := "# Summary\n" +
+		"The **login** page fails for `admin_user`, see [the logs](https://example.com/logs).\n" +
+		"- First\n" +
+		"  - Nested\n" +
+		"1. Step one\n" +
+		"> Quoted ~~old~~ text\n" +
+		"|Name|Value|\n" +
+		"| --- | --- |\n" +
+		"|a|1|\n" +
+		"```java\n" +
+		"int *x* = 1;\n" +
+		"```"
+
+	got, err := jiraToMarkdown(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestADFToMarkdown(t *testing.T) {
+	input := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Context"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Ask "},
+			{"type":"mention","attrs":{"text":"@Jane"}},
+			{"type":"text","text":" about "},
+			{"type":"text","text":"the docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":" now","marks":[{"type":"strong"}]}
+		]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"One"}]}]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Two","marks":[{"type":"code"}]}]}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println()"}]}
+	]}`
+	expected := "### Context\n\nAsk @Jane about [the docs](https://example.com) **now**\n\n- One\n- `Two`\n\n```go\nfmt.Println()\n```"
+
+	got, err := adfToMarkdown(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if _, err := adfToMarkdown("plain text"); err == nil {
+		t.Error("Expected an error for text that isn't an ADF document")
+	}
+}
+
+func TestParseTextConverters(t *testing.T) {
+	converters, err := ParseTextConverters([]string{"body=jira", "Release Notes=HTML"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if converters["body"] != "jira" || converters["release notes"] != "html" {
+		t.Errorf("Unexpected converters: %v", converters)
+	}
+
+	for _, value := range []string{"body", "=jira", "body=rtf"} {
+		if _, err := ParseTextConverters([]string{value}); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestApplyTextConverters(t *testing.T) {
+	items := []parser.ImportItem{
+		{Title: "Fix", Notes: "*urgent*", Fields: map[string]interface{}{"Release Notes": "<p>Done</p>", "Estimate": int64(3)}},
+		{Title: "Draft", Content: parser.ItemContent{Type: "DraftIssue", Title: "Draft", Body: "{{code}}"}, Fields: map[string]interface{}{}},
+	}
+	converters := map[string]string{"body": "jira", "release notes": "html"}
+
+	if err := ApplyTextConverters(items, 0, converters); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items[0].Notes != "**urgent**" || items[0].Fields["Release Notes"] != "Done" || items[0].Fields["Estimate"] != int64(3) {
+		t.Errorf("Unexpected first item: %+v", items[0])
+	}
+	if items[1].Content.Body != "`code`" {
+		t.Errorf("Expected the draft body to be converted, got %q", items[1].Content.Body)
+	}
+
+	err := ApplyTextConverters([]parser.ImportItem{{Title: "Bad", Notes: "not json"}}, 9, map[string]string{"body": "adf"})
+	if err == nil || !strings.Contains(err.Error(), "item 10") {
+		t.Errorf("Expected an error naming item 10, got: %v", err)
+	}
+}
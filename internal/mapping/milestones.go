@@ -0,0 +1,158 @@
+// Milestones for imported issues and pull requests
+// A "Milestone" column is set on the underlying issue, since projects can't set milestones themselves
+package mapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ErrMilestoneNotFound is returned when a repository has no milestone with the given title
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+// IsMilestoneField reports whether a field holds the item's milestone. It is
+// set on the issue or pull request rather than as a project field value.
+func IsMilestoneField(name string) bool {
+	return strings.EqualFold(name, "Milestone")
+}
+
+// MilestoneResolver finds milestones by title, listing each repository's
+// milestones once per run. With create set, missing milestones are created.
+type MilestoneResolver struct {
+	client ghclient.Client
+	create bool
+
+	// mu is held while milestones are listed and created, so a milestone
+	// used by several items is only created once
+	mu         sync.Mutex
+	milestones map[string][]ghclient.Milestone // lowercased owner/repo → milestones
+	missing    map[string]string               // lowercased "owner/repo: title" → as first written
+	created    []string
+	drafts     int // Draft issues with a milestone, which they can't have
+}
+
+// NewMilestoneResolver creates a resolver that looks milestones up through client
+func NewMilestoneResolver(client ghclient.Client, create bool) *MilestoneResolver {
+	return &MilestoneResolver{client: client, create: create, milestones: make(map[string][]ghclient.Milestone), missing: make(map[string]string)}
+}
+
+// Resolve returns the number of the milestone with the given title in a
+// repository, matching the title exactly and then ignoring case
+func (r *MilestoneResolver) Resolve(ctx context.Context, owner, repo, title string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	repoKey := strings.ToLower(owner + "/" + repo)
+	milestones, listed := r.milestones[repoKey]
+	if !listed {
+		var err error
+		milestones, err = r.client.ListMilestones(ctx, owner, repo)
+		if err != nil {
+			return 0, err
+		}
+		r.milestones[repoKey] = milestones
+	}
+
+	for _, milestone := range milestones {
+		if milestone.Title == title {
+			return milestone.Number, nil
+		}
+	}
+	for _, milestone := range milestones {
+		if strings.EqualFold(milestone.Title, title) {
+			return milestone.Number, nil
+		}
+	}
+
+	name := fmt.Sprintf("%s/%s: %s", owner, repo, title)
+	if !r.create {
+		if _, reported := r.missing[strings.ToLower(name)]; !reported {
+			r.missing[strings.ToLower(name)] = name
+		}
+		return 0, fmt.Errorf("%w: %s", ErrMilestoneNotFound, name)
+	}
+
+	milestone, err := r.client.CreateMilestone(ctx, owner, repo, title)
+	if err != nil {
+		return 0, err
+	}
+	r.milestones[repoKey] = append(milestones, *milestone)
+	r.created = append(r.created, name)
+	return milestone.Number, nil
+}
+
+// Missing returns the milestones that weren't found, as "owner/repo: title", sorted
+func (r *MilestoneResolver) Missing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.missing))
+	for _, name := range r.missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Created returns the milestones created by the run, as "owner/repo: title"
+func (r *MilestoneResolver) Created() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.created...)
+}
+
+// Drafts returns the number of draft issues that had a milestone
+func (r *MilestoneResolver) Drafts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.drafts
+}
+
+// SetItemMilestone sets the milestone named by an item's Milestone field on
+// the issue or pull request at contentURL, which is empty for draft issues.
+// Problems are logged rather than failing the item, as for other fields.
+func SetItemMilestone(ctx context.Context, client ghclient.Client, item parser.ImportItem, contentURL string, milestones *MilestoneResolver) {
+	title := ""
+	for name, value := range item.Fields {
+		if IsMilestoneField(name) {
+			title = strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+	if title == "" {
+		return
+	}
+
+	if contentURL == "" {
+		milestones.mu.Lock()
+		milestones.drafts++
+		milestones.mu.Unlock()
+		slog.Debug("Draft issues can't have a milestone, skipping", "title", item.Title, "milestone", title)
+		return
+	}
+
+	owner, repo, err := ghclient.ParseRepositoryURL(contentURL)
+	number, ok := ghclient.ParseContentNumber(contentURL)
+	if err != nil || !ok {
+		slog.Warn("Cannot tell which issue to set the milestone on, skipping", "url", contentURL, "milestone", title)
+		return
+	}
+
+	milestone, err := milestones.Resolve(ctx, owner, repo, title)
+	if err != nil {
+		slog.Warn("Failed to find milestone, skipping", "url", contentURL, "error", err)
+		return
+	}
+	if err := client.SetIssueMilestone(ctx, owner, repo, number, milestone); err != nil {
+		slog.Warn("Failed to set milestone", "url", contentURL, "error", err)
+		return
+	}
+	slog.Debug("Set milestone", "url", contentURL, "milestone", title)
+}
@@ -0,0 +1,109 @@
+// Tests for setting milestones on imported issues
+package mapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// milestoneClient is a ghclient.Client stub holding the milestones of each
+// repository and recording the milestones set on issues
+type milestoneClient struct {
+	ghclient.Client
+	milestones map[string][]ghclient.Milestone
+	listed     int
+	set        []string
+}
+
+func (c *milestoneClient) ListMilestones(ctx context.Context, owner, repo string) ([]ghclient.Milestone, error) {
+	c.listed++
+	return c.milestones[owner+"/"+repo], nil
+}
+
+func (c *milestoneClient) CreateMilestone(ctx context.Context, owner, repo, title string) (*ghclient.Milestone, error) {
+	milestone := ghclient.Milestone{Number: 100 + len(c.milestones[owner+"/"+repo]), Title: title, State: "open"}
+	c.milestones[owner+"/"+repo] = append(c.milestones[owner+"/"+repo], milestone)
+	return &milestone, nil
+}
+
+func (c *milestoneClient) SetIssueMilestone(ctx context.Context, owner, repo string, number, milestone int) error {
+	c.set = append(c.set, fmt.Sprintf("%s/%s#%d → %d", owner, repo, number, milestone))
+	return nil
+}
+
+func newMilestoneClient() *milestoneClient {
+	return &milestoneClient{milestones: map[string][]ghclient.Milestone{
+		"octo/app": {{Number: 1, Title: "v1.0", State: "closed"}, {Number: 2, Title: "v2.0", State: "open"}},
+	}}
+}
+
+func TestMilestoneResolver(t *testing.T) {
+	client := newMilestoneClient()
+	resolver := NewMilestoneResolver(client, false)
+
+	for title, expected := range map[string]int{"v1.0": 1, "V2.0": 2} {
+		number, err := resolver.Resolve(context.Background(), "octo", "app", title)
+		if err != nil || number != expected {
+			t.Errorf("Resolve(%q) = %d, %v, expected %d", title, number, err, expected)
+		}
+	}
+	if _, err := resolver.Resolve(context.Background(), "octo", "app", "v3.0"); !errors.Is(err, ErrMilestoneNotFound) {
+		t.Errorf("Expected v3.0 to be missing, got: %v", err)
+	}
+
+	if client.listed != 1 {
+		t.Errorf("Expected the milestones to be listed once, got %d", client.listed)
+	}
+	if missing := resolver.Missing(); !reflect.DeepEqual(missing, []string{"octo/app: v3.0"}) {
+		t.Errorf("Unexpected missing milestones: %v", missing)
+	}
+}
+
+func TestMilestoneResolverCreates(t *testing.T) {
+	client := newMilestoneClient()
+	resolver := NewMilestoneResolver(client, true)
+
+	first, err := resolver.Resolve(context.Background(), "octo", "app", "v3.0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := resolver.Resolve(context.Background(), "octo", "app", "v3.0")
+	if first != second || len(client.milestones["octo/app"]) != 3 {
+		t.Errorf("Expected v3.0 to be created once, got %v", client.milestones["octo/app"])
+	}
+	if created := resolver.Created(); !reflect.DeepEqual(created, []string{"octo/app: v3.0"}) {
+		t.Errorf("Unexpected created milestones: %v", created)
+	}
+}
+
+func TestSetItemMilestone(t *testing.T) {
+	client := newMilestoneClient()
+	resolver := NewMilestoneResolver(client, false)
+
+	issue := parser.ImportItem{Title: "Fix", URL: "https://github.com/octo/app/issues/7", Fields: map[string]interface{}{"milestone": "v2.0"}}
+	SetItemMilestone(context.Background(), client, issue, issue.URL, resolver)
+	if !reflect.DeepEqual(client.set, []string{"octo/app#7 → 2"}) {
+		t.Errorf("Expected milestone 2 to be set on issue 7, got %v", client.set)
+	}
+
+	draft := parser.ImportItem{Title: "Idea", Fields: map[string]interface{}{"Milestone": "v2.0"}}
+	SetItemMilestone(context.Background(), client, draft, "", resolver)
+	if len(client.set) != 1 || resolver.Drafts() != 1 {
+		t.Errorf("Expected the draft to be counted and skipped, got %v and %d drafts", client.set, resolver.Drafts())
+	}
+}
+
+func TestMilestoneIsNotAProjectField(t *testing.T) {
+	items := []parser.ImportItem{{Title: "Fix", URL: "https://github.com/octo/app/issues/7", Fields: map[string]interface{}{"Milestone": "v2.0"}}}
+
+	issues := ValidateItemFields(items, map[string]ghclient.ProjectField{}, OptionMatcher{}, false)
+	if len(issues) != 0 {
+		t.Errorf("Expected no validation issues for the milestone, got %v", issues)
+	}
+}
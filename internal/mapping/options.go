@@ -0,0 +1,43 @@
+// Matching of values to single-select options
+// Names match exactly and then ignoring case, after replacing the aliases given in the mapping file
+package mapping
+
+import (
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// OptionMatcher finds the single-select option a value stands for. The zero
+// value matches option names ignoring case and has no aliases.
+type OptionMatcher struct {
+	// Aliases maps lowercased field names to lowercased aliases and the
+	// option each stands for, e.g. "status" → "wip" → "In Progress"
+	Aliases map[string]map[string]string
+	// Strict only accepts option names with the exact case (--strict-options)
+	Strict bool
+}
+
+// Find returns the option of field named by value. Aliases are looked up
+// ignoring case.
+func (m OptionMatcher) Find(field ghclient.ProjectField, value string) (ghclient.ProjectFieldOption, bool) {
+	name := value
+	if option, ok := m.Aliases[strings.ToLower(field.Name)][strings.ToLower(strings.TrimSpace(value))]; ok {
+		name = option
+	}
+
+	for _, option := range field.Options {
+		if option.Name == name {
+			return option, true
+		}
+	}
+	if m.Strict {
+		return ghclient.ProjectFieldOption{}, false
+	}
+	for _, option := range field.Options {
+		if strings.EqualFold(option.Name, name) {
+			return option, true
+		}
+	}
+	return ghclient.ProjectFieldOption{}, false
+}
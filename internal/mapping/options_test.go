@@ -0,0 +1,73 @@
+// Tests for matching values to single-select options
+package mapping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+var statusField = ghclient.ProjectField{
+	ID:   "F_status",
+	Name: "Status",
+	Type: "SINGLE_SELECT",
+	Options: []ghclient.ProjectFieldOption{
+		{ID: "opt_todo", Name: "Todo"},
+		{ID: "opt_progress", Name: "In Progress"},
+		{ID: "opt_done", Name: "Done"},
+	},
+}
+
+func TestOptionMatcherFind(t *testing.T) {
+	aliases := map[string]map[string]string{"status": {"wip": "In Progress", "closed": "done"}}
+
+	tests := []struct {
+		name     string
+		matcher  OptionMatcher
+		value    string
+		expected string
+	}{
+		{"exact", OptionMatcher{}, "In Progress", "opt_progress"},
+		{"ignoring case", OptionMatcher{}, "in progress", "opt_progress"},
+		{"strict exact", OptionMatcher{Strict: true}, "Done", "opt_done"},
+		{"strict case mismatch", OptionMatcher{Strict: true}, "done", ""},
+		{"alias", OptionMatcher{Aliases: aliases}, "WIP", "opt_progress"},
+		{"alias to option in other case", OptionMatcher{Aliases: aliases}, "Closed", "opt_done"},
+		{"strict alias to option in other case", OptionMatcher{Aliases: aliases, Strict: true}, "Closed", ""},
+		{"unknown", OptionMatcher{Aliases: aliases}, "Blocked", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			option, ok := tt.matcher.Find(statusField, tt.value)
+			if ok != (tt.expected != "") || option.ID != tt.expected {
+				t.Errorf("Find(%q) = %q, %v; expected %q", tt.value, option.ID, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertFieldValueStrictOptions(t *testing.T) {
+	converted, err := ConvertFieldValue("todo", statusField)
+	if err != nil || !reflect.DeepEqual(converted, map[string]interface{}{"singleSelectOptionId": "opt_todo"}) {
+		t.Errorf("Expected a case-insensitive match by default, got %v, %v", converted, err)
+	}
+
+	_, err = ConvertFieldValueWith("todo", statusField, OptionMatcher{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'Todo'") {
+		t.Errorf("Expected a strict mismatch to suggest the option, got %v", err)
+	}
+}
+
+func TestValidateItemFieldsReportsOptionMatches(t *testing.T) {
+	items := []parser.ImportItem{{Title: "Fix", Fields: map[string]interface{}{"Status": "WIP"}}}
+	matcher := OptionMatcher{Aliases: map[string]map[string]string{"status": {"wip": "In Progress"}}}
+
+	issues := ValidateItemFields(items, map[string]ghclient.ProjectField{"Status": statusField}, matcher, false)
+	if len(issues) != 1 || issues[0].Severity != SeverityNotice || !strings.Contains(issues[0].Message, "will be set to option 'In Progress'") {
+		t.Errorf("Expected a notice about the alias, got %v", issues)
+	}
+}
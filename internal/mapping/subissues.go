@@ -0,0 +1,119 @@
+// Sub-issues for imported issues
+// A "parent" column makes the imported issue a sub-issue of another, so hierarchies such as Jira epics survive an import
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ParentResolver finds the parent issues that items name, either by URL or
+// by the ID of another row of the sources
+type ParentResolver struct {
+	mu     sync.Mutex
+	rows   map[string]string // row ID → issue URL, empty for draft issues
+	linked int
+}
+
+// NewParentResolver creates a resolver without any rows
+func NewParentResolver() *ParentResolver {
+	return &ParentResolver{rows: make(map[string]string)}
+}
+
+// AddRows records the IDs of items, so that other items can name them as
+// their parent. Items without an ID are ignored.
+func (r *ParentResolver) AddRows(items []parser.ImportItem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range items {
+		if item.ID == "" {
+			continue
+		}
+		url := item.URL
+		if url == "" {
+			url = item.Content.URL
+		}
+		r.rows[item.ID] = url
+	}
+}
+
+// Resolve returns the URL of the issue a parent value names: the value itself
+// when it is a URL, or the URL of the row with that ID
+func (r *ParentResolver) Resolve(parent string) (string, error) {
+	if strings.Contains(parent, "://") {
+		return parent, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url, found := r.rows[parent]
+	if !found {
+		return "", fmt.Errorf("no row has the ID %q", parent)
+	}
+	if url == "" {
+		return "", fmt.Errorf("row %q is a draft issue, which can't have sub-issues", parent)
+	}
+	return url, nil
+}
+
+// Linked returns the number of issues made sub-issues of their parent
+func (r *ParentResolver) Linked() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.linked
+}
+
+// SetItemParent makes the issue at contentURL, which is empty for draft
+// issues, a sub-issue of the parent the item names. Problems are logged
+// rather than failing the item, as for milestones.
+func SetItemParent(ctx context.Context, client ghclient.Client, item parser.ImportItem, contentURL string, parents *ParentResolver) {
+	parent := strings.TrimSpace(item.Parent)
+	if parent == "" {
+		return
+	}
+
+	if contentURL == "" || parser.GetItemType(item) != "Issue" {
+		slog.Warn("Only issues can be sub-issues, skipping parent", "title", item.Title, "parent", parent)
+		return
+	}
+
+	parentURL, err := parents.Resolve(parent)
+	if err != nil {
+		slog.Warn("Failed to find parent issue, skipping", "url", contentURL, "error", err)
+		return
+	}
+
+	parentContent, err := client.GetIssueOrPR(ctx, parentURL)
+	if err != nil {
+		slog.Warn("Failed to get parent issue, skipping", "url", contentURL, "parent", parentURL, "error", err)
+		return
+	}
+	content, err := client.GetIssueOrPR(ctx, contentURL)
+	if err != nil {
+		slog.Warn("Failed to get issue, skipping parent", "url", contentURL, "error", err)
+		return
+	}
+
+	parentID, subIssueID := ghclient.GetString(parentContent, "node_id"), ghclient.GetString(content, "node_id")
+	if parentID == "" || subIssueID == "" {
+		slog.Warn("Could not extract the issue IDs, skipping parent", "url", contentURL, "parent", parentURL)
+		return
+	}
+	if err := client.AddSubIssue(ctx, parentID, subIssueID); err != nil {
+		slog.Warn("Failed to add sub-issue", "url", contentURL, "parent", parentURL, "error", err)
+		return
+	}
+
+	parents.mu.Lock()
+	parents.linked++
+	parents.mu.Unlock()
+	slog.Debug("Added sub-issue", "url", contentURL, "parent", parentURL)
+}
@@ -0,0 +1,74 @@
+// Tests for linking imported issues to their parent
+package mapping
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// subIssueClient is a ghclient.Client stub that derives node IDs from issue
+// URLs and records the sub-issues added
+type subIssueClient struct {
+	ghclient.Client
+	added []string
+}
+
+func (c *subIssueClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	return map[string]interface{}{"node_id": "I_" + url[strings.LastIndex(url, "/")+1:]}, nil
+}
+
+func (c *subIssueClient) AddSubIssue(ctx context.Context, issueID, subIssueID string) error {
+	c.added = append(c.added, issueID+" > "+subIssueID)
+	return nil
+}
+
+func TestParentResolver(t *testing.T) {
+	resolver := NewParentResolver()
+	resolver.AddRows([]parser.ImportItem{
+		{ID: "EPIC-1", Title: "Epic", URL: "https://github.com/octo/app/issues/1"},
+		{ID: "EPIC-2", Title: "Draft epic"},
+	})
+
+	if url, err := resolver.Resolve("EPIC-1"); err != nil || url != "https://github.com/octo/app/issues/1" {
+		t.Errorf("Resolve(EPIC-1) = %q, %v", url, err)
+	}
+	if url, err := resolver.Resolve("https://github.com/octo/app/issues/9"); err != nil || url != "https://github.com/octo/app/issues/9" {
+		t.Errorf("Expected URLs to resolve to themselves, got %q, %v", url, err)
+	}
+	if _, err := resolver.Resolve("EPIC-2"); err == nil || !strings.Contains(err.Error(), "draft issue") {
+		t.Errorf("Expected a draft issue parent to fail, got: %v", err)
+	}
+	if _, err := resolver.Resolve("EPIC-3"); err == nil {
+		t.Error("Expected an unknown row ID to fail")
+	}
+}
+
+func TestSetItemParent(t *testing.T) {
+	client := &subIssueClient{}
+	resolver := NewParentResolver()
+	items := []parser.ImportItem{
+		{ID: "EPIC-1", Title: "Epic", URL: "https://github.com/octo/app/issues/1"},
+		{Title: "Story", URL: "https://github.com/octo/app/issues/2", Parent: "EPIC-1"},
+		{Title: "Bug", URL: "https://github.com/octo/app/issues/3", Parent: "https://github.com/octo/app/issues/1"},
+		{Title: "Draft", Parent: "EPIC-1"},
+		{Title: "Fix", URL: "https://github.com/octo/app/pull/4", Parent: "EPIC-1"},
+		{Title: "Orphan", URL: "https://github.com/octo/app/issues/5", Parent: "EPIC-9"},
+	}
+	resolver.AddRows(items)
+
+	for _, item := range items {
+		SetItemParent(context.Background(), client, item, item.URL, resolver)
+	}
+
+	if expected := []string{"I_1 > I_2", "I_1 > I_3"}; !reflect.DeepEqual(client.added, expected) {
+		t.Errorf("Expected sub-issues %v, got %v", expected, client.added)
+	}
+	if resolver.Linked() != 2 {
+		t.Errorf("Expected 2 linked issues, got %d", resolver.Linked())
+	}
+}
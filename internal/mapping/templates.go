@@ -0,0 +1,134 @@
+// Title and body templates
+// Rebuilds item titles and bodies from Go templates over the source row (--title-template, --body-template)
+package mapping
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ItemTemplates rewrite the titles and bodies of items. The zero value leaves
+// items unchanged.
+type ItemTemplates struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// TemplateData is what a title or body template is executed on
+type TemplateData struct {
+	Title      string
+	Body       string
+	URL        string
+	Repository string
+	Assignees  []string
+	Labels     []string
+	// Fields holds the row's other columns as text, so a column the row
+	// doesn't have is empty, e.g. {{.Fields.Team}} or {{index .Fields "Story Points"}}
+	Fields map[string]string
+}
+
+// ParseItemTemplates parses the --title-template and --body-template
+// values; an empty value leaves that part of the items unchanged
+func ParseItemTemplates(title, body string) (ItemTemplates, error) {
+	var templates ItemTemplates
+	var err error
+	if title != "" {
+		if templates.title, err = template.New("title").Option("missingkey=zero").Parse(title); err != nil {
+			return templates, fmt.Errorf("invalid --title-template: %w", err)
+		}
+	}
+	if body != "" {
+		if templates.body, err = template.New("body").Option("missingkey=zero").Parse(body); err != nil {
+			return templates, fmt.Errorf("invalid --body-template: %w", err)
+		}
+	}
+	return templates, nil
+}
+
+// Apply rewrites the titles and bodies of items. Both templates see the item
+// as it was read. offset is the position of the first item in the source,
+// used in error messages.
+func (t ItemTemplates) Apply(items []parser.ImportItem, offset int) error {
+	if t.title == nil && t.body == nil {
+		return nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		data := newTemplateData(*item)
+
+		if t.title != nil {
+			title, err := executeTemplate(t.title, data)
+			if err != nil {
+				return fmt.Errorf("item %d (%q): %w", offset+i+1, item.Title, err)
+			}
+			// Titles are a single line
+			title = strings.Join(strings.Fields(title), " ")
+			item.Title = title
+			if item.Content.Title != "" {
+				item.Content.Title = title
+			}
+		}
+
+		if t.body != nil {
+			body, err := executeTemplate(t.body, data)
+			if err != nil {
+				return fmt.Errorf("item %d (%q): %w", offset+i+1, item.Title, err)
+			}
+			if item.Content.Body != "" {
+				item.Content.Body = body
+			} else {
+				item.Notes = body
+			}
+		}
+	}
+	return nil
+}
+
+// newTemplateData describes item to the templates
+func newTemplateData(item parser.ImportItem) TemplateData {
+	data := TemplateData{
+		Title:      item.Title,
+		Body:       parser.GetItemBody(item),
+		URL:        item.URL,
+		Repository: item.Repository,
+		Assignees:  item.Assignees,
+		Labels:     item.Labels,
+		Fields:     make(map[string]string, len(item.Fields)),
+	}
+	for name, value := range item.Fields {
+		data.Fields[name] = templateText(value)
+	}
+	return data
+}
+
+// templateText formats a field value for a template, joining lists with commas
+func templateText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, part := range v {
+			parts[i] = templateText(part)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// executeTemplate runs a template on data
+func executeTemplate(tmpl *template.Template, data TemplateData) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("cannot apply --%s-template: %w", tmpl.Name(), err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,57 @@
+// Tests for title and body templates
+package mapping
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestItemTemplatesApply(t *testing.T) {
+	templates, err := ParseItemTemplates("[{{.Fields.Team}}] {{.Title}}", "{{.Body}}\n\nEstimate: {{index .Fields \"Story Points\"}}")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items := []parser.ImportItem{
+		{Title: "Login page", Notes: "Build it", Fields: map[string]interface{}{"Team": "Web", "Story Points": 3.0}},
+		{Title: "Untracked", Content: parser.ItemContent{Title: "Untracked", Body: "From content"}},
+	}
+	if err := templates.Apply(items, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if items[0].Title != "[Web] Login page" || items[0].Notes != "Build it\n\nEstimate: 3" {
+		t.Errorf("Unexpected first item %+v", items[0])
+	}
+	// A missing column is empty rather than "<no value>"
+	if items[1].Title != "[] Untracked" || items[1].Content.Title != "[] Untracked" || items[1].Content.Body != "From content\n\nEstimate: " {
+		t.Errorf("Unexpected second item %+v", items[1])
+	}
+}
+
+func TestItemTemplatesTitleIsOneLine(t *testing.T) {
+	templates, err := ParseItemTemplates("{{.Title}}\n{{range .Labels}} {{.}}{{end}}", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items := []parser.ImportItem{{Title: "Fix  crash", Notes: "Kept", Labels: []string{"bug", "p1"}}}
+	if err := templates.Apply(items, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items[0].Title != "Fix crash bug p1" || items[0].Notes != "Kept" {
+		t.Errorf("Unexpected item %+v", items[0])
+	}
+}
+
+func TestItemTemplatesErrors(t *testing.T) {
+	if _, err := ParseItemTemplates("{{.Title", ""); err == nil || !strings.Contains(err.Error(), "--title-template") {
+		t.Errorf("Expected a parse error naming the flag, got: %v", err)
+	}
+
+	templates, _ := ParseItemTemplates("", "{{.Missing}}")
+	err := templates.Apply(make([]parser.ImportItem, 3), 10)
+	if err == nil || !strings.Contains(err.Error(), "item 11") || !strings.Contains(err.Error(), "--body-template") {
+		t.Errorf("Expected an error naming the item and flag, got: %v", err)
+	}
+}
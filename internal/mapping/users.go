@@ -0,0 +1,130 @@
+// Resolution of GitHub logins for USER fields
+// Looks each login up once per run and remembers the ones that don't exist, so they can be reported together
+package mapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// UserResolver resolves logins to user node IDs, caching the answers for the
+// length of a run. Abandoned items may still be resolving in the background
+// after --item-timeout, so it is safe for concurrent use.
+type UserResolver struct {
+	client ghclient.Client
+
+	mu      sync.Mutex
+	ids     map[string]string
+	unknown map[string]string // lowercased login → login as first written
+}
+
+// NewUserResolver creates a resolver that looks logins up through client
+func NewUserResolver(client ghclient.Client) *UserResolver {
+	return &UserResolver{client: client, ids: make(map[string]string), unknown: make(map[string]string)}
+}
+
+// Resolve returns the node IDs of the users named by a USER field value. All
+// logins are looked up, so the error lists every unknown login in the value.
+func (r *UserResolver) Resolve(ctx context.Context, value interface{}) ([]string, error) {
+	logins, err := parseLogins(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids, unknown []string
+	for _, login := range logins {
+		id, err := r.resolveLogin(ctx, login)
+		if errors.Is(err, ghclient.ErrUserNotFound) {
+			unknown = append(unknown, login)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ghclient.ErrUserNotFound, strings.Join(unknown, ", "))
+	}
+	return ids, nil
+}
+
+// resolveLogin looks up a single login, going to the API only the first time
+func (r *UserResolver) resolveLogin(ctx context.Context, login string) (string, error) {
+	key := strings.ToLower(login)
+
+	r.mu.Lock()
+	id, known := r.ids[key]
+	_, missing := r.unknown[key]
+	r.mu.Unlock()
+	if known {
+		return id, nil
+	}
+	if missing {
+		return "", fmt.Errorf("%w: %s", ghclient.ErrUserNotFound, login)
+	}
+
+	id, err := r.client.GetUserID(ctx, login)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case errors.Is(err, ghclient.ErrUserNotFound):
+		r.unknown[key] = login
+	case err == nil:
+		r.ids[key] = id
+	}
+	return id, err
+}
+
+// Unknown returns the logins that didn't match a GitHub user, sorted
+func (r *UserResolver) Unknown() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logins := make([]string, 0, len(r.unknown))
+	for _, login := range r.unknown {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return logins
+}
+
+// parseLogins splits a USER field value into logins. A value may name several
+// users separated by commas, and a leading @ is dropped, so "@alice, bob"
+// names alice and bob.
+func parseLogins(value interface{}) ([]string, error) {
+	var parts []string
+	switch v := value.(type) {
+	case string:
+		parts = strings.Split(v, ",")
+	case []string:
+		parts = v
+	case []interface{}:
+		for _, element := range v {
+			str, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("user field must be a string or a list of strings")
+			}
+			parts = append(parts, str)
+		}
+	default:
+		return nil, fmt.Errorf("user field must be a string or a list of strings")
+	}
+
+	var logins []string
+	for _, part := range parts {
+		if login := strings.TrimPrefix(strings.TrimSpace(part), "@"); login != "" {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return nil, fmt.Errorf("user field has no logins")
+	}
+	return logins, nil
+}
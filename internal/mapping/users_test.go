@@ -0,0 +1,82 @@
+// Tests for resolving logins in USER fields
+package mapping
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// userDirectoryClient is a Client stub that knows a fixed set of users
+type userDirectoryClient struct {
+	ghclient.Client
+	users   map[string]string
+	lookups []string
+}
+
+func (c *userDirectoryClient) GetUserID(ctx context.Context, login string) (string, error) {
+	c.lookups = append(c.lookups, login)
+	if id, ok := c.users[strings.ToLower(login)]; ok {
+		return id, nil
+	}
+	return "", ghclient.ErrUserNotFound
+}
+
+func TestParseLogins(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected []string
+	}{
+		{"alice", []string{"alice"}},
+		{"@alice, bob,", []string{"alice", "bob"}},
+		{[]interface{}{"alice", "@bob"}, []string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		logins, err := parseLogins(tt.value)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", tt.value, err)
+		}
+		if !reflect.DeepEqual(logins, tt.expected) {
+			t.Errorf("parseLogins(%v) = %v, expected %v", tt.value, logins, tt.expected)
+		}
+	}
+
+	for _, value := range []interface{}{" , ", 42, []interface{}{"alice", 1}} {
+		if _, err := parseLogins(value); err == nil {
+			t.Errorf("Expected an error for %v", value)
+		}
+	}
+}
+
+func TestUserResolver(t *testing.T) {
+	client := &userDirectoryClient{users: map[string]string{"alice": "U_alice", "bob": "U_bob"}}
+	resolver := NewUserResolver(client)
+
+	ids, err := resolver.Resolve(context.Background(), "alice, @Bob")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"U_alice", "U_bob"}) {
+		t.Errorf("Unexpected IDs: %v", ids)
+	}
+
+	_, err = resolver.Resolve(context.Background(), "ALICE, ghost, phantom")
+	if !errors.Is(err, ghclient.ErrUserNotFound) || !strings.Contains(err.Error(), "ghost, phantom") {
+		t.Errorf("Expected both unknown logins to be reported, got: %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "ghost"); err == nil {
+		t.Error("Expected an unknown login to stay unknown")
+	}
+
+	// Each login is only looked up once, known or not, whatever its case
+	if len(client.lookups) != 4 {
+		t.Errorf("Expected 4 lookups, got %v", client.lookups)
+	}
+	if unknown := resolver.Unknown(); !reflect.DeepEqual(unknown, []string{"ghost", "phantom"}) {
+		t.Errorf("Unexpected unknown logins: %v", unknown)
+	}
+}
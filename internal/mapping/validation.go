@@ -0,0 +1,47 @@
+// Findings from field validation
+package mapping
+
+import "fmt"
+
+// ValidationSeverity classifies a field validation finding
+type ValidationSeverity int
+
+const (
+	SeverityNotice  ValidationSeverity = iota // Informational, e.g. a value that was normalized
+	SeverityWarning                           // The value will be skipped
+	SeverityError                             // The item will fail to import
+)
+
+// String returns the label used when printing a severity
+func (s ValidationSeverity) String() string {
+	switch s {
+	case SeverityError:
+		return "ERROR"
+	case SeverityWarning:
+		return "WARNING"
+	default:
+		return "NOTICE"
+	}
+}
+
+// ValidationIssue is a single finding from field validation
+type ValidationIssue struct {
+	Severity ValidationSeverity
+	Message  string
+}
+
+// String formats the issue with its severity label
+func (v ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", v.Severity, v.Message)
+}
+
+// CountValidationIssues counts the issues with the given severity
+func CountValidationIssues(issues []ValidationIssue, severity ValidationSeverity) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
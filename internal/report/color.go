@@ -0,0 +1,59 @@
+// Colored output
+// Success, warning and failure lines in color when the terminal supports it (--color, NO_COLOR)
+package report
+
+import (
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// Color enables ANSI colors in the lines written to stdout
+var Color bool
+
+// ANSI escape sequences of the colors used
+const (
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiReset  = "\033[0m"
+)
+
+// UseColor resolves a --color mode for an output stream: always, never, or
+// auto to color a terminal unless NO_COLOR is set (or CLICOLOR_FORCE forces
+// color anywhere)
+func UseColor(mode string, terminal bool) (bool, error) {
+	switch mode {
+	case "", "auto":
+		return term.IsColorForced() || (terminal && !term.IsColorDisabled()), nil
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported --color %q (expected auto, always or never)", mode)
+}
+
+// Success formats a "✓ message" line, in green when Color is set
+func Success(format string, args ...interface{}) string {
+	return colorize(ansiGreen, "✓ "+fmt.Sprintf(format, args...), Color)
+}
+
+// Warning formats a "⚠ message" line, in yellow when Color is set
+func Warning(format string, args ...interface{}) string {
+	return colorize(ansiYellow, "⚠ "+fmt.Sprintf(format, args...), Color)
+}
+
+// Failure formats a "✗ message" line, in red when Color is set
+func Failure(format string, args ...interface{}) string {
+	return colorize(ansiRed, "✗ "+fmt.Sprintf(format, args...), Color)
+}
+
+// colorize wraps text in an ANSI color when enabled is set
+func colorize(color, text string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	return color + text + ansiReset
+}
@@ -0,0 +1,50 @@
+// Tests for colored output
+package report
+
+import "testing"
+
+func TestUseColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	tests := []struct {
+		mode     string
+		terminal bool
+		noColor  string
+		expected bool
+	}{
+		{"auto", true, "", true},
+		{"auto", false, "", false},
+		{"auto", true, "1", false},
+		{"always", false, "1", true},
+		{"never", true, "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		got, err := UseColor(tt.mode, tt.terminal)
+		if err != nil || got != tt.expected {
+			t.Errorf("UseColor(%q, %v) with NO_COLOR=%q = %v, %v; expected %v", tt.mode, tt.terminal, tt.noColor, got, err, tt.expected)
+		}
+	}
+
+	if _, err := UseColor("sometimes", true); err == nil {
+		t.Error("Expected an error for an unsupported mode")
+	}
+}
+
+func TestStatusLines(t *testing.T) {
+	defer func(color bool) { Color = color }(Color)
+
+	Color = false
+	if got := Warning("%d items failed", 2); got != "⚠ 2 items failed" {
+		t.Errorf("Unexpected plain line %q", got)
+	}
+
+	Color = true
+	if got := Success("Imported %d items", 3); got != "\033[32m✓ Imported 3 items\033[0m" {
+		t.Errorf("Unexpected colored line %q", got)
+	}
+	if got := Failure("boom"); got != "\033[31m✗ boom\033[0m" {
+		t.Errorf("Unexpected colored line %q", got)
+	}
+}
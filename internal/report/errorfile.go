@@ -0,0 +1,134 @@
+// Error report files listing the items that failed to import
+// Written in the source formats so the failed rows can be fixed and imported again
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// FailedItem is an item that failed to import, with the reason
+type FailedItem struct {
+	Item parser.ImportItem
+	Err  error
+}
+
+// ValidateErrorFilePath checks that the error file has a supported extension
+func ValidateErrorFilePath(path string) error {
+	switch errorFileFormat(path) {
+	case "csv", "json", "ndjson":
+		return nil
+	}
+	return fmt.Errorf("unsupported error file format %s: use a .csv, .json, .ndjson or .jsonl file", path)
+}
+
+// errorFileFormat returns the format of an error file based on its extension
+func errorFileFormat(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return "csv"
+	case strings.HasSuffix(lower, ".json"):
+		return "json"
+	case strings.HasSuffix(lower, ".ndjson"), strings.HasSuffix(lower, ".jsonl"):
+		return "ndjson"
+	}
+	return ""
+}
+
+// WriteErrorFile writes the failed items to path in the format matching its
+// extension. The file is written even when nothing failed, so a stale report
+// from a previous run is never mistaken for the current one.
+func WriteErrorFile(path string, failures []FailedItem) error {
+	var data []byte
+	var err error
+
+	switch errorFileFormat(path) {
+	case "csv":
+		data, err = failedItemsCSV(failures)
+	case "json":
+		records := make([]map[string]interface{}, len(failures))
+		for i, failure := range failures {
+			records[i] = failedItemRecord(failure)
+		}
+		data, err = json.MarshalIndent(records, "", "  ")
+	case "ndjson":
+		var lines []string
+		for _, failure := range failures {
+			line, marshalErr := json.Marshal(failedItemRecord(failure))
+			if marshalErr != nil {
+				return fmt.Errorf("failed to write error file: %w", marshalErr)
+			}
+			lines = append(lines, string(line)+"\n")
+		}
+		data = []byte(strings.Join(lines, ""))
+	default:
+		return ValidateErrorFilePath(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write error file: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write error file %s: %w", path, err)
+	}
+	return nil
+}
+
+// failedItemRecord converts a failed item into the raw JSON source format
+func failedItemRecord(failure FailedItem) map[string]interface{} {
+	record := parser.ItemRecord(failure.Item)
+	record[parser.ErrorFileColumn] = failure.Err.Error()
+	return record
+}
+
+// failedItemsCSV converts failed items into the CSV source format, with one
+// column per custom field used by any of them
+func failedItemsCSV(failures []FailedItem) ([]byte, error) {
+	fieldSet := make(map[string]bool)
+	for _, failure := range failures {
+		for name := range failure.Item.Fields {
+			fieldSet[name] = true
+		}
+	}
+	fieldNames := make([]string, 0, len(fieldSet))
+	for name := range fieldSet {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	header := append([]string{"title", "url", "repository", "notes", "assignees", "labels"}, fieldNames...)
+	writer.Write(append(header, parser.ErrorFileColumn))
+
+	for _, failure := range failures {
+		item := failure.Item
+		row := []string{
+			item.Title,
+			item.URL,
+			item.Repository,
+			parser.GetItemBody(item),
+			strings.Join(item.Assignees, ","),
+			strings.Join(item.Labels, ","),
+		}
+		for _, name := range fieldNames {
+			value := ""
+			if v, ok := item.Fields[name]; ok && v != nil {
+				value = fmt.Sprintf("%v", v)
+			}
+			row = append(row, value)
+		}
+		writer.Write(append(row, failure.Err.Error()))
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
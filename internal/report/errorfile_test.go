@@ -0,0 +1,74 @@
+// Tests for error report files
+package report
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestWriteErrorFileRoundTrip(t *testing.T) {
+	failures := []FailedItem{
+		{
+			Item: parser.ImportItem{
+				Title:     "Broken issue",
+				URL:       "https://github.com/owner/repo/issues/1",
+				Assignees: []string{"octocat", "hubot"},
+				Fields:    map[string]interface{}{"Status": "Todo", "Estimate": int64(3)},
+			},
+			Err: errors.New("failed to get issue/PR content: not found"),
+		},
+		{
+			Item: parser.ImportItem{Title: "Broken draft", Notes: "Some notes", Fields: map[string]interface{}{"Priority": "High"}},
+			Err:  errors.New("failed to create project item: boom"),
+		},
+	}
+
+	for _, name := range []string{"failed.csv", "failed.json", "failed.ndjson"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := WriteErrorFile(path, failures); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "failed to create project item: boom") {
+				t.Errorf("Expected error message in file, got:\n%s", data)
+			}
+
+			// The error file can be imported again without the error column becoming a field
+			items, err := parser.ParseSources([]string{path}, parser.SourceOptions{})
+			if err != nil {
+				t.Fatalf("Failed to parse error file: %v", err)
+			}
+			if len(items) != 2 {
+				t.Fatalf("Expected 2 items, got %d", len(items))
+			}
+			if items[0].Title != "Broken issue" || items[0].URL != failures[0].Item.URL || len(items[0].Assignees) != 2 {
+				t.Errorf("Unexpected first item: %+v", items[0])
+			}
+			if items[0].Fields["Status"] != "Todo" {
+				t.Errorf("Expected Status field to round-trip, got %v", items[0].Fields["Status"])
+			}
+			if parser.GetItemBody(items[1]) != "Some notes" || items[1].Fields["Priority"] != "High" {
+				t.Errorf("Unexpected second item: %+v", items[1])
+			}
+			for _, item := range items {
+				if _, ok := item.Fields[parser.ErrorFileColumn]; ok {
+					t.Errorf("Expected %s to be ignored when parsing", parser.ErrorFileColumn)
+				}
+			}
+		})
+	}
+
+	if err := ValidateErrorFilePath("failed.txt"); err == nil {
+		t.Error("Expected error for unsupported error file format")
+	}
+}
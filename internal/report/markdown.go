@@ -0,0 +1,160 @@
+// Markdown run summaries
+// A human-readable report of a run (--report) to attach to a migration ticket or post as a PR comment
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// FieldSummary is a field set by the source items and where it was imported
+type FieldSummary struct {
+	Source string
+	// Field is the project field the values went to, empty when the project
+	// has no such field and the values were skipped
+	Field string
+	Type  string
+	// Set and Skipped count the values set on items and skipped, and
+	// SkipReasons says why they were skipped, e.g. "2 bad option"
+	Set         int
+	Skipped     int
+	SkipReasons string
+}
+
+// Summary is everything the Markdown report describes
+type Summary struct {
+	Run        RunReport
+	ProjectURL string
+	Fields     []FieldSummary
+}
+
+// WriteMarkdownReport writes the summary of a run to path as Markdown
+func WriteMarkdownReport(path string, summary Summary) error {
+	if err := os.WriteFile(path, []byte(summary.Markdown()), 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
+
+// Markdown renders the summary: the counts, the field mapping, the failed
+// items with their errors and links to the created items
+func (s Summary) Markdown() string {
+	run := s.Run
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Import into %s\n\n", markdownText(run.ProjectTitle))
+	fmt.Fprintf(&b, "%s\n\n", markdownText(run.Text))
+
+	fmt.Fprintf(&b, "| | Items |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Total | %d |\n", run.Total)
+	fmt.Fprintf(&b, "| Created | %d |\n", run.Created)
+	if run.Updated > 0 {
+		fmt.Fprintf(&b, "| Updated, matched by --match-key | %d |\n", run.Updated)
+	}
+	fmt.Fprintf(&b, "| Failed | %d |\n", run.Failed)
+	if run.TimedOut > 0 {
+		fmt.Fprintf(&b, "| Timed out | %d |\n", run.TimedOut)
+	}
+	fmt.Fprintf(&b, "| Skipped, imported by a previous run | %d |\n\n", run.Skipped)
+
+	fmt.Fprintf(&b, "- **Status:** %s\n", run.Status)
+	fmt.Fprintf(&b, "- **Project:** %s\n", markdownLink(run.Project, s.ProjectURL))
+	switch {
+	case run.FromClassic != "":
+		fmt.Fprintf(&b, "- **Source:** classic project %s\n", markdownCode(run.FromClassic))
+	case run.FromQuery != "":
+		fmt.Fprintf(&b, "- **Source:** search %s\n", markdownCode(run.FromQuery))
+	case len(run.Sources) > 0:
+		sources := make([]string, len(run.Sources))
+		for i, source := range run.Sources {
+			sources[i] = markdownCode(source)
+		}
+		fmt.Fprintf(&b, "- **Source:** %s\n", strings.Join(sources, ", "))
+	}
+	fmt.Fprintf(&b, "- **Started:** %s, took %s\n", run.Started.UTC().Format("2006-01-02 15:04:05 MST"),
+		(time.Duration(run.Seconds * float64(time.Second))).Round(time.Second))
+	if run.RunID != "" {
+		fmt.Fprintf(&b, "- **Run ID:** %s, undo with %s\n", markdownCode(run.RunID), markdownCode("gh project-import undo "+run.RunID))
+	}
+	if run.ErrorFile != "" {
+		fmt.Fprintf(&b, "- **Error file:** %s\n", markdownCode(run.ErrorFile))
+	}
+
+	if len(s.Fields) > 0 {
+		fmt.Fprintf(&b, "\n## Field Mapping\n\n")
+		fmt.Fprintf(&b, "| Source field | Project field | Type | Values set | Values skipped |\n|---|---|---|---:|---|\n")
+		for _, field := range s.Fields {
+			target := markdownText(field.Field)
+			if field.Field == "" {
+				target = "*not in project*"
+			}
+			skipped := fmt.Sprint(field.Skipped)
+			if field.Skipped > 0 {
+				skipped += " (" + markdownText(field.SkipReasons) + ")"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n", markdownText(field.Source), target, markdownText(field.Type), field.Set, skipped)
+		}
+	}
+
+	if len(run.Failures) > 0 {
+		fmt.Fprintf(&b, "\n## Failed Items (%d)\n\n", len(run.Failures))
+		fmt.Fprintf(&b, "| Item | Error |\n|---|---|\n")
+		for _, failure := range run.Failures {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownLink(failure.Title, failure.URL), markdownText(failure.Error))
+		}
+	}
+
+	if len(run.CreatedItems) > 0 {
+		fmt.Fprintf(&b, "\n## Created Items (%d)\n\n", len(run.CreatedItems))
+		for _, item := range run.CreatedItems {
+			if item.URL == "" {
+				// Draft issues only exist in the project
+				fmt.Fprintf(&b, "- %s (draft issue)\n", markdownLink(item.Title, s.ProjectURL))
+				continue
+			}
+			fmt.Fprintf(&b, "- %s\n", markdownLink(item.Title, item.URL))
+		}
+	}
+
+	return b.String()
+}
+
+// markdownEscaper escapes the characters that would end a table cell or
+// start Markdown formatting, and keeps text on one line
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`",
+	"[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;",
+	"\r\n", " ", "\n", " ", "\r", " ",
+)
+
+// markdownText escapes text for a Markdown paragraph or table cell
+func markdownText(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownLink links text to url, or returns the escaped text without one
+func markdownLink(text, url string) string {
+	if text == "" {
+		text = url
+	}
+	if url == "" {
+		return markdownText(text)
+	}
+	return fmt.Sprintf("[%s](<%s>)", markdownText(text), url)
+}
+
+// markdownCode formats text as inline code, with a fence longer than any run
+// of backticks in it
+func markdownCode(text string) string {
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "|", "\\|").Replace(text)
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
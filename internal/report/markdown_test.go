@@ -0,0 +1,85 @@
+// Tests for Markdown run summaries
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMarkdownReport(t *testing.T) {
+	started := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	summary := Summary{
+		Run: RunReport{
+			Text:         `Imported 2 of 3 items to "Roadmap", 1 failed`,
+			Status:       StatusPartial,
+			Sources:      []string{"items.csv"},
+			Project:      "acme/roadmap",
+			ProjectTitle: "Roadmap",
+			Total:        3,
+			Created:      2,
+			Failed:       1,
+			Failures:     []ReportFailure{{Title: "Broken | item", URL: "https://github.com/acme/app/issues/9", Error: "not found"}},
+			CreatedItems: []CreatedItem{
+				{ItemID: "PVTI_1", Title: "Fix login", URL: "https://github.com/acme/app/issues/2"},
+				{ItemID: "PVTI_2", Title: "Plan *launch*"},
+			},
+			RunID:   "20260301-093000",
+			Started: started,
+			Seconds: 12.4,
+		},
+		ProjectURL: "https://github.com/orgs/acme/projects/1",
+		Fields: []FieldSummary{
+			{Source: "Owner", Skipped: 1, SkipReasons: "1 missing field"},
+			{Source: "Status", Field: "Status", Type: "SINGLE_SELECT", Set: 2, Skipped: 1, SkipReasons: "1 bad option"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := WriteMarkdownReport(path, summary); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+
+	for _, expected := range []string{
+		"# Import into Roadmap\n",
+		"| Created | 2 |\n",
+		"| Failed | 1 |\n",
+		"- **Project:** [acme/roadmap](<https://github.com/orgs/acme/projects/1>)\n",
+		"- **Source:** `items.csv`\n",
+		"- **Started:** 2026-03-01 09:30:00 UTC, took 12s\n",
+		"undo with `gh project-import undo 20260301-093000`",
+		"| Owner | *not in project* |  | 0 | 1 (1 missing field) |\n",
+		"| Status | Status | SINGLE\\_SELECT | 2 | 1 (1 bad option) |\n",
+		"| [Broken \\| item](<https://github.com/acme/app/issues/9>) | not found |\n",
+		"- [Fix login](<https://github.com/acme/app/issues/2>)\n",
+		"- [Plan \\*launch\\*](<https://github.com/orgs/acme/projects/1>) (draft issue)\n",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, report)
+		}
+	}
+	if strings.Contains(report, "Timed out") {
+		t.Errorf("Expected no timed out row when nothing timed out, got:\n%s", report)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	tests := map[string]string{
+		"items.csv":      "`items.csv`",
+		"a`b":            "``a`b``",
+		"`quoted`":       "`` `quoted` ``",
+		"line\nbreak|ok": "`line break\\|ok`",
+	}
+	for input, expected := range tests {
+		if got := markdownCode(input); got != expected {
+			t.Errorf("markdownCode(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Completion notifications
+// Posts the run report to a webhook or pipes it to a command when an import finishes (--on-complete-url, --on-complete-cmd)
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// notifyTimeout bounds how long a completion hook may take
+const notifyTimeout = 30 * time.Second
+
+// Run statuses of a RunReport
+const (
+	StatusSucceeded   = "succeeded"   // Every item was imported
+	StatusPartial     = "partial"     // Some items failed
+	StatusFailed      = "failed"      // No item was imported and some failed
+	StatusInterrupted = "interrupted" // Ctrl-C or --timeout stopped the run
+	StatusDryRun      = "dry-run"     // Nothing was imported (--dry-run)
+	StatusAborted     = "aborted"     // The import was declined at the confirmation prompt
+)
+
+// RunReport is the final report of an import run, as sent to completion hooks
+type RunReport struct {
+	// Text summarizes the run in one line, e.g. for a Slack incoming webhook
+	Text         string          `json:"text"`
+	Status       string          `json:"status"`
+	Sources      []string        `json:"sources,omitempty"`
+	FromClassic  string          `json:"from_classic,omitempty"`
+	FromQuery    string          `json:"from_query,omitempty"`
+	Project      string          `json:"project"`
+	ProjectTitle string          `json:"project_title"`
+	Total        int             `json:"total"`
+	Created      int             `json:"created"`
+	Updated      int             `json:"updated,omitempty"`
+	Failed       int             `json:"failed"`
+	TimedOut     int             `json:"timed_out"`
+	Skipped      int             `json:"skipped"`
+	Failures     []ReportFailure `json:"failures"`
+	CreatedItems []CreatedItem   `json:"created_items"`
+	RunID        string          `json:"run_id,omitempty"`
+	Checkpoint   string          `json:"checkpoint,omitempty"`
+	ErrorFile    string          `json:"error_file,omitempty"`
+	Started      time.Time       `json:"started"`
+	Finished     time.Time       `json:"finished"`
+	Seconds      float64         `json:"duration_seconds"`
+}
+
+// ReportFailure is an item that failed to import
+type ReportFailure struct {
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error"`
+}
+
+// CreatedItem is an item the run added to the project
+type CreatedItem struct {
+	ItemID string `json:"item_id"`
+	Title  string `json:"title"`
+	// URL is the issue or pull request, empty for a draft issue
+	URL string `json:"url,omitempty"`
+}
+
+// NewReportFailures describes failed items for a RunReport
+func NewReportFailures(failures []FailedItem) []ReportFailure {
+	result := make([]ReportFailure, len(failures))
+	for i, failure := range failures {
+		result[i] = ReportFailure{Title: failure.Item.Title, URL: failure.Item.URL, Error: failure.Err.Error()}
+	}
+	return result
+}
+
+// RunStatus returns the status of a run that imported created items and
+// failed on failed items
+func RunStatus(created, failed int, interrupted bool) string {
+	switch {
+	case interrupted:
+		return StatusInterrupted
+	case failed > 0 && created == 0:
+		return StatusFailed
+	case failed > 0:
+		return StatusPartial
+	}
+	return StatusSucceeded
+}
+
+// CompletionHooks deliver the run report when an import finishes. The zero
+// value does nothing.
+type CompletionHooks struct {
+	// URL receives the report as a JSON POST request
+	URL string
+	// Command is a program and its arguments, split on whitespace without a
+	// shell, that reads the report on stdin
+	Command []string
+}
+
+// ParseCompletionHooks parses the --on-complete-url and --on-complete-cmd values
+func ParseCompletionHooks(webhook, command string) (CompletionHooks, error) {
+	var hooks CompletionHooks
+	if webhook != "" {
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return hooks, fmt.Errorf("invalid --on-complete-url %q: expected an http or https URL", webhook)
+		}
+		hooks.URL = webhook
+	}
+	if command != "" {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return hooks, fmt.Errorf("invalid --on-complete-cmd %q: no command", command)
+		}
+		if _, err := exec.LookPath(fields[0]); err != nil {
+			return hooks, fmt.Errorf("invalid --on-complete-cmd: %w", err)
+		}
+		hooks.Command = fields
+	}
+	return hooks, nil
+}
+
+// Notify sends the report to the webhook and the command. Both are attempted
+// even if one fails; the errors are returned. The command's output goes to
+// stderr so it never mixes with TSV rows on stdout.
+func (h CompletionHooks) Notify(ctx context.Context, report RunReport) []error {
+	if h.URL == "" && len(h.Command) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return []error{fmt.Errorf("failed to marshal run report: %w", err)}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	var errs []error
+	if h.URL != "" {
+		if err := postReport(ctx, h.URL, data); err != nil {
+			errs = append(errs, fmt.Errorf("--on-complete-url: %w", err))
+		}
+	}
+	if len(h.Command) > 0 {
+		cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			errs = append(errs, fmt.Errorf("--on-complete-cmd %s: %w", h.Command[0], err))
+		}
+	}
+	return errs
+}
+
+// postReport posts the JSON report to a webhook
+func postReport(ctx context.Context, webhook string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gh-project-import")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Tests for completion notifications
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseCompletionHooks(t *testing.T) {
+	for _, webhook := range []string{"ftp://example.com/hook", "hooks.slack.com/services/x", "https://"} {
+		if _, err := ParseCompletionHooks(webhook, ""); err == nil {
+			t.Errorf("Expected an error for --on-complete-url %q", webhook)
+		}
+	}
+	if _, err := ParseCompletionHooks("", "./does-not-exist.sh"); err == nil {
+		t.Error("Expected an error for a missing --on-complete-cmd program")
+	}
+
+	hooks, err := ParseCompletionHooks("", "")
+	if err != nil || hooks.Notify(context.Background(), RunReport{}) != nil {
+		t.Errorf("Expected no hooks to do nothing, got %+v, %v", hooks, err)
+	}
+}
+
+func TestCompletionHooksNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script needs a POSIX shell")
+	}
+
+	var posted RunReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &posted); err != nil {
+			t.Errorf("Expected a JSON report, got %s", body)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "report.json")
+	script := filepath.Join(dir, "notify.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat > \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := ParseCompletionHooks(server.URL, script+" "+output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runReport := RunReport{Text: "Imported 2 of 3 items", Status: RunStatus(2, 1, false), Created: 2, Failed: 1, Failures: []ReportFailure{{Title: "Broken", Error: "boom"}}}
+	if errs := hooks.Notify(context.Background(), runReport); errs != nil {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	if posted.Status != StatusPartial || posted.Created != 2 || len(posted.Failures) != 1 {
+		t.Errorf("Expected the webhook to receive the report, got %+v", posted)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil || !strings.Contains(string(data), `"text":"Imported 2 of 3 items"`) {
+		t.Errorf("Expected the command to receive the report on stdin, got %s (%v)", data, err)
+	}
+}
+
+func TestCompletionHooksNotifyErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	hooks, err := ParseCompletionHooks(server.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := hooks.Notify(context.Background(), RunReport{})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "404") || !strings.Contains(errs[0].Error(), "no such hook") {
+		t.Errorf("Expected the webhook's error to be returned, got %v", errs)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	tests := []struct {
+		created, failed int
+		interrupted     bool
+		want            string
+	}{
+		{3, 0, false, StatusSucceeded},
+		{0, 0, false, StatusSucceeded},
+		{2, 1, false, StatusPartial},
+		{0, 1, false, StatusFailed},
+		{2, 0, true, StatusInterrupted},
+	}
+	for _, tt := range tests {
+		if got := RunStatus(tt.created, tt.failed, tt.interrupted); got != tt.want {
+			t.Errorf("RunStatus(%d, %d, %v) = %s, want %s", tt.created, tt.failed, tt.interrupted, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Run output: summary lines, TSV rows and the validation, truncation and metrics reports
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// PrintResultLine writes the single-line machine-readable summary of a run,
+// e.g. "RESULT created=120 updated=4 failed=2 skipped=9 report=/path/to/checkpoint.json".
+// It is written in every output mode so wrapper scripts can rely on it; the
+// report key is omitted when checkpointing is disabled.
+func PrintResultLine(w io.Writer, created, updated, failed, skipped int, checkpointPath string) {
+	line := fmt.Sprintf("RESULT created=%d updated=%d failed=%d skipped=%d", created, updated, failed, skipped)
+	if checkpointPath != "" {
+		if abs, err := filepath.Abs(checkpointPath); err == nil {
+			checkpointPath = abs
+		}
+		line += " report=" + checkpointPath
+	}
+	fmt.Fprintln(w, line)
+}
+
+// PrintTSVRow prints one tab-separated result line for an item. Tabs and
+// newlines in the title are replaced so every item stays on a single line.
+func PrintTSVRow(status, itemID string, item parser.ImportItem) {
+	title := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(item.Title)
+	fmt.Printf("%s\t%s\t%s\t%s\n", status, itemID, item.URL, title)
+}
+
+// severityColor returns the ANSI color code used for a severity
+func severityColor(s mapping.ValidationSeverity) string {
+	switch s {
+	case mapping.SeverityError:
+		return ansiRed
+	case mapping.SeverityWarning:
+		return ansiYellow
+	default:
+		return ansiCyan
+	}
+}
+
+// PrintValidationIssues prints issues grouped by severity, most severe first,
+// followed by a count of each severity
+func PrintValidationIssues(issues []mapping.ValidationIssue, color bool) {
+	fmt.Printf("Field validation results:\n")
+	for _, severity := range []mapping.ValidationSeverity{mapping.SeverityError, mapping.SeverityWarning, mapping.SeverityNotice} {
+		for _, issue := range issues {
+			if issue.Severity != severity {
+				continue
+			}
+			label := fmt.Sprintf("%-7s", severity)
+			label = colorize(severityColor(severity), label, color)
+			fmt.Printf("  %s %s\n", label, issue.Message)
+		}
+	}
+	fmt.Printf("Validation summary: %d errors, %d warnings, %d notices\n",
+		mapping.CountValidationIssues(issues, mapping.SeverityError),
+		mapping.CountValidationIssues(issues, mapping.SeverityWarning),
+		mapping.CountValidationIssues(issues, mapping.SeverityNotice))
+}
+
+// PrintTruncations writes the truncation section of the report
+func PrintTruncations(w io.Writer, truncations []mapping.Truncation) {
+	fmt.Fprintln(w, Warning("Truncated %d values to fit size limits:", len(truncations)))
+	for _, t := range truncations {
+		fmt.Fprintf(w, "   - %s\n", t)
+	}
+}
+
+// PrintMetrics writes the --metrics section of the report: the API requests
+// of the run and the time spent on each of the items it imported
+func PrintMetrics(w io.Writer, metrics ghclient.MetricsSummary, items int, itemTime time.Duration) {
+	fmt.Fprintln(w, "Metrics:")
+	fmt.Fprintf(w, "   - API requests: %d (%d GraphQL, %d REST), %s in total\n",
+		metrics.GraphQLRequests+metrics.RESTRequests, metrics.GraphQLRequests, metrics.RESTRequests, metrics.RequestTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "   - GraphQL rate limit points: %d\n", metrics.GraphQLPoints)
+	if items > 0 {
+		fmt.Fprintf(w, "   - Average time per item: %s (%d items)\n", (itemTime / time.Duration(items)).Round(time.Millisecond), items)
+	}
+	if len(metrics.Slowest) > 0 {
+		fmt.Fprintln(w, "   - Slowest requests:")
+		for _, operation := range metrics.Slowest {
+			fmt.Fprintf(w, "       %s  %s\n", operation.Duration.Round(time.Millisecond), operation.Name)
+		}
+	}
+}
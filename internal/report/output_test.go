@@ -0,0 +1,47 @@
+// Tests for console output
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+func TestPrintResultLine(t *testing.T) {
+	var out strings.Builder
+	PrintResultLine(&out, 120, 4, 2, 9, "")
+	if out.String() != "RESULT created=120 updated=4 failed=2 skipped=9\n" {
+		t.Errorf("Unexpected result line %q", out.String())
+	}
+
+	out.Reset()
+	PrintResultLine(&out, 1, 0, 0, 0, "/tmp/run.checkpoint.json")
+	if out.String() != "RESULT created=1 updated=0 failed=0 skipped=0 report=/tmp/run.checkpoint.json\n" {
+		t.Errorf("Unexpected result line %q", out.String())
+	}
+}
+
+func TestPrintMetrics(t *testing.T) {
+	var out strings.Builder
+	metrics := ghclient.MetricsSummary{
+		GraphQLRequests: 10,
+		RESTRequests:    2,
+		GraphQLPoints:   11,
+		RequestTime:     3 * time.Second,
+		Slowest:         []ghclient.Operation{{Name: "mutation addProjectV2ItemById", Duration: 1200 * time.Millisecond}},
+	}
+	PrintMetrics(&out, metrics, 4, 2*time.Second)
+
+	expected := `Metrics:
+   - API requests: 12 (10 GraphQL, 2 REST), 3s in total
+   - GraphQL rate limit points: 11
+   - Average time per item: 500ms (4 items)
+   - Slowest requests:
+       1.2s  mutation addProjectV2ItemById
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected metrics:\n%s", out.String())
+	}
+}
@@ -0,0 +1,398 @@
+// Resolution cache for issue, pull request and user lookups
+// Memory, file and Redis backends let scheduled syncs share lookups between runs and hosts
+package ghclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResolutionCache stores the results of resolving issue and pull request URLs
+type ResolutionCache interface {
+	// Get returns the cached value for key and whether it was found
+	Get(key string) (string, bool, error)
+	Set(key, value string) error
+	// Close flushes pending writes and releases the backend
+	Close() error
+}
+
+// OpenResolutionCache opens the cache described by spec: "memory" (or empty)
+// for a cache that lasts one run, "none" to disable caching, "file:PATH" for a
+// JSON file shared between runs, or "redis://[:password@]host:port[/db]" to
+// share the cache between hosts.
+func OpenResolutionCache(spec string) (ResolutionCache, error) {
+	switch {
+	case spec == "" || spec == "memory":
+		return newMemoryCache(), nil
+	case spec == "none":
+		return nil, nil
+	case strings.HasPrefix(spec, "file:"):
+		return openFileCache(strings.TrimPrefix(spec, "file:"))
+	case strings.HasPrefix(spec, "redis://"):
+		return openRedisCache(spec)
+	}
+	return nil, fmt.Errorf("unsupported cache %q (expected memory, none, file:PATH or redis://host:port)", spec)
+}
+
+// memoryCache is a ResolutionCache that lasts for a single run
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string]string)}
+}
+
+func (c *memoryCache) Get(key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	return value, ok, nil
+}
+
+func (c *memoryCache) Set(key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Close() error {
+	return nil
+}
+
+// fileCache is a memory cache loaded from and saved to a JSON file
+type fileCache struct {
+	*memoryCache
+	path  string
+	dirty bool
+}
+
+// openFileCache loads the cache file, starting empty if it doesn't exist yet
+func openFileCache(path string) (*fileCache, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file cache requires a path (file:PATH)")
+	}
+
+	cache := &fileCache{memoryCache: newMemoryCache(), path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cache file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &cache.values); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file %s: %w", path, err)
+	}
+	return cache, nil
+}
+
+func (c *fileCache) Set(key, value string) error {
+	c.mu.Lock()
+	c.dirty = true
+	c.mu.Unlock()
+	return c.memoryCache.Set(key, value)
+}
+
+// Close writes the cache back to its file, replacing it atomically
+func (c *fileCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// redisCacheTTL is how long resolved entries are kept in Redis
+const redisCacheTTL = 7 * 24 * time.Hour
+
+// redisKeyPrefix namespaces the cache keys in a shared Redis database
+const redisKeyPrefix = "gh-project-import:"
+
+// redisCommandTimeout is how long a Redis command may take before the cache
+// gives up and lookups go to the API
+const redisCommandTimeout = 2 * time.Second
+
+// redisCache is a ResolutionCache stored in Redis, spoken to over RESP
+type redisCache struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// timeout limits each command. After a command fails part way, replies
+	// can no longer be matched to commands, so broken holds the failure and
+	// every later command returns it without touching the connection.
+	timeout time.Duration
+	broken  error
+}
+
+// openRedisCache connects to the Redis server in a redis:// URL
+func openRedisCache(spec string) (*redisCache, error) {
+	parsed, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL %s: %w", spec, err)
+	}
+
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+
+	conn, err := net.DialTimeout("tcp", host, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", host, err)
+	}
+	cache := &redisCache{conn: conn, reader: bufio.NewReader(conn), timeout: redisCommandTimeout}
+
+	if password, ok := parsed.User.Password(); ok {
+		if _, err := cache.do("AUTH", password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to authenticate with redis: %w", err)
+		}
+	}
+
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if _, err := strconv.Atoi(db); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+		if _, err := cache.do("SELECT", db); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to select redis database %s: %w", db, err)
+		}
+	}
+
+	return cache, nil
+}
+
+func (c *redisCache) Get(key string) (string, bool, error) {
+	reply, err := c.do("GET", redisKeyPrefix+key)
+	if err != nil {
+		return "", false, fmt.Errorf("redis GET failed: %w", err)
+	}
+	if reply == nil {
+		return "", false, nil
+	}
+	return *reply, true, nil
+}
+
+func (c *redisCache) Set(key, value string) error {
+	ttl := strconv.Itoa(int(redisCacheTTL.Seconds()))
+	if _, err := c.do("SET", redisKeyPrefix+key, value, "EX", ttl); err != nil {
+		return fmt.Errorf("redis SET failed: %w", err)
+	}
+	return nil
+}
+
+func (c *redisCache) Close() error {
+	return c.conn.Close()
+}
+
+// do sends a command and reads its reply, giving up once c.timeout has passed.
+// A nil reply means the key doesn't exist.
+func (c *redisCache) do(args ...string) (*string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broken != nil {
+		return nil, fmt.Errorf("connection unusable after an earlier failure: %w", c.broken)
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	reply, err := c.roundTrip(args)
+	var replyErr redisReplyError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.broken = err
+	}
+	return reply, err
+}
+
+// redisReplyError is an error reply from the server, after which the
+// connection is still in step
+type redisReplyError string
+
+func (e redisReplyError) Error() string { return string(e) }
+
+// roundTrip sends a command and reads a simple, error, integer or bulk
+// string reply
+func (c *redisCache) roundTrip(args []string) (*string, error) {
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(cmd.String())); err != nil {
+		return nil, err
+	}
+
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		value := line[1:]
+		return &value, nil
+	case '-':
+		return nil, redisReplyError(line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk reply %q", line)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, length+2) // Value followed by \r\n
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		value := string(buf[:length])
+		return &value, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
+
+// CachedClient resolves issue and pull request URLs through a cache
+type CachedClient struct {
+	Client
+	cache ResolutionCache
+}
+
+// NewCachedClient wraps a client with a resolution cache
+func NewCachedClient(client Client, cache ResolutionCache) *CachedClient {
+	return &CachedClient{Client: client, cache: cache}
+}
+
+// cachedContent is the part of an issue or PR needed to import it
+type cachedContent struct {
+	NodeID  string `json:"node_id"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// GetIssueOrPR returns the cached node ID and URL of an issue or PR,
+// resolving and caching it on a miss. Cache failures fall back to the API.
+func (cc *CachedClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	key := "issue:" + strings.ToLower(url)
+
+	if value, ok, err := cc.cache.Get(key); err == nil && ok {
+		var content cachedContent
+		if err := json.Unmarshal([]byte(value), &content); err == nil && content.NodeID != "" {
+			return map[string]interface{}{"node_id": content.NodeID, "html_url": content.HTMLURL}, nil
+		}
+	}
+
+	response, err := cc.Client.GetIssueOrPR(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	content := cachedContent{NodeID: GetString(response, "node_id"), HTMLURL: GetString(response, "html_url")}
+	if content.NodeID != "" {
+		if data, err := json.Marshal(content); err == nil {
+			cc.cache.Set(key, string(data))
+		}
+	}
+
+	return response, nil
+}
+
+// Prefetch resolves the issue and pull request URLs that aren't cached yet
+// with batched queries and caches them, so that GetIssueOrPR finds them
+// without a request of its own. It returns the number of URLs resolved.
+func (cc *CachedClient) Prefetch(ctx context.Context, urls []string) (int, error) {
+	var missing []string
+	seen := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		key := "issue:" + strings.ToLower(url)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok, err := cc.cache.Get(key); err == nil && ok {
+			continue
+		}
+		missing = append(missing, url)
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	contents, err := cc.Client.GetIssuesOrPRs(ctx, missing)
+	if err != nil {
+		return 0, err
+	}
+	for url, response := range contents {
+		content := cachedContent{NodeID: GetString(response, "node_id"), HTMLURL: GetString(response, "html_url")}
+		if content.NodeID == "" {
+			continue
+		}
+		if data, err := json.Marshal(content); err == nil {
+			cc.cache.Set("issue:"+strings.ToLower(url), string(data))
+		}
+	}
+	return len(contents), nil
+}
+
+// GetUserID returns the cached node ID of a user, resolving and caching it on
+// a miss. Logins are case-insensitive, so the key is lowercased.
+func (cc *CachedClient) GetUserID(ctx context.Context, login string) (string, error) {
+	key := "user:" + strings.ToLower(login)
+
+	if value, ok, err := cc.cache.Get(key); err == nil && ok && value != "" {
+		return value, nil
+	}
+
+	id, err := cc.Client.GetUserID(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	cc.cache.Set(key, id)
+	return id, nil
+}
@@ -0,0 +1,269 @@
+// Tests for the issue resolution cache backends
+package ghclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exerciseCache checks the basic get/set behavior shared by every backend
+func exerciseCache(t *testing.T, cache ResolutionCache) {
+	t.Helper()
+
+	if _, ok, err := cache.Get("missing"); err != nil || ok {
+		t.Errorf("Expected miss, got ok=%v err=%v", ok, err)
+	}
+	if err := cache.Set("issue:a", `{"node_id":"I_1"}`); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, ok, err := cache.Get("issue:a"); err != nil || !ok || value != `{"node_id":"I_1"}` {
+		t.Errorf("Expected hit, got %q ok=%v err=%v", value, ok, err)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache, err := OpenResolutionCache("memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseCache(t, cache)
+
+	if cache, err := OpenResolutionCache("none"); err != nil || cache != nil {
+		t.Errorf("Expected no cache for none, got %v, %v", cache, err)
+	}
+	if _, err := OpenResolutionCache("memcached://localhost"); err == nil {
+		t.Error("Expected error for unsupported cache")
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache, err := OpenResolutionCache("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseCache(t, cache)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// A later run sees the saved entries
+	cache, err = OpenResolutionCache("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, _ := cache.Get("issue:a"); !ok || value != `{"node_id":"I_1"}` {
+		t.Errorf("Expected entry to persist, got %q ok=%v", value, ok)
+	}
+}
+
+// startFakeRedis serves GET and SET from a map over RESP
+func startFakeRedis(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	values := make(map[string]string)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					args, err := readRESPCommand(reader)
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(args[0]) {
+					case "GET":
+						if value, ok := values[args[1]]; ok {
+							fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+						} else {
+							fmt.Fprint(conn, "$-1\r\n")
+						}
+					case "SET":
+						values[args[1]] = args[2]
+						fmt.Fprint(conn, "+OK\r\n")
+					default:
+						fmt.Fprint(conn, "+OK\r\n")
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// readRESPCommand reads one array-of-bulk-strings command
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, count)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil { // $length
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisCache(t *testing.T) {
+	addr := startFakeRedis(t)
+
+	cache, err := OpenResolutionCache("redis://:secret@" + addr + "/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	exerciseCache(t, cache)
+}
+
+func TestRedisCacheUnresponsive(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		// Accept and read commands but never reply
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(io.Discard, conn)
+	}()
+
+	cache, err := OpenResolutionCache("redis://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	cache.(*redisCache).timeout = 50 * time.Millisecond
+
+	inner := &lookupCountingClient{}
+	client := NewCachedClient(inner, cache)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		content, err := client.GetIssueOrPR(context.Background(), "https://github.com/owner/repo/issues/1")
+		if err != nil || content["node_id"] != "I_1" {
+			t.Fatalf("Expected the lookup to fall back to the API, got %v, %v", content, err)
+		}
+	}
+	if inner.lookups != 3 {
+		t.Errorf("Expected 3 API lookups, got %d", inner.lookups)
+	}
+	// Only the first command waits for the deadline
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the cache to give up quickly, took %s", elapsed)
+	}
+	if _, _, err := cache.Get("issue:a"); err == nil || !strings.Contains(err.Error(), "unusable") {
+		t.Errorf("Expected the broken connection to be reported, got: %v", err)
+	}
+}
+
+// lookupCountingClient is a Client stub that counts issue and user lookups
+type lookupCountingClient struct {
+	Client
+	lookups int
+}
+
+func (c *lookupCountingClient) GetUserID(ctx context.Context, login string) (string, error) {
+	c.lookups++
+	if strings.EqualFold(login, "alice") {
+		return "U_alice", nil
+	}
+	return "", ErrUserNotFound
+}
+
+func (c *lookupCountingClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	c.lookups++
+	return map[string]interface{}{"node_id": "I_1", "html_url": url, "title": "Issue"}, nil
+}
+
+func TestCachedGitHubClient(t *testing.T) {
+	inner := &lookupCountingClient{}
+	client := NewCachedClient(inner, newMemoryCache())
+
+	url := "https://github.com/owner/repo/issues/1"
+	for i := 0; i < 3; i++ {
+		content, err := client.GetIssueOrPR(context.Background(), url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content["node_id"] != "I_1" || content["html_url"] != url {
+			t.Errorf("Unexpected content: %v", content)
+		}
+	}
+
+	if inner.lookups != 1 {
+		t.Errorf("Expected 1 API lookup, got %d", inner.lookups)
+	}
+}
+
+func TestCachedGitHubClientUserIDs(t *testing.T) {
+	inner := &lookupCountingClient{}
+	client := NewCachedClient(inner, newMemoryCache())
+
+	for _, login := range []string{"alice", "Alice"} {
+		if id, err := client.GetUserID(context.Background(), login); err != nil || id != "U_alice" {
+			t.Errorf("GetUserID(%q) = %q, %v", login, id, err)
+		}
+	}
+	if inner.lookups != 1 {
+		t.Errorf("Expected 1 API lookup, got %d", inner.lookups)
+	}
+}
+
+func TestCachedGitHubClientPrefetch(t *testing.T) {
+	fake := NewFakeClient()
+	first := fake.AddIssue("https://github.com/owner/repo/issues/1", "First", "")
+	second := fake.AddIssue("https://github.com/owner/repo/issues/2", "Second", "")
+	client := NewCachedClient(fake, newMemoryCache())
+	ctx := context.Background()
+
+	urls := []string{"https://github.com/owner/repo/issues/1", "https://github.com/owner/repo/issues/2", "https://github.com/owner/repo/issues/1", "https://github.com/owner/repo/issues/3"}
+	if found, err := client.Prefetch(ctx, urls); err != nil || found != 2 {
+		t.Fatalf("Expected 2 URLs to be resolved, got %d, %v", found, err)
+	}
+	for url, id := range map[string]string{urls[0]: first, urls[1]: second} {
+		if content, err := client.GetIssueOrPR(ctx, url); err != nil || content["node_id"] != id {
+			t.Errorf("Expected %s to be %s, got %v, %v", url, id, content, err)
+		}
+	}
+	// Only the URL that wasn't found is looked up again
+	if found, err := client.Prefetch(ctx, urls); err != nil || found != 0 {
+		t.Errorf("Expected nothing more to be found, got %d, %v", found, err)
+	}
+	expected := []string{"GetIssuesOrPRs", "GetIssuesOrPRs"}
+	if strings.Join(fake.Calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected only batched lookups, got %v", fake.Calls)
+	}
+}
@@ -0,0 +1,730 @@
+// In-memory fake of the GitHub API for tests
+// FakeClient keeps projects, issues, users and milestones in memory, so importer code can be tested without recorded snapshots
+package ghclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var _ Client = (*FakeClient)(nil)
+
+// FakeProject is a project held by FakeClient
+type FakeProject struct {
+	Project
+	Owner  string
+	Fields []ProjectField
+	Items  []ProjectItem
+}
+
+// FakeClient is an in-memory Client. Tests fill in its projects, issues,
+// users and milestones, run the code under test, and then inspect what
+// changed. Errors makes a method fail, to test error handling. Use
+// NewFakeClient to create one; it is safe for concurrent use.
+type FakeClient struct {
+	mu sync.Mutex
+
+	Login         string
+	Projects      []*FakeProject
+	Issues        map[string]map[string]interface{} // URL → issue or pull request, as GetIssueOrPR returns it
+	Users         map[string]string                 // Login → node ID
+	Milestones    map[string][]Milestone            // "owner/repo" → milestones
+	ClassicBoards map[string][]ClassicProjectColumn // "owner/repo/number" → columns
+	Searches      map[string][]SearchResult         // Query → results
+	SubIssues     map[string][]string               // Parent node ID → sub-issue node IDs
+
+	// Errors maps a method name, e.g. "CreateDraftIssue", to the error it
+	// returns instead of doing anything
+	Errors map[string]error
+	// Calls lists the names of the methods called, in order
+	Calls []string
+
+	nextID int
+}
+
+// NewFakeClient creates a fake without any projects, acting as user "octocat"
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Login:         "octocat",
+		Issues:        make(map[string]map[string]interface{}),
+		Users:         make(map[string]string),
+		Milestones:    make(map[string][]Milestone),
+		ClassicBoards: make(map[string][]ClassicProjectColumn),
+		Searches:      make(map[string][]SearchResult),
+		SubIssues:     make(map[string][]string),
+		Errors:        make(map[string]error),
+	}
+}
+
+// AddProject adds a project owned by owner with the given fields and returns
+// it. The project gets an ID and number when they are left empty.
+func (f *FakeClient) AddProject(owner string, project Project, fields ...ProjectField) *FakeProject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if project.ID == "" {
+		project.ID = f.newID("PVT")
+	}
+	if project.Number == 0 {
+		project.Number = len(f.Projects) + 1
+	}
+	fake := &FakeProject{Project: project, Owner: owner, Fields: fields}
+	f.Projects = append(f.Projects, fake)
+	return fake
+}
+
+// AddIssue adds the issue or pull request at url and returns its node ID
+func (f *FakeClient) AddIssue(url, title, body string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	number, _ := ParseContentNumber(url)
+	nodeID := f.newID("I")
+	f.Issues[url] = map[string]interface{}{
+		"node_id":  nodeID,
+		"number":   float64(number),
+		"title":    title,
+		"body":     body,
+		"html_url": url,
+		"state":    "open",
+	}
+	return nodeID
+}
+
+// Project returns the project with the given ID, or nil
+func (f *FakeClient) Project(projectID string) *FakeProject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.project(projectID)
+}
+
+// GetUser returns Login
+func (f *FakeClient) GetUser(ctx context.Context) (string, error) {
+	if err := f.call("GetUser"); err != nil {
+		return "", err
+	}
+	return f.Login, nil
+}
+
+// FindProject finds a project by number, by owner/#number or by owner/title
+func (f *FakeClient) FindProject(ctx context.Context, identifier string) (*Project, error) {
+	if err := f.call("FindProject"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	number, numErr := strconv.Atoi(identifier)
+	for _, project := range f.Projects {
+		if numErr == nil && project.Number == number {
+			return &project.Project, nil
+		}
+		if strings.EqualFold(project.Owner+"/"+project.Title, identifier) ||
+			strings.EqualFold(fmt.Sprintf("%s/#%d", project.Owner, project.Number), identifier) {
+			return &project.Project, nil
+		}
+	}
+	return nil, fmt.Errorf("project not found: %s", identifier)
+}
+
+// ListProjects returns the projects of owner
+func (f *FakeClient) ListProjects(ctx context.Context, owner string) ([]Project, error) {
+	if err := f.call("ListProjects"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var projects []Project
+	for _, project := range f.Projects {
+		if strings.EqualFold(project.Owner, owner) {
+			projects = append(projects, project.Project)
+		}
+	}
+	return projects, nil
+}
+
+// GetProjectFields returns the fields of a project
+func (f *FakeClient) GetProjectFields(ctx context.Context, projectID string) ([]ProjectField, error) {
+	if err := f.call("GetProjectFields"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	return append([]ProjectField(nil), project.Fields...), nil
+}
+
+// CreateProjectField adds a field to a project
+func (f *FakeClient) CreateProjectField(ctx context.Context, projectID, name, dataType string) (*ProjectField, error) {
+	if err := f.call("CreateProjectField"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	for _, field := range project.Fields {
+		if field.Name == name {
+			return nil, fmt.Errorf("a field named %s already exists", name)
+		}
+	}
+	field := ProjectField{ID: f.newID("PVTF"), Name: name, Type: dataType}
+	project.Fields = append(project.Fields, field)
+	return &field, nil
+}
+
+// CreateProjectItem adds the issue or pull request with node ID contentID to
+// a project. Like the API, adding content twice returns the existing item.
+func (f *FakeClient) CreateProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	if err := f.call("CreateProjectItem"); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return "", fmt.Errorf("project not found: %s", projectID)
+	}
+
+	var content map[string]interface{}
+	for _, issue := range f.Issues {
+		if GetString(issue, "node_id") == contentID {
+			content = issue
+		}
+	}
+	if content == nil {
+		return "", fmt.Errorf("no issue or pull request has the ID %s", contentID)
+	}
+
+	url := GetString(content, "html_url")
+	for _, item := range project.Items {
+		if item.Content["url"] == url {
+			return item.ID, nil
+		}
+	}
+
+	itemType := "Issue"
+	if strings.Contains(url, "/pull/") {
+		itemType = "PullRequest"
+	}
+	item := ProjectItem{
+		ID: f.newID("PVTI"),
+		Content: map[string]interface{}{
+			"type":   itemType,
+			"id":     contentID,
+			"title":  GetString(content, "title"),
+			"url":    url,
+			"number": GetInt(content, "number"),
+		},
+		Fields: make(map[string]interface{}),
+	}
+	project.Items = append(project.Items, item)
+	return item.ID, nil
+}
+
+// CreateDraftIssue adds a draft issue to a project
+func (f *FakeClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	if err := f.call("CreateDraftIssue"); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return "", fmt.Errorf("project not found: %s", projectID)
+	}
+	item := ProjectItem{
+		ID:      f.newID("PVTI"),
+		Content: map[string]interface{}{"type": "DraftIssue", "id": f.newID("DI"), "title": title, "body": body},
+		Fields:  make(map[string]interface{}),
+	}
+	project.Items = append(project.Items, item)
+	return item.ID, nil
+}
+
+// UpdateDraftIssue changes the title and body of the draft issue whose
+// content has the ID draftIssueID
+func (f *FakeClient) UpdateDraftIssue(ctx context.Context, projectID, draftIssueID, title, body string) error {
+	if err := f.call("UpdateDraftIssue"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	for _, item := range project.Items {
+		if item.Content["type"] == "DraftIssue" && item.Content["id"] == draftIssueID {
+			item.Content["title"] = title
+			item.Content["body"] = body
+			return nil
+		}
+	}
+	return fmt.Errorf("draft issue not found: %s", draftIssueID)
+}
+
+// SetProjectItemFieldValue sets a field of an item. value is a
+// ProjectV2FieldValue as built by the mapping package; the item's Fields
+// hold the value the way ListProjectItems reports it, e.g. option names.
+func (f *FakeClient) SetProjectItemFieldValue(ctx context.Context, projectID, itemID, fieldID string, value interface{}) error {
+	if err := f.call("SetProjectItemFieldValue"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	item := project.item(itemID)
+	if item == nil {
+		return fmt.Errorf("item not found: %s", itemID)
+	}
+
+	var field *ProjectField
+	for i := range project.Fields {
+		if project.Fields[i].ID == fieldID {
+			field = &project.Fields[i]
+		}
+	}
+	if field == nil {
+		return fmt.Errorf("field not found: %s", fieldID)
+	}
+
+	converted, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid value for field %s: %v", field.Name, value)
+	}
+
+	switch field.Type {
+	case "SINGLE_SELECT":
+		for _, option := range field.Options {
+			if option.ID == converted["singleSelectOptionId"] {
+				item.Fields[field.Name] = option.Name
+				return nil
+			}
+		}
+		return fmt.Errorf("no option of field %s has the ID %v", field.Name, converted["singleSelectOptionId"])
+	case "ITERATION":
+		for _, iteration := range field.Iterations {
+			if iteration.ID == converted["iterationId"] {
+				item.Fields[field.Name] = iteration.Title
+				return nil
+			}
+		}
+		return fmt.Errorf("no iteration of field %s has the ID %v", field.Name, converted["iterationId"])
+	case "USER":
+		ids, _ := converted["assigneeIds"].([]string)
+		var logins []string
+		for _, id := range ids {
+			for login, userID := range f.Users {
+				if userID == id {
+					logins = append(logins, login)
+				}
+			}
+		}
+		item.Fields[field.Name] = logins
+	case "NUMBER":
+		item.Fields[field.Name] = converted["number"]
+	case "DATE":
+		item.Fields[field.Name] = converted["date"]
+	default:
+		item.Fields[field.Name] = converted["text"]
+	}
+	return nil
+}
+
+// GetIssueOrPR returns the issue or pull request at url
+func (f *FakeClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	if err := f.call("GetIssueOrPR"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	content, found := f.Issues[url]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrContentNotFound, url)
+	}
+	return content, nil
+}
+
+// GetIssuesOrPRs returns the issues and pull requests at urls, leaving out
+// the URLs it doesn't know
+func (f *FakeClient) GetIssuesOrPRs(ctx context.Context, urls []string) (map[string]map[string]interface{}, error) {
+	if err := f.call("GetIssuesOrPRs"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	contents := make(map[string]map[string]interface{}, len(urls))
+	for _, url := range urls {
+		if content, found := f.Issues[url]; found {
+			contents[url] = content
+		}
+	}
+	return contents, nil
+}
+
+// DeleteProjectItem removes an item from a project
+func (f *FakeClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	if err := f.call("DeleteProjectItem"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	for i, item := range project.Items {
+		if item.ID == itemID {
+			project.Items = append(project.Items[:i], project.Items[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("item not found: %s", itemID)
+}
+
+// ArchiveProjectItem archives an item
+func (f *FakeClient) ArchiveProjectItem(ctx context.Context, projectID, itemID string) error {
+	if err := f.call("ArchiveProjectItem"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	item := project.item(itemID)
+	if item == nil {
+		return fmt.Errorf("item not found: %s", itemID)
+	}
+	item.Archived = true
+	return nil
+}
+
+// SetProjectItemPosition moves an item after afterID, or to the top when
+// afterID is empty
+func (f *FakeClient) SetProjectItemPosition(ctx context.Context, projectID, itemID, afterID string) error {
+	if err := f.call("SetProjectItemPosition"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	item := project.item(itemID)
+	if item == nil {
+		return fmt.Errorf("item not found: %s", itemID)
+	}
+	moved := *item
+
+	var rest []ProjectItem
+	for _, other := range project.Items {
+		if other.ID != itemID {
+			rest = append(rest, other)
+		}
+	}
+
+	position := 0
+	if afterID != "" {
+		position = -1
+		for i, other := range rest {
+			if other.ID == afterID {
+				position = i + 1
+			}
+		}
+		if position < 0 {
+			return fmt.Errorf("item not found: %s", afterID)
+		}
+	}
+
+	project.Items = append(rest[:position:position], append([]ProjectItem{moved}, rest[position:]...)...)
+	return nil
+}
+
+// GetClassicProjectColumns returns the columns of a classic project board
+func (f *FakeClient) GetClassicProjectColumns(ctx context.Context, owner, repo string, number int) ([]ClassicProjectColumn, error) {
+	if err := f.call("GetClassicProjectColumns"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	columns, found := f.ClassicBoards[fmt.Sprintf("%s/%s/%d", owner, repo, number)]
+	if !found {
+		return nil, fmt.Errorf("classic project %d not found in %s/%s", number, owner, repo)
+	}
+	return columns, nil
+}
+
+// SearchIssues returns the results stored for the query, or none
+func (f *FakeClient) SearchIssues(ctx context.Context, query string) ([]SearchResult, error) {
+	if err := f.call("SearchIssues"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.Searches[query], nil
+}
+
+// ListProjectItems returns the items of a project in board order
+func (f *FakeClient) ListProjectItems(ctx context.Context, projectID string) ([]ProjectItem, error) {
+	if err := f.call("ListProjectItems"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	return append([]ProjectItem(nil), project.Items...), nil
+}
+
+// CopyProject copies a project's fields, but not its items, to a new project
+func (f *FakeClient) CopyProject(ctx context.Context, projectID, title string) (*Project, error) {
+	if err := f.call("CopyProject"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	source := f.project(projectID)
+	f.mu.Unlock()
+	if source == nil {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+
+	fields := append([]ProjectField(nil), source.Fields...)
+	copied := f.AddProject(source.Owner, Project{Title: title}, fields...)
+	return &copied.Project, nil
+}
+
+// DeleteProject deletes a project
+func (f *FakeClient) DeleteProject(ctx context.Context, projectID string) error {
+	if err := f.call("DeleteProject"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, project := range f.Projects {
+		if project.ID == projectID {
+			f.Projects = append(f.Projects[:i], f.Projects[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("project not found: %s", projectID)
+}
+
+// ReopenProject marks a project open
+func (f *FakeClient) ReopenProject(ctx context.Context, projectID string) error {
+	if err := f.call("ReopenProject"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	project := f.project(projectID)
+	if project == nil {
+		return fmt.Errorf("project not found: %s", projectID)
+	}
+	project.Closed = false
+	return nil
+}
+
+// GetUserID returns the node ID of a user in Users
+func (f *FakeClient) GetUserID(ctx context.Context, login string) (string, error) {
+	if err := f.call("GetUserID"); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id, found := f.Users[login]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, login)
+	}
+	return id, nil
+}
+
+// ListMilestones returns the milestones of a repository
+func (f *FakeClient) ListMilestones(ctx context.Context, owner, repo string) ([]Milestone, error) {
+	if err := f.call("ListMilestones"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Milestone(nil), f.Milestones[owner+"/"+repo]...), nil
+}
+
+// CreateMilestone adds an open milestone to a repository
+func (f *FakeClient) CreateMilestone(ctx context.Context, owner, repo, title string) (*Milestone, error) {
+	if err := f.call("CreateMilestone"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := owner + "/" + repo
+	milestone := Milestone{Number: len(f.Milestones[key]) + 1, Title: title, State: "open"}
+	f.Milestones[key] = append(f.Milestones[key], milestone)
+	return &milestone, nil
+}
+
+// SetIssueMilestone sets the "milestone" of an issue or pull request to the
+// milestone with the given number
+func (f *FakeClient) SetIssueMilestone(ctx context.Context, owner, repo string, number, milestone int) error {
+	if err := f.call("SetIssueMilestone"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	issue := f.issue(owner, repo, number)
+	if issue == nil {
+		return fmt.Errorf("issue %s/%s#%d not found", owner, repo, number)
+	}
+	for _, candidate := range f.Milestones[owner+"/"+repo] {
+		if candidate.Number == milestone {
+			issue["milestone"] = map[string]interface{}{"number": candidate.Number, "title": candidate.Title}
+			return nil
+		}
+	}
+	return fmt.Errorf("milestone %d not found in %s/%s", milestone, owner, repo)
+}
+
+// AddSubIssue records subIssueID in SubIssues under issueID
+func (f *FakeClient) AddSubIssue(ctx context.Context, issueID, subIssueID string) error {
+	if err := f.call("AddSubIssue"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, existing := range f.SubIssues[issueID] {
+		if existing == subIssueID {
+			return fmt.Errorf("%s is already a sub-issue of %s", subIssueID, issueID)
+		}
+	}
+	f.SubIssues[issueID] = append(f.SubIssues[issueID], subIssueID)
+	return nil
+}
+
+// GetIssueBody returns the body of an issue or pull request
+func (f *FakeClient) GetIssueBody(ctx context.Context, owner, repo string, number int) (string, error) {
+	if err := f.call("GetIssueBody"); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	issue := f.issue(owner, repo, number)
+	if issue == nil {
+		return "", fmt.Errorf("issue %s/%s#%d not found", owner, repo, number)
+	}
+	return GetString(issue, "body"), nil
+}
+
+// SetIssueBody replaces the body of an issue or pull request
+func (f *FakeClient) SetIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	if err := f.call("SetIssueBody"); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	issue := f.issue(owner, repo, number)
+	if issue == nil {
+		return fmt.Errorf("issue %s/%s#%d not found", owner, repo, number)
+	}
+	issue["body"] = body
+	return nil
+}
+
+// call records a call of method and returns the error Errors sets for it
+func (f *FakeClient) call(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, method)
+	return f.Errors[method]
+}
+
+// draftProject returns the ID of the project with the draft issue whose
+// content has the ID draftIssueID, or "" if there is none. Callers hold f.mu.
+func (f *FakeClient) draftProject(draftIssueID string) string {
+	for _, project := range f.Projects {
+		for _, item := range project.Items {
+			if item.Content["type"] == "DraftIssue" && item.Content["id"] == draftIssueID {
+				return project.Project.ID
+			}
+		}
+	}
+	return ""
+}
+
+// newID returns a new node ID with the given prefix. Callers hold f.mu.
+func (f *FakeClient) newID(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s_fake%d", prefix, f.nextID)
+}
+
+// project returns the project with the given ID, or nil. Callers hold f.mu.
+func (f *FakeClient) project(projectID string) *FakeProject {
+	for _, project := range f.Projects {
+		if project.ID == projectID {
+			return project
+		}
+	}
+	return nil
+}
+
+// issue returns the issue or pull request with the given number in
+// owner/repo, or nil. Callers hold f.mu.
+func (f *FakeClient) issue(owner, repo string, number int) map[string]interface{} {
+	for url, issue := range f.Issues {
+		issueOwner, issueRepo, err := ParseRepositoryURL(url)
+		if err != nil || !strings.EqualFold(issueOwner, owner) || !strings.EqualFold(issueRepo, repo) {
+			continue
+		}
+		if n, ok := ParseContentNumber(url); ok && n == number {
+			return issue
+		}
+	}
+	return nil
+}
+
+// item returns the item with the given ID, or nil
+func (p *FakeProject) item(itemID string) *ProjectItem {
+	for i := range p.Items {
+		if p.Items[i].ID == itemID {
+			return &p.Items[i]
+		}
+	}
+	return nil
+}
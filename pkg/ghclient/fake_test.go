@@ -0,0 +1,130 @@
+// Tests for the in-memory fake client
+package ghclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newFakeWithProject() (*FakeClient, *FakeProject) {
+	fake := NewFakeClient()
+	project := fake.AddProject("my-org", Project{Title: "Roadmap"},
+		ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "O_todo", Name: "Todo"}}},
+		ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"},
+		ProjectField{ID: "F_owner", Name: "Owner", Type: "USER"},
+	)
+	return fake, project
+}
+
+func TestFakeClientDraftItems(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	fake.Users["octocat"] = "U_1"
+
+	found, err := fake.FindProject(ctx, "my-org/roadmap")
+	if err != nil || found.ID != project.ID {
+		t.Fatalf("Expected to find the project by owner/title, got %v, %v", found, err)
+	}
+	if _, err := fake.FindProject(ctx, "1"); err != nil {
+		t.Errorf("Expected to find the project by number, got: %v", err)
+	}
+
+	itemID, err := fake.CreateDraftIssue(ctx, project.ID, "Plan launch", "Details")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	values := map[string]map[string]interface{}{
+		"F_status":   {"singleSelectOptionId": "O_todo"},
+		"F_estimate": {"number": float64(3)},
+		"F_owner":    {"assigneeIds": []string{"U_1"}},
+	}
+	for fieldID, value := range values {
+		if err := fake.SetProjectItemFieldValue(ctx, project.ID, itemID, fieldID, value); err != nil {
+			t.Fatalf("Unexpected error setting %s: %v", fieldID, err)
+		}
+	}
+
+	items, _ := fake.ListProjectItems(ctx, project.ID)
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(items))
+	}
+	expected := map[string]interface{}{"Status": "Todo", "Estimate": float64(3), "Owner": []string{"octocat"}}
+	if !reflect.DeepEqual(items[0].Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, items[0].Fields)
+	}
+
+	if err := fake.SetProjectItemFieldValue(ctx, project.ID, itemID, "F_status", map[string]interface{}{"singleSelectOptionId": "O_gone"}); err == nil {
+		t.Error("Expected an error for an unknown option")
+	}
+}
+
+func TestFakeClientIssueItems(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	url := "https://github.com/my-org/app/issues/7"
+	nodeID := fake.AddIssue(url, "Fix login", "Steps")
+
+	first, err := fake.CreateProjectItem(ctx, project.ID, nodeID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := fake.CreateProjectItem(ctx, project.ID, nodeID)
+	if first != second || len(project.Items) != 1 {
+		t.Errorf("Expected adding an issue twice to return the same item, got %s and %s", first, second)
+	}
+	if project.Items[0].Content["number"] != 7 || project.Items[0].Content["type"] != "Issue" {
+		t.Errorf("Unexpected item content: %v", project.Items[0].Content)
+	}
+
+	if err := fake.SetIssueBody(ctx, "my-org", "app", 7, "Closes it"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if body, _ := fake.GetIssueBody(ctx, "my-org", "app", 7); body != "Closes it" {
+		t.Errorf("Expected the new body, got %q", body)
+	}
+
+	if _, err := fake.GetIssueOrPR(ctx, "https://github.com/my-org/app/issues/8"); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("Expected ErrContentNotFound, got: %v", err)
+	}
+}
+
+func TestFakeClientPositions(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	a, _ := fake.CreateDraftIssue(ctx, project.ID, "A", "")
+	b, _ := fake.CreateDraftIssue(ctx, project.ID, "B", "")
+	c, _ := fake.CreateDraftIssue(ctx, project.ID, "C", "")
+
+	if err := fake.SetProjectItemPosition(ctx, project.ID, c, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := fake.SetProjectItemPosition(ctx, project.ID, a, c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var order []string
+	for _, item := range project.Items {
+		order = append(order, item.ID)
+	}
+	if expected := []string{c, a, b}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+}
+
+func TestFakeClientErrors(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	fake.Errors["CreateDraftIssue"] = errors.New("rate limited")
+
+	if _, err := fake.CreateDraftIssue(ctx, project.ID, "A", ""); err == nil || err.Error() != "rate limited" {
+		t.Errorf("Expected the configured error, got: %v", err)
+	}
+	if len(project.Items) != 0 {
+		t.Errorf("Expected a failing call to change nothing, got %d items", len(project.Items))
+	}
+	if expected := []string{"CreateDraftIssue"}; !reflect.DeepEqual(fake.Calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, fake.Calls)
+	}
+}
@@ -0,0 +1,403 @@
+// Fake GitHub API server for tests
+// FakeServer answers the REST and GraphQL requests RealClient sends for projects, fields, items and field values from a FakeClient's state
+package ghclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// FakeServer is an http.Handler serving the subset of the GitHub API that
+// RealClient uses to find and delete projects and to read and change their
+// fields, items and field values. Requests are answered from, and change, the
+// projects, issues and users of Fake, so a test can set up a FakeClient,
+// run code against a RealClient talking to the server, and inspect the
+// result as with FakeClient alone. This exercises RealClient's queries and
+// response parsing without network access. Other requests get a 404 or a
+// GraphQL error.
+type FakeServer struct {
+	Fake *FakeClient
+}
+
+// NewFakeServer creates a server backed by fake
+func NewFakeServer(fake *FakeClient) *FakeServer {
+	return &FakeServer{Fake: fake}
+}
+
+// Client returns a RealClient whose requests are answered by the server
+// in-process, without listening on a port
+func (s *FakeServer) Client() (*RealClient, error) {
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "fake-server",
+		Headers:   map[string]string{"User-Agent": userAgent("")},
+		Transport: handlerTransport{handler: s},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return &RealClient{client: *client}, nil
+}
+
+// handlerTransport is an http.RoundTripper that answers requests with a handler
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip implements http.RoundTripper
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *FakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+		s.serveGraphQL(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"login": s.Fake.Login})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/users/"):
+		s.serveUser(w, strings.TrimPrefix(r.URL.Path, "/users/"))
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "Not Found"})
+	}
+}
+
+// serveUser answers a user lookup. A login is a user if it is in the fake's
+// Users, is the fake's Login or owns one of its projects.
+func (s *FakeServer) serveUser(w http.ResponseWriter, login string) {
+	s.Fake.mu.Lock()
+	defer s.Fake.mu.Unlock()
+
+	id, found := s.Fake.Users[login]
+	if !found {
+		known := strings.EqualFold(login, s.Fake.Login)
+		for _, project := range s.Fake.Projects {
+			known = known || strings.EqualFold(project.Owner, login)
+		}
+		if !known {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "Not Found"})
+			return
+		}
+		id = "U_" + login
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"login": login, "node_id": id, "type": "User"})
+}
+
+// serveGraphQL answers a GraphQL request by the operation its query runs.
+// Errors are reported in the response's errors, as the API does.
+func (s *FakeServer) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"message": "Problems parsing JSON"})
+		return
+	}
+
+	data, err := s.graphQL(r, request.Query, request.Variables)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": err.Error()}}})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// graphQL runs a query or mutation against the fake and returns its data
+func (s *FakeServer) graphQL(r *http.Request, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	ctx := r.Context()
+	str := func(name string) string {
+		value, _ := variables[name].(string)
+		return value
+	}
+
+	switch {
+	case strings.Contains(query, "resource(url:"):
+		// One aliased field rN per $urlN variable
+		var urls []string
+		for i := 0; variables[fmt.Sprintf("url%d", i)] != nil; i++ {
+			urls = append(urls, str(fmt.Sprintf("url%d", i)))
+		}
+		contents, err := s.Fake.GetIssuesOrPRs(ctx, urls)
+		if err != nil {
+			return nil, err
+		}
+		data := make(map[string]interface{}, len(urls))
+		for i, url := range urls {
+			content, found := contents[url]
+			if !found {
+				data[fmt.Sprintf("r%d", i)] = nil
+				continue
+			}
+			typename := "Issue"
+			if strings.Contains(url, "/pull/") {
+				typename = "PullRequest"
+			}
+			data[fmt.Sprintf("r%d", i)] = map[string]interface{}{"__typename": typename, "id": content["node_id"], "url": content["html_url"]}
+		}
+		return data, nil
+
+	case strings.Contains(query, "addProjectV2ItemById"):
+		id, err := s.Fake.CreateProjectItem(ctx, str("projectId"), str("contentId"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"addProjectV2ItemById": map[string]interface{}{"item": map[string]interface{}{"id": id}}}, nil
+
+	case strings.Contains(query, "addProjectV2DraftIssue"):
+		id, err := s.Fake.CreateDraftIssue(ctx, str("projectId"), str("title"), str("body"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"addProjectV2DraftIssue": map[string]interface{}{"projectItem": map[string]interface{}{"id": id}}}, nil
+
+	case strings.Contains(query, "updateProjectV2DraftIssue"):
+		// The API finds the project from the draft's ID
+		s.Fake.mu.Lock()
+		projectID := s.Fake.draftProject(str("draftIssueId"))
+		s.Fake.mu.Unlock()
+		if projectID == "" {
+			return nil, fmt.Errorf("draft issue not found: %s", str("draftIssueId"))
+		}
+		if err := s.Fake.UpdateDraftIssue(ctx, projectID, str("draftIssueId"), str("title"), str("body")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"updateProjectV2DraftIssue": map[string]interface{}{"draftIssue": map[string]interface{}{"id": str("draftIssueId")}}}, nil
+
+	case strings.Contains(query, "updateProjectV2ItemFieldValue"):
+		value, _ := variables["value"].(map[string]interface{})
+		// The fake takes assignees as the mapping package builds them
+		if ids, ok := value["assigneeIds"].([]interface{}); ok {
+			logins := make([]string, len(ids))
+			for i, id := range ids {
+				logins[i], _ = id.(string)
+			}
+			value["assigneeIds"] = logins
+		}
+		if err := s.Fake.SetProjectItemFieldValue(ctx, str("projectId"), str("itemId"), str("fieldId"), value); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"updateProjectV2ItemFieldValue": map[string]interface{}{"projectV2Item": map[string]interface{}{"id": str("itemId")}}}, nil
+
+	case strings.Contains(query, "deleteProjectV2Item"):
+		if err := s.Fake.DeleteProjectItem(ctx, str("projectId"), str("itemId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"deleteProjectV2Item": map[string]interface{}{"deletedItemId": str("itemId")}}, nil
+
+	case strings.Contains(query, "deleteProjectV2("):
+		if err := s.Fake.DeleteProject(ctx, str("projectId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"deleteProjectV2": map[string]interface{}{"projectV2": map[string]interface{}{"id": str("projectId")}}}, nil
+
+	case strings.Contains(query, "createProjectV2Field"):
+		field, err := s.Fake.CreateProjectField(ctx, str("projectId"), str("name"), str("dataType"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"createProjectV2Field": map[string]interface{}{"projectV2Field": map[string]interface{}{"id": field.ID, "name": field.Name, "dataType": field.Type}}}, nil
+
+	case strings.Contains(query, "updateProjectV2("):
+		// RealClient only updates projects to reopen them
+		if err := s.Fake.ReopenProject(ctx, str("projectId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"updateProjectV2": map[string]interface{}{"projectV2": map[string]interface{}{"id": str("projectId"), "closed": false}}}, nil
+
+	case strings.Contains(query, "archiveProjectV2Item"):
+		if err := s.Fake.ArchiveProjectItem(ctx, str("projectId"), str("itemId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"archiveProjectV2Item": map[string]interface{}{"item": map[string]interface{}{"id": str("itemId")}}}, nil
+
+	case strings.Contains(query, "updateProjectV2ItemPosition"):
+		if err := s.Fake.SetProjectItemPosition(ctx, str("projectId"), str("itemId"), str("afterId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"updateProjectV2ItemPosition": map[string]interface{}{"clientMutationId": nil}}, nil
+
+	case strings.Contains(query, "projectsV2("):
+		ownerType := "user"
+		if strings.Contains(query, "organization(") {
+			ownerType = "organization"
+		}
+		projects, err := s.Fake.ListProjects(ctx, str("login"))
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]interface{}, len(projects))
+		for i, project := range projects {
+			nodes[i] = project
+		}
+		return map[string]interface{}{ownerType: map[string]interface{}{"projectsV2": connection(nodes)}}, nil
+
+	case strings.Contains(query, "projectV2(number:"):
+		ownerType := "user"
+		if strings.Contains(query, "organization(") {
+			ownerType = "organization"
+		}
+		projects, err := s.Fake.ListProjects(ctx, str("login"))
+		if err != nil {
+			return nil, err
+		}
+		// JSON numbers decode as float64
+		number, _ := variables["number"].(float64)
+		var node interface{}
+		for _, project := range projects {
+			if project.Number == int(number) {
+				node = project
+			}
+		}
+		return map[string]interface{}{ownerType: map[string]interface{}{"projectV2": node}}, nil
+
+	case strings.Contains(query, "fields(first"):
+		fields, err := s.Fake.GetProjectFields(ctx, str("projectId"))
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]interface{}, len(fields))
+		for i, field := range fields {
+			nodes[i] = fakeFieldNode(field)
+		}
+		return map[string]interface{}{"node": map[string]interface{}{"fields": map[string]interface{}{"nodes": nodes}}}, nil
+
+	case strings.Contains(query, "items(first"):
+		s.Fake.mu.Lock()
+		project := s.Fake.project(str("projectId"))
+		var fields []ProjectField
+		if project != nil {
+			fields = project.Fields
+		}
+		s.Fake.mu.Unlock()
+
+		items, err := s.Fake.ListProjectItems(ctx, str("projectId"))
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]interface{}, len(items))
+		for i, item := range items {
+			nodes[i] = fakeItemNode(item, fields)
+		}
+		return map[string]interface{}{"node": map[string]interface{}{"items": connection(nodes)}}, nil
+	}
+
+	kind, name := graphQLOperation(query)
+	return nil, fmt.Errorf("%s %s isn't supported by the fake server", kind, name)
+}
+
+// connection wraps nodes in a connection with a single page
+func connection(nodes []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": nil},
+		"nodes":    nodes,
+	}
+}
+
+// fakeFieldNode encodes a field the way the API returns it, with the
+// iterations of an iteration field in its configuration
+func fakeFieldNode(field ProjectField) map[string]interface{} {
+	node := map[string]interface{}{"id": field.ID, "name": field.Name, "dataType": field.Type}
+	if field.Type == "SINGLE_SELECT" {
+		node["options"] = field.Options
+	}
+	if field.Type == "ITERATION" {
+		iterations, completed := []interface{}{}, []interface{}{}
+		for _, iteration := range field.Iterations {
+			encoded := map[string]interface{}{"id": iteration.ID, "title": iteration.Title, "startDate": iteration.StartDate, "duration": iteration.Duration}
+			if iteration.Completed {
+				completed = append(completed, encoded)
+			} else {
+				iterations = append(iterations, encoded)
+			}
+		}
+		node["configuration"] = map[string]interface{}{"iterations": iterations, "completedIterations": completed}
+	}
+	return node
+}
+
+// fakeItemTypes maps the content types of FakeClient items to the API's enum
+var fakeItemTypes = map[string]string{"DraftIssue": "DRAFT_ISSUE", "Issue": "ISSUE", "PullRequest": "PULL_REQUEST"}
+
+// fakeItemNode encodes an item the way the API returns it, with each field
+// value under the key its field type uses
+func fakeItemNode(item ProjectItem, fields []ProjectField) map[string]interface{} {
+	content := make(map[string]interface{})
+	for _, key := range []string{"id", "title", "body", "url", "number"} {
+		if value, ok := item.Content[key]; ok {
+			content[key] = value
+		}
+	}
+	if url, ok := item.Content["url"].(string); ok {
+		if owner, repo, err := ParseRepositoryURL(url); err == nil {
+			content["repository"] = map[string]interface{}{"nameWithOwner": owner + "/" + repo}
+		}
+	}
+	if logins, ok := item.Content["assignees"].([]string); ok {
+		content["assignees"] = loginConnection(logins)
+	}
+
+	var values []interface{}
+	for _, field := range fields {
+		value, ok := item.Fields[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+		encoded := map[string]interface{}{"field": map[string]interface{}{"name": field.Name}}
+		switch field.Type {
+		case "SINGLE_SELECT":
+			encoded["name"] = value
+		case "ITERATION":
+			encoded["title"] = value
+		case "USER":
+			logins, _ := value.([]string)
+			encoded["users"] = loginConnection(logins)
+		case "NUMBER":
+			encoded["number"] = value
+		case "DATE":
+			encoded["date"] = value
+		default:
+			encoded["text"] = value
+		}
+		values = append(values, encoded)
+	}
+
+	node := map[string]interface{}{
+		"id":          item.ID,
+		"type":        fakeItemTypes[GetString(item.Content, "type")],
+		"isArchived":  item.Archived,
+		"content":     content,
+		"fieldValues": map[string]interface{}{"nodes": values},
+	}
+	if !item.UpdatedAt.IsZero() {
+		node["updatedAt"] = item.UpdatedAt.Format(time.RFC3339)
+	}
+	return node
+}
+
+// loginConnection encodes logins as a user connection ({nodes: [{login}]})
+func loginConnection(logins []string) map[string]interface{} {
+	nodes := make([]interface{}, len(logins))
+	for i, login := range logins {
+		nodes[i] = map[string]interface{}{"login": login}
+	}
+	return map[string]interface{}{"nodes": nodes}
+}
+
+// writeJSON writes value as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,150 @@
+// Tests for the fake GitHub API server
+package ghclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeServerImport(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	fake.Users["octocat"] = "U_1"
+	issueID := fake.AddIssue("https://github.com/my-org/app/issues/7", "Fix login", "Steps")
+
+	client, err := NewFakeServer(fake).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := client.FindProject(ctx, "my-org/roadmap")
+	if err != nil || found.ID != project.ID {
+		t.Fatalf("Expected to find the project by owner/title, got %v, %v", found, err)
+	}
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil || !reflect.DeepEqual(fields, project.Fields) {
+		t.Fatalf("Expected the project's fields, got %v, %v", fields, err)
+	}
+
+	content, err := client.GetIssueOrPR(ctx, "https://github.com/my-org/app/issues/7")
+	if err != nil || GetString(content, "node_id") != issueID {
+		t.Fatalf("Expected the issue, got %v, %v", content, err)
+	}
+	if _, err := client.GetIssueOrPR(ctx, "https://github.com/my-org/app/issues/8"); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("Expected ErrContentNotFound, got %v", err)
+	}
+	resolved, err := client.GetIssuesOrPRs(ctx, []string{"https://github.com/my-org/app/issues/7", "https://github.com/my-org/app/issues/8"})
+	if err != nil || len(resolved) != 1 || GetString(resolved["https://github.com/my-org/app/issues/7"], "node_id") != issueID {
+		t.Errorf("Expected only the issue that exists to be resolved, got %v, %v", resolved, err)
+	}
+
+	issueItem, err := client.CreateProjectItem(ctx, project.ID, issueID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	draftItem, err := client.CreateDraftIssue(ctx, project.ID, "Plan launch", "Details")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]map[string]interface{}{
+		"F_status":   {"singleSelectOptionId": "O_todo"},
+		"F_estimate": {"number": 3},
+		"F_owner":    {"assigneeIds": []string{"U_1"}},
+	}
+	for fieldID, value := range values {
+		if err := client.SetProjectItemFieldValue(ctx, project.ID, draftItem, fieldID, value); err != nil {
+			t.Fatalf("Unexpected error setting %s: %v", fieldID, err)
+		}
+	}
+	if err := client.SetProjectItemPosition(ctx, project.ID, draftItem, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ArchiveProjectItem(ctx, project.ID, issueItem); err != nil {
+		t.Fatal(err)
+	}
+	updated := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	project.Items[0].UpdatedAt = updated
+
+	items, err := client.ListProjectItems(ctx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != draftItem || !items[1].Archived {
+		t.Fatalf("Expected the draft issue and then the archived issue, got %+v", items)
+	}
+	expected := map[string]interface{}{"Status": "Todo", "Estimate": float64(3), "Owner": []string{"octocat"}}
+	if !reflect.DeepEqual(items[0].Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, items[0].Fields)
+	}
+	if !items[0].UpdatedAt.Equal(updated) || !items[1].UpdatedAt.IsZero() {
+		t.Errorf("Expected the draft's update time and none for the issue, got %v and %v", items[0].UpdatedAt, items[1].UpdatedAt)
+	}
+	if items[1].Content["type"] != "Issue" || items[1].Content["id"] != issueID || items[1].Content["repository"] != "my-org/app" || items[1].Content["number"] != 7 {
+		t.Errorf("Unexpected issue content %v", items[1].Content)
+	}
+
+	draftID := GetString(items[0].Content, "id")
+	if err := client.UpdateDraftIssue(ctx, project.ID, draftID, "Plan the launch", "New details"); err != nil {
+		t.Fatal(err)
+	}
+	if draft := project.Items[0].Content; draft["title"] != "Plan the launch" || draft["body"] != "New details" {
+		t.Errorf("Expected the draft's title and body to be updated, got %v", draft)
+	}
+	if err := client.UpdateDraftIssue(ctx, project.ID, "DI_missing", "Title", ""); err == nil {
+		t.Error("Expected an error updating a draft issue that doesn't exist")
+	}
+
+	if err := client.DeleteProjectItem(ctx, project.ID, issueItem); err != nil || len(project.Items) != 1 {
+		t.Errorf("Expected the issue item to be deleted, got %v with %d items", err, len(project.Items))
+	}
+	if err := client.DeleteProjectItem(ctx, project.ID, issueItem); err == nil {
+		t.Error("Expected an error deleting an item that is already deleted")
+	}
+
+	field, err := client.CreateProjectField(ctx, project.ID, "Imported From", "TEXT")
+	if err != nil || field.Type != "TEXT" || project.Fields[len(project.Fields)-1].ID != field.ID {
+		t.Errorf("Expected the text field to be created, got %+v, %v", field, err)
+	}
+
+	project.Closed = true
+	found, err = client.FindProject(ctx, fmt.Sprintf("my-org/#%d", project.Number))
+	if err != nil || found.ID != project.ID || !found.Closed {
+		t.Errorf("Expected to find the closed project by number, got %+v, %v", found, err)
+	}
+	if err := client.ReopenProject(ctx, project.ID); err != nil || project.Closed {
+		t.Errorf("Expected the project to be reopened, got %v with closed=%v", err, project.Closed)
+	}
+
+	if err := client.DeleteProject(ctx, project.ID); err != nil || len(fake.Projects) != 0 {
+		t.Errorf("Expected the project to be deleted, got %v with %d projects", err, len(fake.Projects))
+	}
+	if err := client.DeleteProject(ctx, project.ID); err == nil {
+		t.Error("Expected an error deleting a project that is already deleted")
+	}
+}
+
+func TestFakeServerErrors(t *testing.T) {
+	ctx := context.Background()
+	fake, project := newFakeWithProject()
+	fake.Errors["CreateDraftIssue"] = errors.New("rate limit exceeded")
+
+	client, err := NewFakeServer(fake).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateDraftIssue(ctx, project.ID, "Plan launch", ""); err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("Expected the fake's error, got %v", err)
+	}
+	if _, err := client.FindProject(ctx, "nobody/roadmap"); err == nil {
+		t.Error("Expected an error for an unknown owner")
+	}
+	if _, err := client.CopyProject(ctx, project.ID, "Sandbox"); err == nil || !strings.Contains(err.Error(), "isn't supported") {
+		t.Errorf("Expected an unsupported operation error, got %v", err)
+	}
+}
@@ -0,0 +1,1589 @@
+// GitHub API wrapper functions for project import operations
+// Provides functions to interact with GitHub Projects v2 API
+package ghclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Project represents a GitHub Projects v2 project
+type Project struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+// ProjectField represents a field in a GitHub project
+type ProjectField struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Type       string               `json:"dataType"`
+	Options    []ProjectFieldOption `json:"options,omitempty"`
+	Iterations []IterationOption    `json:"iterations,omitempty"`
+}
+
+// ProjectFieldOption represents an option for single-select fields
+type ProjectFieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IterationOption represents an iteration option for iteration fields
+type IterationOption struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"startDate,omitempty"` // YYYY-MM-DD
+	Duration  int    `json:"duration,omitempty"`  // Length in days
+	Completed bool   `json:"completed,omitempty"`
+}
+
+// ProjectItem represents an item in a GitHub project
+type ProjectItem struct {
+	ID       string                 `json:"id"`
+	Content  map[string]interface{} `json:"content"`
+	Fields   map[string]interface{} `json:"fieldValues"`
+	Archived bool                   `json:"isArchived,omitempty"`
+	// UpdatedAt is when the item last changed, zero when unknown
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// ClassicProjectColumn represents a column of a classic (v1) project board
+type ClassicProjectColumn struct {
+	Name  string               `json:"name"`
+	Cards []ClassicProjectCard `json:"cards"`
+}
+
+// ClassicProjectCard represents a card on a classic project board. Note cards
+// carry free text; issue and pull request cards carry a content URL instead.
+type ClassicProjectCard struct {
+	Note       string `json:"note,omitempty"`
+	ContentURL string `json:"content_url,omitempty"`
+}
+
+// SearchResult is an issue or pull request found by a search
+type SearchResult struct {
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Repository string `json:"repository"` // owner/repo
+	Type       string `json:"type"`       // Issue or PullRequest
+}
+
+// maxSearchResults is the most results the search API returns for a query
+const maxSearchResults = 1000
+
+// Client is the GitHub API surface the importer uses. RealClient talks to the
+// API, SnapshotClient replays recorded calls and FakeClient keeps projects in
+// memory for tests.
+type Client interface {
+	GetUser(ctx context.Context) (string, error)
+	FindProject(ctx context.Context, identifier string) (*Project, error)
+	ListProjects(ctx context.Context, owner string) ([]Project, error)
+	GetProjectFields(ctx context.Context, projectID string) ([]ProjectField, error)
+	CreateProjectField(ctx context.Context, projectID, name, dataType string) (*ProjectField, error)
+	CreateProjectItem(ctx context.Context, projectID, contentID string) (string, error)
+	CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error)
+	UpdateDraftIssue(ctx context.Context, projectID, draftIssueID, title, body string) error
+	SetProjectItemFieldValue(ctx context.Context, projectID, itemID, fieldID string, value interface{}) error
+	GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error)
+	GetIssuesOrPRs(ctx context.Context, urls []string) (map[string]map[string]interface{}, error)
+	DeleteProjectItem(ctx context.Context, projectID, itemID string) error
+	ArchiveProjectItem(ctx context.Context, projectID, itemID string) error
+	SetProjectItemPosition(ctx context.Context, projectID, itemID, afterID string) error
+	GetClassicProjectColumns(ctx context.Context, owner, repo string, number int) ([]ClassicProjectColumn, error)
+	SearchIssues(ctx context.Context, query string) ([]SearchResult, error)
+	ListProjectItems(ctx context.Context, projectID string) ([]ProjectItem, error)
+	CopyProject(ctx context.Context, projectID, title string) (*Project, error)
+	DeleteProject(ctx context.Context, projectID string) error
+	ReopenProject(ctx context.Context, projectID string) error
+	GetUserID(ctx context.Context, login string) (string, error)
+	ListMilestones(ctx context.Context, owner, repo string) ([]Milestone, error)
+	CreateMilestone(ctx context.Context, owner, repo, title string) (*Milestone, error)
+	SetIssueMilestone(ctx context.Context, owner, repo string, number, milestone int) error
+	AddSubIssue(ctx context.Context, issueID, subIssueID string) error
+	GetIssueBody(ctx context.Context, owner, repo string, number int) (string, error)
+	SetIssueBody(ctx context.Context, owner, repo string, number int, body string) error
+}
+
+// RealClient wraps the GitHub API client
+type RealClient struct {
+	client api.RESTClient
+
+	// timeout and metrics come from the ClientOptions the client was
+	// created with and apply to each of its requests
+	timeout time.Duration
+	metrics *Metrics
+}
+
+// ClientOptions configures the clients created by NewClient
+type ClientOptions struct {
+	// Token authenticates requests. When it is empty gh's stored
+	// credentials are used, which may be in the system keyring.
+	Token string
+	// RequestTag is appended to the User-Agent of every request (--request-tag)
+	RequestTag string
+	// RequestTimeout limits how long a single request may take, 0 for no
+	// limit (--request-timeout)
+	RequestTimeout time.Duration
+	// Metrics, when set, collects the metrics of every request (--metrics)
+	Metrics *Metrics
+	// Trace, when set, receives a trace of every request (--trace-api)
+	Trace io.Writer
+}
+
+// NewClient creates a new GitHub API client configured by options. When the
+// settings file restricts which projects may be modified, the client
+// enforces it.
+func NewClient(clientOptions ClientOptions) (Client, error) {
+	options := api.ClientOptions{
+		Headers:   map[string]string{"User-Agent": userAgent(clientOptions.RequestTag)},
+		AuthToken: clientOptions.Token,
+	}
+	var transport http.RoundTripper = http.DefaultTransport
+	if clientOptions.Metrics != nil {
+		transport = &metricsTransport{base: transport, metrics: clientOptions.Metrics}
+	}
+	if clientOptions.Trace != nil {
+		transport = &traceTransport{base: transport, out: clientOptions.Trace}
+	}
+	if transport != http.DefaultTransport {
+		options.Transport = transport
+	}
+	client, err := api.NewRESTClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	settings, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	realClient := &RealClient{client: *client, timeout: clientOptions.RequestTimeout, metrics: clientOptions.Metrics}
+	guard := ProjectGuard{Allowed: settings.AllowedProjects, Denied: settings.DeniedProjects}
+	if guard.Enabled() {
+		return NewGuardedClient(realClient, guard), nil
+	}
+
+	return realClient, nil
+}
+
+// Version is reported in the User-Agent of every API request
+var Version = "dev"
+
+// userAgent identifies the extension and its version to the GitHub API, so
+// that traffic can be attributed in organization audit logs, followed by
+// the tag when one is given
+func userAgent(tag string) string {
+	agent := "gh-project-import/" + Version
+	if tag != "" {
+		agent += " (" + tag + ")"
+	}
+	return agent
+}
+
+// GetUser returns the authenticated user information
+func (gc *RealClient) GetUser(ctx context.Context) (string, error) {
+	response := struct {
+		Login string `json:"login"`
+	}{}
+
+	err := gc.do(ctx, http.MethodGet, "user", nil, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return response.Login, nil
+}
+
+// FindProject finds a project by identifier (owner/project-name,
+// owner/#project-number or project-number)
+func (gc *RealClient) FindProject(ctx context.Context, identifier string) (*Project, error) {
+	// A bare project number belongs to the authenticated user
+	if num, err := strconv.Atoi(identifier); err == nil {
+		login, err := gc.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return gc.findProjectByNumber(ctx, login, num)
+	}
+
+	// Parse owner/project-name format
+	parts := strings.Split(identifier, "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid project identifier format: %s (expected owner/project-name or project-number)", identifier)
+	}
+
+	owner := parts[0]
+	projectName := strings.Join(parts[1:], "/")
+
+	if number, found := strings.CutPrefix(projectName, "#"); found {
+		if num, err := strconv.Atoi(number); err == nil {
+			return gc.findProjectByNumber(ctx, owner, num)
+		}
+	}
+
+	return gc.findProjectByName(ctx, owner, projectName)
+}
+
+// findProjectByNumber finds a project of a user or organization by its number
+func (gc *RealClient) findProjectByNumber(ctx context.Context, owner string, number int) (*Project, error) {
+	isOrg, err := gc.isOrganization(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine if %s is organization: %w", owner, err)
+	}
+	ownerType := "user"
+	if isOrg {
+		ownerType = "organization"
+	}
+
+	query := fmt.Sprintf(`
+		query($login: String!, $number: Int!) {
+			%s(login: $login) {
+				projectV2(number: $number) {
+					id
+					number
+					title
+					url
+					closed
+				}
+			}
+		}
+	`, ownerType)
+
+	variables := map[string]interface{}{
+		"login":  owner,
+		"number": number,
+	}
+
+	return gc.executeGraphQLQuery(ctx, query, variables, func(data map[string]interface{}) (*Project, error) {
+		ownerData, _ := data[ownerType].(map[string]interface{})
+		projectData, ok := ownerData["projectV2"].(map[string]interface{})
+		if !ok || projectData == nil {
+			return nil, fmt.Errorf("project %s/#%d not found", owner, number)
+		}
+
+		closed, _ := projectData["closed"].(bool)
+		return &Project{
+			ID:     GetString(projectData, "id"),
+			Number: GetInt(projectData, "number"),
+			Title:  GetString(projectData, "title"),
+			URL:    GetString(projectData, "url"),
+			Closed: closed,
+		}, nil
+	})
+}
+
+// findProjectByName finds a project by owner and title, open or closed. See
+// MatchProject for how titles are matched; a match that differs by more than
+// case is only a guess, so it is named in the error rather than used.
+func (gc *RealClient) findProjectByName(ctx context.Context, owner, name string) (*Project, error) {
+	projects, err := gc.ListProjects(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	project, err := MatchProject(projects, owner, name, true)
+	if err == nil && !strings.EqualFold(project.Title, name) {
+		return nil, fmt.Errorf("project %s/%s not found; the closest match is #%d %q, use %s/#%d to pick it", owner, name, project.Number, project.Title, owner, project.Number)
+	}
+	return project, err
+}
+
+// ListProjects lists the projects of a user or organization, closed ones included
+func (gc *RealClient) ListProjects(ctx context.Context, owner string) ([]Project, error) {
+	// First, determine if owner is an organization or user
+	isOrg, err := gc.isOrganization(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine if %s is organization: %w", owner, err)
+	}
+	ownerType := "user"
+	if isOrg {
+		ownerType = "organization"
+	}
+
+	query := fmt.Sprintf(`
+		query($login: String!, $cursor: String) {
+			%s(login: $login) {
+				projectsV2(first: 100, after: $cursor) {
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+					nodes {
+						id
+						number
+						title
+						url
+						closed
+					}
+				}
+			}
+		}
+	`, ownerType)
+
+	var projects []Project
+	var cursor interface{}
+
+	for {
+		variables := map[string]interface{}{
+			"login":  owner,
+			"cursor": cursor,
+		}
+
+		data, err := gc.executeGraphQLRaw(ctx, query, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects of %s: %w", owner, err)
+		}
+
+		ownerData, _ := data[ownerType].(map[string]interface{})
+		projectsData, _ := ownerData["projectsV2"].(map[string]interface{})
+		if projectsData == nil {
+			return nil, fmt.Errorf("owner %s not found", owner)
+		}
+
+		nodes, _ := projectsData["nodes"].([]interface{})
+		for _, node := range nodes {
+			if nodeMap, ok := node.(map[string]interface{}); ok {
+				closed, _ := nodeMap["closed"].(bool)
+				projects = append(projects, Project{
+					ID:     GetString(nodeMap, "id"),
+					Number: GetInt(nodeMap, "number"),
+					Title:  GetString(nodeMap, "title"),
+					URL:    GetString(nodeMap, "url"),
+					Closed: closed,
+				})
+			}
+		}
+
+		pageInfo, _ := projectsData["pageInfo"].(map[string]interface{})
+		if hasNext, _ := pageInfo["hasNextPage"].(bool); !hasNext {
+			break
+		}
+		cursor = GetString(pageInfo, "endCursor")
+	}
+
+	return projects, nil
+}
+
+// isOrganization checks if the given login is an organization
+func (gc *RealClient) isOrganization(ctx context.Context, login string) (bool, error) {
+	response := struct {
+		Type string `json:"type"`
+	}{}
+
+	err := gc.do(ctx, http.MethodGet, "users/"+url.PathEscape(login), nil, &response)
+	if err != nil {
+		return false, err
+	}
+
+	return response.Type == "Organization", nil
+}
+
+// GetProjectFields retrieves the field schema for a project
+func (gc *RealClient) GetProjectFields(ctx context.Context, projectID string) ([]ProjectField, error) {
+	query := `
+		query($projectId: ID!) {
+			node(id: $projectId) {
+				... on ProjectV2 {
+					fields(first: 100) {
+						nodes {
+							... on ProjectV2Field {
+								id
+								name
+								dataType
+							}
+							... on ProjectV2SingleSelectField {
+								id
+								name
+								dataType
+								options {
+									id
+									name
+								}
+							}
+							... on ProjectV2IterationField {
+								id
+								name
+								dataType
+								configuration {
+									iterations {
+										id
+										title
+										startDate
+										duration
+									}
+									completedIterations {
+										id
+										title
+										startDate
+										duration
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	var response struct {
+		Data struct {
+			Node struct {
+				Fields struct {
+					Nodes []json.RawMessage `json:"nodes"`
+				} `json:"fields"`
+			} `json:"node"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	err := gc.postGraphQL(ctx, query, map[string]interface{}{"projectId": projectID}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		if err := permissionError(response.Errors[0].Message); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	var fields []ProjectField
+	for _, node := range response.Data.Node.Fields.Nodes {
+		var field ProjectField
+		if err := json.Unmarshal(node, &field); err != nil {
+			continue // Skip fields we can't parse
+		}
+		
+		// Handle iteration fields specially to extract iterations from configuration
+		if field.Type == "ITERATION" {
+			// Parse node as map to access configuration
+			var nodeMap map[string]interface{}
+			if err := json.Unmarshal(node, &nodeMap); err == nil {
+				if config, ok := nodeMap["configuration"].(map[string]interface{}); ok {
+					field.Iterations = parseIterationConfiguration(config)
+				}
+			}
+		}
+		
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// CreateProjectField adds a field to a project. dataType is the field's type
+// as GetProjectFields reports it, e.g. TEXT; single-select fields, which need
+// options, aren't supported.
+func (gc *RealClient) CreateProjectField(ctx context.Context, projectID, name, dataType string) (*ProjectField, error) {
+	mutation := `
+		mutation($projectId: ID!, $name: String!, $dataType: ProjectV2CustomFieldType!) {
+			createProjectV2Field(input: {projectId: $projectId, name: $name, dataType: $dataType}) {
+				projectV2Field {
+					... on ProjectV2Field {
+						id
+						name
+						dataType
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"name":      name,
+		"dataType":  dataType,
+	}
+
+	data, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create field %s: %w", name, err)
+	}
+
+	if createData, ok := data["createProjectV2Field"].(map[string]interface{}); ok {
+		if fieldData, ok := createData["projectV2Field"].(map[string]interface{}); ok {
+			return &ProjectField{
+				ID:   GetString(fieldData, "id"),
+				Name: GetString(fieldData, "name"),
+				Type: GetString(fieldData, "dataType"),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected response format")
+}
+
+// parseIterationConfiguration extracts the iterations of an iteration field's
+// configuration. Completed iterations are listed separately by the API but can
+// still be assigned, so they are included after the active and upcoming ones.
+func parseIterationConfiguration(config map[string]interface{}) []IterationOption {
+	var result []IterationOption
+	for _, key := range []string{"iterations", "completedIterations"} {
+		iterations, _ := config[key].([]interface{})
+		for _, iter := range iterations {
+			if iterMap, ok := iter.(map[string]interface{}); ok {
+				result = append(result, IterationOption{
+					ID:        GetString(iterMap, "id"),
+					Title:     GetString(iterMap, "title"),
+					StartDate: GetString(iterMap, "startDate"),
+					Duration:  GetInt(iterMap, "duration"),
+					Completed: key == "completedIterations",
+				})
+			}
+		}
+	}
+	return result
+}
+
+// CreateProjectItem creates a new item in the specified project
+func (gc *RealClient) CreateProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	mutation := `
+		mutation($projectId: ID!, $contentId: ID!) {
+			addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
+				item {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"contentId": contentID,
+	}
+
+	data, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to create project item: %w", err)
+	}
+
+	if addData, ok := data["addProjectV2ItemById"].(map[string]interface{}); ok {
+		if itemData, ok := addData["item"].(map[string]interface{}); ok {
+			return GetString(itemData, "id"), nil
+		}
+	}
+
+	return "", fmt.Errorf("unexpected response format")
+}
+
+// CreateDraftIssue creates a draft issue and returns its ID
+func (gc *RealClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	mutation := `
+		mutation($projectId: ID!, $title: String!, $body: String) {
+			addProjectV2DraftIssue(input: {projectId: $projectId, title: $title, body: $body}) {
+				projectItem {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"title":     title,
+		"body":      body,
+	}
+
+	data, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to create draft issue: %w", err)
+	}
+
+	if addData, ok := data["addProjectV2DraftIssue"].(map[string]interface{}); ok {
+		if itemData, ok := addData["projectItem"].(map[string]interface{}); ok {
+			return GetString(itemData, "id"), nil
+		}
+	}
+
+	return "", fmt.Errorf("unexpected response format")
+}
+
+// UpdateDraftIssue changes the title and body of a draft issue. draftIssueID
+// is the ID of the draft's content, as ListProjectItems reports it, not of
+// its project item; projectID is the project the draft is in.
+func (gc *RealClient) UpdateDraftIssue(ctx context.Context, projectID, draftIssueID, title, body string) error {
+	mutation := `
+		mutation($draftIssueId: ID!, $title: String!, $body: String) {
+			updateProjectV2DraftIssue(input: {draftIssueId: $draftIssueId, title: $title, body: $body}) {
+				draftIssue {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"draftIssueId": draftIssueID,
+		"title":        title,
+		"body":         body,
+	}
+
+	if _, err := gc.executeGraphQLMutation(ctx, mutation, variables); err != nil {
+		return fmt.Errorf("failed to update draft issue: %w", err)
+	}
+	return nil
+}
+
+// SetProjectItemFieldValue sets a field value for a project item
+func (gc *RealClient) SetProjectItemFieldValue(ctx context.Context, projectID, itemID, fieldID string, value interface{}) error {
+	mutation := `
+		mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: ProjectV2FieldValue!) {
+			updateProjectV2ItemFieldValue(input: {
+				projectId: $projectId, 
+				itemId: $itemId, 
+				fieldId: $fieldId, 
+				value: $value
+			}) {
+				projectV2Item {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"value":     value,
+	}
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to set field value: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProjectItem deletes an item from a project
+func (gc *RealClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	mutation := `
+		mutation($projectId: ID!, $itemId: ID!) {
+			deleteProjectV2Item(input: {
+				projectId: $projectId,
+				itemId: $itemId
+			}) {
+				deletedItemId
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+	}
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to delete project item: %w", err)
+	}
+
+	return nil
+}
+
+// ArchiveProjectItem archives an item, hiding it from the project's views
+func (gc *RealClient) ArchiveProjectItem(ctx context.Context, projectID, itemID string) error {
+	mutation := `
+		mutation($projectId: ID!, $itemId: ID!) {
+			archiveProjectV2Item(input: {
+				projectId: $projectId,
+				itemId: $itemId
+			}) {
+				item { id }
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+	}
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to archive project item: %w", err)
+	}
+
+	return nil
+}
+
+// SetProjectItemPosition moves an item to just after afterID, or to the top
+// of the project when afterID is empty
+func (gc *RealClient) SetProjectItemPosition(ctx context.Context, projectID, itemID, afterID string) error {
+	mutation := `
+		mutation($projectId: ID!, $itemId: ID!, $afterId: ID) {
+			updateProjectV2ItemPosition(input: {
+				projectId: $projectId,
+				itemId: $itemId,
+				afterId: $afterId
+			}) {
+				clientMutationId
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"afterId":   nil,
+	}
+	if afterID != "" {
+		variables["afterId"] = afterID
+	}
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to set project item position: %w", err)
+	}
+
+	return nil
+}
+
+// CopyProject creates a copy of a project under the same owner. The copy has
+// the project's fields and views but none of its items.
+func (gc *RealClient) CopyProject(ctx context.Context, projectID, title string) (*Project, error) {
+	query := `
+		query($projectId: ID!) {
+			node(id: $projectId) {
+				... on ProjectV2 {
+					owner { id }
+				}
+			}
+		}
+	`
+
+	data, err := gc.executeGraphQLRaw(ctx, query, map[string]interface{}{"projectId": projectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up project owner: %w", err)
+	}
+	nodeData, _ := data["node"].(map[string]interface{})
+	ownerData, _ := nodeData["owner"].(map[string]interface{})
+	ownerID := GetString(ownerData, "id")
+	if ownerID == "" {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+
+	mutation := `
+		mutation($projectId: ID!, $ownerId: ID!, $title: String!) {
+			copyProjectV2(input: {
+				projectId: $projectId,
+				ownerId: $ownerId,
+				title: $title,
+				includeDraftIssues: false
+			}) {
+				projectV2 {
+					id
+					number
+					title
+					url
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"projectId": projectID,
+		"ownerId":   ownerID,
+		"title":     title,
+	}
+
+	data, err = gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy project: %w", err)
+	}
+
+	copyData, _ := data["copyProjectV2"].(map[string]interface{})
+	projectData, ok := copyData["projectV2"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+
+	return &Project{
+		ID:     GetString(projectData, "id"),
+		Number: GetInt(projectData, "number"),
+		Title:  GetString(projectData, "title"),
+		URL:    GetString(projectData, "url"),
+	}, nil
+}
+
+// DeleteProject permanently deletes a project and its items
+func (gc *RealClient) DeleteProject(ctx context.Context, projectID string) error {
+	mutation := `
+		mutation($projectId: ID!) {
+			deleteProjectV2(input: {projectId: $projectId}) {
+				projectV2 {
+					id
+				}
+			}
+		}
+	`
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, map[string]interface{}{"projectId": projectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	return nil
+}
+
+// ReopenProject reopens a closed project
+func (gc *RealClient) ReopenProject(ctx context.Context, projectID string) error {
+	mutation := `
+		mutation($projectId: ID!) {
+			updateProjectV2(input: {projectId: $projectId, closed: false}) {
+				projectV2 {
+					id
+					closed
+				}
+			}
+		}
+	`
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, map[string]interface{}{"projectId": projectID})
+	if err != nil {
+		return fmt.Errorf("failed to reopen project: %w", err)
+	}
+
+	return nil
+}
+
+// GetClassicProjectColumns retrieves the columns and non-archived cards of a
+// repository's classic project board, in board order
+func (gc *RealClient) GetClassicProjectColumns(ctx context.Context, owner, repo string, number int) ([]ClassicProjectColumn, error) {
+	var projects []struct {
+		ID     int `json:"id"`
+		Number int `json:"number"`
+	}
+	projectID := 0
+	for page := 1; projectID == 0; page++ {
+		projects = nil
+		err := gc.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/projects?state=all&per_page=100&page=%d", owner, repo, page), nil, &projects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list classic projects for %s/%s: %w", owner, repo, err)
+		}
+		for _, project := range projects {
+			if project.Number == number {
+				projectID = project.ID
+			}
+		}
+		if len(projects) < 100 {
+			break
+		}
+	}
+	if projectID == 0 {
+		return nil, fmt.Errorf("classic project %d not found in %s/%s", number, owner, repo)
+	}
+
+	var rawColumns []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	err := gc.do(ctx, http.MethodGet, fmt.Sprintf("projects/%d/columns?per_page=100", projectID), nil, &rawColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get classic project columns: %w", err)
+	}
+
+	var columns []ClassicProjectColumn
+	for _, rawColumn := range rawColumns {
+		column := ClassicProjectColumn{Name: rawColumn.Name}
+		for page := 1; ; page++ {
+			var cards []struct {
+				Note       *string `json:"note"`
+				ContentURL string  `json:"content_url"`
+			}
+			err := gc.do(ctx, http.MethodGet, fmt.Sprintf("projects/columns/%d/cards?archived_state=not_archived&per_page=100&page=%d", rawColumn.ID, page), nil, &cards)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get cards for column %s: %w", rawColumn.Name, err)
+			}
+			for _, card := range cards {
+				c := ClassicProjectCard{ContentURL: card.ContentURL}
+				if card.Note != nil {
+					c.Note = *card.Note
+				}
+				column.Cards = append(column.Cards, c)
+			}
+			if len(cards) < 100 {
+				break
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// SearchIssues runs an issue and pull request search, e.g. "repo:acme/api
+// is:issue label:triage", and returns every match. Queries matching more
+// than the search API can return fail rather than import a partial list.
+func (gc *RealClient) SearchIssues(ctx context.Context, query string) ([]SearchResult, error) {
+	var results []SearchResult
+	for page := 1; ; page++ {
+		var response struct {
+			TotalCount int `json:"total_count"`
+			Items      []struct {
+				Title         string          `json:"title"`
+				HTMLURL       string          `json:"html_url"`
+				RepositoryURL string          `json:"repository_url"`
+				PullRequest   json.RawMessage `json:"pull_request"`
+			} `json:"items"`
+		}
+		err := gc.do(ctx, http.MethodGet, fmt.Sprintf("search/issues?q=%s&per_page=100&page=%d", url.QueryEscape(query), page), nil, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %q: %w", query, err)
+		}
+		if response.TotalCount > maxSearchResults {
+			return nil, fmt.Errorf("search %q matches %d issues and pull requests, more than the %d a search can return; narrow the query", query, response.TotalCount, maxSearchResults)
+		}
+
+		for _, item := range response.Items {
+			result := SearchResult{
+				Title:      item.Title,
+				URL:        item.HTMLURL,
+				Repository: strings.TrimPrefix(item.RepositoryURL, "https://api.github.com/repos/"),
+				Type:       "Issue",
+			}
+			if len(item.PullRequest) > 0 && string(item.PullRequest) != "null" {
+				result.Type = "PullRequest"
+			}
+			results = append(results, result)
+		}
+		if len(response.Items) < 100 || len(results) >= response.TotalCount {
+			return results, nil
+		}
+	}
+}
+
+// ListProjectItems retrieves every item in a project along with its field
+// values, keyed by field name. Item content includes a "type" key (DraftIssue,
+// Issue or PullRequest) alongside the node "id", title, body, url and
+// assignees. The ID of a draft issue is the one UpdateDraftIssue takes.
+// UpdatedAt is when the item or its field values last changed.
+func (gc *RealClient) ListProjectItems(ctx context.Context, projectID string) ([]ProjectItem, error) {
+	query := `
+		query($projectId: ID!, $cursor: String) {
+			node(id: $projectId) {
+				... on ProjectV2 {
+					items(first: 100, after: $cursor) {
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+						nodes {
+							id
+							type
+							isArchived
+							updatedAt
+							content {
+								... on DraftIssue {
+									id
+									title
+									body
+								}
+								... on Issue {
+									id
+									title
+									body
+									url
+									number
+									repository { nameWithOwner }
+									assignees(first: 20) { nodes { login } }
+								}
+								... on PullRequest {
+									id
+									title
+									body
+									url
+									number
+									repository { nameWithOwner }
+									assignees(first: 20) { nodes { login } }
+								}
+							}
+							fieldValues(first: 50) {
+								nodes {
+									... on ProjectV2ItemFieldTextValue {
+										text
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+									... on ProjectV2ItemFieldNumberValue {
+										number
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+									... on ProjectV2ItemFieldDateValue {
+										date
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+									... on ProjectV2ItemFieldSingleSelectValue {
+										name
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+									... on ProjectV2ItemFieldIterationValue {
+										title
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+									... on ProjectV2ItemFieldUserValue {
+										users(first: 20) { nodes { login } }
+										field { ... on ProjectV2FieldCommon { name } }
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	var items []ProjectItem
+	var cursor interface{}
+
+	for {
+		variables := map[string]interface{}{
+			"projectId": projectID,
+			"cursor":    cursor,
+		}
+
+		data, err := gc.executeGraphQLRaw(ctx, query, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list project items: %w", err)
+		}
+
+		nodeData, _ := data["node"].(map[string]interface{})
+		itemsData, _ := nodeData["items"].(map[string]interface{})
+		if itemsData == nil {
+			return nil, fmt.Errorf("project %s not found", projectID)
+		}
+
+		nodes, _ := itemsData["nodes"].([]interface{})
+		for _, node := range nodes {
+			if nodeMap, ok := node.(map[string]interface{}); ok {
+				items = append(items, parseProjectItemNode(nodeMap))
+			}
+		}
+
+		pageInfo, _ := itemsData["pageInfo"].(map[string]interface{})
+		if hasNext, _ := pageInfo["hasNextPage"].(bool); !hasNext {
+			break
+		}
+		cursor = GetString(pageInfo, "endCursor")
+	}
+
+	return items, nil
+}
+
+// parseProjectItemNode converts a project item node from GraphQL into a ProjectItem
+func parseProjectItemNode(node map[string]interface{}) ProjectItem {
+	item := ProjectItem{
+		ID:      GetString(node, "id"),
+		Content: map[string]interface{}{"type": GetString(node, "type")},
+		Fields:  make(map[string]interface{}),
+	}
+	item.Archived, _ = node["isArchived"].(bool)
+	if updatedAt, err := time.Parse(time.RFC3339, GetString(node, "updatedAt")); err == nil {
+		item.UpdatedAt = updatedAt
+	}
+
+	// The item type is reported in GraphQL enum form (DRAFT_ISSUE, PULL_REQUEST)
+	switch item.Content["type"] {
+	case "DRAFT_ISSUE":
+		item.Content["type"] = "DraftIssue"
+	case "ISSUE":
+		item.Content["type"] = "Issue"
+	case "PULL_REQUEST":
+		item.Content["type"] = "PullRequest"
+	}
+
+	if content, ok := node["content"].(map[string]interface{}); ok {
+		for _, key := range []string{"id", "title", "body", "url"} {
+			if value := GetString(content, key); value != "" {
+				item.Content[key] = value
+			}
+		}
+		if number := GetInt(content, "number"); number != 0 {
+			item.Content["number"] = number
+		}
+		if repo, ok := content["repository"].(map[string]interface{}); ok {
+			item.Content["repository"] = GetString(repo, "nameWithOwner")
+		}
+		if logins := getLogins(content, "assignees"); len(logins) > 0 {
+			item.Content["assignees"] = logins
+		}
+	}
+
+	fieldValues, _ := node["fieldValues"].(map[string]interface{})
+	values, _ := fieldValues["nodes"].([]interface{})
+	for _, value := range values {
+		valueMap, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field, _ := valueMap["field"].(map[string]interface{})
+		name := GetString(field, "name")
+		if name == "" {
+			continue // Value of a field type we don't query
+		}
+
+		switch {
+		case valueMap["text"] != nil:
+			item.Fields[name] = valueMap["text"]
+		case valueMap["number"] != nil:
+			item.Fields[name] = valueMap["number"]
+		case valueMap["date"] != nil:
+			item.Fields[name] = valueMap["date"]
+		case valueMap["name"] != nil:
+			item.Fields[name] = valueMap["name"]
+		case valueMap["title"] != nil:
+			item.Fields[name] = valueMap["title"]
+		case valueMap["users"] != nil:
+			item.Fields[name] = getLogins(valueMap, "users")
+		}
+	}
+
+	return item
+}
+
+// getLogins extracts the logins from a user connection ({nodes: [{login}]})
+func getLogins(m map[string]interface{}, key string) []string {
+	var logins []string
+	connection, _ := m[key].(map[string]interface{})
+	nodes, _ := connection["nodes"].([]interface{})
+	for _, node := range nodes {
+		if nodeMap, ok := node.(map[string]interface{}); ok {
+			if login := GetString(nodeMap, "login"); login != "" {
+				logins = append(logins, login)
+			}
+		}
+	}
+	return logins
+}
+
+// ParseRepositoryURL extracts owner and repository name from GitHub URL
+func ParseRepositoryURL(url string) (string, string, error) {
+	// Regular expression to match GitHub URLs
+	re := regexp.MustCompile(`github\.com/([^/]+)/([^/]+)`)
+	matches := re.FindStringSubmatch(url)
+
+	if len(matches) < 3 {
+		return "", "", fmt.Errorf("invalid GitHub URL format: %s", url)
+	}
+
+	return matches[1], matches[2], nil
+}
+
+// contentNumberPattern extracts the issue or PR number from a GitHub URL
+var contentNumberPattern = regexp.MustCompile(`/(?:issues|pull)/(\d+)`)
+
+// ParseContentNumber extracts the issue or PR number from a GitHub URL
+func ParseContentNumber(url string) (int, bool) {
+	match := contentNumberPattern.FindStringSubmatch(url)
+	if match == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(match[1])
+	return number, err == nil
+}
+
+// ErrContentNotFound is returned when an issue or PR URL can't be resolved,
+// e.g. because the repository was deleted or is not visible to the token
+var ErrContentNotFound = errors.New("issue or pull request not found")
+
+// GetIssueOrPR retrieves issue or PR information by URL with a single
+// resource(url:) lookup, which works for issues and pull requests alike
+func (gc *RealClient) GetIssueOrPR(ctx context.Context, url string) (map[string]interface{}, error) {
+	if _, _, err := ParseRepositoryURL(url); err != nil {
+		return nil, err
+	}
+	if _, ok := ParseContentNumber(url); !ok {
+		return nil, fmt.Errorf("could not extract issue/PR number from URL: %s", url)
+	}
+
+	contents, err := gc.GetIssuesOrPRs(ctx, []string{url})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue/PR %s: %w", url, err)
+	}
+	content, found := contents[url]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrContentNotFound, url)
+	}
+	return content, nil
+}
+
+// contentBatchSize is the number of URLs GetIssuesOrPRs resolves per query
+const contentBatchSize = 50
+
+// GetIssuesOrPRs looks up the issues and pull requests at urls with one
+// GraphQL query per contentBatchSize URLs, each URL an aliased resource(url:)
+// field. It returns the content of each URL found the way GetIssueOrPR does,
+// with its node_id and html_url, which differs from the URL when the issue
+// has moved. URLs that aren't issues or pull requests are left out.
+func (gc *RealClient) GetIssuesOrPRs(ctx context.Context, urls []string) (map[string]map[string]interface{}, error) {
+	contents := make(map[string]map[string]interface{}, len(urls))
+
+	for start := 0; start < len(urls); start += contentBatchSize {
+		batch := urls[start:min(start+contentBatchSize, len(urls))]
+
+		var params, fields strings.Builder
+		variables := make(map[string]interface{}, len(batch))
+		for i, url := range batch {
+			if i > 0 {
+				params.WriteString(", ")
+			}
+			fmt.Fprintf(&params, "$url%d: URI!", i)
+			fmt.Fprintf(&fields, " r%d: resource(url: $url%d) { __typename ... on Issue { id url } ... on PullRequest { id url } }", i, i)
+			variables[fmt.Sprintf("url%d", i)] = url
+		}
+		query := fmt.Sprintf("query GetIssuesOrPRs(%s) {%s }", params.String(), fields.String())
+
+		data, err := gc.executeGraphQLRaw(ctx, query, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up issues and pull requests: %w", err)
+		}
+
+		for i, url := range batch {
+			node, _ := data[fmt.Sprintf("r%d", i)].(map[string]interface{})
+			switch GetString(node, "__typename") {
+			case "Issue", "PullRequest":
+				contents[url] = map[string]interface{}{"node_id": GetString(node, "id"), "html_url": GetString(node, "url")}
+			}
+		}
+	}
+
+	return contents, nil
+}
+
+// do sends a REST API request
+func (gc *RealClient) do(ctx context.Context, method, path string, body io.Reader, response interface{}) error {
+	// Query strings are left out of the operation name
+	operation, _, _ := strings.Cut(path, "?")
+	return gc.send(ctx, method+" "+operation, method, path, body, response)
+}
+
+// send sends an API request, giving up once ctx is done or the client's
+// timeout has passed, and records it in the client's metrics under the
+// operation name. Every request of the client goes through it.
+func (gc *RealClient) send(ctx context.Context, operation, method, path string, body io.Reader, response interface{}) error {
+	if gc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gc.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := gc.client.DoWithContext(ctx, method, path, body, response)
+	if gc.metrics != nil {
+		gc.metrics.recordRequest(Operation{Name: operation, Duration: time.Since(start)}, path == "graphql")
+	}
+	return err
+}
+
+// graphQLRequest is the body of a GraphQL API request. Values such as logins,
+// project names and IDs are always passed as variables and never written into
+// the query, so quotes or braces in them can't break or alter the query.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// postGraphQL sends a query or mutation with its variables and decodes the
+// response into response. Every GraphQL request of the client goes through it.
+func (gc *RealClient) postGraphQL(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	logGraphQLRequest(query, variables)
+
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	kind, name := graphQLOperation(query)
+	return gc.send(ctx, kind+" "+name, http.MethodPost, "graphql", bytes.NewReader(body), response)
+}
+
+// executeGraphQLQuery executes a GraphQL query and processes the response
+func (gc *RealClient) executeGraphQLQuery(ctx context.Context, query string, variables map[string]interface{}, processor func(map[string]interface{}) (*Project, error)) (*Project, error) {
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	err := gc.postGraphQL(ctx, query, variables, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute GraphQL query: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		errMsg := response.Errors[0].Message
+		if err := permissionError(errMsg); err != nil {
+			return nil, err
+		}
+		// Provide more helpful error messages for common issues
+		if strings.Contains(errMsg, "rate limit") {
+			return nil, fmt.Errorf("GitHub API rate limit exceeded. Please wait and try again later")
+		}
+		if strings.Contains(errMsg, "not found") {
+			return nil, fmt.Errorf("resource not found or insufficient permissions: %s", errMsg)
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", errMsg)
+	}
+
+	return processor(response.Data)
+}
+
+// executeGraphQLMutation executes a GraphQL mutation
+func (gc *RealClient) executeGraphQLMutation(ctx context.Context, mutation string, variables map[string]interface{}) (map[string]interface{}, error) {
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	err := gc.postGraphQL(ctx, mutation, variables, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute GraphQL mutation: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		errMsg := response.Errors[0].Message
+		if err := permissionError(errMsg); err != nil {
+			return nil, err
+		}
+		// Provide more helpful error messages for common issues
+		if strings.Contains(errMsg, "rate limit") {
+			return nil, fmt.Errorf("GitHub API rate limit exceeded. Please wait and try again later")
+		}
+		if strings.Contains(errMsg, "not found") {
+			return nil, fmt.Errorf("resource not found or insufficient permissions: %s", errMsg)
+		}
+		if strings.Contains(errMsg, "already exists") {
+			return nil, fmt.Errorf("item already exists in project: %s", errMsg)
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", errMsg)
+	}
+
+	return response.Data, nil
+}
+
+// GetString safely extracts a string value from a decoded JSON object
+func GetString(m map[string]interface{}, key string) string {
+	if val, ok := m[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+// GetInt safely extracts an integer value from a decoded JSON object
+func GetInt(m map[string]interface{}, key string) int {
+	if val, ok := m[key]; ok {
+		if num, ok := val.(float64); ok {
+			return int(num)
+		}
+	}
+	return 0
+}
+
+// ErrUserNotFound is returned when a login doesn't belong to any GitHub user
+var ErrUserNotFound = errors.New("user not found")
+
+// GetUserID returns the node ID of the user with the given login
+func (gc *RealClient) GetUserID(ctx context.Context, login string) (string, error) {
+	var response map[string]interface{}
+	err := gc.do(ctx, http.MethodGet, "users/"+url.PathEscape(login), nil, &response)
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, login)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user %s: %w", login, err)
+	}
+
+	id := GetString(response, "node_id")
+	if id == "" {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, login)
+	}
+	return id, nil
+}
+
+// Milestone is a repository milestone
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+}
+
+// ListMilestones returns the open and closed milestones of a repository
+func (gc *RealClient) ListMilestones(ctx context.Context, owner, repo string) ([]Milestone, error) {
+	var milestones []Milestone
+	for page := 1; ; page++ {
+		var batch []Milestone
+		err := gc.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/milestones?state=all&per_page=100&page=%d", owner, repo, page), nil, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list milestones for %s/%s: %w", owner, repo, err)
+		}
+		milestones = append(milestones, batch...)
+		if len(batch) < 100 {
+			return milestones, nil
+		}
+	}
+}
+
+// CreateMilestone creates an open milestone in a repository
+func (gc *RealClient) CreateMilestone(ctx context.Context, owner, repo, title string) (*Milestone, error) {
+	body, err := json.Marshal(map[string]string{"title": title})
+	if err != nil {
+		return nil, err
+	}
+
+	var milestone Milestone
+	err = gc.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/milestones", owner, repo), bytes.NewReader(body), &milestone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create milestone %q in %s/%s: %w", title, owner, repo, err)
+	}
+	return &milestone, nil
+}
+
+// SetIssueMilestone sets the milestone of an issue or pull request, given
+// their number and the milestone's number
+func (gc *RealClient) SetIssueMilestone(ctx context.Context, owner, repo string, number, milestone int) error {
+	body, err := json.Marshal(map[string]int{"milestone": milestone})
+	if err != nil {
+		return err
+	}
+
+	err = gc.do(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, number), bytes.NewReader(body), nil)
+	if err != nil {
+		return fmt.Errorf("failed to set milestone of %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+// GetIssueBody returns the body of an issue or pull request, given their number
+func (gc *RealClient) GetIssueBody(ctx context.Context, owner, repo string, number int) (string, error) {
+	var response struct {
+		Body string `json:"body"`
+	}
+	err := gc.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, number), nil, &response)
+	if err != nil {
+		return "", fmt.Errorf("failed to get body of %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return response.Body, nil
+}
+
+// SetIssueBody replaces the body of an issue or pull request, given their number
+func (gc *RealClient) SetIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	err = gc.do(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, number), bytes.NewReader(data), nil)
+	if err != nil {
+		return fmt.Errorf("failed to set body of %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return nil
+}
+
+// AddSubIssue makes an issue a sub-issue of another, given the node IDs of
+// the parent and the sub-issue
+func (gc *RealClient) AddSubIssue(ctx context.Context, issueID, subIssueID string) error {
+	mutation := `
+		mutation($issueId: ID!, $subIssueId: ID!) {
+			addSubIssue(input: {
+				issueId: $issueId,
+				subIssueId: $subIssueId
+			}) {
+				issue {
+					id
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"issueId":    issueID,
+		"subIssueId": subIssueID,
+	}
+
+	_, err := gc.executeGraphQLMutation(ctx, mutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to add sub-issue: %w", err)
+	}
+
+	return nil
+}
+
+// getOrganizationID gets the organization ID for the given organization name
+func (gc *RealClient) getOrganizationID(ctx context.Context, orgName string) (string, error) {
+	query := `
+		query($login: String!) {
+			organization(login: $login) {
+				id
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"login": orgName,
+	}
+
+	data, err := gc.executeGraphQLRaw(ctx, query, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization ID: %w", err)
+	}
+
+	if orgData, ok := data["organization"].(map[string]interface{}); ok {
+		return GetString(orgData, "id"), nil
+	}
+
+	return "", fmt.Errorf("organization not found")
+}
+
+// executeGraphQLRaw executes a GraphQL query and returns raw data
+func (gc *RealClient) executeGraphQLRaw(ctx context.Context, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	err := gc.postGraphQL(ctx, query, variables, &response)
+	if err != nil {
+		return nil, fmt.Errorf("GraphQL request failed: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		errMsg := response.Errors[0].Message
+		if err := permissionError(errMsg); err != nil {
+			return nil, err
+		}
+		if strings.Contains(errMsg, "not found") {
+			return nil, fmt.Errorf("resource not found or insufficient permissions: %s", errMsg)
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", errMsg)
+	}
+
+	return response.Data, nil
+}
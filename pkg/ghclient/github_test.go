@@ -0,0 +1,391 @@
+// Tests GitHub API interactions using recorded snapshots
+package ghclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// title of the recording user's test project
+const testProjectTitle = "Import Test Project"
+
+// newSnapshotClient creates a snapshot client that fails the test if any
+// recorded call isn't replayed
+func newSnapshotClient(t *testing.T, testName string) *SnapshotClient {
+	client, err := NewSnapshotClient(testName)
+	if err != nil {
+		t.Fatalf("Failed to create snapshot client: %v", err)
+	}
+	client.RequireAllUsed = true
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return client
+}
+
+// TestSnapshotGetUser tests the GetUser API call with snapshots
+func TestSnapshotGetUser(t *testing.T) {
+	client := newSnapshotClient(t, "GetUser")
+
+	user, err := client.GetUser(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+
+	if user != client.Login() {
+		t.Errorf("Expected user %s, got %s", client.Login(), user)
+	}
+
+	t.Logf("User: %s", user)
+}
+
+// TestSnapshotFindProject tests the FindProject API call with snapshots
+func TestSnapshotFindProject(t *testing.T) {
+	client := newSnapshotClient(t, "FindProject")
+
+	// Test finding project by name
+	project, err := client.FindProject(context.Background(), fmt.Sprintf("%s/%s", client.Login(), testProjectTitle))
+	if err != nil {
+		t.Fatalf("Failed to find project: %v", err)
+	}
+	t.Logf("Found project by name: %s", project.Title)
+
+	/*
+		 TODO: Find by number is not working correctly in the base github client
+			// Test finding project by number
+			project, err = client.FindProject(context.Background(), fmt.Sprintf("%s/%d", client.Login(), project.Number))
+			if err != nil {
+				t.Fatalf("Failed to find project: %v", err)
+			}
+			t.Logf("Found project by number: %s", project.Title)
+	*/
+}
+
+// TestSnapshotEndToEndWorkflow tests a complete workflow with snapshots
+func TestSnapshotEndToEndWorkflow(t *testing.T) {
+	client := newSnapshotClient(t, "EndToEndWorkflow")
+
+	// 1. Find project by name
+	project, err := client.FindProject(context.Background(), fmt.Sprintf("%s/%s", client.Login(), testProjectTitle))
+	if err != nil {
+		t.Fatalf("Failed to find project: %v", err)
+	}
+	t.Logf("Found project by name: %s", project.Title)
+
+	// 3. Get project fields
+	fields, err := client.GetProjectFields(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project fields: %v", err)
+	}
+	t.Logf("Retrieved %d fields", len(fields))
+
+	// 4. Create a draft issue
+	itemID, err := client.CreateDraftIssue(context.Background(), project.ID, "Test Item", "Test description")
+	if err != nil {
+		t.Fatalf("Failed to create draft issue: %v", err)
+	}
+	t.Logf("Created draft issue: %s", itemID)
+
+	// 5. Set field values
+	if len(fields) > 0 {
+		field := fields[0]
+		var value interface{}
+
+		switch field.Type {
+		case "SINGLE_SELECT":
+			if len(field.Options) > 0 {
+				value = map[string]interface{}{"singleSelectOptionId": field.Options[0].ID}
+			}
+		case "TEXT":
+			value = map[string]interface{}{"text": "Test value"}
+		case "NUMBER":
+			value = map[string]interface{}{"number": 42}
+		}
+
+		if value != nil {
+			err = client.SetProjectItemFieldValue(context.Background(), project.ID, itemID, field.ID, value)
+			if err != nil {
+				t.Fatalf("Failed to set field value: %v", err)
+			}
+			t.Logf("Set field %s to %v", field.Name, value)
+		}
+	}
+
+	//cleanup: delete the created item
+	err = client.DeleteProjectItem(context.Background(), project.ID, itemID)
+	if err != nil {
+		t.Fatalf("Failed to delete project item: %v", err)
+	}
+
+	t.Log("Successfully deleted project item")
+}
+
+// TestParseProjectItemNode tests conversion of a GraphQL project item node
+func TestParseProjectItemNode(t *testing.T) {
+	node := map[string]interface{}{
+		"id":   "PVTI_1",
+		"type": "ISSUE",
+		"content": map[string]interface{}{
+			"title":      "Bug",
+			"url":        "https://github.com/owner/repo/issues/1",
+			"number":     float64(1),
+			"repository": map[string]interface{}{"nameWithOwner": "owner/repo"},
+			"assignees":  map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"login": "octocat"}}},
+		},
+		"fieldValues": map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{"name": "Todo", "field": map[string]interface{}{"name": "Status"}},
+				map[string]interface{}{"number": float64(3), "field": map[string]interface{}{"name": "Estimate"}},
+				map[string]interface{}{"title": "Sprint 1", "field": map[string]interface{}{"name": "Sprint"}},
+				map[string]interface{}{}, // value of an unqueried field type
+			},
+		},
+	}
+
+	item := parseProjectItemNode(node)
+
+	if item.ID != "PVTI_1" || item.Content["type"] != "Issue" || item.Content["repository"] != "owner/repo" {
+		t.Errorf("Unexpected item content: %+v", item)
+	}
+	if assignees, ok := item.Content["assignees"].([]string); !ok || len(assignees) != 1 || assignees[0] != "octocat" {
+		t.Errorf("Expected assignee octocat, got %v", assignees)
+	}
+	if item.Fields["Status"] != "Todo" || item.Fields["Estimate"] != float64(3) || item.Fields["Sprint"] != "Sprint 1" {
+		t.Errorf("Unexpected field values: %v", item.Fields)
+	}
+	if len(item.Fields) != 3 {
+		t.Errorf("Expected 3 field values, got %d", len(item.Fields))
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if got := userAgent(""); got != "gh-project-import/"+Version {
+		t.Errorf("Unexpected User-Agent %q", got)
+	}
+
+	if got := userAgent("nightly-sync"); got != "gh-project-import/"+Version+" (nightly-sync)" {
+		t.Errorf("Unexpected User-Agent %q", got)
+	}
+}
+
+func TestParseIterationConfiguration(t *testing.T) {
+	config := map[string]interface{}{
+		"iterations": []interface{}{
+			map[string]interface{}{"id": "it2", "title": "Sprint 2", "startDate": "2024-01-15", "duration": float64(14)},
+		},
+		"completedIterations": []interface{}{
+			map[string]interface{}{"id": "it1", "title": "Sprint 1", "startDate": "2024-01-01", "duration": float64(14)},
+		},
+	}
+
+	iterations := parseIterationConfiguration(config)
+	if len(iterations) != 2 {
+		t.Fatalf("Expected active and completed iterations, got %+v", iterations)
+	}
+	if iterations[0].ID != "it2" || iterations[0].StartDate != "2024-01-15" || iterations[0].Duration != 14 || iterations[0].Completed {
+		t.Errorf("Unexpected active iteration: %+v", iterations[0])
+	}
+	if iterations[1].ID != "it1" || !iterations[1].Completed {
+		t.Errorf("Expected Sprint 1 to be marked completed, got %+v", iterations[1])
+	}
+}
+
+// recordingTransport answers API requests with canned JSON by path and
+// records the GraphQL requests sent
+type recordingTransport struct {
+	responses map[string]string
+	requests  []graphQLRequest
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		var request graphQLRequest
+		if json.Unmarshal(body, &request) == nil && request.Query != "" {
+			rt.requests = append(rt.requests, request)
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(rt.responses[req.URL.Path])),
+		Request:    req,
+	}, nil
+}
+
+// newRecordingClient creates a RealClient whose requests go to transport
+func newRecordingClient(t *testing.T, transport *recordingTransport) *RealClient {
+	client, err := api.NewRESTClient(api.ClientOptions{Host: "github.com", AuthToken: "test", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return &RealClient{client: *client}
+}
+
+// TestGraphQLVariables checks that names with quotes and braces never end
+// up in queries, and that logins and IDs are sent as variables
+func TestGraphQLVariables(t *testing.T) {
+	name := `Q3 "Launch" {plan} \ roadmap`
+	transport := &recordingTransport{responses: map[string]string{
+		"/users/octo": `{"type": "User"}`,
+		"/graphql":    `{"data": {"user": {"projectsV2": {"nodes": [{"id": "PVT_1", "number": 1, "title": ` + fmt.Sprintf("%q", name) + `}]}}}}`,
+	}}
+	client := newRecordingClient(t, transport)
+
+	project, err := client.FindProject(context.Background(), "octo/"+name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if project.ID != "PVT_1" {
+		t.Errorf("Expected project PVT_1, got %+v", project)
+	}
+
+	transport.responses["/graphql"] = `{"data": {"node": {"fields": {"nodes": []}}}}`
+	if _, err := client.GetProjectFields(context.Background(), `PVT_"1"`); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("Expected 2 GraphQL requests, got %d", len(transport.requests))
+	}
+	find, fields := transport.requests[0], transport.requests[1]
+	if find.Variables["login"] != "octo" || strings.Contains(find.Query, "Launch") || strings.Contains(find.Query, "octo") {
+		t.Errorf("Expected the project name as a variable, got %+v", find)
+	}
+	if fields.Variables["projectId"] != `PVT_"1"` || strings.Contains(fields.Query, "PVT_") {
+		t.Errorf("Expected the project ID as a variable, got %+v", fields)
+	}
+}
+
+// blockingTransport never answers, waiting for the request to be cancelled
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestRequestTimeout(t *testing.T) {
+	client, err := api.NewRESTClient(api.ClientOptions{Host: "github.com", AuthToken: "test", Transport: blockingTransport{}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	realClient := &RealClient{client: *client, timeout: 10 * time.Millisecond}
+	if _, err := realClient.GetUser(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the request to time out, got: %v", err)
+	}
+
+	realClient.timeout = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := realClient.GetUser(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be cancelled, got: %v", err)
+	}
+}
+
+func TestSearchIssues(t *testing.T) {
+	transport := &recordingTransport{responses: map[string]string{
+		"/search/issues": `{"total_count": 2, "items": [
+			{"title": "Crash on start", "html_url": "https://github.com/acme/api/issues/1", "repository_url": "https://api.github.com/repos/acme/api"},
+			{"title": "Fix crash", "html_url": "https://github.com/acme/api/pull/2", "repository_url": "https://api.github.com/repos/acme/api", "pull_request": {"url": "x"}}
+		]}`,
+	}}
+	client := newRecordingClient(t, transport)
+
+	results, err := client.SearchIssues(context.Background(), "repo:acme/api label:triage")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []SearchResult{
+		{Title: "Crash on start", URL: "https://github.com/acme/api/issues/1", Repository: "acme/api", Type: "Issue"},
+		{Title: "Fix crash", URL: "https://github.com/acme/api/pull/2", Repository: "acme/api", Type: "PullRequest"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Expected %+v, got %+v", want, results)
+	}
+
+	transport.responses["/search/issues"] = `{"total_count": 5000, "items": []}`
+	if _, err := client.SearchIssues(context.Background(), "is:issue"); err == nil || !strings.Contains(err.Error(), "narrow the query") {
+		t.Errorf("Expected a query with too many results to fail, got: %v", err)
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	transport := &recordingTransport{responses: map[string]string{
+		"/users/acme": `{"type": "Organization"}`,
+		"/graphql": `{"data": {"organization": {"projectsV2": {"pageInfo": {"hasNextPage": false}, "nodes": [
+			{"id": "PVT_1", "number": 1, "title": "Roadmap 2024", "closed": true},
+			{"id": "PVT_2", "number": 2, "title": "Roadmap 2025"}
+		]}}}}`,
+	}}
+	client := newRecordingClient(t, transport)
+
+	projects, err := client.ListProjects(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []Project{{ID: "PVT_1", Number: 1, Title: "Roadmap 2024", Closed: true}, {ID: "PVT_2", Number: 2, Title: "Roadmap 2025"}}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("Expected %+v, got %+v", want, projects)
+	}
+
+	var ambiguous *AmbiguousProjectError
+	if _, err := client.FindProject(context.Background(), "acme/roadmap"); !errors.As(err, &ambiguous) {
+		t.Errorf("Expected an ambiguous title to fail, got %v", err)
+	}
+	if _, err := client.FindProject(context.Background(), "acme/2025"); err == nil || !strings.Contains(err.Error(), `closest match is #2 "Roadmap 2025", use acme/#2`) {
+		t.Errorf("Expected an approximate title to name the closest match, got %v", err)
+	}
+	project, err := client.FindProject(context.Background(), "acme/ROADMAP 2025")
+	if err != nil || project.ID != "PVT_2" {
+		t.Errorf("Expected a title in another case to find project 2, got %+v (%v)", project, err)
+	}
+}
+
+func TestFindProjectByNumber(t *testing.T) {
+	transport := &recordingTransport{responses: map[string]string{
+		"/users/acme": `{"type": "Organization"}`,
+		"/graphql":    `{"data": {"organization": {"projectV2": {"id": "PVT_1", "number": 1, "title": "Roadmap 2024", "closed": true}}}}`,
+	}}
+	client := newRecordingClient(t, transport)
+
+	project, err := client.FindProject(context.Background(), "acme/#1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := (Project{ID: "PVT_1", Number: 1, Title: "Roadmap 2024", Closed: true}); *project != want {
+		t.Errorf("Expected closed project %+v, got %+v", want, *project)
+	}
+	if find := transport.requests[0]; find.Variables["login"] != "acme" || find.Variables["number"] != float64(1) || !strings.Contains(find.Query, "organization(login: $login)") {
+		t.Errorf("Expected the project to be looked up by number, got %+v", find)
+	}
+
+	// A bare number is a project of the authenticated user
+	transport.responses["/user"] = `{"login": "octo"}`
+	transport.responses["/users/octo"] = `{"type": "User"}`
+	transport.responses["/graphql"] = `{"data": {"user": {"projectV2": {"id": "PVT_7", "number": 7, "title": "Backlog"}}}}`
+	project, err = client.FindProject(context.Background(), "7")
+	if err != nil || project.ID != "PVT_7" {
+		t.Errorf("Expected 7 to find the user's project 7, got %+v (%v)", project, err)
+	}
+	if find := transport.requests[1]; find.Variables["login"] != "octo" || !strings.Contains(find.Query, "user(login: $login)") {
+		t.Errorf("Expected the user's project to be looked up, got %+v", find)
+	}
+
+	transport.responses["/graphql"] = `{"data": {"user": {"projectV2": null}}}`
+	if _, err := client.FindProject(context.Background(), "octo/#9"); err == nil || !strings.Contains(err.Error(), "project octo/#9 not found") {
+		t.Errorf("Expected a missing project to fail, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Project guard rails for shared tokens
+// Restricts mutations to projects matching the configured allow/deny patterns
+package ghclient
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProjectGuard decides which projects may be modified. Patterns are matched
+// case-insensitively against "owner/project-title" and "owner/project-number"
+// using shell glob syntax (e.g. "my-org/Team A *" or "my-org/*"). Denied
+// patterns take precedence; when allowed patterns are set, a project must
+// match one of them.
+type ProjectGuard struct {
+	Allowed []string
+	Denied  []string
+}
+
+// Enabled reports whether any patterns are configured
+func (g ProjectGuard) Enabled() bool {
+	return len(g.Allowed) > 0 || len(g.Denied) > 0
+}
+
+// Check returns an error if the project may not be modified
+func (g ProjectGuard) Check(project *Project) error {
+	names := projectGuardNames(project)
+
+	for _, pattern := range g.Denied {
+		if matchesAnyName(pattern, names) {
+			return fmt.Errorf("project %q is denied by pattern %q in denied_projects", project.Title, pattern)
+		}
+	}
+
+	if len(g.Allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range g.Allowed {
+		if matchesAnyName(pattern, names) {
+			return nil
+		}
+	}
+	return fmt.Errorf("project %q does not match any pattern in allowed_projects", project.Title)
+}
+
+// projectOwnerPattern extracts the owner login from a project URL
+var projectOwnerPattern = regexp.MustCompile(`github\.com/(?:orgs|users)/([^/]+)/projects/`)
+
+// projectGuardNames returns the names a project can be matched by
+func projectGuardNames(project *Project) []string {
+	owner := ""
+	if matches := projectOwnerPattern.FindStringSubmatch(project.URL); matches != nil {
+		owner = matches[1]
+	}
+	return []string{
+		owner + "/" + project.Title,
+		owner + "/" + strconv.Itoa(project.Number),
+	}
+}
+
+// matchesAnyName reports whether the glob pattern matches any of the names
+func matchesAnyName(pattern string, names []string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, name := range names {
+		if matched, err := path.Match(pattern, strings.ToLower(name)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// GuardedClient enforces a ProjectGuard before any mutation. Only
+// projects resolved through FindProject and accepted by the guard can be
+// modified; mutations against any other project ID are refused.
+type GuardedClient struct {
+	Client
+	guard    ProjectGuard
+	approved map[string]bool
+	refusals map[string]error
+}
+
+// NewGuardedClient wraps a client with project guard rails
+func NewGuardedClient(client Client, guard ProjectGuard) *GuardedClient {
+	return &GuardedClient{
+		Client:   client,
+		guard:    guard,
+		approved: make(map[string]bool),
+		refusals: make(map[string]error),
+	}
+}
+
+// FindProject resolves a project and records whether the guard allows modifying it
+func (gc *GuardedClient) FindProject(ctx context.Context, identifier string) (*Project, error) {
+	project, err := gc.Client.FindProject(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gc.guard.Check(project); err != nil {
+		gc.refusals[project.ID] = err
+	} else {
+		gc.approved[project.ID] = true
+	}
+
+	return project, nil
+}
+
+// checkProject returns an error unless the project ID may be modified
+func (gc *GuardedClient) checkProject(projectID string) error {
+	if gc.approved[projectID] {
+		return nil
+	}
+	if err := gc.refusals[projectID]; err != nil {
+		return fmt.Errorf("refusing to modify project: %w", err)
+	}
+	return fmt.Errorf("refusing to modify project %s: it was not resolved through an allowed project identifier", projectID)
+}
+
+// CreateProjectField adds a field to a project if the guard allows it
+func (gc *GuardedClient) CreateProjectField(ctx context.Context, projectID, name, dataType string) (*ProjectField, error) {
+	if err := gc.checkProject(projectID); err != nil {
+		return nil, err
+	}
+	return gc.Client.CreateProjectField(ctx, projectID, name, dataType)
+}
+
+// CreateProjectItem adds content to a project if the guard allows it
+func (gc *GuardedClient) CreateProjectItem(ctx context.Context, projectID, contentID string) (string, error) {
+	if err := gc.checkProject(projectID); err != nil {
+		return "", err
+	}
+	return gc.Client.CreateProjectItem(ctx, projectID, contentID)
+}
+
+// CreateDraftIssue creates a draft issue if the guard allows it
+func (gc *GuardedClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	if err := gc.checkProject(projectID); err != nil {
+		return "", err
+	}
+	return gc.Client.CreateDraftIssue(ctx, projectID, title, body)
+}
+
+// UpdateDraftIssue changes a draft issue if the guard allows it
+func (gc *GuardedClient) UpdateDraftIssue(ctx context.Context, projectID, draftIssueID, title, body string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.UpdateDraftIssue(ctx, projectID, draftIssueID, title, body)
+}
+
+// SetProjectItemFieldValue sets a field value if the guard allows it
+func (gc *GuardedClient) SetProjectItemFieldValue(ctx context.Context, projectID, itemID, fieldID string, value interface{}) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.SetProjectItemFieldValue(ctx, projectID, itemID, fieldID, value)
+}
+
+// DeleteProjectItem deletes an item if the guard allows it
+func (gc *GuardedClient) DeleteProjectItem(ctx context.Context, projectID, itemID string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.DeleteProjectItem(ctx, projectID, itemID)
+}
+
+// ArchiveProjectItem archives an item if the guard allows it
+func (gc *GuardedClient) ArchiveProjectItem(ctx context.Context, projectID, itemID string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.ArchiveProjectItem(ctx, projectID, itemID)
+}
+
+// ReopenProject reopens a project if the guard allows it
+func (gc *GuardedClient) ReopenProject(ctx context.Context, projectID string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.ReopenProject(ctx, projectID)
+}
+
+// SetProjectItemPosition moves an item if the guard allows it
+func (gc *GuardedClient) SetProjectItemPosition(ctx context.Context, projectID, itemID, afterID string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.SetProjectItemPosition(ctx, projectID, itemID, afterID)
+}
+
+// CopyProject copies a project. The copy is new and owned by this run, so the
+// guard allows modifying it.
+func (gc *GuardedClient) CopyProject(ctx context.Context, projectID, title string) (*Project, error) {
+	project, err := gc.Client.CopyProject(ctx, projectID, title)
+	if err != nil {
+		return nil, err
+	}
+	gc.approved[project.ID] = true
+	return project, nil
+}
+
+// DeleteProject deletes a project if the guard allows it
+func (gc *GuardedClient) DeleteProject(ctx context.Context, projectID string) error {
+	if err := gc.checkProject(projectID); err != nil {
+		return err
+	}
+	return gc.Client.DeleteProject(ctx, projectID)
+}
@@ -0,0 +1,167 @@
+// Tests for project guard rails
+package ghclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectGuardCheck(t *testing.T) {
+	project := &Project{ID: "PVT_1", Number: 42, Title: "Team A Backlog", URL: "https://github.com/orgs/my-org/projects/42"}
+
+	tests := []struct {
+		name    string
+		guard   ProjectGuard
+		allowed bool
+	}{
+		{"no patterns", ProjectGuard{}, true},
+		{"allowed by title glob", ProjectGuard{Allowed: []string{"my-org/team a *"}}, true},
+		{"allowed by number", ProjectGuard{Allowed: []string{"my-org/42"}}, true},
+		{"not in allow list", ProjectGuard{Allowed: []string{"my-org/Roadmap"}}, false},
+		{"other owner", ProjectGuard{Allowed: []string{"other-org/*"}}, false},
+		{"denied", ProjectGuard{Denied: []string{"my-org/*"}}, false},
+		{"deny wins over allow", ProjectGuard{Allowed: []string{"my-org/*"}, Denied: []string{"my-org/42"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.guard.Check(project)
+			if tt.allowed && err != nil {
+				t.Errorf("Expected project to be allowed, got: %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Error("Expected project to be refused")
+			}
+		})
+	}
+}
+
+// recordingClient is a Client stub that resolves a fixed project and records mutations
+type recordingClient struct {
+	Client
+	project *Project
+	drafts  int
+}
+
+func (c *recordingClient) FindProject(ctx context.Context, identifier string) (*Project, error) {
+	return c.project, nil
+}
+
+func (c *recordingClient) CreateDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	c.drafts++
+	return "PVTI_draft", nil
+}
+
+func TestGuardedGitHubClient(t *testing.T) {
+	project := &Project{ID: "PVT_1", Number: 7, Title: "Roadmap", URL: "https://github.com/orgs/my-org/projects/7"}
+
+	t.Run("allowed project", func(t *testing.T) {
+		inner := &recordingClient{project: project}
+		client := NewGuardedClient(inner, ProjectGuard{Allowed: []string{"my-org/Roadmap"}})
+
+		if _, err := client.FindProject(context.Background(), "my-org/Roadmap"); err != nil {
+			t.Fatalf("FindProject failed: %v", err)
+		}
+		if _, err := client.CreateDraftIssue(context.Background(), project.ID, "Title", ""); err != nil {
+			t.Fatalf("Expected draft to be created, got: %v", err)
+		}
+		if inner.drafts != 1 {
+			t.Errorf("Expected 1 draft, got %d", inner.drafts)
+		}
+	})
+
+	t.Run("denied project", func(t *testing.T) {
+		inner := &recordingClient{project: project}
+		client := NewGuardedClient(inner, ProjectGuard{Denied: []string{"my-org/Roadmap"}})
+
+		if _, err := client.FindProject(context.Background(), "my-org/Roadmap"); err != nil {
+			t.Fatalf("FindProject should still resolve denied projects: %v", err)
+		}
+		_, err := client.CreateDraftIssue(context.Background(), project.ID, "Title", "")
+		if err == nil || !strings.Contains(err.Error(), "denied") {
+			t.Errorf("Expected denied error, got: %v", err)
+		}
+		if inner.drafts != 0 {
+			t.Errorf("Expected no drafts, got %d", inner.drafts)
+		}
+		if err := client.ArchiveProjectItem(context.Background(), project.ID, "PVTI_1"); err == nil || !strings.Contains(err.Error(), "denied") {
+			t.Errorf("Expected archiving to be denied, got: %v", err)
+		}
+		if err := client.UpdateDraftIssue(context.Background(), project.ID, "DI_1", "Title", ""); err == nil || !strings.Contains(err.Error(), "denied") {
+			t.Errorf("Expected updating a draft to be denied, got: %v", err)
+		}
+	})
+
+	t.Run("unresolved project ID", func(t *testing.T) {
+		inner := &recordingClient{project: project}
+		client := NewGuardedClient(inner, ProjectGuard{Allowed: []string{"my-org/*"}})
+
+		if _, err := client.CreateDraftIssue(context.Background(), "PVT_other", "Title", ""); err == nil {
+			t.Error("Expected mutation on an unresolved project to be refused")
+		}
+	})
+}
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	content := `{"allowed_projects": ["my-org/*"], "denied_projects": ["my-org/Roadmap"]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(settingsEnvVar, path)
+	settings, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if len(settings.AllowedProjects) != 1 || settings.AllowedProjects[0] != "my-org/*" {
+		t.Errorf("Unexpected allowed projects: %v", settings.AllowedProjects)
+	}
+	if len(settings.DeniedProjects) != 1 || settings.DeniedProjects[0] != "my-org/Roadmap" {
+		t.Errorf("Unexpected denied projects: %v", settings.DeniedProjects)
+	}
+
+	t.Setenv(settingsEnvVar, filepath.Join(dir, "missing.json"))
+	if _, err := LoadSettings(); err == nil {
+		t.Error("Expected error for missing explicit settings file")
+	}
+}
+
+// copyingClient is a Client stub whose project copies can be deleted
+type copyingClient struct {
+	recordingClient
+	deleted int
+}
+
+func (c *copyingClient) CopyProject(ctx context.Context, projectID, title string) (*Project, error) {
+	return &Project{ID: "PVT_copy", Title: title}, nil
+}
+
+func (c *copyingClient) DeleteProject(ctx context.Context, projectID string) error {
+	c.deleted++
+	return nil
+}
+
+func TestGuardedGitHubClientApprovesCopies(t *testing.T) {
+	project := &Project{ID: "PVT_1", Number: 7, Title: "Roadmap", URL: "https://github.com/orgs/my-org/projects/7"}
+	inner := &copyingClient{recordingClient: recordingClient{project: project}}
+	client := NewGuardedClient(inner, ProjectGuard{Allowed: []string{"my-org/Other"}})
+
+	sandbox, err := client.CopyProject(context.Background(), project.ID, "Roadmap (roundtrip sandbox)")
+	if err != nil {
+		t.Fatalf("CopyProject failed: %v", err)
+	}
+	if err := client.DeleteProject(context.Background(), sandbox.ID); err != nil {
+		t.Errorf("Expected the copy to be deletable, got: %v", err)
+	}
+	if err := client.DeleteProject(context.Background(), project.ID); err == nil {
+		t.Error("Expected deleting an unresolved project to be refused")
+	}
+	if inner.deleted != 1 {
+		t.Errorf("Expected 1 deletion, got %d", inner.deleted)
+	}
+}
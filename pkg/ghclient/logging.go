@@ -0,0 +1,33 @@
+// Debug logging of GitHub API requests
+package ghclient
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+// graphQLOperationPattern extracts the operation type and name, or the first
+// selected field of an anonymous operation, from a GraphQL document
+var graphQLOperationPattern = regexp.MustCompile(`^\s*(query|mutation)?\s*(\w+)?[^{]*\{\s*(\w+)`)
+
+// graphQLOperation returns the operation type, query or mutation, and the
+// operation name of a GraphQL document
+func graphQLOperation(query string) (operation, name string) {
+	operation = "query"
+	if matches := graphQLOperationPattern.FindStringSubmatch(query); matches != nil {
+		if matches[1] != "" {
+			operation = matches[1]
+		}
+		name = matches[2]
+		if name == "" {
+			name = matches[3]
+		}
+	}
+	return operation, name
+}
+
+// logGraphQLRequest logs a GraphQL operation and its variables at debug level
+func logGraphQLRequest(query string, variables map[string]interface{}) {
+	operation, name := graphQLOperation(query)
+	slog.Debug("GraphQL "+operation, "operation", name, "variables", variables)
+}
@@ -0,0 +1,45 @@
+// Tests for API request logging
+package ghclient
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogGraphQLRequestOperationName(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var out strings.Builder
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logGraphQLRequest("query FindProject($owner: String!) { organization(login: $owner) { id } }", nil)
+	logGraphQLRequest("\n\t\tquery {\n\t\t\tnode(id: \"PVT_1\") { id }\n\t\t}", nil)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "operation=FindProject") || !strings.Contains(lines[1], "operation=node") {
+		t.Errorf("Unexpected log lines: %q", lines)
+	}
+}
+
+func TestLogGraphQLRequestVariables(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var out strings.Builder
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logGraphQLRequest(`mutation($projectId: ID!) { addProjectV2DraftIssue(input: {projectId: $projectId}) { projectItem { id } } }`,
+		map[string]interface{}{"projectId": "PVT_1"})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(out.String()), &record); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", out.String(), err)
+	}
+	if record["msg"] != "GraphQL mutation" || record["operation"] != "addProjectV2DraftIssue" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if variables, ok := record["variables"].(map[string]interface{}); !ok || variables["projectId"] != "PVT_1" {
+		t.Errorf("Expected variables to be logged, got %v", record["variables"])
+	}
+}
@@ -0,0 +1,129 @@
+// API request metrics
+// Counts and times the requests of a run and the GraphQL rate limit points they cost
+package ghclient
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slowestOperationCount is how many of the slowest requests are kept
+const slowestOperationCount = 5
+
+// Metrics collects statistics about API requests. It is safe for concurrent use.
+type Metrics struct {
+	mu              sync.Mutex
+	graphQLRequests int
+	restRequests    int
+	graphQLPoints   int
+	requestTime     time.Duration
+	slowest         []Operation
+
+	// The GraphQL rate limit window of the last response and the points
+	// used in it
+	rateLimitReset string
+	rateLimitUsed  int
+}
+
+// Operation is a single timed API request
+type Operation struct {
+	// Name is the GraphQL operation, e.g. "mutation addProjectV2DraftIssue",
+	// or the REST method and path
+	Name     string
+	Duration time.Duration
+}
+
+// MetricsSummary is a snapshot of the collected metrics
+type MetricsSummary struct {
+	GraphQLRequests int
+	RESTRequests    int
+	// GraphQLPoints is the rate limit cost of the GraphQL requests. It is
+	// read from the rate limit headers, so other clients using the same
+	// token at the same time inflate it.
+	GraphQLPoints int
+	RequestTime   time.Duration
+	// Slowest lists the slowest requests, slowest first
+	Slowest []Operation
+}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// recordRequest notes a request that took duration
+func (m *Metrics) recordRequest(operation Operation, graphQL bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if graphQL {
+		m.graphQLRequests++
+	} else {
+		m.restRequests++
+	}
+	m.requestTime += operation.Duration
+
+	i := sort.Search(len(m.slowest), func(i int) bool { return m.slowest[i].Duration < operation.Duration })
+	if i < slowestOperationCount {
+		m.slowest = append(m.slowest, Operation{})
+		copy(m.slowest[i+1:], m.slowest[i:])
+		m.slowest[i] = operation
+		if len(m.slowest) > slowestOperationCount {
+			m.slowest = m.slowest[:slowestOperationCount]
+		}
+	}
+}
+
+// recordRateLimit notes the GraphQL points used so far in the rate limit
+// window of a response. A request costs the increase since the previous
+// response; the first response of a window is counted as one point.
+func (m *Metrics) recordRateLimit(header http.Header) {
+	if !strings.EqualFold(header.Get("X-Ratelimit-Resource"), "graphql") {
+		return
+	}
+	used, err := strconv.Atoi(header.Get("X-Ratelimit-Used"))
+	if err != nil {
+		return
+	}
+	reset := header.Get("X-Ratelimit-Reset")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if reset == m.rateLimitReset && used >= m.rateLimitUsed {
+		m.graphQLPoints += used - m.rateLimitUsed
+	} else {
+		m.graphQLPoints++
+	}
+	m.rateLimitReset, m.rateLimitUsed = reset, used
+}
+
+// Summary returns the metrics collected so far
+func (m *Metrics) Summary() MetricsSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return MetricsSummary{
+		GraphQLRequests: m.graphQLRequests,
+		RESTRequests:    m.restRequests,
+		GraphQLPoints:   m.graphQLPoints,
+		RequestTime:     m.requestTime,
+		Slowest:         append([]Operation(nil), m.slowest...),
+	}
+}
+
+// metricsTransport reads the rate limit headers of every response
+type metricsTransport struct {
+	base    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.metrics.recordRateLimit(resp.Header)
+	}
+	return resp, err
+}
@@ -0,0 +1,67 @@
+// Tests for API request metrics
+package ghclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMetricsCountsRequests(t *testing.T) {
+	transport := &recordingTransport{responses: map[string]string{
+		"/users/octo": `{"type": "User"}`,
+		"/graphql":    `{"data": {"user": {"projectsV2": {"nodes": [{"id": "PVT_1", "number": 1, "title": "Roadmap"}]}}}}`,
+	}}
+	client := newRecordingClient(t, transport)
+	client.metrics = NewMetrics()
+	if _, err := client.FindProject(context.Background(), "octo/Roadmap"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summary := client.metrics.Summary()
+	if summary.RESTRequests != 1 || summary.GraphQLRequests != 1 || len(summary.Slowest) != 2 {
+		t.Fatalf("Expected one REST and one GraphQL request, got %+v", summary)
+	}
+	names := map[string]bool{summary.Slowest[0].Name: true, summary.Slowest[1].Name: true}
+	if !names["GET users/octo"] || !names["query user"] {
+		t.Errorf("Expected the requests to be named by operation, got %+v", summary.Slowest)
+	}
+}
+
+func TestMetricsKeepsSlowestOperations(t *testing.T) {
+	metrics := NewMetrics()
+	for _, ms := range []int{3, 9, 1, 7, 5, 8, 2} {
+		metrics.recordRequest(Operation{Name: "op", Duration: time.Duration(ms) * time.Millisecond}, true)
+	}
+
+	summary := metrics.Summary()
+	var got []int
+	for _, operation := range summary.Slowest {
+		got = append(got, int(operation.Duration/time.Millisecond))
+	}
+	if len(got) != 5 || got[0] != 9 || got[1] != 8 || got[2] != 7 || got[3] != 5 || got[4] != 3 {
+		t.Errorf("Expected the 5 slowest operations, slowest first, got %v", got)
+	}
+	if summary.GraphQLRequests != 7 || summary.RequestTime != 35*time.Millisecond {
+		t.Errorf("Expected every request to be counted, got %+v", summary)
+	}
+}
+
+func TestMetricsGraphQLPoints(t *testing.T) {
+	metrics := NewMetrics()
+	rateLimit := func(resource, used, reset string) http.Header {
+		return http.Header{"X-Ratelimit-Resource": {resource}, "X-Ratelimit-Used": {used}, "X-Ratelimit-Reset": {reset}}
+	}
+
+	metrics.recordRateLimit(rateLimit("graphql", "40", "1000")) // first response counts 1
+	metrics.recordRateLimit(rateLimit("graphql", "41", "1000"))
+	metrics.recordRateLimit(rateLimit("graphql", "44", "1000"))
+	metrics.recordRateLimit(rateLimit("core", "900", "1000"))  // REST requests don't cost points
+	metrics.recordRateLimit(rateLimit("graphql", "2", "2000")) // a new window counts 1
+	metrics.recordRateLimit(rateLimit("graphql", "bad", "2000"))
+
+	if points := metrics.Summary().GraphQLPoints; points != 6 {
+		t.Errorf("Expected 6 points, got %d", points)
+	}
+}
@@ -0,0 +1,91 @@
+// Project title matching
+// Resolves a project title that is close to, but not exactly, the title of one of an owner's projects
+package ghclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AmbiguousProjectError reports a project title that matches several projects
+type AmbiguousProjectError struct {
+	Owner      string
+	Name       string
+	Candidates []Project
+}
+
+func (e *AmbiguousProjectError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, project := range e.Candidates {
+		names[i] = fmt.Sprintf("#%d %q", project.Number, project.Title)
+		if project.Closed {
+			names[i] += " (closed)"
+		}
+	}
+	return fmt.Sprintf("%d projects of %s match %q: %s", len(e.Candidates), e.Owner, e.Name, strings.Join(names, ", "))
+}
+
+// MatchProject picks the project of owner whose title matches name. Titles
+// are compared in rounds: exactly, ignoring case, containing name, and with
+// every word of name starting a word of the title ("plat bo" matches
+// "Platform Board"). The first round with a match decides; several matches
+// are an *AmbiguousProjectError. Closed projects are left out unless
+// includeClosed is set.
+func MatchProject(projects []Project, owner, name string, includeClosed bool) (*Project, error) {
+	matchers := []func(title string) bool{
+		func(title string) bool { return title == name },
+		func(title string) bool { return strings.EqualFold(title, name) },
+		func(title string) bool { return strings.Contains(strings.ToLower(title), strings.ToLower(name)) },
+		func(title string) bool { return hasWordPrefixes(title, name) },
+	}
+
+	closedMatch := false
+	for _, matches := range matchers {
+		var candidates []Project
+		for _, project := range projects {
+			if !matches(project.Title) {
+				continue
+			}
+			if project.Closed && !includeClosed {
+				closedMatch = true
+				continue
+			}
+			candidates = append(candidates, project)
+		}
+
+		switch {
+		case len(candidates) == 1:
+			return &candidates[0], nil
+		case len(candidates) > 1:
+			return nil, &AmbiguousProjectError{Owner: owner, Name: name, Candidates: candidates}
+		}
+	}
+
+	if closedMatch {
+		return nil, fmt.Errorf("project %s/%s is closed (use --reopen to reopen it before importing, or --include-closed to import into it as is)", owner, name)
+	}
+	return nil, fmt.Errorf("project %s/%s not found", owner, name)
+}
+
+// hasWordPrefixes reports whether every word of name starts a word of title,
+// ignoring case
+func hasWordPrefixes(title, name string) bool {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) == 0 {
+		return false
+	}
+	titleWords := strings.Fields(strings.ToLower(title))
+	for _, word := range words {
+		found := false
+		for _, titleWord := range titleWords {
+			if strings.HasPrefix(titleWord, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+// Tests for project title matching
+package ghclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchProject(t *testing.T) {
+	projects := []Project{
+		{Number: 1, Title: "Roadmap"},
+		{Number: 2, Title: "roadmap"},
+		{Number: 3, Title: "Platform Board"},
+		{Number: 4, Title: "Platform Roadmap 2024", Closed: true},
+		{Number: 5, Title: "Web Board"},
+		{Number: 6, Title: "Archive", Closed: true},
+	}
+
+	tests := []struct {
+		name          string
+		includeClosed bool
+		want          int // Project number, 0 for an error
+	}{
+		{"Roadmap", false, 1},
+		{"ROADMAP", false, 0}, // Projects 1 and 2 match ignoring case
+		{"platform", false, 3},
+		{"platform", true, 0}, // The closed project matches too
+		{"plat bo", false, 3},
+		{"web", false, 5},
+		{"Archive", false, 0},
+		{"Archive", true, 6},
+		{"Missing", false, 0},
+	}
+	for _, tt := range tests {
+		project, err := MatchProject(projects, "acme", tt.name, tt.includeClosed)
+		got := 0
+		if err == nil {
+			got = project.Number
+		}
+		if got != tt.want {
+			t.Errorf("MatchProject(%q, %v) = #%d (%v), want #%d", tt.name, tt.includeClosed, got, err, tt.want)
+		}
+	}
+
+	var ambiguous *AmbiguousProjectError
+	_, err := MatchProject(projects, "acme", "board", false)
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 || !strings.Contains(err.Error(), `#3 "Platform Board", #5 "Web Board"`) {
+		t.Errorf("Expected both boards as candidates, got %v", err)
+	}
+	if _, err := MatchProject(projects, "acme", "Archive", false); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("Expected a closed project to be pointed out, got %v", err)
+	}
+}
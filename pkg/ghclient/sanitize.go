@@ -0,0 +1,168 @@
+// Snapshot sanitization
+// Redacts credentials and identifying data from recorded snapshots so they can be committed publicly
+package ghclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SnapshotLogin replaces the login of the user who recorded a snapshot
+const SnapshotLogin = "snapshot-user"
+
+// SnapshotSanitizer redacts what a recorded snapshot shouldn't publish: the
+// token the requests were sent with, the login of the user who recorded it,
+// and node IDs, which encode the database IDs of users and of possibly
+// private repositories and projects. Request headers other than
+// Content-Type are never recorded, so the Authorization header doesn't
+// reach a snapshot in the first place.
+type SnapshotSanitizer struct {
+	Token string
+	Login string
+}
+
+// Sanitize redacts the snapshot in place. Each node ID is replaced with the
+// same placeholder everywhere it appears, keeping its type prefix, so a
+// replayed request that sends an ID from an earlier response still matches
+// the recording.
+func (s SnapshotSanitizer) Sanitize(snapshot *Snapshot) {
+	ids := make(map[string]string)
+	for _, interaction := range snapshot.Interactions {
+		if value, ok := decodeJSONBody(interaction.Response.Body); ok {
+			collectNodeIDs(value, "", ids)
+		}
+	}
+
+	var login *regexp.Regexp
+	if s.Login != "" {
+		login = regexp.MustCompile(`(?i)(^|[^A-Za-z0-9-])` + regexp.QuoteMeta(s.Login) + `([^A-Za-z0-9-]|$)`)
+	}
+	redact := func(text string) string {
+		if replacement, ok := ids[text]; ok {
+			return replacement
+		}
+		if s.Token != "" {
+			text = strings.ReplaceAll(text, s.Token, "REDACTED")
+		}
+		if login != nil {
+			text = login.ReplaceAllString(text, "${1}"+SnapshotLogin+"${2}")
+		}
+		return text
+	}
+
+	for i := range snapshot.Interactions {
+		interaction := &snapshot.Interactions[i]
+		url := interaction.Request.URL
+		for id, replacement := range ids {
+			url = strings.ReplaceAll(url, id, replacement)
+		}
+		interaction.Request.URL = redact(url)
+		interaction.Request.Body = redactBody(interaction.Request.Body, redact)
+		interaction.Response.Body = redactBody(interaction.Response.Body, redact)
+	}
+}
+
+// nodeIDKeys are the keys GraphQL and REST responses give node IDs under
+var nodeIDKeys = map[string]bool{"id": true, "node_id": true}
+
+// collectNodeIDs adds a placeholder to ids for each node ID in value. Short
+// IDs, such as those of single select options and iterations, aren't node
+// IDs and are kept.
+func collectNodeIDs(value interface{}, key string, ids map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// Sorted, so the same recording gets the same placeholders
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectNodeIDs(v[k], k, ids)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectNodeIDs(child, key, ids)
+		}
+	case string:
+		if !nodeIDKeys[key] || len(v) < 12 {
+			return
+		}
+		if _, seen := ids[v]; seen {
+			return
+		}
+		prefix := "ID"
+		if index := strings.Index(v, "_"); index > 0 {
+			prefix = v[:index]
+		}
+		ids[v] = fmt.Sprintf("%s_REDACTED_%d", prefix, len(ids)+1)
+	}
+}
+
+// redactBody applies redact to every string in a JSON body, or to the whole
+// body if it isn't JSON
+func redactBody(body string, redact func(string) string) string {
+	value, ok := decodeJSONBody(body)
+	if !ok {
+		return redact(body)
+	}
+	value, changed := redactJSON(value, redact)
+	if !changed {
+		return body
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactJSON applies redact to the strings of a decoded JSON value and
+// reports whether any of them changed
+func redactJSON(value interface{}, redact func(string) string) (interface{}, bool) {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			var c bool
+			v[k], c = redactJSON(child, redact)
+			changed = changed || c
+		}
+		// The recording user's database ID identifies them as well as their login
+		if id, ok := v["id"].(json.Number); ok && v["login"] == SnapshotLogin && id != "0" {
+			v["id"] = json.Number("0")
+			changed = true
+		}
+	case []interface{}:
+		for i, child := range v {
+			var c bool
+			v[i], c = redactJSON(child, redact)
+			changed = changed || c
+		}
+	case string:
+		redacted := redact(v)
+		return redacted, redacted != v
+	}
+	return value, changed
+}
+
+// decodeJSONBody decodes a JSON body, keeping numbers as they were written
+func decodeJSONBody(body string) (interface{}, bool) {
+	if body == "" {
+		return nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return nil, false
+	}
+	return value, true
+}
@@ -0,0 +1,58 @@
+// Settings file support for options shared across runs
+// Read from GH_PROJECT_IMPORT_CONFIG or the user's config directory
+package ghclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// settingsEnvVar names the environment variable that points at the settings file
+const settingsEnvVar = "GH_PROJECT_IMPORT_CONFIG"
+
+// Settings holds options read from the settings file
+type Settings struct {
+	// AllowedProjects and DeniedProjects restrict which projects may be
+	// modified; see ProjectGuard for the pattern syntax
+	AllowedProjects []string `json:"allowed_projects,omitempty"`
+	DeniedProjects  []string `json:"denied_projects,omitempty"`
+}
+
+// settingsPath returns the settings file location and whether it was set explicitly
+func settingsPath() (string, bool) {
+	if path := os.Getenv(settingsEnvVar); path != "" {
+		return path, true
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(configDir, "gh-project-import", "config.json"), false
+}
+
+// LoadSettings reads the settings file. A missing default file yields empty
+// settings, but a file named by GH_PROJECT_IMPORT_CONFIG must exist.
+func LoadSettings() (Settings, error) {
+	var settings Settings
+
+	path, explicit := settingsPath()
+	if path == "" {
+		return settings, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return settings, nil
+	} else if err != nil {
+		return settings, fmt.Errorf("failed to read settings file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("failed to parse settings file %s: %w", path, err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,384 @@
+// Snapshot testing framework for GitHub API interactions
+// Records and replays the raw HTTP requests and responses of GitHub API calls for deterministic testing
+package ghclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// SnapshotMode defines the operating mode for snapshot tests
+type SnapshotMode int
+
+const (
+	SnapshotModeReplay SnapshotMode = iota // Default: replay from snapshots
+	SnapshotModeRecord                     // Record new snapshots from real API calls
+	SnapshotModeBypass                     // Make real API calls without recording
+)
+
+// Interaction is a single HTTP request to the API and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as sent to the API. URL is the path and query
+// string without the host; Body is the exact request body, e.g. the GraphQL
+// query and its variables.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is the API's response to a recorded request
+type RecordedResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Snapshot represents a complete test scenario with multiple API calls
+type Snapshot struct {
+	TestName     string        `json:"test_name"`
+	Interactions []Interaction `json:"interactions"`
+	Created      time.Time     `json:"created"`
+	Updated      time.Time     `json:"updated"`
+}
+
+// SnapshotTransport is an http.RoundTripper that records the requests sent
+// through it and their responses, or replays recorded responses without
+// touching the network. A replayed request is answered by the first unused
+// recording with the same method, URL and body, whatever order the requests
+// were recorded in, so a change to a query fails the test until the
+// snapshot is recorded again.
+type SnapshotTransport struct {
+	mode     SnapshotMode
+	base     http.RoundTripper
+	snapshot *Snapshot
+
+	mu   sync.Mutex
+	used []bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *SnapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == SnapshotModeBypass {
+		return t.base.RoundTrip(req)
+	}
+
+	recorded := RecordedRequest{Method: req.Method, URL: req.URL.RequestURI()}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		recorded.Body = string(body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if t.mode == SnapshotModeRecord {
+		return t.record(req, recorded)
+	}
+	return t.replay(req, recorded)
+}
+
+// record sends the request and adds it and its response to the snapshot
+func (t *SnapshotTransport) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot.Interactions = append(t.snapshot.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(body),
+		},
+	})
+	return resp, nil
+}
+
+// replay answers the request with the response of the first unused
+// interaction recorded for the same request
+func (t *SnapshotTransport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.used == nil {
+		t.used = make([]bool, len(t.snapshot.Interactions))
+	}
+	index := -1
+	for i, interaction := range t.snapshot.Interactions {
+		if !t.used[i] && interaction.Request.matches(recorded) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("no unused recorded call matches %s %s %s (try running with SNAPSHOT_MODE=record to update the snapshot)",
+			recorded.Method, recorded.URL, recorded.Body)
+	}
+	t.used[index] = true
+	interaction := t.snapshot.Interactions[index]
+
+	header := make(http.Header)
+	if interaction.Response.ContentType != "" {
+		header.Set("Content-Type", interaction.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// checkAllUsed returns an error listing the recorded calls that haven't been
+// replayed
+func (t *SnapshotTransport) checkAllUsed() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var unused []string
+	for i, interaction := range t.snapshot.Interactions {
+		if i >= len(t.used) || !t.used[i] {
+			unused = append(unused, fmt.Sprintf("  call %d: %s %s %s", i+1, interaction.Request.Method, interaction.Request.URL, interaction.Request.Body))
+		}
+	}
+	if len(unused) > 0 {
+		return fmt.Errorf("%d recorded calls weren't replayed (try running with SNAPSHOT_MODE=record to update the snapshot):\n%s", len(unused), strings.Join(unused, "\n"))
+	}
+	return nil
+}
+
+// matches reports whether a request is the recorded one. JSON bodies are
+// compared by value, so formatting and key order don't matter.
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	return r.Method == other.Method && r.URL == other.URL && normalizeBody(r.Body) == normalizeBody(other.Body)
+}
+
+// normalizeBody returns a JSON body in compact form with sorted keys, and any
+// other body unchanged
+func normalizeBody(body string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
+
+// SnapshotClient is a RealClient whose requests go through a
+// SnapshotTransport, so it sends exactly the requests the real client sends
+type SnapshotClient struct {
+	Client
+
+	// RequireAllUsed makes Close fail in replay mode if some recorded calls
+	// were never replayed, i.e. the test no longer makes them
+	RequireAllUsed bool
+
+	transport   *SnapshotTransport
+	sanitizer   SnapshotSanitizer
+	snapshotDir string
+	testName    string
+}
+
+// NewSnapshotClient creates a new snapshot-enabled GitHub client
+func NewSnapshotClient(testName string) (*SnapshotClient, error) {
+	mode := getSnapshotMode()
+	snapshotDir := getSnapshotDir()
+
+	// Create snapshot directory if it doesn't exist
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	client := &SnapshotClient{
+		transport:   &SnapshotTransport{mode: mode, base: http.DefaultTransport},
+		snapshotDir: snapshotDir,
+		testName:    testName,
+	}
+
+	// Load or create snapshot
+	if err := client.loadOrCreateSnapshot(); err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	options := api.ClientOptions{
+		Headers:   map[string]string{"User-Agent": userAgent("")},
+		Transport: client.transport,
+	}
+	if mode == SnapshotModeReplay {
+		// Replay never reaches the API, so no credentials are needed
+		options.Host = "github.com"
+		options.AuthToken = "snapshot-replay"
+	}
+	restClient, err := api.NewRESTClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	client.Client = &RealClient{client: *restClient}
+
+	if mode == SnapshotModeReplay {
+		client.sanitizer.Login = SnapshotLogin
+		return client, nil
+	}
+
+	// Look up who is recording without recording it, so their login and
+	// token can be redacted from the snapshot
+	host, _ := auth.DefaultHost()
+	client.sanitizer.Token, _ = auth.TokenForHost(host)
+	options.Transport = client.transport.base
+	loginClient, err := api.NewRESTClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	client.sanitizer.Login, err = (&RealClient{client: *loginClient}).GetUser(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the recording user: %w", err)
+	}
+
+	return client, nil
+}
+
+// Login returns the login of the user the snapshot is recorded as. Tests use
+// it as the owner of the projects they work with: it is the recording user's
+// login while recording, and the placeholder that replaced it in the
+// snapshot while replaying.
+func (sgc *SnapshotClient) Login() string {
+	return sgc.sanitizer.Login
+}
+
+// Close saves the snapshot if in record mode. In replay mode with
+// RequireAllUsed set, it reports the recorded calls that weren't replayed.
+func (sgc *SnapshotClient) Close() error {
+	switch sgc.transport.mode {
+	case SnapshotModeRecord:
+		return sgc.saveSnapshot()
+	case SnapshotModeReplay:
+		if sgc.RequireAllUsed {
+			return sgc.transport.checkAllUsed()
+		}
+	}
+	return nil
+}
+
+// loadOrCreateSnapshot loads an existing snapshot or creates a new one
+func (sgc *SnapshotClient) loadOrCreateSnapshot() error {
+	snapshotPath := sgc.getSnapshotPath()
+
+	switch sgc.transport.mode {
+	case SnapshotModeBypass:
+		return nil
+	case SnapshotModeRecord:
+		// In record mode, create a new snapshot
+		sgc.transport.snapshot = &Snapshot{
+			TestName:     sgc.testName,
+			Interactions: []Interaction{},
+			Created:      time.Now(),
+			Updated:      time.Now(),
+		}
+		return nil
+	}
+
+	// In replay mode, load existing snapshot
+	if _, err := os.Stat(snapshotPath); os.IsNotExist(err) {
+		return fmt.Errorf("snapshot file not found: %s (try running with SNAPSHOT_MODE=record to create it)", snapshotPath)
+	}
+
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+
+	sgc.transport.snapshot = &snapshot
+	return nil
+}
+
+// saveSnapshot sanitizes the current snapshot and saves it to disk
+func (sgc *SnapshotClient) saveSnapshot() error {
+	snapshot := sgc.transport.snapshot
+	snapshot.Updated = time.Now()
+	sgc.sanitizer.Sanitize(snapshot)
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	snapshotPath := sgc.getSnapshotPath()
+	if err := os.WriteFile(snapshotPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// getSnapshotPath returns the file path for the snapshot
+func (sgc *SnapshotClient) getSnapshotPath() string {
+	// Create safe filename from test name
+	safeTestName := strings.ReplaceAll(sgc.testName, " ", "_")
+	safeTestName = regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(safeTestName, "_")
+	return filepath.Join(sgc.snapshotDir, safeTestName+".json")
+}
+
+// Helper functions
+
+// getSnapshotMode returns the current snapshot mode from environment
+func getSnapshotMode() SnapshotMode {
+	mode := strings.ToLower(os.Getenv("SNAPSHOT_MODE"))
+	switch mode {
+	case "record":
+		return SnapshotModeRecord
+	case "bypass":
+		return SnapshotModeBypass
+	default:
+		return SnapshotModeReplay
+	}
+}
+
+// getSnapshotDir returns the snapshot directory from environment or default
+func getSnapshotDir() string {
+	if dir := os.Getenv("SNAPSHOT_DIR"); dir != "" {
+		return dir
+	}
+	return "testdata/snapshots"
+}
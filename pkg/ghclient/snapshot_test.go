@@ -0,0 +1,167 @@
+// Tests for Snapshot tooling
+package ghclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSnapshotModes tests different snapshot modes
+func TestSnapshotModes(t *testing.T) {
+	// Test that snapshot mode can be controlled via environment variables
+	originalMode := os.Getenv("SNAPSHOT_MODE")
+	defer os.Setenv("SNAPSHOT_MODE", originalMode) // Restore
+
+	// Test replay mode (default)
+	os.Setenv("SNAPSHOT_MODE", "replay")
+	mode := getSnapshotMode()
+	if mode != SnapshotModeReplay {
+		t.Errorf("Expected replay mode, got %v", mode)
+	}
+
+	// Test record mode
+	os.Setenv("SNAPSHOT_MODE", "record")
+	mode = getSnapshotMode()
+	if mode != SnapshotModeRecord {
+		t.Errorf("Expected record mode, got %v", mode)
+	}
+
+	// Test bypass mode
+	os.Setenv("SNAPSHOT_MODE", "bypass")
+	mode = getSnapshotMode()
+	if mode != SnapshotModeBypass {
+		t.Errorf("Expected bypass mode, got %v", mode)
+	}
+}
+
+// TestSnapshotDirectory tests snapshot directory configuration
+func TestSnapshotDirectory(t *testing.T) {
+	originalDir := os.Getenv("SNAPSHOT_DIR")
+	defer os.Setenv("SNAPSHOT_DIR", originalDir) // Restore
+
+	// Test default directory
+	os.Unsetenv("SNAPSHOT_DIR")
+	dir := getSnapshotDir()
+	expectedDefault := "testdata/snapshots"
+	if dir != expectedDefault {
+		t.Errorf("Expected default directory %s, got %s", expectedDefault, dir)
+	}
+
+	// Test custom directory
+	customDir := "/tmp/test-snapshots"
+	os.Setenv("SNAPSHOT_DIR", customDir)
+	dir = getSnapshotDir()
+	if dir != customDir {
+		t.Errorf("Expected custom directory %s, got %s", customDir, dir)
+	}
+}
+
+// TestSnapshotTransportReplay tests that replayed requests must match a recording
+func TestSnapshotTransportReplay(t *testing.T) {
+	recording := func(a int, response string) Interaction {
+		return Interaction{
+			Request:  RecordedRequest{Method: "POST", URL: "/graphql", Body: fmt.Sprintf(`{"query":"q","variables":{"a":%d,"b":2}}`, a)},
+			Response: RecordedResponse{StatusCode: 200, ContentType: "application/json", Body: response},
+		}
+	}
+	transport := &SnapshotTransport{mode: SnapshotModeReplay, snapshot: &Snapshot{Interactions: []Interaction{
+		recording(1, `{"data":1}`),
+		recording(2, `{"data":2}`),
+		recording(1, `{"data":3}`),
+	}}}
+
+	send := func(body string) (string, error) {
+		req, err := http.NewRequest("POST", "https://api.github.com/graphql", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			return "", err
+		}
+		data, _ := io.ReadAll(resp.Body)
+		return string(data), nil
+	}
+
+	if _, err := send(`{"query":"q","variables":{"a":1,"b":3}}`); err == nil || !strings.Contains(err.Error(), "no unused recorded call matches") {
+		t.Errorf("Expected a mismatch error, got %v", err)
+	}
+
+	// Requests are matched out of order, and repeats replay in recorded order
+	var got []string
+	for _, body := range []string{`{"query":"q","variables":{"a":2,"b":2}}`, `{"variables": {"b": 2, "a": 1}, "query": "q"}`} {
+		data, err := send(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, data)
+	}
+	if err := transport.checkAllUsed(); err == nil || !strings.Contains(err.Error(), "1 recorded calls weren't replayed") || !strings.Contains(err.Error(), "call 3") {
+		t.Errorf("Expected the third call to be unused, got %v", err)
+	}
+	data, err := send(`{"query":"q","variables":{"a":1,"b":2}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, data)
+	if strings.Join(got, " ") != `{"data":2} {"data":1} {"data":3}` {
+		t.Errorf("Unexpected responses %v", got)
+	}
+	if err := transport.checkAllUsed(); err != nil {
+		t.Errorf("Expected every call to be used, got %v", err)
+	}
+
+	if _, err := send(`{"query":"q","variables":{"a":1,"b":2}}`); err == nil {
+		t.Error("Expected an error once the recordings are used up")
+	}
+}
+
+// TestSnapshotSanitizer tests redaction of recorded snapshots
+func TestSnapshotSanitizer(t *testing.T) {
+	snapshot := &Snapshot{Interactions: []Interaction{
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "/users/Octo-Cat?token=ghp_secret"},
+			Response: RecordedResponse{StatusCode: 200, Body: `{"id":42,"login":"octo-cat","node_id":"MDQ6VXNlcjQy","html_url":"https://github.com/octo-cat"}`},
+		},
+		{
+			Request:  RecordedRequest{Method: "POST", URL: "/graphql", Body: `{"query":"q","variables":{"login":"octo-cat","owner":"octo-cats"}}`},
+			Response: RecordedResponse{StatusCode: 200, Body: `{"data":{"node":{"id":"PVT_kwHOAAAAKs4AAAAB","options":[{"id":"f75ad846"}]}}}`},
+		},
+		{
+			Request:  RecordedRequest{Method: "POST", URL: "/graphql", Body: `{"query":"q","variables":{"projectId":"PVT_kwHOAAAAKs4AAAAB"}}`},
+			Response: RecordedResponse{StatusCode: 200, Body: "not json ghp_secret"},
+		},
+	}}
+
+	SnapshotSanitizer{Token: "ghp_secret", Login: "octo-cat"}.Sanitize(snapshot)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"ghp_secret", "octo-cat\\", "Octo-Cat", "MDQ6VXNlcjQy", "PVT_kwHO", `\"id\":42`} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("Expected %s to be redacted from %s", leaked, data)
+		}
+	}
+
+	interactions := snapshot.Interactions
+	if interactions[0].Request.URL != "/users/"+SnapshotLogin+"?token=REDACTED" {
+		t.Errorf("Unexpected URL %s", interactions[0].Request.URL)
+	}
+	if !strings.Contains(interactions[1].Request.Body, `"owner":"octo-cats"`) {
+		t.Errorf("Expected other logins to be kept, got %s", interactions[1].Request.Body)
+	}
+	if !strings.Contains(interactions[1].Response.Body, `"id":"f75ad846"`) {
+		t.Errorf("Expected option IDs to be kept, got %s", interactions[1].Response.Body)
+	}
+	projectID := "PVT_REDACTED_2"
+	if !strings.Contains(interactions[1].Response.Body, projectID) || !strings.Contains(interactions[2].Request.Body, projectID) {
+		t.Errorf("Expected the project ID to be replaced with %s everywhere, got %+v", projectID, interactions[1:])
+	}
+}
@@ -0,0 +1,75 @@
+// Authentication tokens
+// Picks the token of API requests from --token or the environment, and explains tokens that can't access projects
+package ghclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// Kinds of tokens, told apart by their prefix
+const (
+	TokenClassic     = "classic personal access token"
+	TokenFineGrained = "fine-grained personal access token"
+	TokenOAuth       = "OAuth token"      // e.g. from gh auth login
+	TokenApp         = "GitHub App token" // including the GITHUB_TOKEN of Actions
+	TokenUnknown     = "token"
+)
+
+// ErrTokenPermission is returned when the token is valid but isn't allowed
+// to read or change the project
+var ErrTokenPermission = errors.New("token lacks permission")
+
+// ResolveToken returns the token to use and where it came from: the --token
+// flag, else the GH_TOKEN or GITHUB_TOKEN environment variables
+// (GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN for a GH_HOST other than
+// github.com), else the token in gh's configuration file. None of these
+// read the system keyring, so they work in containers without gh; an empty
+// token leaves finding credentials to gh.
+func ResolveToken(flag string) (token, source string) {
+	if flag != "" {
+		return flag, "--token"
+	}
+	host, _ := auth.DefaultHost()
+	token, source = auth.TokenFromEnvOrConfig(host)
+	if token == "" {
+		return "", ""
+	}
+	return token, source
+}
+
+// TokenKind describes a token by its prefix, e.g. TokenFineGrained for
+// github_pat_ tokens
+func TokenKind(token string) string {
+	switch {
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenFineGrained
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenClassic
+	case strings.HasPrefix(token, "gho_"):
+		return TokenOAuth
+	case strings.HasPrefix(token, "ghs_"), strings.HasPrefix(token, "ghu_"):
+		return TokenApp
+	}
+	return TokenUnknown
+}
+
+// permissionError explains a GraphQL error message about a token that lacks
+// permission, or returns nil for any other message
+func permissionError(message string) error {
+	var hint string
+	switch {
+	case strings.Contains(message, "not accessible by personal access token"):
+		hint = "a fine-grained personal access token needs the Projects permission (read and write) of the organization that owns the project, and Issues read access to the repositories of imported issues; projects owned by a user need a classic token with the project scope"
+	case strings.Contains(message, "not accessible by integration"):
+		hint = "a GitHub App token needs the Projects permission (read and write) of the organization that owns the project; the GITHUB_TOKEN of Actions can't be given it, so pass a personal access token or App token with --token or GH_TOKEN"
+	case strings.Contains(message, "not been granted the required scopes"):
+		hint = "the token needs the project scope: run gh auth refresh -s project, or create a classic token with the project and repo scopes"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrTokenPermission, hint, message)
+}
@@ -0,0 +1,81 @@
+// Tests for authentication tokens
+package ghclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestResolveToken(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	if token, source := ResolveToken(""); token != "" || source != "" {
+		t.Errorf("Expected no token without a flag, environment or config, got %q from %q", token, source)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "ghs_actions")
+	if token, source := ResolveToken(""); token != "ghs_actions" || source != "GITHUB_TOKEN" {
+		t.Errorf("Expected the GITHUB_TOKEN token, got %q from %q", token, source)
+	}
+
+	t.Setenv("GH_TOKEN", "github_pat_env")
+	if token, source := ResolveToken(""); token != "github_pat_env" || source != "GH_TOKEN" {
+		t.Errorf("Expected GH_TOKEN to take precedence over GITHUB_TOKEN, got %q from %q", token, source)
+	}
+
+	if token, source := ResolveToken("ghp_flag"); token != "ghp_flag" || source != "--token" {
+		t.Errorf("Expected --token to take precedence over the environment, got %q from %q", token, source)
+	}
+}
+
+func TestTokenKind(t *testing.T) {
+	tests := map[string]string{
+		"ghp_abc":         TokenClassic,
+		"github_pat_11AB": TokenFineGrained,
+		"gho_abc":         TokenOAuth,
+		"ghs_abc":         TokenApp,
+		"ghu_abc":         TokenApp,
+		"0123456789abcde": TokenUnknown,
+	}
+	for token, expected := range tests {
+		if kind := TokenKind(token); kind != expected {
+			t.Errorf("TokenKind(%q) = %q, expected %q", token, kind, expected)
+		}
+	}
+}
+
+func TestTokenPermissionErrors(t *testing.T) {
+	tests := map[string]string{
+		"Resource not accessible by personal access token": "Projects permission (read and write)",
+		"Resource not accessible by integration":           "GITHUB_TOKEN of Actions can't be given it",
+		"Your token has not been granted the required scopes to execute this query. The 'projectV2' field requires one of the following scopes: ['read:project']": "gh auth refresh -s project",
+	}
+	for message, hint := range tests {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"errors": []map[string]interface{}{{"type": "FORBIDDEN", "message": message}},
+			})
+		})
+		rest, err := api.NewRESTClient(api.ClientOptions{Host: "github.com", AuthToken: "test", Transport: handlerTransport{handler: handler}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &RealClient{client: *rest}
+
+		_, err = client.GetProjectFields(context.Background(), "PVT_1")
+		if !errors.Is(err, ErrTokenPermission) || !strings.Contains(err.Error(), hint) {
+			t.Errorf("Expected a token permission error mentioning %q for %q, got %v", hint, message, err)
+		}
+		if _, err := client.CreateProjectItem(context.Background(), "PVT_1", "I_1"); !errors.Is(err, ErrTokenPermission) {
+			t.Errorf("Expected mutations to return a token permission error for %q, got %v", message, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// API request tracing
+// Writes every request with its variables, status, duration and returned errors, to diagnose rejected calls (--trace-api)
+package ghclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceMu keeps the lines of concurrent requests from interleaving
+var traceMu sync.Mutex
+
+// secretKeyPattern matches the names of variables whose values are masked
+var secretKeyPattern = regexp.MustCompile(`(?i)token|secret|password|credential|authorization`)
+
+// secretValuePattern matches GitHub tokens wherever they appear
+var secretValuePattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+// traceTransport writes a trace of every request to out, e.g.
+//
+//	2026-03-01T09:30:00Z mutation addProjectV2ItemById → 200 in 312ms variables={"contentId":"I_1","projectId":"PVT_1"}
+//	    error: Could not resolve to a node with the global id of 'I_1'
+//
+// The Authorization header is never written, and variables that look like
+// secrets are masked.
+type traceTransport struct {
+	base http.RoundTripper
+	out  io.Writer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	operation, variables := traceRequest(req, body)
+	line := fmt.Sprintf("%s %s", start.UTC().Format(time.RFC3339), operation)
+	var errs []string
+	if err != nil {
+		line += fmt.Sprintf(" → failed after %s", elapsed)
+		errs = append(errs, err.Error())
+	} else {
+		line += fmt.Sprintf(" → %d in %s", resp.StatusCode, elapsed)
+		if resp.Body != nil {
+			data, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			if readErr != nil {
+				return resp, readErr
+			}
+			errs = responseErrors(resp.StatusCode, data)
+		}
+	}
+	if variables != "" {
+		line += " variables=" + variables
+	}
+
+	var b strings.Builder
+	b.WriteString(line + "\n")
+	for _, message := range errs {
+		fmt.Fprintf(&b, "    error: %s\n", message)
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	io.WriteString(t.out, b.String())
+
+	return resp, err
+}
+
+// traceRequest returns the operation of a request, a GraphQL operation or
+// the REST method and path, and its masked variables or JSON body
+func traceRequest(req *http.Request, body []byte) (operation, variables string) {
+	var value interface{}
+	if len(body) > 0 && json.Unmarshal(body, &value) != nil {
+		return req.Method + " " + req.URL.Path, "(not JSON)"
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		var request graphQLRequest
+		if json.Unmarshal(body, &request) == nil && request.Query != "" {
+			kind, name := graphQLOperation(request.Query)
+			operation = kind + " " + name
+			value = nil
+			if len(request.Variables) > 0 {
+				value = request.Variables
+			}
+		}
+	}
+	if operation == "" {
+		operation = req.Method + " " + req.URL.Path
+	}
+
+	if value != nil {
+		if data, err := json.Marshal(maskSecrets(value, "")); err == nil {
+			variables = string(data)
+		}
+	}
+	return operation, variables
+}
+
+// maskSecrets returns a copy of a decoded JSON value with the values of
+// secret-looking keys and any GitHub tokens replaced
+func maskSecrets(value interface{}, key string) interface{} {
+	if key != "" && secretKeyPattern.MatchString(key) {
+		return "REDACTED"
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			masked[k] = maskSecrets(child, k)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, child := range v {
+			masked[i] = maskSecrets(child, "")
+		}
+		return masked
+	case string:
+		return secretValuePattern.ReplaceAllString(v, "REDACTED")
+	}
+	return value
+}
+
+// responseErrors returns the error messages of a response: the errors of a
+// GraphQL response, or the message of a failed REST response
+func responseErrors(status int, body []byte) []string {
+	var response struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		if status >= 400 {
+			return []string{http.StatusText(status)}
+		}
+		return nil
+	}
+
+	var errs []string
+	for _, e := range response.Errors {
+		if e.Message != "" {
+			errs = append(errs, e.Message)
+		}
+	}
+	if len(errs) == 0 && status >= 400 {
+		message := response.Message
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		errs = append(errs, message)
+	}
+	return errs
+}
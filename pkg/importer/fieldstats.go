@@ -0,0 +1,129 @@
+// Field value statistics
+// Counts the field values set on imported items and those skipped, by field and reason
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SkipReason is why a field value wasn't set on an item
+type SkipReason string
+
+// Reasons for skipping a field value, in the order they are reported
+const (
+	SkipMissingField SkipReason = "missing field"    // The project has no field of that name
+	SkipBadOption    SkipReason = "bad option"       // No single-select option, iteration or user matches the value
+	SkipConversion   SkipReason = "conversion error" // The value can't be converted to the field's type
+	SkipRejected     SkipReason = "rejected"         // GitHub rejected the value
+)
+
+// SkipReasons lists every SkipReason in the order they are reported
+var SkipReasons = []SkipReason{SkipMissingField, SkipBadOption, SkipConversion, SkipRejected}
+
+// FieldCount counts the values of a field set on items and skipped
+type FieldCount struct {
+	Set     int
+	Skipped map[SkipReason]int
+}
+
+// SkippedTotal returns how many values were skipped for any reason
+func (c FieldCount) SkippedTotal() int {
+	total := 0
+	for _, count := range c.Skipped {
+		total += count
+	}
+	return total
+}
+
+// SkipSummary describes the skipped values by reason, e.g. "2 bad option,
+// 1 conversion error"
+func (c FieldCount) SkipSummary() string {
+	var parts []string
+	for _, reason := range SkipReasons {
+		if n := c.Skipped[reason]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, reason))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FieldStats counts the field values of the imported items. The milestone
+// is set on the issue rather than as a field, and isn't counted. It is safe
+// for concurrent use.
+type FieldStats struct {
+	mu     sync.Mutex
+	fields map[string]*FieldCount
+}
+
+// NewFieldStats creates empty statistics
+func NewFieldStats() *FieldStats {
+	return &FieldStats{fields: make(map[string]*FieldCount)}
+}
+
+// count returns the count of a field, adding it when it is new. The caller
+// holds s.mu.
+func (s *FieldStats) count(field string) *FieldCount {
+	count, ok := s.fields[field]
+	if !ok {
+		count = &FieldCount{Skipped: make(map[SkipReason]int)}
+		s.fields[field] = count
+	}
+	return count
+}
+
+// recordSet counts a value set on an item
+func (s *FieldStats) recordSet(field string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count(field).Set++
+}
+
+// recordSkip counts a value skipped for reason
+func (s *FieldStats) recordSkip(field string, reason SkipReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count(field).Skipped[reason]++
+}
+
+// Fields returns the names of the fields with values, sorted
+func (s *FieldStats) Fields() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Field returns the count of a field
+func (s *FieldStats) Field(field string) FieldCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := FieldCount{Skipped: make(map[SkipReason]int)}
+	if c, ok := s.fields[field]; ok {
+		count.Set = c.Set
+		for reason, n := range c.Skipped {
+			count.Skipped[reason] = n
+		}
+	}
+	return count
+}
+
+// Total returns the counts of every field added up
+func (s *FieldStats) Total() FieldCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := FieldCount{Skipped: make(map[SkipReason]int)}
+	for _, c := range s.fields {
+		total.Set += c.Set
+		for reason, n := range c.Skipped {
+			total.Skipped[reason] += n
+		}
+	}
+	return total
+}
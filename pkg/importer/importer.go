@@ -0,0 +1,306 @@
+// Import of single items into a project
+// The Importer creates a project item for each source item and sets its fields, milestone, parent and linked pull requests
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// Options control how items are imported
+type Options struct {
+	// FallbackToDraft imports issues and pull requests whose URL can't be
+	// found as draft issues instead of failing them
+	FallbackToDraft bool
+	// CreateMilestones creates milestones that don't exist yet
+	CreateMilestones bool
+	// StrictOptions only matches single-select values to option names with
+	// the exact case
+	StrictOptions bool
+	// OptionAliases maps lowercased field names to lowercased aliases and the
+	// option each stands for, e.g. "status" → "wip" → "In Progress"
+	OptionAliases map[string]map[string]string
+	// ArchiveAfter lists the FIELD=PATTERN conditions of items to archive
+	// once imported
+	ArchiveAfter []string
+}
+
+// optionMatcher matches single-select values to options
+func (o Options) optionMatcher() mapping.OptionMatcher {
+	return mapping.OptionMatcher{Aliases: o.OptionAliases, Strict: o.StrictOptions}
+}
+
+// Importer imports items into one project. The users, milestones and parent
+// issues that items name are looked up once and remembered for the
+// importer's lifetime.
+type Importer struct {
+	client   ghclient.Client
+	project  *ghclient.Project
+	fieldMap map[string]ghclient.ProjectField
+	options  Options
+	archive  mapping.ItemFilter
+
+	Users      *mapping.UserResolver
+	Milestones *mapping.MilestoneResolver
+	Parents    *mapping.ParentResolver
+	// Fields counts the field values set and skipped
+	Fields *FieldStats
+	// Existing, when set, holds the project items that matching source
+	// items update instead of being created again
+	Existing *ExistingItems
+}
+
+// New creates an importer for project, whose fields are keyed by name
+func New(client ghclient.Client, project *ghclient.Project, fieldMap map[string]ghclient.ProjectField, options Options) (*Importer, error) {
+	archive, err := mapping.ParseItemFilter(options.ArchiveAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Importer{
+		client:     client,
+		project:    project,
+		fieldMap:   fieldMap,
+		options:    options,
+		archive:    archive,
+		Users:      mapping.NewUserResolver(client),
+		Milestones: mapping.NewMilestoneResolver(client, options.CreateMilestones),
+		Parents:    mapping.NewParentResolver(),
+		Fields:     NewFieldStats(),
+	}, nil
+}
+
+// ItemResult describes the outcome of importing a single item
+type ItemResult struct {
+	// ItemID is the ID of the project item, which may be set even when the
+	// import failed after the item was created
+	ItemID string
+	// MovedTo is the current URL of an issue or PR that was transferred to
+	// another repository, or whose repository was renamed, since the export
+	MovedTo string
+	// FellBackToDraft is set when the item's issue or PR couldn't be found
+	// and a draft issue was created instead
+	FellBackToDraft bool
+	// Updated is set when the item matched an existing project item, which
+	// was updated instead of creating a new one
+	Updated bool
+}
+
+// ImportItem imports a single item to the project, or updates the project
+// item it matches in Existing. Items that other items name as their parent
+// must be added with Parents.AddRows first.
+func (im *Importer) ImportItem(ctx context.Context, item parser.ImportItem) (ItemResult, error) {
+	if im.Existing != nil {
+		if existing, ok := im.Existing.Find(item); ok {
+			return im.updateItem(ctx, existing, item)
+		}
+	}
+
+	var result ItemResult
+	var itemID string
+	var err error
+	// contentURL is the issue or PR the item was created from, if any
+	var contentURL string
+
+	itemType := parser.GetItemType(item)
+
+	// Create the item based on its type
+	switch itemType {
+	case "DraftIssue":
+		itemID, err = im.client.CreateDraftIssue(ctx, im.project.ID, item.Title, parser.GetItemBody(item))
+	case "Issue", "PullRequest":
+		// For existing issues/PRs, we need to get their content ID and add them to the project
+		if item.URL == "" {
+			return result, fmt.Errorf("URL is required for existing issues and pull requests")
+		}
+
+		// Get the issue/PR content
+		var content map[string]interface{}
+		content, err = im.client.GetIssueOrPR(ctx, item.URL)
+		if errors.Is(err, ghclient.ErrContentNotFound) && im.options.FallbackToDraft {
+			slog.Debug("Issue not found, importing as a draft issue", "url", item.URL)
+			result.FellBackToDraft = true
+			itemID, err = im.client.CreateDraftIssue(ctx, im.project.ID, item.Title, fallbackDraftBody(item))
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to get issue/PR content: %w", err)
+		}
+
+		// The API follows redirects for transferred issues and renamed
+		// repositories, so the content may live somewhere else now
+		result.MovedTo = movedContentURL(item.URL, content)
+		contentURL = item.URL
+		if result.MovedTo != "" {
+			contentURL = result.MovedTo
+		}
+
+		// Extract the content ID (node_id)
+		contentID, ok := content["node_id"].(string)
+		if !ok {
+			return result, fmt.Errorf("could not extract content ID from issue/PR")
+		}
+
+		// Add the issue/PR to the project
+		itemID, err = im.client.CreateProjectItem(ctx, im.project.ID, contentID)
+	default:
+		return result, fmt.Errorf("unsupported item type: %s", itemType)
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("failed to create project item: %w", err)
+	}
+	result.ItemID = itemID
+
+	return result, im.completeItem(ctx, itemID, item, contentURL)
+}
+
+// updateItem brings the existing project item a source item matched up to
+// date: the title and body of a draft issue, and the field values, milestone
+// and parent the source gives
+func (im *Importer) updateItem(ctx context.Context, existing ghclient.ProjectItem, item parser.ImportItem) (ItemResult, error) {
+	result := ItemResult{ItemID: existing.ID, Updated: true}
+	slog.Debug("Updating matching project item", "title", item.Title, "id", existing.ID, "match", im.Existing.key)
+
+	contentURL := ghclient.GetString(existing.Content, "url")
+	if ghclient.GetString(existing.Content, "type") == "DraftIssue" {
+		body := parser.GetItemBody(item)
+		if item.Title != ghclient.GetString(existing.Content, "title") || body != ghclient.GetString(existing.Content, "body") {
+			if err := im.client.UpdateDraftIssue(ctx, im.project.ID, ghclient.GetString(existing.Content, "id"), item.Title, body); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, im.completeItem(ctx, existing.ID, item, contentURL)
+}
+
+// completeItem sets the milestone, parent, linked pull requests and field
+// values of a created or updated item, and archives it if it matches
+// --archive-after
+func (im *Importer) completeItem(ctx context.Context, itemID string, item parser.ImportItem, contentURL string) error {
+	mapping.SetItemMilestone(ctx, im.client, item, contentURL, im.Milestones)
+	mapping.SetItemParent(ctx, im.client, item, contentURL, im.Parents)
+	mapping.LinkPullRequests(ctx, im.client, item, contentURL)
+
+	// Set field values
+	if err := im.setItemFields(ctx, itemID, item); err != nil {
+		return err
+	}
+
+	if im.archive.Match(item.Fields) {
+		if err := im.client.ArchiveProjectItem(ctx, im.project.ID, itemID); err != nil {
+			return fmt.Errorf("failed to archive item: %w", err)
+		}
+		slog.Debug("Archived item", "title", item.Title, "id", itemID)
+	}
+	return nil
+}
+
+// movedContentURL returns the current URL of an issue or PR when it no longer
+// matches the URL in the source, or "" if it hasn't moved. Issues and pull
+// requests share numbers, so only the repository and number are compared.
+func movedContentURL(sourceURL string, content map[string]interface{}) string {
+	currentURL := ghclient.GetString(content, "html_url")
+	if currentURL == "" {
+		return ""
+	}
+
+	sourceOwner, sourceRepo, err := ghclient.ParseRepositoryURL(sourceURL)
+	if err != nil {
+		return ""
+	}
+	currentOwner, currentRepo, err := ghclient.ParseRepositoryURL(currentURL)
+	if err != nil {
+		return ""
+	}
+
+	sourceNumber, ok := ghclient.ParseContentNumber(sourceURL)
+	if !ok {
+		return ""
+	}
+	currentNumber, ok := ghclient.ParseContentNumber(currentURL)
+	if !ok {
+		return ""
+	}
+
+	if strings.EqualFold(sourceOwner+"/"+sourceRepo, currentOwner+"/"+currentRepo) && sourceNumber == currentNumber {
+		return ""
+	}
+	return currentURL
+}
+
+// fallbackDraftBody returns the body of a draft issue standing in for an
+// issue or PR that couldn't be found, keeping a record of the original URL
+func fallbackDraftBody(item parser.ImportItem) string {
+	body := parser.GetItemBody(item)
+	if body != "" {
+		body += "\n\n"
+	}
+	return body + "Originally imported from " + item.URL
+}
+
+// setItemFields sets field values for a project item. Logins in USER fields
+// are resolved through im.Users. Each value is counted in im.Fields.
+func (im *Importer) setItemFields(ctx context.Context, itemID string, item parser.ImportItem) error {
+	// Process all custom fields from the Fields map
+	for fieldName, fieldValue := range item.Fields {
+		// The milestone is set on the issue by SetItemMilestone
+		if mapping.IsMilestoneField(fieldName) {
+			continue
+		}
+
+		field, exists := im.fieldMap[fieldName]
+		if !exists {
+			slog.Debug("Field not found in project, skipping", "field", fieldName)
+			im.Fields.recordSkip(fieldName, SkipMissingField)
+			continue
+		}
+
+		if field.Type == "USER" {
+			ids, err := im.Users.Resolve(ctx, fieldValue)
+			if err != nil {
+				slog.Warn("Failed to resolve users, skipping field", "field", fieldName, "error", err)
+				if errors.Is(err, ghclient.ErrUserNotFound) {
+					im.Fields.recordSkip(fieldName, SkipBadOption)
+				} else {
+					im.Fields.recordSkip(fieldName, SkipConversion)
+				}
+				continue
+			}
+			fieldValue = ids
+		}
+
+		// Convert the field value to the appropriate format for GraphQL
+		convertedValue, err := mapping.ConvertFieldValueWith(fieldValue, field, im.options.optionMatcher())
+		if err != nil {
+			slog.Debug("Failed to convert field, skipping", "field", fieldName, "error", err)
+			if errors.Is(err, mapping.ErrNoSuchOption) {
+				im.Fields.recordSkip(fieldName, SkipBadOption)
+			} else {
+				im.Fields.recordSkip(fieldName, SkipConversion)
+			}
+			continue
+		}
+
+		// Set the field value
+		err = im.client.SetProjectItemFieldValue(ctx, im.project.ID, itemID, field.ID, convertedValue)
+		if err != nil {
+			slog.Warn("Failed to set field", "field", fieldName, "error", err)
+			im.Fields.recordSkip(fieldName, SkipRejected)
+			continue
+		}
+		im.Fields.recordSet(fieldName)
+
+		slog.Debug("Set field", "field", fieldName, "value", fieldValue)
+	}
+
+	return nil
+}
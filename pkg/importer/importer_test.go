@@ -0,0 +1,155 @@
+// Tests for importing single items
+package importer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestImportItemFallbackToDraft(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	project := client.AddProject("owner", ghclient.Project{Title: "Test Project"})
+	item := parser.ImportItem{
+		Title: "Moved issue",
+		URL:   "https://github.com/old-org/repo/issues/7",
+		Notes: "Original notes",
+	}
+
+	// Without the option the item fails
+	im, _ := New(client, &project.Project, nil, Options{})
+	if _, err := im.ImportItem(context.Background(), item); !errors.Is(err, ghclient.ErrContentNotFound) {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+
+	// With the option a draft carrying the URL is created instead
+	im, _ = New(client, &project.Project, nil, Options{FallbackToDraft: true})
+	result, err := im.ImportItem(context.Background(), item)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !result.FellBackToDraft || result.ItemID == "" {
+		t.Errorf("Expected a draft item, got %+v", result)
+	}
+	content := project.Items[0].Content
+	if content["title"] != "Moved issue" {
+		t.Errorf("Expected draft title 'Moved issue', got %v", content["title"])
+	}
+	expectedBody := "Original notes\n\nOriginally imported from https://github.com/old-org/repo/issues/7"
+	if content["body"] != expectedBody {
+		t.Errorf("Expected draft body %q, got %v", expectedBody, content["body"])
+	}
+}
+
+func TestMovedContentURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceURL string
+		htmlURL   string
+		expected  string
+	}{
+		{"same issue", "https://github.com/owner/repo/issues/1", "https://github.com/owner/repo/issues/1", ""},
+		{"case difference", "https://github.com/Owner/Repo/issues/1", "https://github.com/owner/repo/issues/1", ""},
+		{"pull request via issues URL", "https://github.com/owner/repo/issues/1", "https://github.com/owner/repo/pull/1", ""},
+		{"renamed repository", "https://github.com/owner/old/issues/1", "https://github.com/owner/new/issues/1", "https://github.com/owner/new/issues/1"},
+		{"transferred issue", "https://github.com/owner/repo/issues/1", "https://github.com/other/repo/issues/40", "https://github.com/other/repo/issues/40"},
+		{"no html_url", "https://github.com/owner/repo/issues/1", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := map[string]interface{}{}
+			if tt.htmlURL != "" {
+				content["html_url"] = tt.htmlURL
+			}
+			if got := movedContentURL(tt.sourceURL, content); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestImportItemResolvesUsers(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.Users["alice"] = "U_alice"
+	reviewer := ghclient.ProjectField{ID: "F_reviewer", Name: "Reviewer", Type: "USER"}
+	owner := ghclient.ProjectField{ID: "F_owner", Name: "Owner", Type: "USER"}
+	project := client.AddProject("owner", ghclient.Project{Title: "Test Project"}, reviewer, owner)
+	fieldMap := map[string]ghclient.ProjectField{"Reviewer": reviewer, "Owner": owner}
+	item := parser.ImportItem{Title: "Review", Fields: map[string]interface{}{"Reviewer": "alice", "Owner": "ghost"}}
+
+	im, _ := New(client, &project.Project, fieldMap, Options{})
+	if _, err := im.ImportItem(context.Background(), item); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{"Reviewer": []string{"alice"}}
+	if !reflect.DeepEqual(project.Items[0].Fields, expected) {
+		t.Errorf("Expected only the resolved reviewer to be set, got %v", project.Items[0].Fields)
+	}
+	if unknown := im.Users.Unknown(); !reflect.DeepEqual(unknown, []string{"ghost"}) {
+		t.Errorf("Expected ghost to be reported unknown, got %v", unknown)
+	}
+}
+
+func TestImportItemCountsFieldValues(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	status := ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_todo", Name: "Todo"}}}
+	estimate := ghclient.ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"}
+	owner := ghclient.ProjectField{ID: "F_owner", Name: "Owner", Type: "USER"}
+	project := client.AddProject("owner", ghclient.Project{Title: "Test Project"}, status, estimate, owner)
+	fieldMap := map[string]ghclient.ProjectField{"Status": status, "Estimate": estimate, "Owner": owner}
+
+	im, _ := New(client, &project.Project, fieldMap, Options{})
+	items := []parser.ImportItem{
+		{Title: "First", Fields: map[string]interface{}{"Status": "Todo", "Estimate": "3", "Team": "Core"}},
+		{Title: "Second", Fields: map[string]interface{}{"Status": "Someday", "Estimate": "lots", "Owner": "ghost", "Milestone": "v1"}},
+	}
+	for _, item := range items {
+		if _, err := im.ImportItem(context.Background(), item); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if names := im.Fields.Fields(); !reflect.DeepEqual(names, []string{"Estimate", "Owner", "Status", "Team"}) {
+		t.Errorf("Unexpected fields %v", names)
+	}
+	if count := im.Fields.Field("Status"); count.Set != 1 || count.SkipSummary() != "1 bad option" {
+		t.Errorf("Unexpected Status count %+v", count)
+	}
+	if count := im.Fields.Field("Estimate"); count.Set != 1 || count.SkipSummary() != "1 conversion error" {
+		t.Errorf("Unexpected Estimate count %+v", count)
+	}
+	total := im.Fields.Total()
+	if total.Set != 2 || total.SkippedTotal() != 4 || total.SkipSummary() != "1 missing field, 2 bad option, 1 conversion error" {
+		t.Errorf("Unexpected total %+v (%s)", total, total.SkipSummary())
+	}
+}
+
+func TestImportItemArchiveAfter(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	project := client.AddProject("owner", ghclient.Project{Title: "Test Project"})
+
+	im, err := New(client, &project.Project, nil, Options{ArchiveAfter: []string{"Status=Done"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []string{"Todo", "done"} {
+		item := parser.ImportItem{Title: status, Fields: map[string]interface{}{"Status": status}}
+		if _, err := im.ImportItem(context.Background(), item); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if project.Items[0].Archived || !project.Items[1].Archived {
+		t.Errorf("Expected only the done item to be archived, got %+v", project.Items)
+	}
+
+	if _, err := New(client, &project.Project, nil, Options{ArchiveAfter: []string{"Status"}}); err == nil {
+		t.Error("Expected an error for a condition without a pattern")
+	}
+}
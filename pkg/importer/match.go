@@ -0,0 +1,124 @@
+// Matching source items to project items
+// Finds the project item a source item already stands for, by URL, title or a field such as an external ID (--match-key)
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// MatchKey is what a source item and a project item must share to be the
+// same item: their issue or pull request URL, their title, or the value of a
+// field
+type MatchKey struct {
+	// Kind is "url", "title" or "field"
+	Kind string
+	// Field is the field compared for the "field" kind
+	Field string
+}
+
+// ParseMatchKey parses a match key, one of url, title or field:NAME
+func ParseMatchKey(value string) (MatchKey, error) {
+	switch value {
+	case "url", "title":
+		return MatchKey{Kind: value}, nil
+	}
+	if name, ok := strings.CutPrefix(value, "field:"); ok && strings.TrimSpace(name) != "" {
+		return MatchKey{Kind: "field", Field: strings.TrimSpace(name)}, nil
+	}
+	return MatchKey{}, fmt.Errorf("invalid match key %q (expected url, title or field:NAME)", value)
+}
+
+func (k MatchKey) String() string {
+	if k.Kind == "field" {
+		return "field:" + k.Field
+	}
+	return k.Kind
+}
+
+// sourceKey returns the key of a source item, "" if it has none
+func (k MatchKey) sourceKey(item parser.ImportItem) string {
+	switch k.Kind {
+	case "url":
+		return normalizeKey(item.URL, true)
+	case "title":
+		return normalizeKey(item.Title, true)
+	case "field":
+		if value, ok := item.Fields[k.Field]; ok && value != nil {
+			return normalizeKey(fmt.Sprint(value), false)
+		}
+	}
+	return ""
+}
+
+// projectKey returns the key of a project item, "" if it has none
+func (k MatchKey) projectKey(item ghclient.ProjectItem) string {
+	switch k.Kind {
+	case "url":
+		return normalizeKey(ghclient.GetString(item.Content, "url"), true)
+	case "title":
+		return normalizeKey(ghclient.GetString(item.Content, "title"), true)
+	case "field":
+		if value, ok := item.Fields[k.Field]; ok && value != nil {
+			return normalizeKey(fmt.Sprint(value), false)
+		}
+	}
+	return ""
+}
+
+// normalizeKey trims a key and, when fold is set, ignores its case and any
+// trailing slash. Field values such as external IDs may be case sensitive
+// and aren't folded.
+func normalizeKey(key string, fold bool) string {
+	key = strings.TrimSpace(key)
+	if fold {
+		key = strings.ToLower(strings.TrimSuffix(key, "/"))
+	}
+	return key
+}
+
+// ExistingItems are the items of a project keyed by a MatchKey, which
+// ImportItem updates instead of creating the source items that match them
+type ExistingItems struct {
+	key   MatchKey
+	items map[string]ghclient.ProjectItem
+	// Duplicates counts the project items whose key an earlier item already
+	// has; source items only ever update the first
+	Duplicates int
+}
+
+// IndexProjectItems keys the items of a project by key. Items without a key
+// can't be matched and are left out.
+func IndexProjectItems(items []ghclient.ProjectItem, key MatchKey) *ExistingItems {
+	existing := &ExistingItems{key: key, items: make(map[string]ghclient.ProjectItem)}
+	for _, item := range items {
+		k := key.projectKey(item)
+		if k == "" {
+			continue
+		}
+		if _, ok := existing.items[k]; ok {
+			existing.Duplicates++
+			continue
+		}
+		existing.items[k] = item
+	}
+	return existing
+}
+
+// Find returns the project item a source item matches
+func (e *ExistingItems) Find(item parser.ImportItem) (ghclient.ProjectItem, bool) {
+	k := e.key.sourceKey(item)
+	if k == "" {
+		return ghclient.ProjectItem{}, false
+	}
+	existing, ok := e.items[k]
+	return existing, ok
+}
+
+// Len returns the number of items that can be matched
+func (e *ExistingItems) Len() int {
+	return len(e.items)
+}
@@ -0,0 +1,112 @@
+// Tests for matching source items to project items
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestParseMatchKey(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected MatchKey
+		wantErr  bool
+	}{
+		{"url", MatchKey{Kind: "url"}, false},
+		{"title", MatchKey{Kind: "title"}, false},
+		{"field:ExternalID", MatchKey{Kind: "field", Field: "ExternalID"}, false},
+		{"field: Jira Key ", MatchKey{Kind: "field", Field: "Jira Key"}, false},
+		{"field:", MatchKey{}, true},
+		{"URL", MatchKey{}, true},
+		{"", MatchKey{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			key, err := ParseMatchKey(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if key != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestExistingItemsFind(t *testing.T) {
+	items := []ghclient.ProjectItem{
+		{ID: "PVTI_1", Content: map[string]interface{}{"type": "Issue", "title": "Fix login", "url": "https://github.com/owner/repo/issues/1"}, Fields: map[string]interface{}{"Jira Key": "ABC-1"}},
+		{ID: "PVTI_2", Content: map[string]interface{}{"type": "DraftIssue", "title": "Write docs"}, Fields: map[string]interface{}{"Jira Key": "ABC-2"}},
+		{ID: "PVTI_3", Content: map[string]interface{}{"type": "DraftIssue", "title": "write docs "}},
+	}
+
+	tests := []struct {
+		key      string
+		item     parser.ImportItem
+		expected string
+	}{
+		{"url", parser.ImportItem{URL: "https://github.com/Owner/Repo/issues/1/"}, "PVTI_1"},
+		{"url", parser.ImportItem{Title: "Write docs"}, ""},
+		{"title", parser.ImportItem{Title: "WRITE DOCS"}, "PVTI_2"},
+		{"title", parser.ImportItem{Title: "Something else"}, ""},
+		{"field:Jira Key", parser.ImportItem{Fields: map[string]interface{}{"Jira Key": " ABC-2"}}, "PVTI_2"},
+		{"field:Jira Key", parser.ImportItem{Fields: map[string]interface{}{"Jira Key": "abc-2"}}, ""},
+		{"field:Jira Key", parser.ImportItem{Title: "Fix login"}, ""},
+	}
+
+	for _, tt := range tests {
+		key, _ := ParseMatchKey(tt.key)
+		existing := IndexProjectItems(items, key)
+		found, ok := existing.Find(tt.item)
+		if found.ID != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("%s: expected %q to match %q, got %q", tt.key, tt.item.Title, tt.expected, found.ID)
+		}
+	}
+
+	titles := IndexProjectItems(items, MatchKey{Kind: "title"})
+	if titles.Len() != 2 || titles.Duplicates != 1 {
+		t.Errorf("Expected 2 titles and 1 duplicate, got %d and %d", titles.Len(), titles.Duplicates)
+	}
+}
+
+func TestImportItemUpdatesMatchingItem(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	key := ghclient.ProjectField{ID: "F_key", Name: "Jira Key", Type: "TEXT"}
+	status := ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "TEXT"}
+	project := client.AddProject("owner", ghclient.Project{Title: "Test Project"}, key, status)
+	fieldMap := map[string]ghclient.ProjectField{"Jira Key": key, "Status": status}
+
+	im, _ := New(client, &project.Project, fieldMap, Options{})
+	first := parser.ImportItem{Title: "Old title", Fields: map[string]interface{}{"Jira Key": "ABC-1", "Status": "Todo"}}
+	if _, err := im.ImportItem(context.Background(), first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items, err := client.ListProjectItems(context.Background(), project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im.Existing = IndexProjectItems(items, MatchKey{Kind: "field", Field: "Jira Key"})
+	again := parser.ImportItem{Title: "New title", Fields: map[string]interface{}{"Jira Key": "ABC-1", "Status": "Done"}}
+	result, err := im.ImportItem(context.Background(), again)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.Updated || result.ItemID != items[0].ID {
+		t.Errorf("Expected %s to be updated, got %+v", items[0].ID, result)
+	}
+	if len(project.Items) != 1 {
+		t.Fatalf("Expected the item not to be added again, got %d items", len(project.Items))
+	}
+	if title := project.Items[0].Content["title"]; title != "New title" {
+		t.Errorf("Expected the draft to be renamed, got %v", title)
+	}
+	if value := project.Items[0].Fields["Status"]; value != "Done" {
+		t.Errorf("Expected Status Done, got %v", value)
+	}
+}
@@ -0,0 +1,115 @@
+// Library entry points for embedding the importer
+// ParseSource reads items, Plan checks them against a project without changing it, and Apply imports them
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjeffryes/gh-project-import/internal/mapping"
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+// ParseSource reads and validates the items of a source: a JSON, NDJSON, CSV,
+// TOML or Markdown file, glob pattern or URL, optionally compressed
+func ParseSource(source string, options parser.SourceOptions) ([]parser.ImportItem, error) {
+	items, err := parser.ParseSources([]string{source}, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := parser.ValidateImportItems(items); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return items, nil
+}
+
+// ImportPlan is an import checked against its destination project, which
+// Apply carries out
+type ImportPlan struct {
+	Project *ghclient.Project
+	// Fields are the project's fields keyed by name
+	Fields  map[string]ghclient.ProjectField
+	Items   []parser.ImportItem
+	Options Options
+
+	// Errors lists values that will make their item fail to import, and
+	// Warnings the values that will be skipped
+	Errors   []string
+	Warnings []string
+}
+
+// Plan looks up the project named by identifier (owner/project-name or a
+// project number) and checks the value of every item's fields against it.
+// Nothing is changed.
+func Plan(ctx context.Context, client ghclient.Client, identifier string, items []parser.ImportItem, options Options) (*ImportPlan, error) {
+	if _, err := mapping.ParseItemFilter(options.ArchiveAfter); err != nil {
+		return nil, err
+	}
+
+	project, err := client.FindProject(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	fields, err := client.GetProjectFields(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	plan := &ImportPlan{
+		Project: project,
+		Fields:  make(map[string]ghclient.ProjectField),
+		Items:   items,
+		Options: options,
+	}
+	for _, field := range fields {
+		plan.Fields[field.Name] = field
+	}
+
+	for _, issue := range mapping.ValidateEveryItemField(items, plan.Fields, options.optionMatcher(), false) {
+		switch issue.Severity {
+		case mapping.SeverityError:
+			plan.Errors = append(plan.Errors, issue.Message)
+		case mapping.SeverityWarning:
+			plan.Warnings = append(plan.Warnings, issue.Message)
+		}
+	}
+	return plan, nil
+}
+
+// Result is the outcome of Apply
+type Result struct {
+	// ItemIDs holds the project item ID of each planned item, in order, or
+	// "" for items that weren't created
+	ItemIDs []string
+	// Failed maps the positions of the items that failed to their error
+	Failed map[int]error
+}
+
+// Apply imports the planned items, carrying on past items that fail. It
+// stops early when ctx is done, returning the items imported so far.
+func Apply(ctx context.Context, client ghclient.Client, plan *ImportPlan) (*Result, error) {
+	im, err := New(client, plan.Project, plan.Fields, plan.Options)
+	if err != nil {
+		return nil, err
+	}
+	im.Parents.AddRows(plan.Items)
+
+	result := &Result{ItemIDs: make([]string, len(plan.Items)), Failed: make(map[int]error)}
+	for i, item := range plan.Items {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("import stopped early: %w", ctx.Err())
+		}
+
+		itemResult, err := im.ImportItem(ctx, item)
+		result.ItemIDs[i] = itemResult.ItemID
+		if err != nil {
+			result.Failed[i] = err
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("failed to import %d of %d items", len(result.Failed), len(plan.Items))
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+// Tests for planning and applying imports
+package importer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+	"github.com/mjeffryes/gh-project-import/pkg/parser"
+)
+
+func TestPlanAndApply(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv")
+	csv := "Title,Status,Estimate\nPlan launch,in progress,3\nWrite docs,Blocked,1\n"
+	if err := os.WriteFile(source, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := ParseSource(source, parser.SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := ghclient.NewFakeClient()
+	project := client.AddProject("owner", ghclient.Project{Title: "Roadmap"},
+		ghclient.ProjectField{ID: "F_status", Name: "Status", Type: "SINGLE_SELECT", Options: []ghclient.ProjectFieldOption{{ID: "O_progress", Name: "In Progress"}}},
+		ghclient.ProjectField{ID: "F_estimate", Name: "Estimate", Type: "NUMBER"},
+	)
+
+	plan, err := Plan(context.Background(), client, "owner/Roadmap", items, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "Blocked") {
+		t.Errorf("Expected a warning for the unknown option, got %v", plan.Warnings)
+	}
+	if len(project.Items) != 0 {
+		t.Fatal("Expected planning to change nothing")
+	}
+
+	result, err := Apply(context.Background(), client, plan)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.ItemIDs) != 2 || len(project.Items) != 2 {
+		t.Fatalf("Expected 2 imported items, got %v", result.ItemIDs)
+	}
+	if status := project.Items[0].Fields["Status"]; status != "In Progress" {
+		t.Errorf("Expected the option to be matched ignoring case, got %v", status)
+	}
+	if _, set := project.Items[1].Fields["Status"]; set {
+		t.Errorf("Expected the unknown option to be skipped, got %v", project.Items[1].Fields)
+	}
+}
+
+func TestApplyReportsFailedItems(t *testing.T) {
+	client := ghclient.NewFakeClient()
+	client.AddProject("owner", ghclient.Project{Title: "Roadmap"})
+	items := []parser.ImportItem{
+		{Title: "Draft"},
+		{Title: "Missing", URL: "https://github.com/owner/repo/issues/9"},
+	}
+
+	plan, err := Plan(context.Background(), client, "owner/Roadmap", items, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := Apply(context.Background(), client, plan)
+	if err == nil || !strings.Contains(err.Error(), "failed to import 1 of 2 items") {
+		t.Errorf("Expected the failed item to be reported, got: %v", err)
+	}
+	if result.ItemIDs[0] == "" || !errors.Is(result.Failed[1], ghclient.ErrContentNotFound) {
+		t.Errorf("Expected only the missing issue to fail, got %+v", result)
+	}
+}
@@ -0,0 +1,132 @@
+// CSV source updates
+// Rewrites cells of a CSV source in place, for values that flow back from a project
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bodyColumns are the columns an item's body is read from, in the order
+// UpdateCSVFile looks for them
+var bodyColumns = []string{"body", "description", "notes"}
+
+// UpdateCSVFile rewrites cells of a CSV source in place. fn is called with
+// each data row, read as an item, and returns the row's new values keyed by
+// column name, matched case-insensitively; "body" names the body,
+// description or notes column. Values of columns the file doesn't have are
+// ignored. The file is only rewritten when a cell changes, keeping its
+// delimiter but not any comment lines above the header row. It returns the
+// number of cells changed.
+//
+// Only UTF-8 files with a header row can be updated.
+func UpdateCSVFile(filename string, dialect CSVDialect, fn func(ImportItem) map[string]string) (int, error) {
+	if dialect.NoHeader {
+		return 0, fmt.Errorf("cannot update %s: CSV files without a header row can't be updated", filename)
+	}
+	if enc := strings.ToLower(strings.TrimSpace(dialect.Encoding)); enc != "" && enc != "utf-8" && enc != "utf8" {
+		return 0, fmt.Errorf("cannot update %s: only UTF-8 CSV files can be updated", filename)
+	}
+	delimiter, err := dialect.delimiter()
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	reader, err := dialect.newReader(file)
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read CSV file %s: %w", filename, err)
+	}
+	if len(records) == 0 {
+		return 0, fmt.Errorf("CSV file must have at least a header row and one data row")
+	}
+
+	headers := records[0]
+	columns := make(map[string]int, len(headers))
+	for i, header := range headers {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	for _, name := range bodyColumns {
+		if i, ok := columns[name]; ok {
+			columns["body"] = i
+			break
+		}
+	}
+
+	changed := 0
+	for row, record := range records[1:] {
+		if len(record) != len(headers) {
+			return 0, fmt.Errorf("row %d has %d fields, expected %d", row+2, len(record), len(headers))
+		}
+		item, err := convertCSVRecordToImportItem(headers, record)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse CSV row %d: %w", row+2, err)
+		}
+		for name, value := range fn(item) {
+			i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+			if ok && record[i] != value {
+				record[i] = value
+				changed++
+			}
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = delimiter
+	if err := writer.WriteAll(records); err != nil {
+		return 0, fmt.Errorf("failed to write CSV file %s: %w", filename, err)
+	}
+	if err := replaceFile(filename, &buf); err != nil {
+		return 0, fmt.Errorf("failed to write CSV file %s: %w", filename, err)
+	}
+	return changed, nil
+}
+
+// replaceFile replaces the contents of path through a temporary file in the
+// same directory, keeping its permissions
+func replaceFile(path string, r io.Reader) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,50 @@
+// Tests for CSV source updates
+package parser
+
+import (
+	"os"
+	"testing"
+)
+
+func TestUpdateCSVFile(t *testing.T) {
+	path := writeCSVBytes(t, "# Exported from the tracker\nTitle;Status;Description\nPlan launch;Todo;Old notes\nWrite docs;Todo;\n")
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := UpdateCSVFile(path, CSVDialect{Delimiter: ";"}, func(item ImportItem) map[string]string {
+		if item.Title != "Plan launch" {
+			return map[string]string{"status": "Todo"}
+		}
+		return map[string]string{"Status": "Done; shipped", "body": "New notes", "Estimate": "3"}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 changed cells, got %d", changed)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Title;Status;Description\nPlan launch;\"Done; shipped\";New notes\nWrite docs;Todo;\n"
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0640 {
+		t.Errorf("Expected the file to keep its permissions, got %v", info.Mode().Perm())
+	}
+
+	// A file without changes is left alone
+	if changed, err := UpdateCSVFile(path, CSVDialect{Delimiter: ";"}, func(ImportItem) map[string]string { return nil }); err != nil || changed != 0 {
+		t.Errorf("Expected no changes, got %d and %v", changed, err)
+	}
+
+	for _, dialect := range []CSVDialect{{NoHeader: true}, {Encoding: "latin-1"}} {
+		if _, err := UpdateCSVFile(path, dialect, func(ImportItem) map[string]string { return nil }); err == nil {
+			t.Errorf("Expected %+v to be rejected", dialect)
+		}
+	}
+}
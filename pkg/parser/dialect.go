@@ -0,0 +1,139 @@
+// CSV dialects
+// Handles the delimiters, quoting, header rows and text encodings of CSV files written by spreadsheets and other tools
+package parser
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// CSVDialect describes how a CSV source is written. The zero value reads
+// comma-separated UTF-8 with a header row.
+type CSVDialect struct {
+	Delimiter  string // Field delimiter: a single character, or "tab" (default ",")
+	Encoding   string // Text encoding, one of CSVEncodingNames (default utf-8)
+	NoHeader   bool   // The first row is data; columns are named by position: 1, 2, 3...
+	LazyQuotes bool   // Accept stray quotes in unquoted fields and unescaped quotes in quoted fields
+}
+
+// csvEncodings maps --encoding names to their decoders. A byte order mark
+// always takes precedence, so UTF-16 exports are read whatever is chosen.
+var csvEncodings = map[string]encoding.Encoding{
+	"utf-8":        encoding.Nop,
+	"utf-16":       unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	"latin-1":      charmap.ISO8859_1,
+	"windows-1252": charmap.Windows1252,
+}
+
+// csvEncodingAliases are other common spellings of the encoding names
+var csvEncodingAliases = map[string]string{
+	"utf8":       "utf-8",
+	"utf16":      "utf-16",
+	"latin1":     "latin-1",
+	"iso-8859-1": "latin-1",
+	"cp1252":     "windows-1252",
+}
+
+// CSVEncodingNames lists the names accepted by --encoding
+func CSVEncodingNames() []string {
+	return []string{"utf-8", "utf-16", "utf-16le", "utf-16be", "latin-1", "windows-1252"}
+}
+
+// Validate checks the delimiter and encoding names
+func (d CSVDialect) Validate() error {
+	if _, err := d.delimiter(); err != nil {
+		return err
+	}
+	_, err := d.encoding()
+	return err
+}
+
+// delimiter returns the field delimiter rune
+func (d CSVDialect) delimiter() (rune, error) {
+	switch strings.ToLower(d.Delimiter) {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	delimiter, size := utf8.DecodeRuneInString(d.Delimiter)
+	if size != len(d.Delimiter) || delimiter == utf8.RuneError || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return 0, fmt.Errorf("invalid --delimiter %q (expected a single character other than a quote, or tab)", d.Delimiter)
+	}
+	return delimiter, nil
+}
+
+// encoding returns the decoder for the text encoding
+func (d CSVDialect) encoding() (encoding.Encoding, error) {
+	name := strings.ToLower(strings.TrimSpace(d.Encoding))
+	if name == "" {
+		name = "utf-8"
+	}
+	if alias, ok := csvEncodingAliases[name]; ok {
+		name = alias
+	}
+	enc, ok := csvEncodings[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported --encoding %q (expected one of %s)", d.Encoding, strings.Join(CSVEncodingNames(), ", "))
+	}
+	return enc, nil
+}
+
+// newReader returns a CSV reader that decodes r to UTF-8, dropping any
+// byte order mark and the comment lines above the header row
+func (d CSVDialect) newReader(r io.Reader) (*csv.Reader, error) {
+	delimiter, err := d.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	enc, err := d.encoding()
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := bufio.NewReader(transform.NewReader(r, unicode.BOMOverride(enc.NewDecoder())))
+	if !d.NoHeader {
+		if err := skipCommentLines(decoded); err != nil {
+			return nil, err
+		}
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.Comma = delimiter
+	reader.LazyQuotes = d.LazyQuotes
+	return reader, nil
+}
+
+// skipCommentLines discards the lines at the start of r that begin with "#"
+// followed by a space or the end of the line, such as the notes written by
+// the template subcommand. A header named "#" is kept.
+func skipCommentLines(r *bufio.Reader) error {
+	for {
+		// A read error is left for the CSV reader to report
+		prefix, _ := r.Peek(2)
+		if len(prefix) == 0 || prefix[0] != '#' {
+			return nil
+		}
+		if len(prefix) == 2 && !strings.ContainsRune(" \t\r\n", rune(prefix[1])) {
+			return nil
+		}
+		if _, err := r.ReadString('\n'); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Tests for CSV dialects
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// writeCSVBytes writes raw CSV content to a temporary file
+func writeCSVBytes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	return path
+}
+
+func TestCSVDialectDelimiters(t *testing.T) {
+	tests := []struct {
+		delimiter string
+		content   string
+	}{
+		{";", "Title;Status\nCafé;Todo\n"},
+		{"tab", "Title\tStatus\nCafé\tTodo\n"},
+		{`\t`, "Title\tStatus\nCafé\tTodo\n"},
+		{"|", "Title|Status\nCafé|Todo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.delimiter, func(t *testing.T) {
+			items, err := ParseSourceFile(writeCSVBytes(t, tt.content), SourceOptions{CSV: CSVDialect{Delimiter: tt.delimiter}})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(items) != 1 || items[0].Title != "Café" || items[0].Fields["Status"] != "Todo" {
+				t.Errorf("Unexpected items: %+v", items)
+			}
+		})
+	}
+}
+
+func TestCSVDialectEncodings(t *testing.T) {
+	content := "Title,Status\nRésumé review,Todo\n"
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latin1, err := charmap.ISO8859_1.NewEncoder().String(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		encoding string
+	}{
+		{"UTF-8 with BOM", "\ufeff" + content, ""},
+		{"UTF-16 detected by its BOM", utf16, ""},
+		{"UTF-16 given explicitly", utf16, "utf-16"},
+		{"Latin-1", latin1, "latin1"},
+		{"Windows-1252", latin1, "windows-1252"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseSourceFile(writeCSVBytes(t, tt.content), SourceOptions{CSV: CSVDialect{Encoding: tt.encoding}})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(items) != 1 || items[0].Title != "Résumé review" || items[0].Fields["Status"] != "Todo" {
+				t.Errorf("Unexpected items: %+v", items)
+			}
+		})
+	}
+}
+
+func TestCSVDialectNoHeader(t *testing.T) {
+	source := writeCSVBytes(t, "Fix login,Todo,3\nWrite docs,Done,1\n")
+
+	items, err := ParseSourceFile(source, SourceOptions{
+		Columns: map[string]string{"1": "Title", "2": "Status"},
+		CSV:     CSVDialect{NoHeader: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "Fix login" || items[1].Fields["Status"] != "Done" || items[1].Fields["3"] != int64(1) {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	// Without a mapping there is no title column
+	if _, err := ParseSourceFile(source, SourceOptions{CSV: CSVDialect{NoHeader: true}}); err == nil || !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("Expected the first row to be reported as missing a title, got %v", err)
+	}
+}
+
+func TestCSVDialectLazyQuotes(t *testing.T) {
+	source := writeCSVBytes(t, "Title,Status\nThe \"new\" login,Todo\n")
+
+	if _, err := ParseSourceFile(source, SourceOptions{}); err == nil {
+		t.Error("Expected a stray quote to fail without --lazy-quotes")
+	}
+	items, err := ParseSourceFile(source, SourceOptions{CSV: CSVDialect{LazyQuotes: true}})
+	if err != nil || len(items) != 1 || items[0].Title != `The "new" login` {
+		t.Errorf("Expected the quotes to be kept, got %+v, %v", items, err)
+	}
+}
+
+func TestCSVDialectValidate(t *testing.T) {
+	valid := []CSVDialect{{}, {Delimiter: ";"}, {Delimiter: "TAB"}, {Encoding: "UTF-16BE"}, {Encoding: "iso-8859-1"}}
+	for _, dialect := range valid {
+		if err := dialect.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", dialect, err)
+		}
+	}
+
+	invalid := []CSVDialect{{Delimiter: ";;"}, {Delimiter: `"`}, {Delimiter: "\n"}, {Encoding: "ebcdic"}}
+	for _, dialect := range invalid {
+		if err := dialect.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", dialect)
+		}
+	}
+}
+
+func TestCSVCommentLinesAboveHeader(t *testing.T) {
+	content := "# Status: one of: Todo | Done\n#\n# notes\r\n#,Title\n1,# Not a comment\n"
+	items, err := ParseSourceFile(writeCSVBytes(t, content), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "# Not a comment" || items[0].Fields["#"] != int64(1) {
+		t.Errorf("Expected the comments skipped and the # column kept, got %+v", items)
+	}
+}
@@ -0,0 +1,162 @@
+// Markdown table sources
+// Reads items from the GitHub-flavored Markdown tables of a document such as a repository's BACKLOG.md
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// markdownDelimiterCell matches a cell of a table's delimiter row, e.g. --- or :---:
+var markdownDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+// markdownLineBreak matches the <br> tags used for line breaks inside cells
+var markdownLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// ParseMarkdownFile parses the Markdown tables of a file
+func ParseMarkdownFile(filename string) ([]ImportItem, error) {
+	var items []ImportItem
+	err := streamMarkdownFile(filename, nil, func(item ImportItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// streamMarkdownFile reads every table with a Title column, after renaming
+// columns according to headerMap, and calls fn for each row. Each table has
+// its own header row, and text around the tables, other tables and tables in
+// code blocks are ignored. Cells are converted like CSV cells. An error from
+// fn stops reading and is returned unchanged.
+func streamMarkdownFile(filename string, headerMap map[string]string, fn func(ImportItem) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	var (
+		lineNum  int
+		fence    string   // The fence of the code block being skipped
+		previous string   // The previous line, a possible header row
+		headers  []string // The header of the table being read, or nil
+		tables   int      // Tables with a Title column
+	)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence, headers, previous = line[:3], nil, ""
+			continue
+		}
+
+		if headers != nil {
+			if line == "" || !strings.Contains(line, "|") {
+				headers = nil
+			} else {
+				record := splitMarkdownRow(line)
+				// Rows are padded or cut to the width of the header
+				record = append(record, make([]string, max(0, len(headers)-len(record)))...)[:len(headers)]
+				item, err := convertCSVRecordToImportItem(headers, record)
+				if err != nil {
+					return fmt.Errorf("failed to parse Markdown table row on line %d: %w", lineNum, err)
+				}
+				if err := fn(item); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if isMarkdownDelimiterRow(line) && strings.Contains(previous, "|") {
+			header := splitMarkdownRow(previous)
+			if len(header) == len(splitMarkdownRow(line)) {
+				mapped := mapCSVHeaders(header, headerMap)
+				if hasTitleColumn(mapped) {
+					headers = mapped
+					tables++
+				}
+			}
+		}
+		previous = line
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	if tables == 0 {
+		return fmt.Errorf("no Markdown table with a Title column found in %s", filename)
+	}
+	return nil
+}
+
+// splitMarkdownRow splits a table row into its trimmed cells. Escaped pipes
+// (\|) are kept in the cell, and <br> tags become line breaks.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	cells = append(cells, cell.String())
+
+	for i, c := range cells {
+		cells[i] = markdownLineBreak.ReplaceAllString(strings.TrimSpace(c), "\n")
+	}
+	return cells
+}
+
+// isMarkdownDelimiterRow reports whether a line is the delimiter row below a
+// table's header, e.g. | --- | :---: |
+func isMarkdownDelimiterRow(line string) bool {
+	if !strings.Contains(line, "-") {
+		return false
+	}
+	for _, cell := range splitMarkdownRow(line) {
+		if !markdownDelimiterCell.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTitleColumn reports whether a table header has a Title column
+func hasTitleColumn(headers []string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(strings.TrimSpace(header), "title") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Tests for Markdown table sources
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const backlogMarkdown = "# Backlog\n" +
+	"\n" +
+	"Legend:\n" +
+	"\n" +
+	"| Symbol | Meaning |\n" +
+	"| ------ | ------- |\n" +
+	"| 🔥     | Urgent  |\n" +
+	"\n" +
+	"## Now\n" +
+	"\n" +
+	"| Title | Status | Estimate | Body |\n" +
+	"|:------|:------:|---------:|------|\n" +
+	"| Add login | Todo | 3 | First line<br>Second line |\n" +
+	"| Support `a \\| b` | In Progress | | |\n" +
+	"Short row | Done\n" +
+	"\n" +
+	"```markdown\n" +
+	"| Title | Status |\n" +
+	"| ----- | ------ |\n" +
+	"| Example only | Todo |\n" +
+	"```\n" +
+	"\n" +
+	"## Later\n" +
+	"\n" +
+	"| Title | Assignees |\n" +
+	"| --- | --- |\n" +
+	"| Write docs | octocat, hubot |\n"
+
+func TestParseMarkdownFile(t *testing.T) {
+	items, err := ParseSourceFile(writeSourceFile(t, "BACKLOG.md", backlogMarkdown), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	want := []string{"Add login", "Support `a | b`", "Short row", "Write docs"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Fatalf("Expected items %v, got %v", want, titles)
+	}
+
+	if items[0].Content.Body != "First line\nSecond line" || items[0].Fields["Estimate"] != int64(3) {
+		t.Errorf("Unexpected first item: %+v", items[0])
+	}
+	if items[2].Fields["Status"] != "Done" {
+		t.Errorf("Expected a row without outer pipes to be read, got %+v", items[2])
+	}
+	if !reflect.DeepEqual(items[3].Assignees, []string{"octocat", "hubot"}) {
+		t.Errorf("Expected the second table to have its own columns, got %+v", items[3])
+	}
+}
+
+func TestParseMarkdownFileWithMapping(t *testing.T) {
+	source := writeSourceFile(t, "backlog.markdown", "| Task | State |\n| --- | --- |\n| Ship it | Done |\n")
+
+	if _, err := ParseSourceFile(source, SourceOptions{}); err == nil || !strings.Contains(err.Error(), "no Markdown table with a Title column") {
+		t.Errorf("Expected an error without a Title column, got: %v", err)
+	}
+
+	items, err := ParseSourceFile(source, SourceOptions{Columns: map[string]string{"task": "title", "state": "Status"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Ship it" || items[0].Fields["Status"] != "Done" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+}
+
+func TestSplitMarkdownRow(t *testing.T) {
+	tests := map[string][]string{
+		"| a | b |":       {"a", "b"},
+		"a | b":           {"a", "b"},
+		"| a \\| b | c |": {"a | b", "c"},
+		"| a | |":         {"a", ""},
+		"| x<BR/>y |":     {"x\ny"},
+	}
+	for line, want := range tests {
+		if got := splitMarkdownRow(line); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitMarkdownRow(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
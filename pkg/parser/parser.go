@@ -0,0 +1,576 @@
+// File parsing utilities for JSON and CSV import files
+// Handles parsing and validation of input data for project import
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mjeffryes/gh-project-import/pkg/ghclient"
+)
+
+// ErrorFileColumn holds the import error of each row in an error file. The
+// parsers ignore it, so an error file can be used as a source directly.
+const ErrorFileColumn = "import_error"
+
+// ImportItem represents a project item to be imported
+type ImportItem struct {
+	ID         string                 `json:"id,omitempty"` // Project item ID when read from a project export, or the ID other rows give as their parent
+	Title      string                 `json:"title"`
+	URL        string                 `json:"url,omitempty"`
+	Content    ItemContent            `json:"content,omitempty"`
+	Assignees  []string               `json:"assignees,omitempty"`
+	Repository string                 `json:"repository,omitempty"`
+	Labels     []string               `json:"labels,omitempty"`
+	Notes      string                 `json:"notes,omitempty"`
+	Parent     string                 `json:"parent,omitempty"`
+	LinkedPRs  []string               `json:"linked_prs,omitempty"`
+	Fields     map[string]interface{} `json:"-"` // All other fields
+	Origin     string                 `json:"-"` // Source file and position, set when importing several sources
+}
+
+// ItemContent represents the content of a project item
+type ItemContent struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Body       string `json:"body,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ParseJSONFile parses a JSON file containing project items
+func ParseJSONFile(filename string) ([]ImportItem, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	// Handle both array format and object with items array
+	var items []ImportItem
+	var rawItems []map[string]interface{}
+
+	// First try to parse as array directly
+	if err := json.Unmarshal(data, &rawItems); err != nil {
+		// Try to parse as object with items array
+		var wrapper struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON file %s: %w", filename, err)
+		}
+		rawItems = wrapper.Items
+	}
+
+	// Convert raw items to ImportItem structs
+	for i, rawItem := range rawItems {
+		item, err := convertRawItemToImportItem(rawItem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse item %d: %w", i, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// maxNDJSONLineSize bounds the length of a single line in an NDJSON source
+const maxNDJSONLineSize = 16 * 1024 * 1024
+
+// ParseNDJSONFile parses a newline-delimited JSON (JSON Lines) file where each
+// line holds one project item. The file is read line by line so large exports
+// don't have to be loaded into memory at once.
+func ParseNDJSONFile(filename string) ([]ImportItem, error) {
+	var items []ImportItem
+	err := StreamNDJSONFile(filename, func(item ImportItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// StreamNDJSONFile parses an NDJSON file line by line, calling fn for each
+// item without holding the whole file in memory. An error from fn stops
+// reading and is returned unchanged.
+func StreamNDJSONFile(filename string, fn func(ImportItem) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue // Skip blank lines
+		}
+
+		var rawItem map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rawItem); err != nil {
+			return fmt.Errorf("failed to parse JSON on line %d: %w", lineNum, err)
+		}
+
+		item, err := convertRawItemToImportItem(rawItem)
+		if err != nil {
+			return fmt.Errorf("failed to parse item on line %d: %w", lineNum, err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// ParseCSVFile parses a CSV file containing project items
+func ParseCSVFile(filename string) ([]ImportItem, error) {
+	return parseCSVFileWithHeaders(filename, nil, CSVDialect{})
+}
+
+// parseCSVFileWithHeaders parses a CSV file written in dialect, renaming
+// columns according to headerMap (keyed by lowercased source header) before
+// the rows are converted
+func parseCSVFileWithHeaders(filename string, headerMap map[string]string, dialect CSVDialect) ([]ImportItem, error) {
+	var items []ImportItem
+	err := streamCSVFileWithHeaders(filename, headerMap, dialect, func(item ImportItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// streamCSVFileWithHeaders parses a CSV file row by row like
+// parseCSVFileWithHeaders, calling fn for each item. An error from fn stops
+// reading and is returned unchanged.
+func streamCSVFileWithHeaders(filename string, headerMap map[string]string, dialect CSVDialect, fn func(ImportItem) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	reader, err := dialect.newReader(file)
+	if err != nil {
+		return err
+	}
+	// Each row is converted before the next is read, so the record can be reused
+	reader.ReuseRecord = true
+
+	var headers []string
+	row := 0
+	if !dialect.NoHeader {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return fmt.Errorf("CSV file must have at least a header row and one data row")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV file %s: %w", filename, err)
+		}
+		headers = mapCSVHeaders(record, headerMap)
+		row = 1
+	}
+	headerRows := row
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV file %s: %w", filename, err)
+		}
+		row++
+
+		if headers == nil {
+			// Without a header row, columns are named by their position
+			names := make([]string, len(record))
+			for i := range record {
+				names[i] = strconv.Itoa(i + 1)
+			}
+			headers = mapCSVHeaders(names, headerMap)
+		}
+
+		if len(record) != len(headers) {
+			return fmt.Errorf("row %d has %d fields, expected %d", row, len(record), len(headers))
+		}
+
+		item, err := convertCSVRecordToImportItem(headers, record)
+		if err != nil {
+			return fmt.Errorf("failed to parse CSV row %d: %w", row, err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	if row == headerRows {
+		if dialect.NoHeader {
+			return fmt.Errorf("CSV file must have at least one row")
+		}
+		return fmt.Errorf("CSV file must have at least a header row and one data row")
+	}
+
+	return nil
+}
+
+// mapCSVHeaders renames the columns of a header row according to headerMap
+func mapCSVHeaders(record []string, headerMap map[string]string) []string {
+	headers := make([]string, len(record))
+	for i, header := range record {
+		headers[i] = header
+		if headerMap != nil {
+			normalized := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+			if mapped, ok := headerMap[normalized]; ok {
+				headers[i] = mapped
+			}
+		}
+	}
+	return headers
+}
+
+// convertRawItemToImportItem converts a raw map to ImportItem
+func convertRawItemToImportItem(rawItem map[string]interface{}) (ImportItem, error) {
+	item := ImportItem{
+		Fields: make(map[string]interface{}),
+	}
+
+	// Extract known fields
+	if id, ok := rawItem["id"].(string); ok {
+		item.ID = id
+	}
+
+	if title, ok := rawItem["title"].(string); ok {
+		item.Title = title
+	}
+
+	if url, ok := rawItem["url"].(string); ok {
+		item.URL = url
+	}
+
+	if repo, ok := rawItem["repository"].(string); ok {
+		item.Repository = repo
+	}
+
+	if notes, ok := rawItem["notes"].(string); ok {
+		item.Notes = notes
+	}
+
+	if parent, ok := rawItem["parent"].(string); ok {
+		item.Parent = parent
+	}
+
+	// Handle assignees
+	if assigneesRaw, ok := rawItem["assignees"]; ok {
+		if assigneesList, ok := assigneesRaw.([]interface{}); ok {
+			for _, assignee := range assigneesList {
+				if assigneeStr, ok := assignee.(string); ok {
+					item.Assignees = append(item.Assignees, assigneeStr)
+				}
+			}
+		}
+	}
+
+	// Handle labels
+	if labelsRaw, ok := rawItem["labels"]; ok {
+		if labelsList, ok := labelsRaw.([]interface{}); ok {
+			for _, label := range labelsList {
+				if labelStr, ok := label.(string); ok {
+					item.Labels = append(item.Labels, labelStr)
+				}
+			}
+		}
+	}
+
+	// Handle linked pull requests, as a list or comma-separated
+	switch linked := rawItem["linked_prs"].(type) {
+	case []interface{}:
+		for _, url := range linked {
+			if urlStr, ok := url.(string); ok {
+				item.LinkedPRs = append(item.LinkedPRs, urlStr)
+			}
+		}
+	case string:
+		item.LinkedPRs = splitList(linked)
+	}
+
+	// Handle content
+	if contentRaw, ok := rawItem["content"].(map[string]interface{}); ok {
+		item.Content = ItemContent{
+			Type:       ghclient.GetString(contentRaw, "type"),
+			Title:      ghclient.GetString(contentRaw, "title"),
+			Body:       ghclient.GetString(contentRaw, "body"),
+			Number:     ghclient.GetInt(contentRaw, "number"),
+			Repository: ghclient.GetString(contentRaw, "repository"),
+			URL:        ghclient.GetString(contentRaw, "url"),
+		}
+	}
+
+	// Store all other fields in Fields map
+	knownFields := map[string]bool{
+		"title": true, "url": true, "repository": true, "assignees": true,
+		"labels": true, "notes": true, "content": true, "id": true,
+		"parent": true, "linked_prs": true, ErrorFileColumn: true,
+	}
+
+	for key, value := range rawItem {
+		// Keys starting with "//" are comments, such as the option lists
+		// written by the template subcommand
+		if !knownFields[key] && !strings.HasPrefix(key, "//") {
+			item.Fields[key] = value
+		}
+	}
+
+	// Validate required fields
+	if item.Title == "" && item.Content.Title == "" {
+		return item, fmt.Errorf("item must have either 'title' field or 'content.title'")
+	}
+
+	// Use content title if no top-level title
+	if item.Title == "" {
+		item.Title = item.Content.Title
+	}
+
+	return item, nil
+}
+
+// ParseItemRecord converts an item in the raw JSON source format, one object
+// with the custom fields alongside title, url and the other known keys
+func ParseItemRecord(record map[string]interface{}) (ImportItem, error) {
+	return convertRawItemToImportItem(record)
+}
+
+// ItemRecord converts an item into the raw JSON source format, which
+// ParseItemRecord reads back
+func ItemRecord(item ImportItem) map[string]interface{} {
+	record := make(map[string]interface{}, len(item.Fields)+8)
+	for name, value := range item.Fields {
+		record[name] = value
+	}
+
+	record["title"] = item.Title
+	if item.ID != "" {
+		record["id"] = item.ID
+	}
+	if item.URL != "" {
+		record["url"] = item.URL
+	}
+	if item.Repository != "" {
+		record["repository"] = item.Repository
+	}
+	if item.Notes != "" {
+		record["notes"] = item.Notes
+	}
+	if item.Parent != "" {
+		record["parent"] = item.Parent
+	}
+	if len(item.LinkedPRs) > 0 {
+		record["linked_prs"] = item.LinkedPRs
+	}
+	if len(item.Assignees) > 0 {
+		record["assignees"] = item.Assignees
+	}
+	if len(item.Labels) > 0 {
+		record["labels"] = item.Labels
+	}
+	if item.Content != (ItemContent{}) {
+		record["content"] = item.Content
+	}
+	return record
+}
+
+// convertCSVRecordToImportItem converts a CSV record to ImportItem
+func convertCSVRecordToImportItem(headers []string, record []string) (ImportItem, error) {
+	item := ImportItem{
+		Fields: make(map[string]interface{}),
+	}
+
+	for i, header := range headers {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue // Skip empty values
+		}
+
+		// Normalize header name
+		normalizedHeader := strings.ToLower(strings.TrimSpace(header))
+
+		switch normalizedHeader {
+		case "title":
+			item.Title = value
+		case "url":
+			item.URL = value
+		case "repository":
+			item.Repository = value
+		case "body", "description":
+			item.Content.Body = trimBody(record[i])
+		case "notes":
+			item.Notes = trimBody(record[i])
+		case "id":
+			item.ID = value
+		case "parent":
+			item.Parent = value
+		case "linked_prs":
+			item.LinkedPRs = splitList(value)
+		case "assignees", "assignee":
+			// Handle comma-separated assignees
+			assignees := strings.Split(value, ",")
+			for _, assignee := range assignees {
+				if assignee = strings.TrimSpace(assignee); assignee != "" {
+					item.Assignees = append(item.Assignees, assignee)
+				}
+			}
+		case ErrorFileColumn:
+			// Error reports can be imported again once fixed
+		case "labels", "label":
+			// Handle comma-separated labels
+			labels := strings.Split(value, ",")
+			for _, label := range labels {
+				if label = strings.TrimSpace(label); label != "" {
+					item.Labels = append(item.Labels, label)
+				}
+			}
+		default:
+			// Try to parse as number if it looks like one
+			if num, err := strconv.ParseFloat(value, 64); err == nil {
+				// Check if it's actually an integer
+				if num == float64(int64(num)) {
+					item.Fields[header] = int64(num)
+				} else {
+					item.Fields[header] = num
+				}
+			} else {
+				item.Fields[header] = value
+			}
+		}
+	}
+
+	// Validate required fields
+	if item.Title == "" {
+		return item, fmt.Errorf("item must have a 'Title' field")
+	}
+
+	return item, nil
+}
+
+// splitList splits a comma-separated cell, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// trimBody removes the blank lines and trailing spaces around a body cell.
+// Unlike other cells, a multi-line body keeps the indentation of its first
+// line, which Markdown may depend on.
+func trimBody(value string) string {
+	value = strings.TrimRight(value, " \t\r\n")
+	for {
+		line, rest, found := strings.Cut(value, "\n")
+		if !found || strings.TrimSpace(line) != "" {
+			break
+		}
+		value = rest
+	}
+	if !strings.Contains(value, "\n") {
+		return strings.TrimSpace(value)
+	}
+	return value
+}
+
+// ValidateImportItems performs basic validation on import items
+func ValidateImportItems(items []ImportItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf("no items found to import")
+	}
+
+	return ValidateImportItemsFrom(items, 0)
+}
+
+// ValidateImportItemsFrom validates a chunk of items whose first item is at
+// position offset in the source, so errors report the item's source position
+func ValidateImportItemsFrom(items []ImportItem, offset int) error {
+	for i, item := range items {
+		if err := ValidateImportItem(item); err != nil {
+			if item.Origin != "" {
+				return fmt.Errorf("validation failed for item %d (%s): %w", offset+i+1, item.Origin, err)
+			}
+			return fmt.Errorf("validation failed for item %d: %w", offset+i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateImportItem validates a single import item
+func ValidateImportItem(item ImportItem) error {
+	if item.Title == "" {
+		return fmt.Errorf("item must have a title")
+	}
+
+	// If URL is provided, it should be a valid GitHub URL
+	if item.URL != "" {
+		if !strings.Contains(item.URL, "github.com") {
+			return fmt.Errorf("URL must be a GitHub URL: %s", item.URL)
+		}
+	}
+
+	return nil
+}
+
+// GetItemType determines the type of item (Issue, PullRequest, or DraftIssue)
+func GetItemType(item ImportItem) string {
+	if item.Content.Type != "" {
+		return item.Content.Type
+	}
+
+	if item.URL != "" {
+		if strings.Contains(item.URL, "/pull/") {
+			return "PullRequest"
+		} else if strings.Contains(item.URL, "/issues/") {
+			return "Issue"
+		}
+	}
+
+	// Default to DraftIssue if no URL
+	return "DraftIssue"
+}
+
+// GetItemBody returns the body text for an item
+func GetItemBody(item ImportItem) string {
+	if item.Content.Body != "" {
+		return item.Content.Body
+	}
+	if item.Notes != "" {
+		return item.Notes
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+// Tests for parsing CSV and JSON sources
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCSVMultiLineBodies(t *testing.T) {
+	// Quoted cells may hold line breaks, written as CRLF by spreadsheets
+	content := "Title,Body,Status\r\n" +
+		"Fix login,\"## Steps\r\n\r\n1. Open the app\r\n2. Sign in\r\n\r\n```\r\n    stack trace\r\n```\r\n\",Todo\r\n" +
+		"Write docs,\"\r\n\r\n    indented code\r\nand \"\"quotes\"\"\r\n  \",Done\r\n" +
+		"Plan,  One line  ,Todo\r\n"
+
+	items, err := ParseSourceFile(writeCSVBytes(t, content), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	expected := []string{
+		"## Steps\n\n1. Open the app\n2. Sign in\n\n```\n    stack trace\n```",
+		"    indented code\nand \"quotes\"",
+		"One line",
+	}
+	for i, body := range expected {
+		if got := GetItemBody(items[i]); got != body {
+			t.Errorf("Item %d: expected body %q, got %q", i+1, body, got)
+		}
+		if _, exists := items[i].Fields["Body"]; exists {
+			t.Errorf("Item %d: expected the body not to become a custom field", i+1)
+		}
+	}
+	if items[0].Fields["Status"] != "Todo" {
+		t.Errorf("Expected the column after the body to be read, got %v", items[0].Fields)
+	}
+}
+
+func TestCSVBodyColumns(t *testing.T) {
+	content := "Title,Description,Notes\n" +
+		"Both,\"The description\nof the item\",Some notes\n" +
+		"Notes only,,\"Line one\nLine two\"\n"
+
+	items, err := ParseSourceFile(writeCSVBytes(t, content), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The body column is kept apart from Notes and takes precedence over it
+	if items[0].Content.Body != "The description\nof the item" || items[0].Notes != "Some notes" {
+		t.Errorf("Unexpected body and notes: %q, %q", items[0].Content.Body, items[0].Notes)
+	}
+	if GetItemBody(items[0]) != "The description\nof the item" {
+		t.Errorf("Expected the description to be the body, got %q", GetItemBody(items[0]))
+	}
+	if GetItemBody(items[1]) != "Line one\nLine two" {
+		t.Errorf("Expected the notes to be the body, got %q", GetItemBody(items[1]))
+	}
+}
+
+func TestJSONCommentKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.json")
+	content := `[{"// Status": "one of: Todo | Done", "title": "Task", "Status": "Todo"}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := ParseJSONFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items[0].Fields) != 1 || items[0].Fields["Status"] != "Todo" {
+		t.Errorf("Expected only the Status field, got %v", items[0].Fields)
+	}
+}
+
+func TestParentColumns(t *testing.T) {
+	content := "ID,Title,URL,Parent\n" +
+		"EPIC-1,Epic,https://github.com/octo/app/issues/1,\n" +
+		"EPIC-2,Story,https://github.com/octo/app/issues/2,EPIC-1\n"
+
+	items, err := ParseSourceFile(writeCSVBytes(t, content), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items[0].ID != "EPIC-1" || items[1].Parent != "EPIC-1" || len(items[1].Fields) != 0 {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(path, []byte(`[{"title": "Story", "parent": "https://github.com/octo/app/issues/1"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err = ParseJSONFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items[0].Parent != "https://github.com/octo/app/issues/1" || len(items[0].Fields) != 0 {
+		t.Errorf("Expected the parent URL, got %+v", items[0])
+	}
+}
+
+func TestLinkedPRsColumn(t *testing.T) {
+	content := "Title,URL,linked_prs\n" +
+		"Story,https://github.com/octo/app/issues/2,\"https://github.com/octo/app/pull/7, https://github.com/octo/app/pull/8\"\n"
+
+	items, err := ParseSourceFile(writeCSVBytes(t, content), SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items[0].LinkedPRs) != 2 || items[0].LinkedPRs[1] != "https://github.com/octo/app/pull/8" {
+		t.Errorf("Unexpected linked pull requests: %v", items[0].LinkedPRs)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(path, []byte(`[{"title": "Story", "linked_prs": ["https://github.com/octo/app/pull/7"]}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err = ParseJSONFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items[0].LinkedPRs) != 1 || len(items[0].Fields) != 0 {
+		t.Errorf("Expected one linked pull request, got %+v", items[0])
+	}
+}
+
+func TestItemRecordRoundTrip(t *testing.T) {
+	item := ImportItem{
+		ID:        "PVTI_1",
+		Title:     "Fix login",
+		URL:       "https://github.com/owner/repo/issues/1",
+		Assignees: []string{"alice"},
+		Labels:    []string{"bug"},
+		Parent:    "PVTI_0",
+		LinkedPRs: []string{"https://github.com/owner/repo/pull/2"},
+		Content:   ItemContent{Type: "Issue", Title: "Fix login", Number: 1},
+		Fields:    map[string]interface{}{"Status": "Todo", "Estimate": 3.0},
+	}
+
+	// Records are exchanged as JSON
+	data, err := json.Marshal(ItemRecord(item))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseItemRecord(record)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, item) {
+		t.Errorf("Expected %+v, got %+v", item, parsed)
+	}
+}
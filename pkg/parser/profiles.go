@@ -0,0 +1,472 @@
+// Import profiles for exports produced by other project management tools
+// Each profile knows how to turn a tool's export into import items
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ImportProfile describes how to read a third-party tool's export. CSV-based
+// profiles set CSVHeaders, which --mapping can override; others provide Parse.
+// A profile may set both, in which case Parse handles the non-CSV exports.
+// PostProcess, if set, cleans up the items parsed from a CSV export.
+type ImportProfile struct {
+	Name        string
+	Description string
+	CSVHeaders  map[string]string
+	Parse       func(filename string) ([]ImportItem, error)
+	PostProcess func(items []ImportItem)
+}
+
+// importProfiles lists the profiles selectable with --profile
+var importProfiles = map[string]ImportProfile{
+	"jira": {
+		Name:        "jira",
+		Description: "Jira CSV export (Summary, Description, Issue key, Sprint, Status, Priority)",
+		CSVHeaders:  jiraCSVHeaders,
+	},
+	"asana": {
+		Name:        "asana",
+		Description: "Asana CSV export (Name, Notes, Section/Column, Assignee, Due Date, Tags)",
+		CSVHeaders:  asanaCSVHeaders,
+	},
+	"linear": {
+		Name:        "linear",
+		Description: "Linear CSV export (Title, Description, Status, Assignee, Estimate, Cycle Name)",
+		CSVHeaders:  linearCSVHeaders,
+	},
+	"gitlab": {
+		Name:        "gitlab",
+		Description: "GitLab issues CSV export or issues API JSON (labels, milestone, weight, iteration)",
+		CSVHeaders:  gitlabCSVHeaders,
+		Parse:       parseGitLabJSONFile,
+	},
+	"monday": {
+		Name:        "monday",
+		Description: "Monday.com board CSV export (Name, Status, Person, Date, Numbers)",
+		CSVHeaders:  mondayCSVHeaders,
+	},
+	"clickup": {
+		Name:        "clickup",
+		Description: "ClickUp CSV export (Task Name, Task Content, Status, Assignees, Tags, Priority, Due Date)",
+		CSVHeaders:  clickupCSVHeaders,
+		PostProcess: cleanClickUpItems,
+	},
+	"pivotal": {
+		Name:        "pivotal",
+		Description: "Pivotal Tracker CSV export (Type, Estimate, Current State, Owned By, Labels, Epic)",
+		CSVHeaders:  pivotalCSVHeaders,
+		PostProcess: cleanPivotalItems,
+	},
+	"trello": {
+		Name:        "trello",
+		Description: "Trello board JSON export (cards become draft issues, lists become Status)",
+		Parse:       parseTrelloJSONFile,
+	},
+}
+
+// GetImportProfile looks up a profile by name
+func GetImportProfile(name string) (ImportProfile, error) {
+	profile, ok := importProfiles[strings.ToLower(name)]
+	if !ok {
+		return ImportProfile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(ImportProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// ImportProfileNames returns the sorted names of all registered profiles
+func ImportProfileNames() []string {
+	names := make([]string, 0, len(importProfiles))
+	for name := range importProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jiraCSVHeaders maps Jira's CSV export columns to importer columns. Status and
+// Priority keep their names so they land on the project's single-select fields.
+var jiraCSVHeaders = map[string]string{
+	"summary":     "Title",
+	"description": "Notes",
+	"issue key":   "Issue Key",
+	"sprint":      "Iteration",
+	"status":      "Status",
+	"priority":    "Priority",
+}
+
+// asanaCSVHeaders maps Asana's CSV export columns to importer columns. The
+// board section a task sits in becomes its Status.
+var asanaCSVHeaders = map[string]string{
+	"name":           "Title",
+	"notes":          "Notes",
+	"section/column": "Status",
+	"assignee":       "Assignees",
+	"due date":       "Due Date",
+	"tags":           "Labels",
+	"task id":        "Asana ID",
+	"estimate":       "Estimate",
+}
+
+// linearCSVHeaders maps Linear's CSV export columns to importer columns. The
+// cycle an issue belongs to becomes its Iteration.
+var linearCSVHeaders = map[string]string{
+	"title":       "Title",
+	"description": "Notes",
+	"status":      "Status",
+	"priority":    "Priority",
+	"assignee":    "Assignees",
+	"estimate":    "Estimate",
+	"due date":    "Due Date",
+	"labels":      "Labels",
+	"cycle name":  "Iteration",
+	"id":          "Linear ID",
+}
+
+// gitlabCSVHeaders maps GitLab's issues CSV export columns to importer
+// columns. GitLab URLs can't be added to a GitHub project, so issues become
+// draft issues that keep their GitLab URL and ID in text fields.
+var gitlabCSVHeaders = map[string]string{
+	"title":             "Title",
+	"description":       "Notes",
+	"url":               "GitLab URL",
+	"issue id":          "GitLab ID",
+	"state":             "State",
+	"assignee username": "Assignees",
+	"labels":            "Labels",
+	"milestone":         "Milestone",
+	"weight":            "Estimate",
+	"iteration":         "Iteration",
+	"due date":          "Due Date",
+}
+
+// mondayCSVHeaders maps Monday.com's board CSV export columns to importer
+// columns. Status and Numbers columns keep their names.
+var mondayCSVHeaders = map[string]string{
+	"name":    "Title",
+	"item id": "Monday ID",
+	"person":  "Assignees",
+	"owner":   "Assignees",
+	"date":    "Due Date",
+	"notes":   "Notes",
+}
+
+// clickupCSVHeaders maps ClickUp's CSV export columns to importer columns
+var clickupCSVHeaders = map[string]string{
+	"task name":    "Title",
+	"task content": "Notes",
+	"task id":      "ClickUp ID",
+	"status":       "Status",
+	"assignees":    "Assignees",
+	"tags":         "Labels",
+	"priority":     "Priority",
+	"due date":     "Due Date",
+}
+
+// cleanClickUpItems strips the brackets ClickUp puts around list values, e.g.
+// an Assignees cell of "[Jane Doe, John Smith]"
+func cleanClickUpItems(items []ImportItem) {
+	for i := range items {
+		items[i].Assignees = trimListBrackets(items[i].Assignees)
+		items[i].Labels = trimListBrackets(items[i].Labels)
+	}
+}
+
+// trimListBrackets removes a leading "[" and trailing "]" from a split list
+func trimListBrackets(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		value = strings.TrimSpace(strings.Trim(value, "[]"))
+		if value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// pivotalCSVHeaders maps Pivotal Tracker's CSV export columns to importer
+// columns. Tracker repeats the Owned By column once per owner, and each of
+// them adds to Assignees. Story URLs point at Tracker, so stories become
+// draft issues that keep the URL in a text field.
+var pivotalCSVHeaders = map[string]string{
+	"id":            "Tracker ID",
+	"title":         "Title",
+	"description":   "Notes",
+	"url":           "Tracker URL",
+	"type":          "Story Type",
+	"estimate":      "Estimate",
+	"current state": "Status",
+	"owned by":      "Assignees",
+	"labels":        "Labels",
+	"epic":          "Epic",
+	"iteration":     "Iteration",
+	"deadline":      "Due Date",
+}
+
+// pivotalDateLayout is the format of dates in Pivotal Tracker's CSV export
+const pivotalDateLayout = "Jan 2, 2006"
+
+// cleanPivotalItems converts Tracker's dates, e.g. "Mar 5, 2024", to the
+// YYYY-MM-DD form a DATE field expects, and its iteration numbers to the
+// "Iteration N" titles GitHub gives iterations by default
+func cleanPivotalItems(items []ImportItem) {
+	for i := range items {
+		if due, ok := items[i].Fields["Due Date"].(string); ok {
+			if parsed, err := time.Parse(pivotalDateLayout, due); err == nil {
+				items[i].Fields["Due Date"] = parsed.Format("2006-01-02")
+			}
+		}
+		if number, ok := items[i].Fields["Iteration"].(int64); ok {
+			items[i].Fields["Iteration"] = fmt.Sprintf("Iteration %d", number)
+		}
+	}
+}
+
+// optionAliasesKey holds the single-select option aliases in a mapping file,
+// e.g. {"aliases": {"Status": {"WIP": "In Progress"}}}
+const optionAliasesKey = "aliases"
+
+// readMappingFile reads a mapping file's top-level JSON object
+func readMappingFile(filename string) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file %s: %w", filename, err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file %s: %w", filename, err)
+	}
+	return raw, nil
+}
+
+// isOptionAliases reports whether a mapping file entry holds the option
+// aliases rather than mapping a column that happens to be named "aliases"
+func isOptionAliases(key string, value json.RawMessage) bool {
+	return strings.EqualFold(key, optionAliasesKey) && strings.HasPrefix(strings.TrimSpace(string(value)), "{")
+}
+
+// LoadColumnMapping reads a JSON object mapping source column names to
+// importer columns, e.g. {"Story Points": "Estimate"}
+func LoadColumnMapping(filename string) (map[string]string, error) {
+	raw, err := readMappingFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := make(map[string]string, len(raw))
+	for column, value := range raw {
+		if isOptionAliases(column, value) {
+			continue
+		}
+		var target string
+		if err := json.Unmarshal(value, &target); err != nil {
+			return nil, fmt.Errorf("failed to parse mapping file %s: column %q must map to a column name", filename, column)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(column))] = target
+	}
+	return mapping, nil
+}
+
+// LoadOptionAliases reads the single-select option aliases of a mapping
+// file, keyed by lowercased field name and then lowercased alias
+func LoadOptionAliases(filename string) (map[string]map[string]string, error) {
+	raw, err := readMappingFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range raw {
+		if !isOptionAliases(key, value) {
+			continue
+		}
+
+		var fields map[string]map[string]string
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse aliases in mapping file %s: %w", filename, err)
+		}
+
+		aliases := make(map[string]map[string]string, len(fields))
+		for field, options := range fields {
+			fieldAliases := make(map[string]string, len(options))
+			for alias, option := range options {
+				fieldAliases[strings.ToLower(strings.TrimSpace(alias))] = option
+			}
+			aliases[strings.ToLower(strings.TrimSpace(field))] = fieldAliases
+		}
+		return aliases, nil
+	}
+	return nil, nil
+}
+
+// mergeColumnMappings combines a profile's default column mapping with user
+// overrides, which take precedence
+func mergeColumnMappings(defaults, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(overrides))
+	for column, target := range defaults {
+		merged[column] = target
+	}
+	for column, target := range overrides {
+		merged[column] = target
+	}
+	return merged
+}
+
+// trelloBoard is the subset of a Trello board export used for import
+type trelloBoard struct {
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Cards []struct {
+		Name   string `json:"name"`
+		Desc   string `json:"desc"`
+		IDList string `json:"idList"`
+		Due    string `json:"due"`
+		Closed bool   `json:"closed"`
+		Labels []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+}
+
+// parseTrelloJSONFile parses a Trello board export. Each open card becomes a
+// draft issue whose Status is the name of the list it sits in.
+func parseTrelloJSONFile(filename string) ([]ImportItem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("failed to parse Trello board %s: %w", filename, err)
+	}
+
+	listNames := make(map[string]string)
+	for _, list := range board.Lists {
+		listNames[list.ID] = list.Name
+	}
+
+	var items []ImportItem
+	for i, card := range board.Cards {
+		if card.Closed {
+			continue // Skip archived cards
+		}
+		if card.Name == "" {
+			return nil, fmt.Errorf("card %d has no name", i+1)
+		}
+
+		item := ImportItem{
+			Title:   card.Name,
+			Content: ItemContent{Type: "DraftIssue", Title: card.Name, Body: card.Desc},
+			Fields:  make(map[string]interface{}),
+		}
+
+		if status, ok := listNames[card.IDList]; ok {
+			item.Fields["Status"] = status
+		}
+
+		// Trello due dates are full timestamps; the DATE field only needs the day
+		if len(card.Due) >= len("2006-01-02") {
+			item.Fields["Due Date"] = card.Due[:len("2006-01-02")]
+		}
+
+		for _, label := range card.Labels {
+			// Unnamed Trello labels are identified only by their color
+			if label.Name != "" {
+				item.Labels = append(item.Labels, label.Name)
+			} else if label.Color != "" {
+				item.Labels = append(item.Labels, label.Color)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// gitlabIssue is the subset of an issue from GitLab's issues API used for import
+type gitlabIssue struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	WebURL      string   `json:"web_url"`
+	Labels      []string `json:"labels"`
+	DueDate     string   `json:"due_date"`
+	Weight      *int     `json:"weight"`
+	Assignees   []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Iteration *struct {
+		Title string `json:"title"`
+	} `json:"iteration"`
+}
+
+// parseGitLabJSONFile parses a JSON array of issues as returned by GitLab's
+// issues API, mapping them the same way as the CSV export
+func parseGitLabJSONFile(filename string) ([]ImportItem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	var issues []gitlabIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse GitLab issues %s: %w", filename, err)
+	}
+
+	items := make([]ImportItem, 0, len(issues))
+	for i, issue := range issues {
+		if issue.Title == "" {
+			return nil, fmt.Errorf("issue %d has no title", i+1)
+		}
+
+		item := ImportItem{
+			Title:   issue.Title,
+			Content: ItemContent{Type: "DraftIssue", Title: issue.Title, Body: issue.Description},
+			Labels:  issue.Labels,
+			Fields:  make(map[string]interface{}),
+		}
+
+		for _, assignee := range issue.Assignees {
+			item.Assignees = append(item.Assignees, assignee.Username)
+		}
+		if issue.WebURL != "" {
+			item.Fields["GitLab URL"] = issue.WebURL
+		}
+		if issue.IID != 0 {
+			item.Fields["GitLab ID"] = int64(issue.IID)
+		}
+		if issue.State != "" {
+			item.Fields["State"] = issue.State
+		}
+		if issue.Milestone != nil && issue.Milestone.Title != "" {
+			item.Fields["Milestone"] = issue.Milestone.Title
+		}
+		if issue.Weight != nil {
+			item.Fields["Estimate"] = int64(*issue.Weight)
+		}
+		if issue.Iteration != nil && issue.Iteration.Title != "" {
+			item.Fields["Iteration"] = issue.Iteration.Title
+		}
+		if issue.DueDate != "" {
+			item.Fields["Due Date"] = issue.DueDate
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
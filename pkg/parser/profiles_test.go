@@ -0,0 +1,361 @@
+// Tests for third-party export profiles
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJiraProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvFile := filepath.Join(tmpDir, "jira.csv")
+
+	csvContent := "\ufeffSummary,Issue key,Issue Type,Status,Priority,Description,Sprint,Sprint,Labels,Labels\n" +
+		"Login fails,PROJ-1,Bug,In Progress,High,\"Steps:\n1. open app\",Sprint 1,Sprint 2,auth,urgent\n" +
+		"Add export,PROJ-2,Story,To Do,Medium,,,,,\n"
+
+	if err := os.WriteFile(csvFile, []byte(csvContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := ParseSourceFile(csvFile, SourceOptions{Profile: "jira"})
+	if err != nil {
+		t.Fatalf("Failed to parse Jira export: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "Login fails" {
+		t.Errorf("Expected title from Summary, got %q", first.Title)
+	}
+	if GetItemBody(first) != "Steps:\n1. open app" {
+		t.Errorf("Expected body from Description, got %q", GetItemBody(first))
+	}
+	if first.Fields["Issue Key"] != "PROJ-1" {
+		t.Errorf("Expected Issue Key field, got %v", first.Fields["Issue Key"])
+	}
+	if first.Fields["Iteration"] != "Sprint 2" {
+		t.Errorf("Expected latest sprint as Iteration, got %v", first.Fields["Iteration"])
+	}
+	if first.Fields["Status"] != "In Progress" || first.Fields["Priority"] != "High" {
+		t.Errorf("Expected Status and Priority to be preserved, got %v", first.Fields)
+	}
+	if len(first.Labels) != 2 {
+		t.Errorf("Expected 2 labels, got %v", first.Labels)
+	}
+	if GetItemType(items[1]) != "DraftIssue" {
+		t.Errorf("Expected Jira issues to become draft issues, got %s", GetItemType(items[1]))
+	}
+
+	if _, err := ParseSourceFile(csvFile, SourceOptions{Profile: "nope"}); err == nil || !strings.Contains(err.Error(), "unknown profile") {
+		t.Errorf("Expected unknown profile error, got: %v", err)
+	}
+}
+
+func TestTrelloProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "board.json")
+
+	jsonContent := `{
+		"name": "Roadmap",
+		"lists": [{"id": "l1", "name": "To Do"}, {"id": "l2", "name": "Done"}],
+		"cards": [
+			{"name": "Write docs", "desc": "All of them", "idList": "l1", "due": "2024-12-31T17:00:00.000Z",
+			 "labels": [{"name": "docs", "color": "blue"}, {"name": "", "color": "red"}]},
+			{"name": "Old card", "idList": "l2", "closed": true},
+			{"name": "Ship it", "idList": "l2", "due": null, "labels": []}
+		]
+	}`
+
+	if err := os.WriteFile(jsonFile, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := ParseSourceFile(jsonFile, SourceOptions{Profile: "trello"})
+	if err != nil {
+		t.Fatalf("Failed to parse Trello board: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 open cards, got %d", len(items))
+	}
+
+	first := items[0]
+	if GetItemType(first) != "DraftIssue" || GetItemBody(first) != "All of them" {
+		t.Errorf("Expected draft issue with card description, got %+v", first)
+	}
+	if first.Fields["Status"] != "To Do" {
+		t.Errorf("Expected Status from list name, got %v", first.Fields["Status"])
+	}
+	if first.Fields["Due Date"] != "2024-12-31" {
+		t.Errorf("Expected Due Date '2024-12-31', got %v", first.Fields["Due Date"])
+	}
+	if len(first.Labels) != 2 || first.Labels[1] != "red" {
+		t.Errorf("Expected labels [docs red], got %v", first.Labels)
+	}
+
+	if _, exists := items[1].Fields["Due Date"]; exists {
+		t.Errorf("Expected no Due Date for card without due date")
+	}
+}
+
+func TestAsanaAndLinearProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	asanaFile := filepath.Join(tmpDir, "asana.csv")
+	asanaContent := "Task ID,Name,Section/Column,Assignee,Due Date,Tags,Notes,Story Points\n" +
+		"1201,Plan launch,In Progress,octocat,2024-06-01,\"launch,q3\",Kickoff notes,5\n"
+	if err := os.WriteFile(asanaFile, []byte(asanaContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	linearFile := filepath.Join(tmpDir, "linear.csv")
+	linearContent := "ID,Title,Description,Status,Priority,Assignee,Estimate,Labels,Cycle Name\n" +
+		"ENG-7,Fix crash,Stack trace attached,Todo,Urgent,hubot,3,\"Bug, Frontend\",Cycle 12\n"
+	if err := os.WriteFile(linearFile, []byte(linearContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	mappingFile := filepath.Join(tmpDir, "mapping.json")
+	if err := os.WriteFile(mappingFile, []byte(`{"Story Points": "Estimate", "Section/Column": "Stage"}`), 0644); err != nil {
+		t.Fatalf("Failed to create mapping file: %v", err)
+	}
+
+	asana, err := ParseSourceFile(asanaFile, SourceOptions{Profile: "asana"})
+	if err != nil {
+		t.Fatalf("Failed to parse Asana export: %v", err)
+	}
+	item := asana[0]
+	if item.Title != "Plan launch" || GetItemBody(item) != "Kickoff notes" {
+		t.Errorf("Unexpected Asana title/body: %+v", item)
+	}
+	if item.Fields["Status"] != "In Progress" || item.Fields["Due Date"] != "2024-06-01" || item.Fields["Asana ID"] != int64(1201) {
+		t.Errorf("Unexpected Asana fields: %v", item.Fields)
+	}
+	if len(item.Assignees) != 1 || len(item.Labels) != 2 {
+		t.Errorf("Unexpected Asana assignees/labels: %v %v", item.Assignees, item.Labels)
+	}
+
+	// The mapping file overrides and extends the profile defaults
+	asana, err = ParseSourceFile(asanaFile, SourceOptions{Profile: "asana", MappingFile: mappingFile})
+	if err != nil {
+		t.Fatalf("Failed to parse Asana export with mapping: %v", err)
+	}
+	if asana[0].Fields["Stage"] != "In Progress" || asana[0].Fields["Estimate"] != int64(5) {
+		t.Errorf("Expected mapping overrides to apply, got %v", asana[0].Fields)
+	}
+	if _, exists := asana[0].Fields["Status"]; exists {
+		t.Errorf("Expected overridden Status mapping to be replaced, got %v", asana[0].Fields)
+	}
+
+	linear, err := ParseSourceFile(linearFile, SourceOptions{Profile: "linear"})
+	if err != nil {
+		t.Fatalf("Failed to parse Linear export: %v", err)
+	}
+	item = linear[0]
+	if item.Title != "Fix crash" || GetItemBody(item) != "Stack trace attached" {
+		t.Errorf("Unexpected Linear title/body: %+v", item)
+	}
+	if item.Fields["Linear ID"] != "ENG-7" || item.Fields["Iteration"] != "Cycle 12" || item.Fields["Estimate"] != int64(3) {
+		t.Errorf("Unexpected Linear fields: %v", item.Fields)
+	}
+	if len(item.Labels) != 2 || item.Labels[1] != "Frontend" {
+		t.Errorf("Unexpected Linear labels: %v", item.Labels)
+	}
+}
+
+func TestGitLabProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvFile := filepath.Join(tmpDir, "gitlab.csv")
+	csvContent := "Issue ID,URL,Title,State,Description,Assignee Username,Labels,Milestone,Weight,Iteration,Due Date\n" +
+		"12,https://gitlab.com/group/app/-/issues/12,Fix login,opened,Steps to reproduce,octocat,\"bug,auth\",v1.0,3,Sprint 4,2024-06-01\n"
+	if err := os.WriteFile(csvFile, []byte(csvContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	jsonFile := filepath.Join(tmpDir, "gitlab.json")
+	jsonContent := `[{
+		"iid": 12,
+		"title": "Fix login",
+		"description": "Steps to reproduce",
+		"state": "opened",
+		"web_url": "https://gitlab.com/group/app/-/issues/12",
+		"labels": ["bug", "auth"],
+		"assignees": [{"username": "octocat"}],
+		"milestone": {"title": "v1.0"},
+		"weight": 3,
+		"iteration": {"title": "Sprint 4"},
+		"due_date": "2024-06-01"
+	}]`
+	if err := os.WriteFile(jsonFile, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for _, source := range []string{csvFile, jsonFile} {
+		t.Run(filepath.Ext(source), func(t *testing.T) {
+			items, err := ParseSourceFile(source, SourceOptions{Profile: "gitlab"})
+			if err != nil {
+				t.Fatalf("Failed to parse GitLab export: %v", err)
+			}
+			if len(items) != 1 {
+				t.Fatalf("Expected 1 item, got %d", len(items))
+			}
+
+			item := items[0]
+			if item.Title != "Fix login" || GetItemBody(item) != "Steps to reproduce" {
+				t.Errorf("Unexpected title/body: %q / %q", item.Title, GetItemBody(item))
+			}
+			if GetItemType(item) != "DraftIssue" {
+				t.Errorf("Expected GitLab issues to become drafts, got %s", GetItemType(item))
+			}
+			if len(item.Labels) != 2 || item.Labels[0] != "bug" {
+				t.Errorf("Unexpected labels: %v", item.Labels)
+			}
+			if len(item.Assignees) != 1 || item.Assignees[0] != "octocat" {
+				t.Errorf("Unexpected assignees: %v", item.Assignees)
+			}
+
+			expected := map[string]interface{}{
+				"GitLab URL": "https://gitlab.com/group/app/-/issues/12",
+				"GitLab ID":  int64(12),
+				"State":      "opened",
+				"Milestone":  "v1.0",
+				"Estimate":   int64(3),
+				"Iteration":  "Sprint 4",
+				"Due Date":   "2024-06-01",
+			}
+			for name, value := range expected {
+				if item.Fields[name] != value {
+					t.Errorf("Expected %s = %v (%T), got %v (%T)", name, value, value, item.Fields[name], item.Fields[name])
+				}
+			}
+		})
+	}
+}
+
+func TestMondayAndClickUpProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mondayFile := filepath.Join(tmpDir, "monday.csv")
+	mondayContent := "Name,Item ID,Person,Status,Date,Numbers\n" +
+		"Launch page,123456,\"Jane Doe, John Smith\",Working on it,2024-06-01,5\n"
+	if err := os.WriteFile(mondayFile, []byte(mondayContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	monday, err := ParseSourceFile(mondayFile, SourceOptions{Profile: "monday"})
+	if err != nil {
+		t.Fatalf("Failed to parse Monday.com export: %v", err)
+	}
+	if monday[0].Title != "Launch page" || len(monday[0].Assignees) != 2 || monday[0].Assignees[1] != "John Smith" {
+		t.Errorf("Unexpected Monday.com item: %+v", monday[0])
+	}
+	if monday[0].Fields["Status"] != "Working on it" || monday[0].Fields["Due Date"] != "2024-06-01" ||
+		monday[0].Fields["Numbers"] != int64(5) || monday[0].Fields["Monday ID"] != int64(123456) {
+		t.Errorf("Unexpected Monday.com fields: %v", monday[0].Fields)
+	}
+
+	clickupFile := filepath.Join(tmpDir, "clickup.csv")
+	clickupContent := "Task ID,Task Name,Task Content,Status,Assignees,Tags,Priority,Due Date\n" +
+		"86abc,Fix signup,Users can't sign up,in progress,\"[Jane Doe, John Smith]\",\"[bug, web]\",high,2024-06-01\n"
+	if err := os.WriteFile(clickupFile, []byte(clickupContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	clickup, err := ParseSourceFile(clickupFile, SourceOptions{Profile: "clickup"})
+	if err != nil {
+		t.Fatalf("Failed to parse ClickUp export: %v", err)
+	}
+	item := clickup[0]
+	if item.Title != "Fix signup" || GetItemBody(item) != "Users can't sign up" {
+		t.Errorf("Unexpected ClickUp title/body: %q / %q", item.Title, GetItemBody(item))
+	}
+	if len(item.Assignees) != 2 || item.Assignees[0] != "Jane Doe" || item.Assignees[1] != "John Smith" {
+		t.Errorf("Expected brackets to be stripped from assignees, got %q", item.Assignees)
+	}
+	if len(item.Labels) != 2 || item.Labels[0] != "bug" || item.Labels[1] != "web" {
+		t.Errorf("Expected brackets to be stripped from labels, got %q", item.Labels)
+	}
+	if item.Fields["ClickUp ID"] != "86abc" || item.Fields["Status"] != "in progress" || item.Fields["Priority"] != "high" {
+		t.Errorf("Unexpected ClickUp fields: %v", item.Fields)
+	}
+}
+
+func TestPivotalProfile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tracker.csv")
+	content := "Id,Title,Labels,Iteration,Type,Estimate,Current State,Deadline,Description,URL,Owned By,Owned By\n" +
+		"1001,Checkout flow,\"payments, web\",12,feature,3,started,\"Mar 5, 2024\",Build it,https://www.pivotaltracker.com/story/show/1001,Jane Doe,John Smith\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	items, err := ParseSourceFile(file, SourceOptions{Profile: "pivotal"})
+	if err != nil {
+		t.Fatalf("Failed to parse Pivotal Tracker export: %v", err)
+	}
+	item := items[0]
+	if item.Title != "Checkout flow" || GetItemBody(item) != "Build it" || item.URL != "" {
+		t.Errorf("Unexpected Pivotal Tracker item: %+v", item)
+	}
+	if len(item.Assignees) != 2 || item.Assignees[1] != "John Smith" {
+		t.Errorf("Expected an assignee per Owned By column, got %q", item.Assignees)
+	}
+	if len(item.Labels) != 2 || item.Labels[0] != "payments" {
+		t.Errorf("Unexpected labels: %q", item.Labels)
+	}
+	expected := map[string]interface{}{
+		"Tracker ID":  int64(1001),
+		"Tracker URL": "https://www.pivotaltracker.com/story/show/1001",
+		"Story Type":  "feature",
+		"Estimate":    int64(3),
+		"Status":      "started",
+		"Iteration":   "Iteration 12",
+		"Due Date":    "2024-03-05",
+	}
+	for name, value := range expected {
+		if item.Fields[name] != value {
+			t.Errorf("Expected %s = %v (%T), got %v (%T)", name, value, value, item.Fields[name], item.Fields[name])
+		}
+	}
+}
+
+func TestMappingFileOptionAliases(t *testing.T) {
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	content := `{"Story Points": "Estimate", "aliases": {"Status": {"WIP": "In Progress", " Shipped ": "Done"}}}`
+	if err := os.WriteFile(mappingFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create mapping file: %v", err)
+	}
+
+	columns, err := LoadColumnMapping(mappingFile)
+	if err != nil {
+		t.Fatalf("Failed to load column mapping: %v", err)
+	}
+	if len(columns) != 1 || columns["story points"] != "Estimate" {
+		t.Errorf("Expected the aliases to be left out of the column mapping, got %v", columns)
+	}
+
+	aliases, err := LoadOptionAliases(mappingFile)
+	if err != nil {
+		t.Fatalf("Failed to load option aliases: %v", err)
+	}
+	if aliases["status"]["wip"] != "In Progress" || aliases["status"]["shipped"] != "Done" {
+		t.Errorf("Unexpected aliases: %v", aliases)
+	}
+
+	// A column named aliases is still a column
+	if err := os.WriteFile(mappingFile, []byte(`{"Aliases": "Also Known As"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if columns, err := LoadColumnMapping(mappingFile); err != nil || columns["aliases"] != "Also Known As" {
+		t.Errorf("Expected an aliases column to be mapped, got %v, %v", columns, err)
+	}
+	if aliases, err := LoadOptionAliases(mappingFile); err != nil || aliases != nil {
+		t.Errorf("Expected no option aliases, got %v, %v", aliases, err)
+	}
+}
@@ -0,0 +1,515 @@
+// Expansion and staging of sources
+// Expands glob patterns, and downloads and decompresses sources into a private temporary directory that is removed after parsing
+package parser
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expandSources replaces glob patterns such as backlog/*.csv with the files
+// they match, in sorted order. URLs and plain paths are kept as given, and
+// a source listed more than once is only imported once.
+func expandSources(sources []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		matches := []string{source}
+		if !isRemoteSource(source) && strings.ContainsAny(source, "*?[") {
+			var err error
+			matches, err = filepath.Glob(source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid source pattern %q: %w", source, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("source pattern %q matched no files", source)
+			}
+			sort.Strings(matches)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// isRemoteSource reports whether the source is an http(s) URL
+func isRemoteSource(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// Source formats, as given by --source-format or a source's extension
+const (
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatCSV      = "csv"
+	FormatTOML     = "toml"
+	FormatMarkdown = "markdown"
+)
+
+// sourceFormat returns the format of a local source: the given format, or
+// the one its extension names. It is empty for unknown extensions.
+func sourceFormat(path, format string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".json"):
+		return FormatJSON
+	case strings.HasSuffix(lower, ".ndjson"), strings.HasSuffix(lower, ".jsonl"):
+		return FormatNDJSON
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(lower, ".toml"):
+		return FormatTOML
+	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".markdown"):
+		return FormatMarkdown
+	}
+	return ""
+}
+
+// contentTypeFormat returns the source format of an HTTP Content-Type, or
+// "" when it doesn't name one
+func contentTypeFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "application/json", "text/json":
+		return FormatJSON
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines", "application/jsonlines":
+		return FormatNDJSON
+	case "text/csv", "application/csv":
+		return FormatCSV
+	case "application/toml":
+		return FormatTOML
+	case "text/markdown", "text/x-markdown":
+		return FormatMarkdown
+	}
+	return ""
+}
+
+// isCompressedSource reports whether the source is a .gz or .zip file
+func isCompressedSource(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".zip")
+}
+
+// stageSource returns a local, uncompressed path for the source. URLs are
+// downloaded and .gz and .zip files decompressed into a temporary directory
+// readable only by the current user; the returned cleanup function removes it
+// unless options.KeepTemp is set. Plain local files are returned unchanged.
+func stageSource(ctx context.Context, source string, options SourceOptions) (string, func(), error) {
+	remote := isRemoteSource(source)
+	if !remote && !isCompressedSource(source) {
+		return source, func() {}, nil
+	}
+
+	// MkdirTemp creates the directory with mode 0700
+	dir, err := os.MkdirTemp("", "gh-project-import-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() {
+		if options.KeepTemp {
+			fmt.Fprintf(os.Stderr, "Kept temporary source files in %s\n", dir)
+			return
+		}
+		os.RemoveAll(dir)
+	}
+
+	path := source
+	if remote {
+		path, err = downloadSource(ctx, source, dir, options)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	switch lower := strings.ToLower(path); {
+	case strings.HasSuffix(lower, ".gz"):
+		path, err = decompressSource(path, dir)
+	case strings.HasSuffix(lower, ".zip"):
+		path, err = extractZipSource(path, dir, options.Format)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return path, cleanup, nil
+}
+
+// downloadSource saves the URL into dir, named after the last element of its
+// path. When neither options.Format nor that name gives the format, the
+// extension of the Content-Type the server sent is added to the name. The
+// download gives up once ctx is done or options.Timeout has passed.
+func downloadSource(ctx context.Context, source, dir string, options SourceOptions) (string, error) {
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL %s: %w", source, err)
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL %s: %w", source, err)
+	}
+	for _, header := range options.Headers {
+		name, value, _ := strings.Cut(header, ":")
+		req.Header.Set(strings.TrimSpace(name), os.ExpandEnv(strings.TrimSpace(value)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil && ctx.Err() == context.DeadlineExceeded && options.Timeout > 0 {
+		return "", fmt.Errorf("failed to download source %s: no response within %s", source, options.Timeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to download source %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download source %s: %s", source, resp.Status)
+	}
+
+	name := filepath.Base(parsed.Path)
+	if name == "." || name == "/" {
+		name = "source"
+	}
+	lowerName := strings.ToLower(name)
+	if options.Format == "" && options.Profile == "" && !strings.HasSuffix(lowerName, ".zip") && sourceFormat(strings.TrimSuffix(lowerName, ".gz"), "") == "" {
+		format := contentTypeFormat(resp.Header.Get("Content-Type"))
+		if format == "" {
+			return "", fmt.Errorf("cannot tell the format of source %s from its name or Content-Type %q; use --source-format", source, resp.Header.Get("Content-Type"))
+		}
+		name += "." + format
+	}
+
+	path := filepath.Join(dir, name)
+	if err := writeTempFile(path, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download source %s: %w", source, err)
+	}
+
+	return path, nil
+}
+
+// decompressSource decompresses a gzip file into dir, dropping the .gz suffix
+func decompressSource(source, dir string) (string, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("cannot access source file %s: %w", source, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress %s: %w", source, err)
+	}
+	defer reader.Close()
+
+	name := filepath.Base(source)
+	path := filepath.Join(dir, name[:len(name)-len(".gz")])
+	if err := writeTempFile(path, reader); err != nil {
+		return "", fmt.Errorf("failed to decompress %s: %w", source, err)
+	}
+
+	return path, nil
+}
+
+// extractZipSource extracts the source file held by a zip archive into dir.
+// Directories, hidden files and macOS resource forks are ignored; any other
+// file counts as a source when format is set, and otherwise when its
+// extension names a format. The archive must hold exactly one source.
+func extractZipSource(source, dir, format string) (string, error) {
+	archive, err := zip.OpenReader(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to open zip archive %s: %w", source, err)
+	}
+	defer archive.Close()
+
+	var entries []*zip.File
+	var names []string
+	for _, entry := range archive.File {
+		name := filepath.Base(entry.Name)
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(entry.Name, "__MACOSX/") {
+			continue
+		}
+		if sourceFormat(name, format) == "" {
+			continue
+		}
+		entries = append(entries, entry)
+		names = append(names, entry.Name)
+	}
+
+	switch len(entries) {
+	case 0:
+		return "", fmt.Errorf("zip archive %s holds no .json, .ndjson, .jsonl, .csv, .toml or .md file", source)
+	case 1:
+	default:
+		return "", fmt.Errorf("zip archive %s holds %d source files (%s); extract it and pass each file with --source", source, len(entries), strings.Join(names, ", "))
+	}
+
+	entry, err := entries[0].Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s from %s: %w", entries[0].Name, source, err)
+	}
+	defer entry.Close()
+
+	// Only the base name is used, so entries can't be written outside dir
+	path := filepath.Join(dir, filepath.Base(entries[0].Name))
+	if err := writeTempFile(path, entry); err != nil {
+		return "", fmt.Errorf("failed to extract %s from %s: %w", entries[0].Name, source, err)
+	}
+
+	return path, nil
+}
+
+// writeTempFile copies r into a new file readable only by the current user
+func writeTempFile(path string, r io.Reader) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// SourceOptions controls how a source file is read
+type SourceOptions struct {
+	Profile     string            // Name of an import profile for another tool's export
+	MappingFile string            // JSON file mapping CSV column names to importer columns
+	Columns     map[string]string // Inline column mapping, applied after MappingFile
+	KeepTemp    bool              // Keep downloaded and decompressed copies of the source
+	CSV         CSVDialect        // Delimiter, encoding and header row of CSV sources
+	Format      string            // Format of every source, one of the Format constants (default: from the file name, or the Content-Type of URLs)
+	Headers     []string          // HTTP headers for URL sources, as "Name: value"; $VARIABLES in values are expanded
+
+	// Timeout limits how long downloading a URL source may take, 0 for no
+	// limit. A download also stops when the context it was started with is
+	// done.
+	Timeout time.Duration
+}
+
+// Validate checks the format, the HTTP headers and the CSV dialect
+func (o SourceOptions) Validate() error {
+	switch strings.ToLower(o.Format) {
+	case "", FormatJSON, FormatNDJSON, FormatCSV, FormatTOML, FormatMarkdown:
+	default:
+		return fmt.Errorf("unsupported --source-format %q (expected json, ndjson, csv, toml or markdown)", o.Format)
+	}
+	for _, header := range o.Headers {
+		if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" || strings.ContainsAny(strings.TrimSpace(name), " \t") {
+			return fmt.Errorf("invalid --source-header %q (expected \"Name: value\")", header)
+		}
+	}
+	return o.CSV.Validate()
+}
+
+// ParseSources expands glob patterns in sources and parses every matching
+// file into a single list. With more than one file, each item records the
+// file and position it came from so errors can be traced back to it.
+func ParseSources(sources []string, options SourceOptions) ([]ImportItem, error) {
+	return ParseSourcesContext(context.Background(), sources, options)
+}
+
+// ParseSourcesContext is ParseSources with a context that stops downloads
+// of URL sources once it is done
+func ParseSourcesContext(ctx context.Context, sources []string, options SourceOptions) ([]ImportItem, error) {
+	var items []ImportItem
+	err := StreamSourcesContext(ctx, sources, options, func(item ImportItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// StreamSources is the streaming form of ParseSources, calling fn for each
+// item in source order. An error from fn stops reading and is returned unchanged.
+func StreamSources(sources []string, options SourceOptions, fn func(ImportItem) error) error {
+	return StreamSourcesContext(context.Background(), sources, options, fn)
+}
+
+// StreamSourcesContext is StreamSources with a context that stops downloads
+// of URL sources once it is done
+func StreamSourcesContext(ctx context.Context, sources []string, options SourceOptions, fn func(ImportItem) error) error {
+	files, err := expandSources(sources)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		position := 0
+		err := streamSource(ctx, file, options, func(item ImportItem) error {
+			position++
+			if len(files) > 1 {
+				item.Origin = fmt.Sprintf("%s item %d", file, position)
+			}
+			return fn(item)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSourceFile reads a local file, URL or gzip-compressed source. Remote
+// and compressed sources are staged in a temporary directory first.
+func ParseSourceFile(source string, options SourceOptions) ([]ImportItem, error) {
+	return ParseSourceFileContext(context.Background(), source, options)
+}
+
+// ParseSourceFileContext is ParseSourceFile with a context that stops the
+// download of a URL source once it is done
+func ParseSourceFileContext(ctx context.Context, source string, options SourceOptions) ([]ImportItem, error) {
+	var items []ImportItem
+	err := streamSource(ctx, source, options, func(item ImportItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// streamSource is the streaming form of ParseSourceFile
+func streamSource(ctx context.Context, source string, options SourceOptions, fn func(ImportItem) error) error {
+	path, cleanup, err := stageSource(ctx, source, options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return streamLocalSourceFile(path, options, fn)
+}
+
+// streamLocalSourceFile checks that the source exists and parses it with the
+// named profile, or based on its extension when no profile is given, calling
+// fn for each item. CSV, NDJSON and Markdown files are read row by
+// row; JSON arrays, TOML files and non-CSV profile exports are parsed whole
+// and then replayed.
+func streamLocalSourceFile(source string, options SourceOptions, fn func(ImportItem) error) error {
+	// Validate source file exists and is readable
+	if _, err := os.Stat(source); os.IsNotExist(err) {
+		return fmt.Errorf("source file does not exist: %s", source)
+	} else if err != nil {
+		return fmt.Errorf("cannot access source file %s: %w", source, err)
+	}
+
+	var mapping map[string]string
+	if options.MappingFile != "" {
+		var err error
+		mapping, err = LoadColumnMapping(options.MappingFile)
+		if err != nil {
+			return err
+		}
+	}
+	if len(options.Columns) > 0 {
+		mapping = mergeColumnMappings(mapping, options.Columns)
+	}
+
+	format := sourceFormat(source, options.Format)
+	isCSV := format == FormatCSV
+	if len(mapping) > 0 && !isCSV && format != FormatMarkdown {
+		return fmt.Errorf("--mapping is only supported for CSV and Markdown table sources")
+	}
+
+	// Errors from fn are passed through rather than reported as parse errors
+	var consumerErr error
+	emit := func(item ImportItem) error {
+		consumerErr = fn(item)
+		return consumerErr
+	}
+	replay := func(items []ImportItem, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if options.Profile != "" {
+		profile, profileErr := GetImportProfile(options.Profile)
+		if profileErr != nil {
+			return profileErr
+		}
+		if profile.CSVHeaders != nil && isCSV {
+			err = streamCSVFileWithHeaders(source, mergeColumnMappings(profile.CSVHeaders, mapping), options.CSV, func(item ImportItem) error {
+				if profile.PostProcess != nil {
+					items := []ImportItem{item}
+					profile.PostProcess(items)
+					item = items[0]
+				}
+				return emit(item)
+			})
+		} else if profile.Parse != nil {
+			err = replay(profile.Parse(source))
+		} else {
+			return fmt.Errorf("the %s profile expects a CSV export, got %s", profile.Name, source)
+		}
+	} else if format == FormatJSON {
+		err = replay(ParseJSONFile(source))
+	} else if format == FormatNDJSON {
+		err = StreamNDJSONFile(source, emit)
+	} else if isCSV {
+		err = streamCSVFileWithHeaders(source, mapping, options.CSV, emit)
+	} else if format == FormatTOML {
+		err = replay(ParseTOMLFile(source))
+	} else if format == FormatMarkdown {
+		err = streamMarkdownFile(source, mapping, emit)
+	} else {
+		return fmt.Errorf("unsupported file format. Only .json, .ndjson, .jsonl, .csv, .toml and .md files are supported; use --source-format for other names")
+	}
+
+	if consumerErr != nil {
+		return consumerErr
+	}
+	if err != nil {
+		// Provide more specific error context
+		if strings.Contains(err.Error(), "permission denied") {
+			return fmt.Errorf("permission denied reading file %s. Check file permissions", source)
+		}
+		if strings.Contains(err.Error(), "invalid character") {
+			return fmt.Errorf("invalid JSON format in file %s: %w", source, err)
+		}
+		return fmt.Errorf("failed to parse source file %s: %w", source, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+// Tests for expanding and staging sources
+package parser
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stagedCSV = "title,Status\nFirst,Todo\nSecond,Done\n"
+
+// writeGzipFile writes content to a gzip-compressed file
+func writeGzipFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCompressedSource(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.csv.gz")
+	writeGzipFile(t, source, stagedCSV)
+
+	items, err := ParseSourceFile(source, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(items) != 2 || items[1].Title != "Second" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+}
+
+// writeZipFile writes a zip archive holding the given files
+func writeZipFile(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, content := range files {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseZipSource(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "jira-export.zip")
+	writeZipFile(t, source, map[string]string{
+		"export/issues.csv":            stagedCSV,
+		"export/README.txt":            "Exported from Jira",
+		"__MACOSX/export/._issues.csv": "resource fork",
+	})
+
+	items, err := ParseSourceFile(source, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "First" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	several := filepath.Join(dir, "several.zip")
+	writeZipFile(t, several, map[string]string{"a.csv": stagedCSV, "b.json": "[]"})
+	if _, err := ParseSourceFile(several, SourceOptions{}); err == nil || !strings.Contains(err.Error(), "2 source files") {
+		t.Errorf("Expected an archive with several sources to be rejected, got: %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.zip")
+	writeZipFile(t, empty, map[string]string{"notes.txt": "nothing to import"})
+	if _, err := ParseSourceFile(empty, SourceOptions{}); err == nil || !strings.Contains(err.Error(), "no .json") {
+		t.Errorf("Expected an archive without sources to be rejected, got: %v", err)
+	}
+
+	// With --source-format any file in the archive counts
+	other := filepath.Join(dir, "other.zip")
+	writeZipFile(t, other, map[string]string{"issues.txt": stagedCSV})
+	items, err = ParseSourceFile(other, SourceOptions{Format: FormatCSV})
+	if err != nil || len(items) != 2 {
+		t.Errorf("Expected issues.txt to be read as CSV, got %+v, %v", items, err)
+	}
+}
+
+func TestParseRemoteSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/export/items.csv":
+			w.Write([]byte(stagedCSV))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	items, err := ParseSourceFile(server.URL+"/export/items.csv", SourceOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "First" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	if _, err := ParseSourceFile(server.URL+"/missing.csv", SourceOptions{}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected download error, got: %v", err)
+	}
+}
+
+func TestParseRemoteSourceFormatAndHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/backlog":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`[{"title": "From JSON"}]`))
+		case "/api/rows":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte(stagedCSV))
+		default:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("{\"title\": \"From NDJSON\"}\n"))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("SOURCE_TOKEN", "s3cret")
+	options := SourceOptions{Headers: []string{"Authorization: Bearer $SOURCE_TOKEN"}}
+
+	items, err := ParseSourceFile(server.URL+"/api/backlog", options)
+	if err != nil || len(items) != 1 || items[0].Title != "From JSON" {
+		t.Errorf("Expected the format from the Content-Type, got %+v, %v", items, err)
+	}
+	items, err = ParseSourceFile(server.URL+"/api/rows", options)
+	if err != nil || len(items) != 2 {
+		t.Errorf("Expected CSV rows from the Content-Type, got %+v, %v", items, err)
+	}
+
+	if _, err := ParseSourceFile(server.URL+"/api/stream", options); err == nil || !strings.Contains(err.Error(), "--source-format") {
+		t.Errorf("Expected an unknown format to ask for --source-format, got: %v", err)
+	}
+	options.Format = FormatNDJSON
+	items, err = ParseSourceFile(server.URL+"/api/stream", options)
+	if err != nil || len(items) != 1 || items[0].Title != "From NDJSON" {
+		t.Errorf("Expected --source-format to override the Content-Type, got %+v, %v", items, err)
+	}
+
+	if _, err := ParseSourceFile(server.URL+"/api/backlog", SourceOptions{}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the download to fail without the header, got: %v", err)
+	}
+}
+
+func TestParseRemoteSourceStops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	_, err := ParseSourceFile(server.URL+"/slow.csv", SourceOptions{Timeout: 50 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "no response within 50ms") {
+		t.Errorf("Expected the download to time out, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := ParseSourceFileContext(ctx, server.URL+"/slow.csv", SourceOptions{}); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("Expected the download to stop with the context, got: %v", err)
+	}
+}
+
+func TestSourceOptionsValidate(t *testing.T) {
+	valid := SourceOptions{Format: "CSV", Headers: []string{"Authorization: token abc", "X-Api-Key:abc"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, options := range []SourceOptions{
+		{Format: "xml"},
+		{Headers: []string{"Authorization"}},
+		{Headers: []string{": value"}},
+		{Headers: []string{"Bad Name: value"}},
+		{CSV: CSVDialect{Delimiter: "ab"}},
+	} {
+		if err := options.Validate(); err == nil {
+			t.Errorf("Expected an error for %+v", options)
+		}
+	}
+}
+
+func TestParseLocalSourceWithFormat(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "export.txt")
+	if err := os.WriteFile(source, []byte(stagedCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseSourceFile(source, SourceOptions{}); err == nil {
+		t.Error("Expected an unknown extension to be rejected")
+	}
+	items, err := ParseSourceFile(source, SourceOptions{Format: FormatCSV})
+	if err != nil || len(items) != 2 {
+		t.Errorf("Expected --source-format to read the file as CSV, got %+v, %v", items, err)
+	}
+}
+
+func TestStageSourceCleanup(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "items.json.gz")
+	writeGzipFile(t, source, `[{"title": "Only"}]`)
+
+	path, cleanup, err := stageSource(context.Background(), source, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if filepath.Base(path) != "items.json" {
+		t.Errorf("Expected decompressed file items.json, got %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected staged file to exist: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected staged file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	cleanup()
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary directory to be removed, got: %v", err)
+	}
+
+	// Plain local files are used in place
+	plain := filepath.Join(t.TempDir(), "items.csv")
+	path, cleanup, err = stageSource(context.Background(), plain, SourceOptions{})
+	if err != nil || path != plain {
+		t.Errorf("Expected local file to be used in place, got %s, %v", path, err)
+	}
+	cleanup()
+}
+
+func TestParseSourcesGlob(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "b.csv"), []byte("title\nThird\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.csv"), []byte(stagedCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The glob and an explicit path overlap; each file is only imported once
+	sources := []string{filepath.Join(dir, "*.csv"), filepath.Join(dir, "a.csv")}
+	items, err := ParseSources(sources, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+	if items[0].Title != "First" || items[2].Title != "Third" {
+		t.Errorf("Expected items in file order, got %q and %q", items[0].Title, items[2].Title)
+	}
+	if want := filepath.Join(dir, "b.csv") + " item 1"; items[2].Origin != want {
+		t.Errorf("Expected origin %q, got %q", want, items[2].Origin)
+	}
+}
+
+func TestParseSourcesSingleFileHasNoOrigin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(path, []byte(stagedCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := ParseSources([]string{path}, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items[0].Origin != "" {
+		t.Errorf("Expected no origin for a single source, got %q", items[0].Origin)
+	}
+}
+
+func TestParseSourcesUnmatchedGlob(t *testing.T) {
+	_, err := ParseSources([]string{filepath.Join(t.TempDir(), "*.csv")}, SourceOptions{})
+	if err == nil || !strings.Contains(err.Error(), "matched no files") {
+		t.Errorf("Expected unmatched pattern error, got: %v", err)
+	}
+}
+
+func TestValidateImportItemsReportsOrigin(t *testing.T) {
+	items := []ImportItem{{Title: "Fine"}, {Origin: "backlog/b.csv item 4"}}
+	err := ValidateImportItems(items)
+	if err == nil || !strings.Contains(err.Error(), "backlog/b.csv item 4") {
+		t.Errorf("Expected error naming the source file, got: %v", err)
+	}
+}
@@ -0,0 +1,639 @@
+// TOML sources
+// Reads items from a TOML array of tables, e.g. [[items]], using the keys of the JSON source format
+package parser
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseTOMLFile parses a TOML file holding the items as an array of tables.
+// The array is named items; a file with a single array of tables may name it
+// anything. Each table has the keys of an item in the JSON source format.
+func ParseTOMLFile(filename string) ([]ImportItem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	document, err := parseTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TOML file %s: %w", filename, err)
+	}
+
+	rawItems, err := tomlItemTables(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TOML file %s: %w", filename, err)
+	}
+
+	items := make([]ImportItem, 0, len(rawItems))
+	for i, rawItem := range rawItems {
+		item, err := convertRawItemToImportItem(rawItem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse item %d: %w", i, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// tomlItemTables finds the array of tables holding the items
+func tomlItemTables(document map[string]interface{}) ([]map[string]interface{}, error) {
+	var names []string
+	for name, value := range document {
+		if isTableArray(value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	name := "items"
+	if _, ok := document[name]; !ok || !isTableArray(document[name]) {
+		if len(names) != 1 {
+			return nil, fmt.Errorf("expected the items as an array of tables, e.g. [[items]], found %d arrays of tables", len(names))
+		}
+		name = names[0]
+	}
+
+	values := document[name].([]interface{})
+	tables := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		tables[i] = value.(map[string]interface{})
+	}
+	return tables, nil
+}
+
+// isTableArray reports whether value is a non-empty array of tables
+func isTableArray(value interface{}) bool {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		if _, ok := v.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlParser reads the subset of TOML used for item lists: tables, arrays of
+// tables, dotted keys, strings, numbers, booleans, arrays and inline tables.
+// Values come out as encoding/json would decode them, with every number a
+// float64; dates and times are kept as text.
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+
+	// defined records the tables opened by a [table] header, which may not
+	// be opened twice
+	defined map[string]bool
+}
+
+// parseTOML parses a TOML document
+func parseTOML(src string) (map[string]interface{}, error) {
+	p := &tomlParser{src: strings.TrimPrefix(src, "\ufeff"), line: 1, defined: make(map[string]bool)}
+	root := make(map[string]interface{})
+	current := root
+
+	for {
+		p.skipBlankLines()
+		if p.eof() {
+			return root, nil
+		}
+
+		var err error
+		if p.peek() == '[' {
+			current, err = p.parseTableHeader(root)
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		if err := p.endOfLine(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+	}
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.src[p.pos:], prefix)
+}
+
+// skipSpaces skips spaces and tabs
+func (p *tomlParser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment up to the end of the line
+func (p *tomlParser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipBlankLines skips whitespace, comments and line breaks
+func (p *tomlParser) skipBlankLines() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r':
+			p.pos++
+		case '\n':
+			p.pos++
+			p.line++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine checks that only a comment follows on the line
+func (p *tomlParser) endOfLine() error {
+	p.skipSpaces()
+	p.skipComment()
+	if p.hasPrefix("\r\n") {
+		p.pos++
+	}
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return fmt.Errorf("unexpected %q after value", p.rest())
+	}
+	p.pos++
+	p.line++
+	return nil
+}
+
+// rest returns the remainder of the current line, for error messages
+func (p *tomlParser) rest() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		return p.src[p.pos:]
+	}
+	return strings.TrimRight(p.src[p.pos:p.pos+end], "\r")
+}
+
+// parseTableHeader reads a [table] or [[array]] header and returns the table
+// that the following keys belong to
+func (p *tomlParser) parseTableHeader(root map[string]interface{}) (map[string]interface{}, error) {
+	array := p.hasPrefix("[[")
+	if array {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+	p.skipSpaces()
+	key, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if array {
+		if !p.hasPrefix("]]") {
+			return nil, fmt.Errorf("expected ]] to close the array of tables header")
+		}
+		p.pos += 2
+	} else {
+		if p.peek() != ']' {
+			return nil, fmt.Errorf("expected ] to close the table header")
+		}
+		p.pos++
+	}
+
+	parent, err := p.descend(root, key[:len(key)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := key[len(key)-1]
+
+	if array {
+		table := make(map[string]interface{})
+		switch existing := parent[last].(type) {
+		case nil:
+			parent[last] = []interface{}{table}
+		case []interface{}:
+			if !isTableArray(existing) {
+				return nil, fmt.Errorf("%s is not an array of tables", strings.Join(key, "."))
+			}
+			parent[last] = append(existing, table)
+		default:
+			return nil, fmt.Errorf("%s is already defined", strings.Join(key, "."))
+		}
+		return table, nil
+	}
+
+	id := fmt.Sprintf("%p.%s", parent, last)
+	if p.defined[id] {
+		return nil, fmt.Errorf("table %s is defined twice", strings.Join(key, "."))
+	}
+	p.defined[id] = true
+	switch existing := parent[last].(type) {
+	case nil:
+		table := make(map[string]interface{})
+		parent[last] = table
+		return table, nil
+	case map[string]interface{}:
+		return existing, nil
+	}
+	return nil, fmt.Errorf("%s is already defined", strings.Join(key, "."))
+}
+
+// descend walks a table path from table, creating missing tables. The path
+// continues in the last table of an array of tables.
+func (p *tomlParser) descend(table map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for i, name := range path {
+		switch next := table[name].(type) {
+		case nil:
+			created := make(map[string]interface{})
+			table[name] = created
+			table = created
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			if !isTableArray(next) {
+				return nil, fmt.Errorf("%s is not a table", strings.Join(path[:i+1], "."))
+			}
+			table = next[len(next)-1].(map[string]interface{})
+		default:
+			return nil, fmt.Errorf("%s is not a table", strings.Join(path[:i+1], "."))
+		}
+	}
+	return table, nil
+}
+
+// parseKeyValue reads a key = value line into table
+func (p *tomlParser) parseKeyValue(table map[string]interface{}) error {
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpaces()
+	if p.peek() != '=' {
+		return fmt.Errorf("expected = after key %s", strings.Join(key, "."))
+	}
+	p.pos++
+	p.skipSpaces()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(key, "."), err)
+	}
+
+	parent, err := p.descend(table, key[:len(key)-1])
+	if err != nil {
+		return err
+	}
+	last := key[len(key)-1]
+	if _, exists := parent[last]; exists {
+		return fmt.Errorf("duplicate key %s", strings.Join(key, "."))
+	}
+	parent[last] = value
+	return nil
+}
+
+// parseKey reads a bare, quoted or dotted key
+func (p *tomlParser) parseKey() ([]string, error) {
+	var key []string
+	for {
+		p.skipSpaces()
+		var part string
+		switch p.peek() {
+		case '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		case '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, fmt.Errorf("expected a key, found %q", p.rest())
+			}
+			part = p.src[start:p.pos]
+		}
+		key = append(key, part)
+
+		p.skipSpaces()
+		if p.peek() != '.' {
+			return key, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseValue reads a value
+func (p *tomlParser) parseValue() (interface{}, error) {
+	switch {
+	case p.hasPrefix(`"""`):
+		return p.parseMultilineString(`"""`, true)
+	case p.hasPrefix("'''"):
+		return p.parseMultilineString("'''", false)
+	case p.peek() == '"':
+		return p.parseBasicString()
+	case p.peek() == '\'':
+		return p.parseLiteralString()
+	case p.peek() == '[':
+		return p.parseArray()
+	case p.peek() == '{':
+		return p.parseInlineTable()
+	case p.hasPrefix("true"):
+		p.pos += len("true")
+		return true, nil
+	case p.hasPrefix("false"):
+		p.pos += len("false")
+		return false, nil
+	}
+	return p.parseNumberOrDate()
+}
+
+// parseBasicString reads a "string" with escapes
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseEscape reads an escape sequence at the backslash
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	p.pos++
+	if p.eof() {
+		return fmt.Errorf("unterminated string")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte(0x1b)
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return fmt.Errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape \\%c%s", c, p.src[p.pos:p.pos+size])
+		}
+		b.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return fmt.Errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+// parseLiteralString reads a 'string' without escapes
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// parseMultilineString reads a multi-line basic or literal string, quoted
+// with three double or single quotes. A line break right after the opening quotes is dropped, and in
+// basic strings a backslash at the end of a line joins it to the next.
+func (p *tomlParser) parseMultilineString(quotes string, escapes bool) (string, error) {
+	p.pos += len(quotes)
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.hasPrefix("\n") {
+		p.pos++
+		p.line++
+	}
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated multi-line string")
+		}
+		if p.hasPrefix(quotes) {
+			// Up to two quotes may end the content right before the closing ones
+			extra := 0
+			for extra < 2 && p.pos+len(quotes)+extra < len(p.src) && p.src[p.pos+len(quotes)+extra] == quotes[0] {
+				extra++
+			}
+			b.WriteString(p.src[p.pos : p.pos+extra])
+			p.pos += len(quotes) + extra
+			return b.String(), nil
+		}
+
+		c := p.peek()
+		if c == '\\' && escapes {
+			// A line-ending backslash trims the line break and the
+			// whitespace that follows
+			rest := strings.TrimLeft(p.src[p.pos+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				p.pos++
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if c == '\n' {
+			p.line++
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+}
+
+// parseArray reads an [array], which may span lines
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.pos++
+	values := []interface{}{}
+	for {
+		p.skipBlankLines()
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipBlankLines()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return values, nil
+		default:
+			return nil, fmt.Errorf("expected , or ] in array, found %q", p.rest())
+		}
+	}
+}
+
+// parseInlineTable reads an { inline = "table" } on a single line
+func (p *tomlParser) parseInlineTable() (map[string]interface{}, error) {
+	p.pos++
+	table := make(map[string]interface{})
+	p.skipSpaces()
+	if p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, fmt.Errorf("expected , or } in inline table, found %q", p.rest())
+		}
+	}
+}
+
+// parseNumberOrDate reads an integer, float, date or time. Dates and times
+// are returned as written.
+func (p *tomlParser) parseNumberOrDate() (interface{}, error) {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\r\n,]}#", p.peek()) < 0 {
+		p.pos++
+	}
+	// A date and a time may be separated by a space
+	if p.pos-start == 10 && p.hasPrefix(" ") && p.pos+3 < len(p.src) && isDigit(p.src[p.pos+1]) && isDigit(p.src[p.pos+2]) && p.src[p.pos+3] == ':' {
+		p.pos++
+		for !p.eof() && strings.IndexByte("0123456789Zz.:+-", p.peek()) >= 0 {
+			p.pos++
+		}
+	}
+	token := p.src[start:p.pos]
+	if token == "" {
+		return nil, fmt.Errorf("expected a value, found %q", p.rest())
+	}
+
+	if isTOMLDateTime(token) {
+		return token, nil
+	}
+
+	switch strings.TrimLeft(token, "+-") {
+	case "inf":
+		if strings.HasPrefix(token, "-") {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	digits := strings.ReplaceAll(token, "_", "")
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(digits, prefix) {
+			n, err := strconv.ParseInt(digits[2:], base, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s", token)
+			}
+			return float64(n), nil
+		}
+	}
+	n, err := strconv.ParseFloat(digits, 64)
+	if err != nil || strings.ContainsAny(digits, "xXoOnN") {
+		return nil, fmt.Errorf("invalid value %s", token)
+	}
+	return n, nil
+}
+
+// isTOMLDateTime reports whether a token is a date, time or date-time, such
+// as 2024-05-01, 07:32:00 or 2024-05-01T07:32:00Z
+func isTOMLDateTime(token string) bool {
+	if len(token) >= 10 && isDigit(token[0]) && token[4] == '-' && token[7] == '-' {
+		return true
+	}
+	return len(token) >= 8 && isDigit(token[0]) && token[2] == ':' && token[5] == ':'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,122 @@
+// Tests for TOML sources
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSourceFile writes content to a temporary file with the given name
+func writeSourceFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	return path
+}
+
+func TestParseTOMLFile(t *testing.T) {
+	source := writeSourceFile(t, "backlog.toml", `# Team backlog
+title = "Q3"
+
+[[items]]
+title = "Add user authentication"
+notes = """
+Implement the OAuth2 login flow.
+Use the "device" grant."""
+Status = 'Todo'
+Estimate = 5
+"Story Points" = 1_000
+assignees = [
+  "octocat", # lead
+  "hubot",
+]
+Due = 2024-07-01
+
+[[items]]
+title = "Fix database connection issue"
+url = "https://github.com/owner/repo/issues/123"
+labels = ["bug", "db"]
+Ratio = 0.5
+Blocked = false
+
+[items.content]
+body = "Line one\nLine two é"
+`)
+
+	items, err := ParseSourceFile(source, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "Add user authentication" || first.Notes != "Implement the OAuth2 login flow.\nUse the \"device\" grant." {
+		t.Errorf("Unexpected title or notes: %q, %q", first.Title, first.Notes)
+	}
+	if !reflect.DeepEqual(first.Assignees, []string{"octocat", "hubot"}) {
+		t.Errorf("Unexpected assignees: %v", first.Assignees)
+	}
+	wantFields := map[string]interface{}{"Status": "Todo", "Estimate": float64(5), "Story Points": float64(1000), "Due": "2024-07-01"}
+	if !reflect.DeepEqual(first.Fields, wantFields) {
+		t.Errorf("Expected fields %v, got %v", wantFields, first.Fields)
+	}
+
+	second := items[1]
+	if second.URL != "https://github.com/owner/repo/issues/123" || !reflect.DeepEqual(second.Labels, []string{"bug", "db"}) {
+		t.Errorf("Unexpected second item: %+v", second)
+	}
+	if second.Fields["Ratio"] != 0.5 || second.Fields["Blocked"] != false {
+		t.Errorf("Unexpected second item fields: %v", second.Fields)
+	}
+	if second.Content.Body != "Line one\nLine two é" {
+		t.Errorf("Expected [items.content] to belong to the second item, got %q", second.Content.Body)
+	}
+}
+
+func TestParseTOMLFileOtherArrayName(t *testing.T) {
+	source := writeSourceFile(t, "backlog.toml", "[[task]]\ntitle = 'One'\n\n[[task]]\ntitle = 'Two'\nfields = { Status = \"Done\", Size.Points = 3 }\n")
+
+	items, err := ParseSourceFile(source, SourceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1].Title != "Two" {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+	want := map[string]interface{}{"Status": "Done", "Size": map[string]interface{}{"Points": float64(3)}}
+	if !reflect.DeepEqual(items[1].Fields["fields"], want) {
+		t.Errorf("Expected an inline table, got %v", items[1].Fields["fields"])
+	}
+}
+
+func TestParseTOMLFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no items", "title = 'x'\n", "array of tables"},
+		{"several arrays", "[[a]]\ntitle = 'x'\n[[b]]\ntitle = 'y'\n", "found 2 arrays"},
+		{"duplicate key", "[[items]]\ntitle = 'x'\ntitle = 'y'\n", "line 3: duplicate key title"},
+		{"unterminated string", "[[items]]\ntitle = \"x\n", "line 2: title: unterminated string"},
+		{"trailing text", "[[items]]\ntitle = 'x' y\n", "unexpected \"y\" after value"},
+		{"bad value", "[[items]]\ntitle = 'x'\nEstimate = five\n", "invalid value five"},
+		{"missing title", "[[items]]\nStatus = 'Todo'\n", "failed to parse item 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSourceFile(writeSourceFile(t, "items.toml", tt.content), SourceOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+# Cross-compiles the extension for gh-extension-precompile. The main package
+# lives in ./cmd/gh-project-import rather than the repository root.
+set -e
+
+version="${1:-dev}"
+platforms=(
+  darwin-amd64
+  darwin-arm64
+  linux-386
+  linux-amd64
+  linux-arm
+  linux-arm64
+  windows-386
+  windows-amd64
+  windows-arm64
+)
+
+mkdir -p dist
+for p in "${platforms[@]}"; do
+  goos="${p%-*}"
+  goarch="${p#*-}"
+  ext=""
+  if [ "$goos" = "windows" ]; then
+    ext=".exe"
+  fi
+  GOOS="$goos" GOARCH="$goarch" CGO_ENABLED=0 go build -trimpath \
+    -ldflags="-s -w -X main.version=${version}" \
+    -o "dist/${p}${ext}" ./cmd/gh-project-import
+done